@@ -1,19 +1,30 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"sort"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
 	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/notify"
 	"github.com/riddopic/cc-tools/internal/output"
 )
 
-const configSetArgs = 2
+const (
+	configSetArgs  = 2
+	audioPlayerKey = "notify.audio.player"
+	yesFlag        = "yes"
+)
 
 func newConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -23,8 +34,14 @@ func newConfigCmd() *cobra.Command {
 	cmd.AddCommand(
 		newConfigGetCmd(),
 		newConfigSetCmd(),
+		newConfigSetManyCmd(),
 		newConfigListCmd(),
 		newConfigResetCmd(),
+		newConfigKeysCmd(),
+		newConfigTemplateCmd(),
+		newConfigPathCmd(),
+		newConfigMigrateLegacyCmd(),
+		newConfigDoctorKeysCmd(),
 	)
 	return cmd
 }
@@ -42,15 +59,48 @@ func newConfigGetCmd() *cobra.Command {
 }
 
 func newConfigSetCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "set <key> <value>",
-		Short:   "Set a configuration value",
+	var valueType string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a configuration value",
+		Long: "Sets a configuration value. Keys that run commands or change the environment of " +
+			"validation commands are security-relevant: the old and new values are shown and the " +
+			"change must be confirmed, or passed --yes when stdin is not a terminal.",
 		Args:    cobra.ExactArgs(configSetArgs),
-		Example: "  cc-tools config set validate.timeout 90",
-		RunE: func(_ *cobra.Command, args []string) error {
-			return handleConfigSet(context.Background(), newTerminal(), newConfigManager(), args[0], args[1])
+		Example: "  cc-tools config set validate.timeout 90\n  cc-tools config set drift.enabled false --type bool",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return handleConfigSet(
+				context.Background(), newTerminal(), newConfigManager(), newConfirmPrompt(cmd, yes),
+				args[0], args[1], valueType,
+			)
+		},
+	}
+	cmd.Flags().StringVar(&valueType, "type", "", "force value interpretation: string, int, float, bool, or list")
+	cmd.Flags().BoolVarP(&yes, yesFlag, "y", false, "change security-relevant keys without asking")
+	return cmd
+}
+
+func newConfigSetManyCmd() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "set-many <key=value>...",
+		Short: "Set several configuration values and save once",
+		Long: "Validates every key=value pair before writing anything. If any pair is invalid, " +
+			"the configuration file is left unchanged. Security-relevant keys are confirmed as " +
+			"with config set.",
+		Args:    cobra.MinimumNArgs(1),
+		Example: "  cc-tools config set-many validate.timeout=90 validate.cooldown=10 drift.enabled=false",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return handleConfigSetMany(
+				context.Background(), newTerminal(), newConfigManager(), newConfirmPrompt(cmd, yes), args,
+			)
 		},
 	}
+	cmd.Flags().BoolVarP(&yes, yesFlag, "y", false, "change security-relevant keys without asking")
+	return cmd
 }
 
 func newConfigListCmd() *cobra.Command {
@@ -80,6 +130,114 @@ func newConfigResetCmd() *cobra.Command {
 	}
 }
 
+func newConfigKeysCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:     "keys",
+		Short:   "List configuration keys with types, defaults, and descriptions",
+		Args:    cobra.NoArgs,
+		Example: "  cc-tools config keys\n  cc-tools config keys --json",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return handleConfigKeys(newTerminal(), jsonOutput)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	return cmd
+}
+
+func newConfigTemplateCmd() *cobra.Command {
+	var withComments bool
+
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Print the default configuration with every key present",
+		Long: "Prints the default configuration as JSON, with every key set to its default. " +
+			"With --with-comments, each setting is preceded by a // comment describing it; " +
+			"remove the comments before saving that variant as a config file.",
+		Args:    cobra.NoArgs,
+		Example: "  cc-tools config template > config.json\n  cc-tools config template --with-comments",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return handleConfigTemplate(newTerminal(), withComments)
+		},
+	}
+	cmd.Flags().BoolVar(&withComments, "with-comments", false, "describe each setting in a // comment")
+	return cmd
+}
+
+func newConfigDoctorKeysCmd() *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor-keys",
+		Short: "Report config file keys that cc-tools does not recognize",
+		Long: "Compares the keys in the config file with the known configuration keys and lists " +
+			"the rest, which are ignored when the file is loaded: typos such as notify.quite_hours, " +
+			"or keys from another cc-tools version. Exits 1 when any are found. With --fix, they " +
+			"are removed from the file instead.",
+		Args:    cobra.NoArgs,
+		Example: "  cc-tools config doctor-keys\n  cc-tools config doctor-keys --fix",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return handleConfigDoctorKeys(context.Background(), newTerminal(), newConfigManager(), fix)
+		},
+	}
+	cmd.Flags().BoolVar(&fix, "fix", false, "remove unknown keys from the config file")
+	return cmd
+}
+
+func newConfigPathCmd() *cobra.Command {
+	var checkExists, mcpPath, debugPath bool
+
+	cmd := &cobra.Command{
+		Use:   "path",
+		Short: "Print the configuration file path",
+		Args:  cobra.NoArgs,
+		Example: `  cc-tools config path
+  cat "$(cc-tools config path)"
+  cc-tools config path --mcp --exists`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			out := newTerminal()
+			var path string
+			switch {
+			case mcpPath:
+				path = newMCPManager(out).SettingsPath()
+			case debugPath:
+				path = newDebugManager().ConfigPath()
+			default:
+				path = newConfigManager().GetConfigPath()
+			}
+			return handleConfigPath(out, path, checkExists)
+		},
+	}
+	cmd.Flags().BoolVar(&checkExists, "exists", false, "also report whether the file exists")
+	cmd.Flags().BoolVar(&mcpPath, "mcp", false, "print the Claude settings file that defines MCP servers")
+	cmd.Flags().BoolVar(&debugPath, "debug", false, "print the debug configuration file")
+	cmd.MarkFlagsMutuallyExclusive("mcp", "debug")
+	return cmd
+}
+
+func newConfigMigrateLegacyCmd() *cobra.Command {
+	var outputPath string
+
+	cmd := &cobra.Command{
+		Use:   "migrate-legacy <file>",
+		Short: "Convert a legacy map-based config file to the structured format",
+		Long: "Reads a config file written before the structured format, fills in defaults for missing " +
+			"settings, and writes the result to --output, or stdout when it is omitted. " +
+			"The live configuration is never read or modified.",
+		Args: cobra.ExactArgs(1),
+		Example: `  cc-tools config migrate-legacy old-config.json
+  cc-tools config migrate-legacy old-config.json --output config.json`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return handleConfigMigrateLegacy(
+				newTerminal(), args[0], outputPath, newConfigManager().GetConfigPath(),
+			)
+		},
+	}
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "file to write the converted config to")
+	return cmd
+}
+
 func handleConfigGet(ctx context.Context, out *output.Terminal, manager *config.Manager, key string) error {
 	if err := manager.EnsureConfig(ctx); err != nil {
 		return fmt.Errorf("ensure config: %w", err)
@@ -97,26 +255,160 @@ func handleConfigGet(ctx context.Context, out *output.Terminal, manager *config.
 		for _, k := range keys {
 			_ = out.Info("  %s", k)
 		}
-		return errors.New("key not found")
+		return fmt.Errorf("%w: %s", config.ErrUnknownKey, key)
 	}
 
 	_ = out.Raw(fmt.Sprintf("%v\n", value))
 	return nil
 }
 
-func handleConfigSet(ctx context.Context, out *output.Terminal, manager *config.Manager, key, value string) error {
+func handleConfigSet(
+	ctx context.Context,
+	out *output.Terminal,
+	manager *config.Manager,
+	prompt *confirmPrompt,
+	key, value, valueType string,
+) error {
 	if err := manager.EnsureConfig(ctx); err != nil {
 		return fmt.Errorf("ensure config: %w", err)
 	}
 
+	if err := prompt.confirm(ctx, out, manager, []config.KeyValue{{Key: key, Value: value}}); err != nil {
+		return err
+	}
+
+	if valueType != "" {
+		vt, err := config.ParseValueType(valueType)
+		if err != nil {
+			return err
+		}
+		if setErr := manager.SetTyped(ctx, key, value, vt); setErr != nil {
+			return fmt.Errorf("set config value: %w", setErr)
+		}
+		_ = out.Success("✓ Set %s = %s", key, value)
+		warnConfigValue(out, key, value)
+		return nil
+	}
+
 	if err := manager.Set(ctx, key, value); err != nil {
 		return fmt.Errorf("set config value: %w", err)
 	}
 
 	_ = out.Success("✓ Set %s = %s", key, value)
+	warnConfigValue(out, key, value)
 	return nil
 }
 
+func handleConfigSetMany(
+	ctx context.Context, out *output.Terminal, manager *config.Manager, prompt *confirmPrompt, args []string,
+) error {
+	pairs, err := parseKeyValueArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if ensureErr := manager.EnsureConfig(ctx); ensureErr != nil {
+		return fmt.Errorf("ensure config: %w", ensureErr)
+	}
+
+	if confirmErr := prompt.confirm(ctx, out, manager, pairs); confirmErr != nil {
+		return confirmErr
+	}
+
+	if setErr := manager.SetMany(ctx, pairs); setErr != nil {
+		return fmt.Errorf("set config values: %w", setErr)
+	}
+
+	for _, pair := range pairs {
+		_ = out.Success("✓ Set %s = %s", pair.Key, pair.Value)
+		warnConfigValue(out, pair.Key, pair.Value)
+	}
+	return nil
+}
+
+// parseKeyValueArgs splits key=value arguments. The value may itself
+// contain "=".
+func parseKeyValueArgs(args []string) ([]config.KeyValue, error) {
+	pairs := make([]config.KeyValue, 0, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid argument %q: want key=value", arg)
+		}
+		pairs = append(pairs, config.KeyValue{Key: key, Value: value})
+	}
+	return pairs, nil
+}
+
+// confirmPrompt asks before security-relevant keys are changed. A nil
+// *confirmPrompt, as built for --yes, approves every change.
+type confirmPrompt struct {
+	in          io.Reader
+	interactive bool
+}
+
+// newConfirmPrompt returns the prompt for cmd, or nil when yes is set.
+func newConfirmPrompt(cmd *cobra.Command, yes bool) *confirmPrompt {
+	if yes {
+		return nil
+	}
+	in := cmd.InOrStdin()
+	return &confirmPrompt{in: in, interactive: isTerminal(in)}
+}
+
+// confirm shows the old and new value of each sensitive key in pairs on
+// stderr and asks once whether to apply them. It fails without asking when
+// stdin is not a terminal, and when the answer is not yes.
+func (p *confirmPrompt) confirm(
+	ctx context.Context, out *output.Terminal, manager *config.Manager, pairs []config.KeyValue,
+) error {
+	if p == nil {
+		return nil
+	}
+
+	var keys []string
+	for _, pair := range pairs {
+		if config.IsSensitive(pair.Key) {
+			keys = append(keys, pair.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if !p.interactive {
+		return fmt.Errorf("%s is security-relevant; pass --yes to change it without a terminal",
+			strings.Join(keys, ", "))
+	}
+
+	for _, pair := range pairs {
+		if !config.IsSensitive(pair.Key) {
+			continue
+		}
+		old, _, _ := manager.GetValue(ctx, pair.Key)
+		_ = out.RawError(fmt.Sprintf("%s: %q → %q\n", pair.Key, old, pair.Value))
+	}
+	_ = out.RawError("Apply this security-relevant change? [y/N] ")
+
+	answer, _ := bufio.NewReader(p.in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errors.New("change not confirmed; nothing was saved")
+	}
+}
+
+// warnConfigValue prints a warning for values that were saved but will not
+// work on this machine.
+func warnConfigValue(out *output.Terminal, key, value string) {
+	if key != audioPlayerKey {
+		return
+	}
+	if err := notify.CheckPlayer(value, exec.LookPath); err != nil {
+		_ = out.Warning("%v; audio notifications will fail until it is installed", err)
+	}
+}
+
 func handleConfigList(ctx context.Context, out *output.Terminal, manager *config.Manager) error {
 	if err := manager.EnsureConfig(ctx); err != nil {
 		return fmt.Errorf("ensure config: %w", err)
@@ -127,21 +419,34 @@ func handleConfigList(ctx context.Context, out *output.Terminal, manager *config
 		return fmt.Errorf("get all config: %w", err)
 	}
 
-	keys := make([]string, 0, len(settings))
-	for key := range settings {
-		keys = append(keys, key)
+	keys, err := manager.GetAllKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("get config keys: %w", err)
 	}
-	sort.Strings(keys)
-
-	table := output.NewTable(
-		[]string{"Setting", "Value", "Status"},
-		[]int{30, 25, 10},
-	)
 
 	defaultStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 	customStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
 
+	_ = out.Info("Configuration Settings")
+
+	// Keys arrive sorted, so each section's keys are contiguous and get one
+	// table under a section header.
+	var table *output.TableRenderer
+	section := ""
 	for _, key := range keys {
+		keySection, _, _ := strings.Cut(key, ".")
+		if table == nil || keySection != section {
+			if table != nil {
+				_ = out.Write(table.Render())
+			}
+			section = keySection
+			_ = out.Info("\n[%s]", section)
+			table = output.NewTable(
+				[]string{"Setting", "Value", "Status"},
+				[]int{30, 25, 10},
+			)
+		}
+
 		info := settings[key]
 		var status string
 		if info.IsDefault {
@@ -157,9 +462,9 @@ func handleConfigList(ctx context.Context, out *output.Terminal, manager *config
 
 		table.AddRow([]string{key, value, status})
 	}
-
-	_ = out.Info("Configuration Settings")
-	_ = out.Write(table.Render())
+	if table != nil {
+		_ = out.Write(table.Render())
+	}
 
 	configPath := manager.GetConfigPath()
 	_ = out.Info("\nConfig file: %s", configPath)
@@ -182,3 +487,139 @@ func handleConfigReset(ctx context.Context, out *output.Terminal, manager *confi
 
 	return nil
 }
+
+// handleConfigDoctorKeys lists the unknown keys in the config file, or
+// removes them when fix is set. Unknown keys left in place exit 1.
+func handleConfigDoctorKeys(ctx context.Context, out *output.Terminal, manager *config.Manager, fix bool) error {
+	path := manager.GetConfigPath()
+
+	if fix {
+		pruned, err := manager.PruneUnknownKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("prune unknown keys: %w", err)
+		}
+		if len(pruned) == 0 {
+			_ = out.Success("✓ No unknown keys in %s", path)
+			return nil
+		}
+		_ = out.Success("✓ Removed %d unknown keys from %s:", len(pruned), path)
+		for _, key := range pruned {
+			_ = out.Write("  " + key)
+		}
+		return nil
+	}
+
+	unknown, err := manager.UnknownKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("check config keys: %w", err)
+	}
+	if len(unknown) == 0 {
+		_ = out.Success("✓ No unknown keys in %s", path)
+		return nil
+	}
+
+	_ = out.Warning("Unknown keys in %s (ignored when loading):", path)
+	for _, key := range unknown {
+		_ = out.Write("  " + key)
+	}
+	_ = out.Info("Run 'cc-tools config keys' for valid keys, or 'cc-tools config doctor-keys --fix' to remove these.")
+
+	return &exitError{code: 1}
+}
+
+func handleConfigKeys(out *output.Terminal, jsonOutput bool) error {
+	keys := config.Keys()
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(keys, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal keys: %w", err)
+		}
+		_ = out.Raw(string(data) + "\n")
+		return nil
+	}
+
+	table := output.NewTable(
+		[]string{"Key", "Type", "Default", "Description"},
+		[]int{30, 8, 25, 50},
+	)
+	for _, k := range keys {
+		def := k.Default
+		if def == "" {
+			def = "(empty)"
+		}
+		table.AddRow([]string{k.Key, string(k.Type), def, k.Description})
+	}
+
+	_ = out.Write(table.Render())
+	return nil
+}
+
+func handleConfigTemplate(out *output.Terminal, withComments bool) error {
+	data, err := config.Template(withComments)
+	if err != nil {
+		return fmt.Errorf("render config template: %w", err)
+	}
+	_ = out.Raw(string(data))
+	return nil
+}
+
+func handleConfigPath(out *output.Terminal, path string, checkExists bool) error {
+	if !checkExists {
+		_ = out.Raw(path + "\n")
+		return nil
+	}
+
+	status := "exists"
+	if _, err := os.Stat(path); err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("stat %s: %w", path, err)
+		}
+		status = "missing"
+	}
+	_ = out.Raw(fmt.Sprintf("%s\t%s\n", path, status))
+	return nil
+}
+
+// handleConfigMigrateLegacy converts the legacy config at src and writes it
+// to dst, or to out when dst is empty. livePath is the active config file,
+// which is refused as a destination.
+func handleConfigMigrateLegacy(out *output.Terminal, src, dst, livePath string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("read legacy config: %w", err)
+	}
+
+	values, err := config.ConvertLegacy(data)
+	if err != nil {
+		return fmt.Errorf("convert %s: %w", src, err)
+	}
+
+	encoded, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+
+	if dst == "" {
+		_ = out.Raw(string(encoded) + "\n")
+		return nil
+	}
+
+	if sameFile(dst, livePath) {
+		return fmt.Errorf("refusing to overwrite the live config %s", livePath)
+	}
+
+	if writeErr := os.WriteFile(dst, encoded, 0o600); writeErr != nil {
+		return fmt.Errorf("write %s: %w", dst, writeErr)
+	}
+
+	_ = out.Success("✓ Wrote structured config to %s", dst)
+	return nil
+}
+
+// sameFile reports whether a and b name the same path once made absolute.
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
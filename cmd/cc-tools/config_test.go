@@ -5,7 +5,10 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,7 +62,7 @@ func TestHandleConfigGet(t *testing.T) {
 			err := handleConfigGet(ctx, out, mgr, tt.key)
 
 			if tt.wantErr {
-				require.Error(t, err)
+				require.ErrorIs(t, err, config.ErrUnknownKey)
 				return
 			}
 
@@ -102,7 +105,7 @@ func TestHandleConfigSet(t *testing.T) {
 			out, stdout := newTestTerminal(t)
 			ctx := context.Background()
 
-			err := handleConfigSet(ctx, out, mgr, tt.key, tt.value)
+			err := handleConfigSet(ctx, out, mgr, nil, tt.key, tt.value, "")
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -121,6 +124,214 @@ func TestHandleConfigSet(t *testing.T) {
 	}
 }
 
+func TestHandleConfigSetMany(t *testing.T) {
+	t.Run("applies every pair", func(t *testing.T) {
+		mgr := newTestConfigManager(t)
+		out, stdout := newTestTerminal(t)
+		ctx := context.Background()
+
+		args := []string{"validate.timeout=90", "notifications.ntfy_topic=a=b", "drift.enabled=false"}
+		require.NoError(t, handleConfigSetMany(ctx, out, mgr, nil, args))
+		assert.Contains(t, stdout.String(), "Set validate.timeout = 90")
+
+		cfg, err := config.NewManagerWithPath(mgr.GetConfigPath()).GetConfig(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 90, cfg.Validate.Timeout)
+		assert.Equal(t, "a=b", cfg.Notifications.NtfyTopic)
+		assert.False(t, cfg.Drift.Enabled)
+	})
+
+	t.Run("invalid pair writes nothing", func(t *testing.T) {
+		mgr := newTestConfigManager(t)
+		out, _ := newTestTerminal(t)
+		ctx := context.Background()
+		require.NoError(t, mgr.EnsureConfig(ctx))
+		before, err := os.ReadFile(mgr.GetConfigPath())
+		require.NoError(t, err)
+
+		err = handleConfigSetMany(ctx, out, mgr, nil, []string{"validate.timeout=90", "validate.cooldown=soon"})
+		require.ErrorIs(t, err, config.ErrInvalidValue)
+		assert.Contains(t, err.Error(), "validate.cooldown")
+
+		after, err := os.ReadFile(mgr.GetConfigPath())
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+
+	t.Run("malformed argument", func(t *testing.T) {
+		mgr := newTestConfigManager(t)
+		out, _ := newTestTerminal(t)
+
+		err := handleConfigSetMany(context.Background(), out, mgr, nil, []string{"validate.timeout"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "want key=value")
+		assert.NoFileExists(t, mgr.GetConfigPath())
+	})
+}
+
+func TestHandleConfigSet_AudioPlayerWarning(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		wantWarn bool
+	}{
+		{name: "missing player warns", value: "cc-tools-no-such-player --quiet", wantWarn: true},
+		{name: "auto does not warn", value: "auto", wantWarn: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := newTestConfigManager(t)
+			out, stdout := newTestTerminal(t)
+			ctx := context.Background()
+
+			require.NoError(t, handleConfigSet(ctx, out, mgr, nil, audioPlayerKey, tt.value, ""))
+
+			if tt.wantWarn {
+				assert.Contains(t, stdout.String(), `"cc-tools-no-such-player" not found on PATH`)
+			} else {
+				assert.NotContains(t, stdout.String(), "not found on PATH")
+			}
+
+			value, _, err := mgr.GetValue(ctx, audioPlayerKey)
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+}
+
+func TestHandleConfigSet_Type(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		value     string
+		valueType string
+		wantErr   string
+	}{
+		{
+			name:      "matching type",
+			key:       "validate.timeout",
+			value:     "120",
+			valueType: "int",
+			wantErr:   "",
+		},
+		{
+			name:      "mismatched type",
+			key:       "validate.timeout",
+			value:     "120",
+			valueType: "string",
+			wantErr:   "has type int",
+		},
+		{
+			name:      "list on non-list key",
+			key:       "pre_commit_reminder.command",
+			value:     "a,b",
+			valueType: "list",
+			wantErr:   "not list",
+		},
+		{
+			name:      "unknown type",
+			key:       "validate.timeout",
+			value:     "120",
+			valueType: "map",
+			wantErr:   "unknown value type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := newTestConfigManager(t)
+			out, _ := newTestTerminal(t)
+
+			err := handleConfigSet(context.Background(), out, mgr, nil, tt.key, tt.value, tt.valueType)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHandleConfigSet_ConfirmSensitive(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		answer      string
+		interactive bool
+		wantErr     string
+		wantSaved   bool
+	}{
+		{name: "confirmed", key: "validate.clean_env", answer: "y\n", interactive: true, wantSaved: true},
+		{name: "confirmed with yes", key: "validate.clean_env", answer: "YES\n", interactive: true, wantSaved: true},
+		{name: "declined", key: "validate.clean_env", answer: "n\n", interactive: true, wantErr: "not confirmed"},
+		{name: "no answer", key: "validate.clean_env", answer: "", interactive: true, wantErr: "not confirmed"},
+		{name: "no terminal", key: "validate.clean_env", answer: "y\n", interactive: false, wantErr: "--yes"},
+		{name: "not sensitive", key: "drift.enabled", answer: "", interactive: false, wantSaved: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := newTestConfigManager(t)
+			var stdout, stderr bytes.Buffer
+			out := output.NewTerminal(&stdout, &stderr)
+			ctx := context.Background()
+			prompt := &confirmPrompt{in: strings.NewReader(tt.answer), interactive: tt.interactive}
+
+			value := "true"
+			if tt.key == "drift.enabled" {
+				value = "false"
+			}
+			err := handleConfigSet(ctx, out, mgr, prompt, tt.key, value, "")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			got, _, getErr := config.NewManagerWithPath(mgr.GetConfigPath()).GetValue(ctx, tt.key)
+			require.NoError(t, getErr)
+			if tt.wantSaved {
+				assert.Equal(t, value, got)
+			} else {
+				assert.NotEqual(t, value, got)
+			}
+
+			if tt.interactive {
+				assert.Contains(t, stderr.String(), `validate.clean_env: "false" → "true"`)
+				assert.Contains(t, stderr.String(), "[y/N]")
+			} else {
+				assert.Empty(t, stderr.String(), "nothing is asked without a terminal")
+			}
+		})
+	}
+}
+
+func TestConfigSetCmd_Yes(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv(configPathEnv, configPath)
+
+	run := func(args ...string) error {
+		cmd := newConfigSetCmd()
+		cmd.SetIn(strings.NewReader(""))
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	err := run("validate.clean_env", "true")
+	require.Error(t, err, "stdin is not a terminal, so the change needs --yes")
+	assert.Contains(t, err.Error(), "--yes")
+
+	require.NoError(t, run("validate.clean_env", "true", "--yes"))
+	got, _, err := config.NewManagerWithPath(configPath).GetValue(context.Background(), "validate.clean_env")
+	require.NoError(t, err)
+	assert.Equal(t, "true", got)
+}
+
 func TestHandleConfigList(t *testing.T) {
 	mgr := newTestConfigManager(t)
 	out, stdout := newTestTerminal(t)
@@ -133,6 +344,23 @@ func TestHandleConfigList(t *testing.T) {
 	assert.Contains(t, result, "validate.timeout")
 	assert.Contains(t, result, "validate.cooldown")
 	assert.Contains(t, result, "notifications.ntfy_topic")
+	assert.Contains(t, result, "[validate]")
+	assert.Contains(t, result, "[notifications]")
+	assert.Less(t, strings.Index(result, "[notifications]"), strings.Index(result, "[validate]"),
+		"sections should be listed in key order")
+}
+
+func TestHandleConfigList_Deterministic(t *testing.T) {
+	mgr := newTestConfigManager(t)
+	ctx := context.Background()
+
+	render := func() string {
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleConfigList(ctx, out, mgr))
+		return stdout.String()
+	}
+
+	assert.Equal(t, render(), render())
 }
 
 func TestHandleConfigReset(t *testing.T) {
@@ -166,7 +394,7 @@ func TestHandleConfigReset(t *testing.T) {
 			// Set a non-default value first.
 			if tt.key == "validate.timeout" || tt.key == "" {
 				setOut, _ := newTestTerminal(t)
-				setErr := handleConfigSet(ctx, setOut, mgr, "validate.timeout", "999")
+				setErr := handleConfigSet(ctx, setOut, mgr, nil, "validate.timeout", "999", "")
 				require.NoError(t, setErr)
 			}
 
@@ -192,6 +420,149 @@ func TestHandleConfigReset(t *testing.T) {
 	}
 }
 
+func TestHandleConfigDoctorKeys(t *testing.T) {
+	ctx := context.Background()
+	stale := `{"validate": {"timeout": 90}, "notify": {"quite_hours": {"enabled": true}}}`
+
+	t.Run("reports bogus key", func(t *testing.T) {
+		mgr := newTestConfigManager(t)
+		require.NoError(t, os.WriteFile(mgr.GetConfigPath(), []byte(stale), 0o600))
+
+		out, stdout := newTestTerminal(t)
+		err := handleConfigDoctorKeys(ctx, out, mgr, false)
+
+		var exitErr *exitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 1, exitErr.code)
+		assert.Contains(t, stdout.String(), "notify.quite_hours")
+		assert.NotContains(t, stdout.String(), "validate.timeout")
+	})
+
+	t.Run("fix removes bogus key", func(t *testing.T) {
+		mgr := newTestConfigManager(t)
+		require.NoError(t, os.WriteFile(mgr.GetConfigPath(), []byte(stale), 0o600))
+
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleConfigDoctorKeys(ctx, out, mgr, true))
+		assert.Contains(t, stdout.String(), "Removed 1 unknown keys")
+		assert.Contains(t, stdout.String(), "notify.quite_hours")
+
+		checkOut, checkStdout := newTestTerminal(t)
+		require.NoError(t, handleConfigDoctorKeys(ctx, checkOut, mgr, false))
+		assert.Contains(t, checkStdout.String(), "No unknown keys")
+	})
+}
+
+func TestHandleConfigKeys(t *testing.T) {
+	t.Run("table", func(t *testing.T) {
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleConfigKeys(out, false))
+		assert.Contains(t, stdout.String(), "validate.timeout")
+		assert.Contains(t, stdout.String(), "Validation timeout")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleConfigKeys(out, true))
+
+		var keys []config.KeyInfo
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &keys))
+		assert.Len(t, keys, len(config.Keys()))
+		assert.Contains(t, keys, config.KeyInfo{
+			Key:         "validate.timeout",
+			Type:        config.TypeInt,
+			Default:     "60",
+			Description: "Validation timeout in seconds",
+		})
+	})
+}
+
+func TestHandleConfigTemplate(t *testing.T) {
+	out, stdout := newTestTerminal(t)
+	require.NoError(t, handleConfigTemplate(out, false))
+
+	var values config.Values
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &values))
+	assert.Equal(t, config.GetDefaultConfig(), &values)
+}
+
+func TestHandleConfigPath(t *testing.T) {
+	existing := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(existing, []byte("{}"), 0o600))
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	tests := []struct {
+		name        string
+		path        string
+		checkExists bool
+		want        string
+	}{
+		{name: "path only", path: missing, checkExists: false, want: missing + "\n"},
+		{name: "existing file", path: existing, checkExists: true, want: existing + "\texists\n"},
+		{name: "missing file", path: missing, checkExists: true, want: missing + "\tmissing\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stdout := newTestTerminal(t)
+			require.NoError(t, handleConfigPath(out, tt.path, tt.checkExists))
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
+
+func TestConfigPathCmd(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	stdoutText := captureStdout(t, func() {
+		cmd := newConfigPathCmd()
+		require.NoError(t, cmd.RunE(cmd, nil))
+	})
+	assert.Equal(t, newConfigManager().GetConfigPath()+"\n", stdoutText)
+}
+
+func TestConfigFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		useFlag bool
+	}{
+		{name: "--config flag", useFlag: true},
+		{name: "CC_TOOLS_CONFIG env", useFlag: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configHome := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", configHome)
+			t.Setenv(configPathEnv, "")
+			t.Cleanup(func() { configPath, quiet = "", false })
+			customPath := filepath.Join(t.TempDir(), "custom.json")
+
+			args := []string{"config", "set", "validate.timeout", "90", "--quiet"}
+			if tt.useFlag {
+				args = append([]string{"--config", customPath}, args...)
+			} else {
+				t.Setenv(configPathEnv, customPath)
+			}
+
+			root := newRootCmd()
+			root.SetArgs(args)
+			require.NoError(t, root.Execute())
+
+			data, err := os.ReadFile(customPath)
+			require.NoError(t, err)
+			var saved map[string]any
+			require.NoError(t, json.Unmarshal(data, &saved))
+			validate, ok := saved["validate"].(map[string]any)
+			require.True(t, ok, "validate section should be saved")
+			assert.InDelta(t, 90, validate["timeout"], 0)
+
+			assert.NoFileExists(t, filepath.Join(configHome, "cc-tools", "config.json"))
+		})
+	}
+}
+
 // Command-execution tests exercise the Cobra RunE wrappers to cover
 // the newTerminal → newConfigManager → handler delegation path.
 
@@ -201,6 +572,18 @@ func TestConfigGetCmd(t *testing.T) {
 	require.NoError(t, cmd.RunE(cmd, []string{"validate.timeout"}))
 }
 
+func TestConfigGetCmd_Quiet(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(configPathEnv, "")
+
+	out := captureStdout(t, func() {
+		root := newRootCmd()
+		root.SetArgs([]string{"config", "get", "validate.timeout", "-q"})
+		require.NoError(t, root.Execute())
+	})
+	assert.Equal(t, "60\n", out, "--quiet must not hide the value that was asked for")
+}
+
 func TestConfigSetCmd(t *testing.T) {
 	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
 	cmd := newConfigSetCmd()
@@ -224,3 +607,48 @@ func TestConfigResetCmd_WithKey(t *testing.T) {
 	cmd := newConfigResetCmd()
 	require.NoError(t, cmd.RunE(cmd, []string{"validate.timeout"}))
 }
+
+func TestHandleConfigMigrateLegacy(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "legacy.json")
+	require.NoError(t, os.WriteFile(src, []byte(`{"validate":{"timeout":90},"compact":{"threshold":75}}`), 0o600))
+	livePath := filepath.Join(dir, "live.json")
+
+	t.Run("writes the structured config", func(t *testing.T) {
+		dst := filepath.Join(dir, "config.json")
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleConfigMigrateLegacy(out, src, dst, livePath))
+		assert.Contains(t, stdout.String(), dst)
+
+		data, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		var cfg config.Values
+		require.NoError(t, json.Unmarshal(data, &cfg))
+		assert.Equal(t, 90, cfg.Validate.Timeout)
+		assert.Equal(t, 75, cfg.Compact.Threshold)
+		assert.Equal(t, config.GetDefaultConfig().Validate.Cooldown, cfg.Validate.Cooldown)
+		assert.NoFileExists(t, livePath)
+	})
+
+	t.Run("prints to stdout without an output path", func(t *testing.T) {
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleConfigMigrateLegacy(out, src, "", livePath))
+
+		var cfg config.Values
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &cfg))
+		assert.Equal(t, 90, cfg.Validate.Timeout)
+	})
+
+	t.Run("refuses the live config", func(t *testing.T) {
+		out, _ := newTestTerminal(t)
+		err := handleConfigMigrateLegacy(out, src, livePath, livePath)
+		require.Error(t, err)
+		assert.NoFileExists(t, livePath)
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		out, _ := newTestTerminal(t)
+		err := handleConfigMigrateLegacy(out, filepath.Join(dir, "missing.json"), "", livePath)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -3,16 +3,23 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"sort"
 
 	"github.com/spf13/cobra"
 
 	"github.com/riddopic/cc-tools/internal/debug"
+	"github.com/riddopic/cc-tools/internal/mcp"
 	"github.com/riddopic/cc-tools/internal/output"
 	"github.com/riddopic/cc-tools/internal/shared"
 )
 
+// defaultDebugTailLines is how many lines debug tail prints before following.
+const defaultDebugTailLines = 10
+
 func newDebugCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "debug",
@@ -24,30 +31,38 @@ func newDebugCmd() *cobra.Command {
 		newDebugStatusCmd(),
 		newDebugListCmd(),
 		newDebugFilenameCmd(),
+		newDebugTailCmd(),
 	)
 	return cmd
 }
 
 func newDebugEnableCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "enable",
-		Short:   "Enable debug logging for the current directory",
-		Example: "  cc-tools debug enable",
-		RunE: func(_ *cobra.Command, _ []string) error {
+		Use:   "enable [dir|glob]",
+		Short: "Enable debug logging for a directory or glob",
+		Long: "Enable debug logging for dir, or the current directory, and everything under it. " +
+			"A glob such as '~/work/*' enables every matching directory; quote it so the shell " +
+			"stores the pattern rather than expanding it.",
+		Args: cobra.MaximumNArgs(1),
+		Example: `  cc-tools debug enable
+  cc-tools debug enable '~/work/*'`,
+		RunE: func(_ *cobra.Command, args []string) error {
 			out := newTerminal()
-			return enableDebug(context.Background(), out, newDebugManager())
+			return enableDebug(context.Background(), out, newDebugManager(), firstArg(args))
 		},
 	}
 }
 
 func newDebugDisableCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "disable",
-		Short:   "Disable debug logging for the current directory",
-		Example: "  cc-tools debug disable",
-		RunE: func(_ *cobra.Command, _ []string) error {
+		Use:   "disable [dir|glob]",
+		Short: "Disable debug logging for a directory or glob",
+		Args:  cobra.MaximumNArgs(1),
+		Example: `  cc-tools debug disable
+  cc-tools debug disable '~/work/*'`,
+		RunE: func(_ *cobra.Command, args []string) error {
 			out := newTerminal()
-			return disableDebug(context.Background(), out, newDebugManager())
+			return disableDebug(context.Background(), out, newDebugManager(), firstArg(args))
 		},
 	}
 }
@@ -86,10 +101,48 @@ func newDebugFilenameCmd() *cobra.Command {
 	}
 }
 
-func enableDebug(ctx context.Context, out *output.Terminal, manager *debug.Manager) error {
-	dir, err := os.Getwd()
+func newDebugTailCmd() *cobra.Command {
+	var lines int
+
+	cmd := &cobra.Command{
+		Use:   "tail [dir]",
+		Short: "Follow the debug log for a directory",
+		Long: "Print the end of the debug log for dir, or the current directory, and keep " +
+			"printing lines as they are written until interrupted. The log is created if it " +
+			"does not exist yet.",
+		Args: cobra.MaximumNArgs(1),
+		Example: `  cc-tools debug tail
+  cc-tools debug tail ~/src/project -n 50`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := debugTailPath(firstArg(args))
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			return tailDebugLog(ctx, cmd.OutOrStdout(), path, lines)
+		},
+	}
+	cmd.Flags().IntVarP(&lines, "lines", "n", defaultDebugTailLines, "number of lines to show before following")
+	return cmd
+}
+
+// enableDebug enables debug logging for target, which is a directory, a
+// glob pattern, or empty for the current directory.
+func enableDebug(ctx context.Context, out *output.Terminal, manager *debug.Manager, target string) error {
+	if debug.IsGlob(target) {
+		pattern, err := manager.EnableGlob(ctx, target)
+		if err != nil {
+			return fmt.Errorf("enable debug: %w", err)
+		}
+		_ = out.Success("✓ Debug logging enabled for directories matching %s", pattern)
+		_ = out.Info("  Each directory logs to its own file; see cc-tools debug filename.")
+		return nil
+	}
+
+	dir, err := debugTargetDir(target)
 	if err != nil {
-		return fmt.Errorf("get current directory: %w", err)
+		return err
 	}
 
 	logFile, err := manager.Enable(ctx, dir)
@@ -105,10 +158,20 @@ func enableDebug(ctx context.Context, out *output.Terminal, manager *debug.Manag
 	return nil
 }
 
-func disableDebug(ctx context.Context, out *output.Terminal, manager *debug.Manager) error {
-	dir, err := os.Getwd()
+// disableDebug disables debug logging for target, which is a directory, a
+// glob pattern passed to enable, or empty for the current directory.
+func disableDebug(ctx context.Context, out *output.Terminal, manager *debug.Manager, target string) error {
+	if debug.IsGlob(target) {
+		if err := manager.Disable(ctx, target); err != nil {
+			return fmt.Errorf("disable debug: %w", err)
+		}
+		_ = out.Success("✓ Debug logging disabled for directories matching %s", target)
+		return nil
+	}
+
+	dir, err := debugTargetDir(target)
 	if err != nil {
-		return fmt.Errorf("get current directory: %w", err)
+		return err
 	}
 
 	if disableErr := manager.Disable(ctx, dir); disableErr != nil {
@@ -121,7 +184,7 @@ func disableDebug(ctx context.Context, out *output.Terminal, manager *debug.Mana
 }
 
 func showDebugStatus(ctx context.Context, out *output.Terminal, manager *debug.Manager) error {
-	dir, err := os.Getwd()
+	dir, err := workingDir()
 	if err != nil {
 		return fmt.Errorf("get current directory: %w", err)
 	}
@@ -150,17 +213,53 @@ func showDebugStatus(ctx context.Context, out *output.Terminal, manager *debug.M
 	return nil
 }
 
+// debugTargetDir returns target, or the current directory when it is empty.
+func debugTargetDir(target string) (string, error) {
+	if target != "" {
+		return target, nil
+	}
+	dir, err := workingDir()
+	if err != nil {
+		return "", fmt.Errorf("get current directory: %w", err)
+	}
+	return dir, nil
+}
+
+// firstArg returns args[0], or "" when there are no arguments.
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
 func listDebugDirs(ctx context.Context, out *output.Terminal, manager *debug.Manager) error {
 	dirs, err := manager.GetEnabledDirs(ctx)
 	if err != nil {
 		return fmt.Errorf("list debug directories: %w", err)
 	}
 
-	if len(dirs) == 0 {
+	globs, err := manager.GetEnabledGlobs(ctx)
+	if err != nil {
+		return fmt.Errorf("list debug patterns: %w", err)
+	}
+
+	if len(dirs) == 0 && len(globs) == 0 {
 		_ = out.Info("No directories have debug logging enabled")
 		return nil
 	}
 
+	if len(globs) > 0 {
+		_ = out.Info("Patterns with debug logging enabled:")
+		for _, pattern := range globs {
+			_ = out.Write("  " + pattern)
+		}
+		if len(dirs) == 0 {
+			return nil
+		}
+		_ = out.Write("")
+	}
+
 	sort.Strings(dirs)
 
 	table := output.NewTable(
@@ -185,7 +284,7 @@ func listDebugDirs(ctx context.Context, out *output.Terminal, manager *debug.Man
 }
 
 func showDebugFilename(out *output.Terminal) error {
-	wd, err := os.Getwd()
+	wd, err := workingDir()
 	if err != nil {
 		return fmt.Errorf("get current directory: %w", err)
 	}
@@ -193,3 +292,31 @@ func showDebugFilename(out *output.Terminal) error {
 	_ = out.Raw("\n")
 	return nil
 }
+
+// debugTailPath returns the debug log path for dir. An empty dir means the
+// current directory, resolved exactly as the log writer resolves it.
+func debugTailPath(dir string) (string, error) {
+	if dir == "" {
+		return getDebugLogPath(), nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", dir, err)
+	}
+	return shared.GetDebugLogPathForDir(abs), nil
+}
+
+// tailDebugLog writes the last lines of the log at path to w and follows it
+// until ctx is done. A missing log is created empty so there is something
+// to follow before the first logged invocation.
+func tailDebugLog(ctx context.Context, w io.Writer, path string, lines int) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("create debug log: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("create debug log: %w", err)
+	}
+
+	return mcp.TailLog(ctx, w, path, lines, true)
+}
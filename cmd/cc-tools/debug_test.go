@@ -5,6 +5,8 @@ package main
 import (
 	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -36,7 +38,7 @@ func TestEnableDebug(t *testing.T) {
 	out, stdout := newDebugTestTerminal(t)
 	ctx := context.Background()
 
-	err := enableDebug(ctx, out, mgr)
+	err := enableDebug(ctx, out, mgr, "")
 	require.NoError(t, err)
 
 	outputStr := stdout.String()
@@ -53,10 +55,10 @@ func TestDisableDebug(t *testing.T) {
 
 	// Enable first so there is something to disable.
 	enableOut, _ := newDebugTestTerminal(t)
-	require.NoError(t, enableDebug(ctx, enableOut, mgr))
+	require.NoError(t, enableDebug(ctx, enableOut, mgr, ""))
 
 	out, stdout := newDebugTestTerminal(t)
-	err := disableDebug(ctx, out, mgr)
+	err := disableDebug(ctx, out, mgr, "")
 	require.NoError(t, err)
 	assert.Contains(t, stdout.String(), "Debug logging disabled")
 }
@@ -84,7 +86,7 @@ func TestShowDebugStatus(t *testing.T) {
 
 		// Enable debug first.
 		enableOut, _ := newDebugTestTerminal(t)
-		require.NoError(t, enableDebug(ctx, enableOut, mgr))
+		require.NoError(t, enableDebug(ctx, enableOut, mgr, ""))
 
 		out, stdout := newDebugTestTerminal(t)
 		err := showDebugStatus(ctx, out, mgr)
@@ -116,7 +118,7 @@ func TestListDebugDirs(t *testing.T) {
 
 		// Enable debug for a directory.
 		enableOut, _ := newDebugTestTerminal(t)
-		require.NoError(t, enableDebug(ctx, enableOut, mgr))
+		require.NoError(t, enableDebug(ctx, enableOut, mgr, ""))
 
 		out, stdout := newDebugTestTerminal(t)
 		err := listDebugDirs(ctx, out, mgr)
@@ -128,6 +130,34 @@ func TestListDebugDirs(t *testing.T) {
 	})
 }
 
+func TestEnableDebug_Glob(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	mgr := newIsolatedDebugManager(t)
+	ctx := context.Background()
+
+	out, stdout := newDebugTestTerminal(t)
+	require.NoError(t, enableDebug(ctx, out, mgr, "~/work/*"))
+	pattern := filepath.Join(home, "work", "*")
+	assert.Contains(t, stdout.String(), "directories matching "+pattern)
+
+	enabled, err := mgr.IsEnabled(ctx, filepath.Join(home, "work", "repo"))
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	listOut, listStdout := newDebugTestTerminal(t)
+	require.NoError(t, listDebugDirs(ctx, listOut, mgr))
+	assert.Contains(t, listStdout.String(), "Patterns with debug logging enabled")
+	assert.Contains(t, listStdout.String(), pattern)
+
+	disableOut, _ := newDebugTestTerminal(t)
+	require.NoError(t, disableDebug(ctx, disableOut, mgr, "~/work/*"))
+	enabled, err = mgr.IsEnabled(ctx, filepath.Join(home, "work", "repo"))
+	require.NoError(t, err)
+	assert.False(t, enabled)
+}
+
 func TestShowDebugFilename(t *testing.T) {
 	tmpDir := t.TempDir()
 	t.Chdir(tmpDir)
@@ -141,6 +171,55 @@ func TestShowDebugFilename(t *testing.T) {
 	assert.Contains(t, outputStr, "cc-tools")
 }
 
+func TestDebugTailPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	t.Run("defaults to the writer's path for the current directory", func(t *testing.T) {
+		path, err := debugTailPath("")
+		require.NoError(t, err)
+		assert.Equal(t, getDebugLogPath(), path)
+	})
+
+	t.Run("resolves an explicit directory to the same path", func(t *testing.T) {
+		path, err := debugTailPath(tmpDir)
+		require.NoError(t, err)
+		assert.Equal(t, getDebugLogPath(), path)
+	})
+
+	t.Run("resolves a relative directory against the current one", func(t *testing.T) {
+		path, err := debugTailPath(".")
+		require.NoError(t, err)
+		assert.Equal(t, getDebugLogPath(), path)
+	})
+}
+
+func TestTailDebugLog(t *testing.T) {
+	t.Run("creates a missing log and stops when cancelled", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing.debug")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var buf bytes.Buffer
+		require.NoError(t, tailDebugLog(ctx, &buf, path, defaultDebugTailLines))
+
+		assert.FileExists(t, path)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("prints the last lines of an existing log", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "existing.debug")
+		require.NoError(t, os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0o600))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var buf bytes.Buffer
+		require.NoError(t, tailDebugLog(ctx, &buf, path, 2))
+
+		assert.Equal(t, "two\nthree\n", buf.String())
+	})
+}
+
 // Command-execution tests exercise the Cobra RunE wrappers to cover
 // the newTerminal → newDebugManager → handler delegation path.
 
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/hooks"
+	"github.com/riddopic/cc-tools/internal/output"
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+func newDiscoverCmd() *cobra.Command {
+	var explain bool
+	var projectRoot string
+
+	cmd := &cobra.Command{
+		Use:   "discover [dir]",
+		Short: "Show the lint and test commands validate would run",
+		Long: "Prints the lint and test commands that validate discovers for dir (default: the current " +
+			"directory). With --explain, lists every candidate found, in priority order, with the one " +
+			"that wins and why the others lose.",
+		Args:    cobra.MaximumNArgs(1),
+		Example: "  cc-tools discover\n  cc-tools discover --explain internal/api",
+		RunE: func(_ *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			absDir, err := filepath.Abs(dir)
+			if err != nil {
+				return fmt.Errorf("resolve %s: %w", dir, err)
+			}
+			override, err := projectRootOverride(projectRoot)
+			if err != nil {
+				return err
+			}
+			root, err := shared.ResolveProjectRoot(override, absDir, nil)
+			if err != nil {
+				return fmt.Errorf("find project root: %w", err)
+			}
+
+			defaults := config.GetDefaultConfig()
+			cfg := loadConfigForDir(absDir)
+			timeout, _ := resolveValidateConfig(defaults, cfg, defaults.Validate.Timeout, defaults.Validate.Cooldown, 0)
+			discovery := hooks.NewCommandDiscovery(root, timeout, nil)
+			discovery.SetRunFrom(validateOptions(cfg).RunFrom)
+
+			return handleDiscover(context.Background(), newTerminal(), discovery, absDir, explain)
+		},
+	}
+	cmd.Flags().BoolVar(&explain, "explain", false, "list every candidate command in priority order")
+	cmd.Flags().StringVar(&projectRoot, projectRootFlag, "",
+		"use this directory as the project root instead of detecting it (env: "+projectRootEnv+")")
+	return cmd
+}
+
+// handleDiscover prints the lint and test commands discovery finds for dir,
+// or with explain every candidate for each.
+func handleDiscover(
+	ctx context.Context,
+	out *output.Terminal,
+	discovery *hooks.CommandDiscovery,
+	dir string,
+	explain bool,
+) error {
+	var b strings.Builder
+	for _, cmdType := range []hooks.CommandType{hooks.CommandTypeLint, hooks.CommandTypeTest} {
+		candidates, err := discovery.ExplainCommand(ctx, cmdType, dir)
+		if err != nil {
+			return fmt.Errorf("discover %s command: %w", cmdType, err)
+		}
+
+		if !explain {
+			if len(candidates) == 0 {
+				_, _ = fmt.Fprintf(&b, "%s: none found\n", cmdType)
+				continue
+			}
+			_, _ = fmt.Fprintf(&b, "%s: %s\n", cmdType, describeCommand(candidates[0].Command))
+			continue
+		}
+
+		_, _ = fmt.Fprintf(&b, "%s candidates, in priority order:\n", cmdType)
+		if len(candidates) == 0 {
+			b.WriteString("  none found\n")
+		}
+		for _, c := range candidates {
+			marker := " "
+			if c.Selected {
+				marker = "✓"
+			}
+			_, _ = fmt.Fprintf(&b, "  %s %s\n      %s\n", marker, describeCommand(c.Command), c.Reason)
+		}
+	}
+
+	_ = out.Raw(b.String())
+	return nil
+}
+
+// describeCommand formats cmd with where it was found and where it runs.
+func describeCommand(cmd *hooks.DiscoveredCommand) string {
+	return fmt.Sprintf("%s  (%s, in %s)", cmd, cmd.Source, cmd.WorkingDir)
+}
@@ -0,0 +1,43 @@
+//go:build testmode
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+func TestHandleDiscover(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":   "module example.com/demo\n",
+		"Makefile": ".PHONY: lint\nlint:\n\t@true\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	discovery := hooks.NewCommandDiscovery(dir, 10, nil)
+
+	t.Run("summary", func(t *testing.T) {
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleDiscover(context.Background(), out, discovery, dir, false))
+		assert.Contains(t, stdout.String(), "lint: make lint  (Makefile, in "+dir+")\n")
+		assert.Contains(t, stdout.String(), "test: go test ./...  (go.mod, in "+dir+")\n")
+	})
+
+	t.Run("explain lists every candidate", func(t *testing.T) {
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleDiscover(context.Background(), out, discovery, dir, true))
+		assert.Contains(t, stdout.String(), "lint candidates, in priority order:\n"+
+			"  ✓ make lint  (Makefile, in "+dir+")\n      first match in priority order\n")
+		assert.Contains(t, stdout.String(), "    go vet ./...  (go.mod, in "+dir+")\n"+
+			"      Makefile is checked before go.mod\n", "the go vet fallback loses to the Makefile")
+	})
+}
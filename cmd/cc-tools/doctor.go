@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/output"
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// Permissions doctor expects: no access for other users, and no write
+// access for the group.
+const (
+	doctorDirPerm  os.FileMode = 0o750
+	doctorFilePerm os.FileMode = 0o600
+)
+
+// cacheSubdirs are the directories under the cache directory that hooks
+// write to by default.
+var cacheSubdirs = []string{"compact", "drift", "observations", "session-start", "stop"}
+
+func newDoctorCmd() *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the config file and cache directories, optionally creating them",
+		Long: "Checks that the config file, its directory, and the cache directories exist and that other " +
+			"users cannot read or write them. Exits 1 when something needs fixing. With --fix, creates what " +
+			"is missing and tightens permissions, reporting each action. Existing settings are never changed, " +
+			"so --fix is safe to run again.",
+		Args:    cobra.NoArgs,
+		Example: "  cc-tools doctor\n  cc-tools doctor --fix",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cacheDir, err := shared.CacheDir()
+			if err != nil {
+				return fmt.Errorf("find cache directory: %w", err)
+			}
+			dirs := []string{cacheDir}
+			for _, sub := range cacheSubdirs {
+				dirs = append(dirs, filepath.Join(cacheDir, sub))
+			}
+			return handleDoctor(context.Background(), newTerminal(), newConfigManager(), dirs, fix)
+		},
+	}
+	cmd.Flags().BoolVar(&fix, "fix", false, "create missing files and directories and tighten permissions")
+	return cmd
+}
+
+// handleDoctor checks the config file of manager, its directory, and
+// cacheDirs. With fix, it repairs each problem and reports what it did;
+// without, it reports the problems and returns an exit code of 1.
+func handleDoctor(
+	ctx context.Context,
+	out *output.Terminal,
+	manager *config.Manager,
+	cacheDirs []string,
+	fix bool,
+) error {
+	configPath := shared.ResolveFallbackFile(manager.GetConfigPath())
+	dirs := append([]string{filepath.Dir(configPath)}, cacheDirs...)
+
+	problems := 0
+	for _, dir := range dirs {
+		n, err := doctorPath(out, dir, true, fix)
+		if err != nil {
+			return err
+		}
+		problems += n
+	}
+
+	if _, err := os.Stat(configPath); shared.IsMissing(err) {
+		problems++
+		if !fix {
+			_ = out.Warning("✗ Config file %s is missing", configPath)
+		} else {
+			if ensureErr := manager.EnsureConfig(ctx); ensureErr != nil {
+				return fmt.Errorf("create config file: %w", ensureErr)
+			}
+			configPath = manager.GetConfigPath()
+			_ = out.Success("✓ Created config file %s with the defaults", configPath)
+		}
+	}
+	n, err := doctorPath(out, configPath, false, fix)
+	if err != nil {
+		return err
+	}
+	problems += n
+
+	switch {
+	case problems == 0:
+		_ = out.Success("✓ Config file and cache directories are in place")
+	case !fix:
+		_ = out.Info("Run 'cc-tools doctor --fix' to repair these.")
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+// doctorPath checks that path exists, creating it when it is a missing
+// directory and fix is set, and that its permissions are no wider than
+// doctor expects. It returns the number of problems found.
+func doctorPath(out *output.Terminal, path string, isDir, fix bool) (int, error) {
+	want, kind := doctorFilePerm, "file"
+	if isDir {
+		want, kind = doctorDirPerm, "directory"
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case shared.IsMissing(err) && !isDir:
+		// A missing config file is reported by the caller.
+		return 0, nil
+	case shared.IsMissing(err):
+		if !fix {
+			_ = out.Warning("✗ Directory %s is missing", path)
+			return 1, nil
+		}
+		if mkErr := os.MkdirAll(path, want); mkErr != nil {
+			return 0, fmt.Errorf("create %s: %w", path, mkErr)
+		}
+		_ = out.Success("✓ Created directory %s", path)
+		return 1, nil
+	case err != nil:
+		return 0, fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	perm := info.Mode().Perm()
+	if perm&^want == 0 {
+		return 0, nil
+	}
+	if !fix {
+		_ = out.Warning("✗ The %s %s has permissions %#o; want at most %#o", kind, path, perm, want)
+		return 1, nil
+	}
+	if chmodErr := os.Chmod(path, perm&want); chmodErr != nil {
+		return 0, fmt.Errorf("set permissions on %s: %w", path, chmodErr)
+	}
+	_ = out.Success("✓ Set permissions on %s to %#o", path, perm&want)
+	return 1, nil
+}
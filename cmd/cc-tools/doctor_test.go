@@ -0,0 +1,81 @@
+//go:build testmode
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/config"
+)
+
+func TestHandleDoctor(t *testing.T) {
+	root := t.TempDir()
+	configPath := filepath.Join(root, "config", "cc-tools", "config.json")
+	cacheDirs := []string{
+		filepath.Join(root, "cache", "cc-tools"),
+		filepath.Join(root, "cache", "cc-tools", "compact"),
+	}
+
+	t.Run("reports what is missing without changing anything", func(t *testing.T) {
+		out, stdout := newTestTerminal(t)
+		err := handleDoctor(context.Background(), out, config.NewManagerWithPath(configPath), cacheDirs, false)
+
+		var exitErr *exitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 1, exitErr.code)
+		assert.Contains(t, stdout.String(), "Config file "+configPath+" is missing")
+		assert.Contains(t, stdout.String(), "Directory "+cacheDirs[1]+" is missing")
+		assert.NoDirExists(t, filepath.Dir(configPath))
+		assert.NoDirExists(t, cacheDirs[0])
+	})
+
+	t.Run("fix creates the config file and cache directories", func(t *testing.T) {
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleDoctor(context.Background(), out, config.NewManagerWithPath(configPath), cacheDirs, true))
+
+		assert.Contains(t, stdout.String(), "Created config file "+configPath)
+		for _, dir := range cacheDirs {
+			assert.Contains(t, stdout.String(), "Created directory "+dir)
+			info, err := os.Stat(dir)
+			require.NoError(t, err)
+			assert.Zero(t, info.Mode().Perm()&^doctorDirPerm, "no wider than %#o", doctorDirPerm)
+		}
+		info, err := os.Stat(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, doctorFilePerm, info.Mode().Perm())
+	})
+
+	t.Run("fix is a no-op when everything exists", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(configPath, []byte(`{"validate":{"timeout":90}}`), 0o600))
+
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleDoctor(context.Background(), out, config.NewManagerWithPath(configPath), cacheDirs, true))
+		assert.Equal(t, "✓ Config file and cache directories are in place\n", stdout.String())
+
+		data, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"validate":{"timeout":90}}`, string(data), "existing settings are kept")
+	})
+
+	t.Run("fix tightens permissions", func(t *testing.T) {
+		require.NoError(t, os.Chmod(configPath, 0o644))
+		require.NoError(t, os.Chmod(cacheDirs[1], 0o777))
+
+		out, stdout := newTestTerminal(t)
+		require.NoError(t, handleDoctor(context.Background(), out, config.NewManagerWithPath(configPath), cacheDirs, true))
+		assert.Contains(t, stdout.String(), "Set permissions on "+configPath+" to 0600")
+
+		info, err := os.Stat(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		info, err = os.Stat(cacheDirs[1])
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+	})
+}
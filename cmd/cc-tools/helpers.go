@@ -1,24 +1,84 @@
 package main
 
 import (
+	"io"
 	"os"
 
 	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/debug"
 	"github.com/riddopic/cc-tools/internal/mcp"
 	"github.com/riddopic/cc-tools/internal/output"
+	"github.com/riddopic/cc-tools/internal/shared"
 	"github.com/riddopic/cc-tools/internal/skipregistry"
 )
 
 func newTerminal() *output.Terminal {
-	return output.NewTerminal(os.Stdout, os.Stderr)
+	t := output.NewTerminal(os.Stdout, os.Stderr, output.WithColor(colorMode))
+	t.SetQuiet(quiet)
+	return t
+}
+
+// statusOut returns the writer for commands whose only output is a status
+// message, such as a confirmation that a change was made, discarding it
+// when --quiet is set. Commands that print data the user asked for write
+// to os.Stdout whatever --quiet says.
+func statusOut() io.Writer {
+	if quiet {
+		return io.Discard
+	}
+	return os.Stdout
+}
+
+// workingDir returns the current directory with symlinks resolved, so skip
+// and debug entries are keyed by the physical path that hooks see.
+func workingDir() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err //nolint:wrapcheck // callers wrap with context
+	}
+	return shared.ResolvePath(dir), nil
 }
 
 func newSkipRegistry() *skipregistry.JSONRegistry {
 	return skipregistry.NewRegistry(skipregistry.DefaultStorage())
 }
 
+// projectRootFlag names the flag, and projectRootEnv the environment
+// variable used when it is not given, that override project root detection
+// for discovery-based commands.
+const (
+	projectRootFlag = "project-root"
+	projectRootEnv  = "CC_TOOLS_PROJECT_ROOT"
+)
+
+// projectRootOverride returns the project root chosen by --project-root, or
+// by CC_TOOLS_PROJECT_ROOT when the flag is empty, with symlinks resolved.
+// It returns "" when neither is set and an error when the chosen directory
+// does not exist.
+func projectRootOverride(flag string) (string, error) {
+	root := flag
+	if root == "" {
+		root = os.Getenv(projectRootEnv)
+	}
+	if root == "" {
+		return "", nil
+	}
+	return shared.ResolveProjectRoot(root, "", nil) //nolint:wrapcheck // the error names the project root
+}
+
+// configPathEnv names the environment variable that overrides the config
+// file location when --config is not given.
+const configPathEnv = "CC_TOOLS_CONFIG"
+
+// newConfigManager returns a config manager for the file chosen by --config,
+// then $CC_TOOLS_CONFIG, falling back to the default location.
 func newConfigManager() *config.Manager {
+	if configPath != "" {
+		return config.NewManagerWithPath(configPath)
+	}
+	if envPath := os.Getenv(configPathEnv); envPath != "" {
+		return config.NewManagerWithPath(envPath)
+	}
 	return config.NewManager()
 }
 
@@ -29,3 +89,19 @@ func newDebugManager() *debug.Manager {
 func newMCPManager(out *output.Terminal) *mcp.Manager {
 	return mcp.NewManager(out)
 }
+
+// newDryRunMCPManager returns a manager that prints the claude commands it
+// would run. Progress messages are silenced so only the commands and any
+// errors are shown.
+func newDryRunMCPManager(out *output.Terminal) *mcp.Manager {
+	out.SetQuiet(true)
+	return mcp.NewManagerWithExecutor(out, mcp.NewDryRunExecutor(os.Stdout, &mcp.RealCommandExecutor{}))
+}
+
+// selectMCPManager returns a dry-run manager when dryRun is set.
+func selectMCPManager(out *output.Terminal, dryRun bool) *mcp.Manager {
+	if dryRun {
+		return newDryRunMCPManager(out)
+	}
+	return newMCPManager(out)
+}
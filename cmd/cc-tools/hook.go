@@ -7,27 +7,209 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/handler"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 func newHookCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:    "hook",
-		Short:  "Handle Claude Code hook events",
-		Long:   "Reads hook event JSON from stdin, dispatches to registered handlers, and writes structured output.",
+	var inputPath string
+
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Handle Claude Code hook events",
+		Long: "Reads hook event JSON from stdin (or --input), dispatches to registered handlers, " +
+			"and writes structured output.",
 		Hidden: true,
-		RunE:   runHook,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			override, err := timeoutOverride(cmd)
+			if err != nil {
+				return err
+			}
+			return runHook(cmd, inputPath, override)
+		},
+	}
+	cmd.Flags().StringVar(&inputPath, "input", "", "read the hook event JSON from a file instead of stdin")
+	cmd.Flags().Int(timeoutFlag, 0, "override validate.timeout in seconds for this invocation")
+	cmd.AddCommand(newHookListCmd(), newHookBenchCmd())
+	return cmd
+}
+
+func newHookListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the handlers run for each hook event",
+		Long:    "Prints each hook event with its handlers in dispatch order, marking handlers turned off by config.",
+		Args:    cobra.NoArgs,
+		Example: "  cc-tools hook list",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg := loadConfigForDir("")
+			if cfg == nil {
+				cfg = config.GetDefaultConfig()
+			}
+			registry := handler.NewDefaultRegistry(cfg)
+			registerProjectHooks(registry, "", cmd.ErrOrStderr())
+			printHookList(cmd.OutOrStdout(), registry)
+			return nil
+		},
+	}
+}
+
+// defaultBenchIterations is how many times hook bench dispatches the event.
+const defaultBenchIterations = 10
+
+func newHookBenchCmd() *cobra.Command {
+	var (
+		inputPath  string
+		iterations int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bench <event>",
+		Short: "Measure the latency of each handler for a hook event",
+		Long: "Dispatches the event to its handlers repeatedly and prints each handler's mean and " +
+			"95th percentile latency. Handlers run for real, with their usual side effects. " +
+			"The payload is read from --input, or is a minimal one for the current directory.",
+		Args: cobra.ExactArgs(1),
+		Example: `  cc-tools hook bench SessionStart
+  cc-tools hook bench PostToolUse --input edit.json -n 50`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input, err := benchInput(args[0], inputPath)
+			if err != nil {
+				return err
+			}
+
+			cfg := loadConfigForDir(input.Cwd)
+			if cfg == nil {
+				cfg = config.GetDefaultConfig()
+			}
+			newRegistry := func(opts ...handler.RegistryOption) *handler.Registry {
+				registry := handler.NewDefaultRegistry(cfg, opts...)
+				registerProjectHooks(registry, input.Cwd, cmd.ErrOrStderr())
+				return registry
+			}
+			return benchHook(cmd.Context(), cmd.OutOrStdout(), newRegistry, input, iterations)
+		},
+	}
+	cmd.Flags().StringVar(&inputPath, "input", "", "read the fixture payload from a file")
+	cmd.Flags().IntVarP(&iterations, "iterations", "n", defaultBenchIterations, "number of dispatches")
+	return cmd
+}
+
+// benchInput returns the payload for hook bench: the file at path with its
+// event name replaced by event, or a minimal payload for the current
+// directory when path is empty.
+func benchInput(event, path string) (*hookcmd.HookInput, error) {
+	if !slices.Contains(hookcmd.AllEvents(), event) {
+		return nil, fmt.Errorf("unknown hook event %q", event)
+	}
+
+	if path == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("get working directory: %w", err)
+		}
+		return &hookcmd.HookInput{HookEventName: event, SessionID: "bench", Cwd: cwd}, nil
+	}
+
+	data, err := readHookInput(nil, path, 0, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+	input, err := hookcmd.ParseInput(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	input.HookEventName = event
+	return input, nil
+}
+
+// benchHook dispatches input iterations times through a registry built by
+// newRegistry and writes each handler's latency to w.
+func benchHook(
+	ctx context.Context,
+	w io.Writer,
+	newRegistry func(...handler.RegistryOption) *handler.Registry,
+	input *hookcmd.HookInput,
+	iterations int,
+) error {
+	if iterations < 1 {
+		return fmt.Errorf("iterations must be at least 1, got %d", iterations)
+	}
+
+	recorder := handler.NewLatencyRecorder()
+	registry := newRegistry(handler.WithTimingFunc(recorder.Record))
+	if len(registry.Handlers(input.HookEventName)) == 0 {
+		return fmt.Errorf("no handlers registered for %s", input.HookEventName)
+	}
+
+	for range iterations {
+		registry.Dispatch(ctx, input)
+	}
+
+	stats := recorder.Stats()
+	width := len("HANDLER")
+	for _, s := range stats {
+		width = max(width, len(s.Name))
+	}
+
+	_, _ = fmt.Fprintf(w, "%s: %d iterations\n", input.HookEventName, iterations)
+	_, _ = fmt.Fprintf(w, "%-*s  %10s  %10s  %6s\n", width, "HANDLER", "MEAN", "P95", "ERRORS")
+	for _, s := range stats {
+		_, _ = fmt.Fprintf(w, "%-*s  %10s  %10s  %6d\n", width, s.Name,
+			s.Mean.Round(time.Microsecond), s.P95.Round(time.Microsecond), s.Errors)
+	}
+	return nil
+}
+
+// printHookList writes every event that has handlers, followed by the
+// handler names in dispatch order.
+func printHookList(w io.Writer, registry *handler.Registry) {
+	for _, event := range hookcmd.AllEvents() {
+		handlers := registry.Handlers(event)
+		if len(handlers) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintln(w, event)
+		for _, h := range handlers {
+			if e, ok := h.(handler.Enabler); ok && !e.Enabled() {
+				_, _ = fmt.Fprintf(w, "  %s (disabled)\n", h.Name())
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "  %s\n", h.Name())
+		}
+	}
+}
+
+// registerProjectHooks adds the external handlers declared in
+// .claude/hooks.json at the project root containing dir, or the current
+// directory when dir is empty. A file that cannot be loaded is reported on
+// warn and otherwise ignored so hooks keep working.
+func registerProjectHooks(registry *handler.Registry, dir string, warn io.Writer) {
+	root, err := shared.FindProjectRoot(dir, nil)
+	if err != nil {
+		return
+	}
+	if regErr := handler.RegisterExternalHooks(registry, root); regErr != nil {
+		_, _ = fmt.Fprintf(warn, "cc-tools: skipping external hooks: %v\n", regErr)
 	}
 }
 
-func runHook(cmd *cobra.Command, _ []string) error {
-	data, readErr := io.ReadAll(os.Stdin)
+// runHook dispatches one hook event. A positive timeoutOverride replaces
+// validate.timeout in the config handed to handlers.
+func runHook(cmd *cobra.Command, inputPath string, timeoutOverride int) error {
+	data, readErr := readHookInput(cmd.InOrStdin(), inputPath, maxInputBytes(), cmd.ErrOrStderr())
 	if readErr != nil {
+		if inputPath != "" {
+			return readErr
+		}
 		return nil //nolint:nilerr // hooks must not block on stdin errors
 	}
 	if len(data) == 0 {
@@ -39,15 +221,79 @@ func runHook(cmd *cobra.Command, _ []string) error {
 		return nil //nolint:nilerr // hooks must not block on parse errors
 	}
 
-	cfg := loadConfig()
-	registry := handler.NewDefaultRegistry(cfg)
+	// Handlers see the config of the project the event came from.
+	cfg := loadConfigForDir(input.Cwd)
+
+	if timeoutOverride > 0 {
+		if cfg == nil {
+			cfg = config.GetDefaultConfig()
+		}
+		cfg.Validate.Timeout = timeoutOverride
+	}
+
+	// Handler timings, errors, and panic stack traces go to the debug log so
+	// slow or failing handlers can be found.
+	var opts []handler.RegistryOption
+	if logFile := openDebugLog(); logFile != nil {
+		defer func() { _ = logFile.Close() }()
+		opts = append(opts, handler.WithTimingLog(logFile), handler.WithErrorLog(logFile))
+	}
+
+	registry := handler.NewDefaultRegistry(cfg, opts...)
+	registerProjectHooks(registry, input.Cwd, cmd.ErrOrStderr())
 	resp := registry.Dispatch(cmd.Context(), input)
 
-	return writeHookResponse(os.Stdout, os.Stderr, resp)
+	return writeHookResponse(cmd.OutOrStdout(), cmd.ErrOrStderr(), resp)
+}
+
+// readHookInput reads the hook payload from path, or from stdin when path
+// is empty. Stdin over limit bytes yields no payload; see readLimited.
+func readHookInput(stdin io.Reader, path string, limit int, warn io.Writer) ([]byte, error) {
+	if path == "" {
+		return readLimited(stdin, limit, warn)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path is supplied by the user running the command
+	if err != nil {
+		return nil, fmt.Errorf("read hook input: %w", err)
+	}
+	return data, nil
+}
+
+// readLimited reads r up to limit bytes so an oversized payload cannot
+// exhaust memory. Longer input is not parsed: a warning that the event was
+// ignored goes to warn and no data is returned, so the caller does nothing.
+// A limit of zero or less reads everything.
+func readLimited(r io.Reader, limit int, warn io.Writer) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > limit {
+		_, _ = fmt.Fprintf(warn,
+			"cc-tools: event ignored: input exceeds hook.max_input_bytes (%d bytes)\n", limit)
+		return nil, nil
+	}
+	return data, nil
+}
+
+// maxInputBytes returns hook.max_input_bytes from the user's config, or its
+// default when the config could not be loaded. It is read before the input,
+// so a project config cannot change it.
+func maxInputBytes() int {
+	cfg := loadConfig()
+	if cfg == nil {
+		return config.GetDefaultConfig().Hook.MaxInputBytes
+	}
+	return cfg.Hook.MaxInputBytes
 }
 
 func loadConfig() *config.Values {
-	mgr := config.NewManager()
+	mgr := newConfigManager()
 	cfg, err := mgr.GetConfig(context.TODO())
 	if err != nil {
 		return nil
@@ -55,6 +301,16 @@ func loadConfig() *config.Values {
 	return cfg
 }
 
+// loadConfigForDir is loadConfig with the .cc-tools.json of the project
+// containing dir merged in, or of the current directory when dir is empty.
+func loadConfigForDir(dir string) *config.Values {
+	cfg, err := newConfigManager().ConfigForDir(context.TODO(), dir)
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
 func writeHookResponse(stdout, stderr io.Writer, resp *handler.Response) error {
 	if resp.Stderr != "" {
 		_, _ = stderr.Write([]byte(resp.Stderr))
@@ -4,13 +4,21 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/handler"
+	"github.com/riddopic/cc-tools/internal/hookcmd"
 )
 
 func TestWriteHookResponse(t *testing.T) {
@@ -140,3 +148,174 @@ func TestExitError(t *testing.T) {
 	err := &exitError{code: 42}
 	assert.Equal(t, "exit code 42", err.Error())
 }
+
+func TestHookCmd_InputFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	payload := `{"hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"git commit -m x"}}`
+	path := filepath.Join(t.TempDir(), "payload.json")
+	require.NoError(t, os.WriteFile(path, []byte(payload), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	cmd := newHookCmd()
+	cmd.SetIn(bytes.NewReader(nil))
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"--input", path})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, stderr.String(), "Reminder: Run", "pre-commit reminder handler should have run")
+}
+
+func TestHookCmd_InputFileMissing(t *testing.T) {
+	cmd := newHookCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--input", filepath.Join(t.TempDir(), "missing.json")})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read hook input")
+}
+
+func TestHookCmd_OversizedInput(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	configDir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "cc-tools")
+	require.NoError(t, os.MkdirAll(configDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"),
+		[]byte(`{"hook": {"max_input_bytes": 64}}`), 0o600))
+
+	// Valid JSON that would trigger the pre-commit reminder if parsed.
+	payload := `{"hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"git commit -m x"},` +
+		`"cwd":"` + t.TempDir() + `"}`
+	require.Greater(t, len(payload), 64)
+
+	var stdout, stderr bytes.Buffer
+	cmd := newHookCmd()
+	cmd.SetIn(strings.NewReader(payload))
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs(nil)
+
+	require.NoError(t, cmd.Execute())
+	assert.Empty(t, stdout.String())
+	assert.Equal(t, "cc-tools: event ignored: input exceeds hook.max_input_bytes (64 bytes)\n", stderr.String(),
+		"the event is not parsed, so no handler runs")
+}
+
+// endlessReader yields 'x' forever and counts the bytes handed out.
+type endlessReader struct {
+	read int
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'x'
+	}
+	r.read += len(p)
+	return len(p), nil
+}
+
+func TestReadLimited(t *testing.T) {
+	t.Run("oversized input is ignored", func(t *testing.T) {
+		src := &endlessReader{read: 0}
+		var warn bytes.Buffer
+
+		data, err := readLimited(src, 1024, &warn)
+		require.NoError(t, err)
+		assert.Nil(t, data, "an oversized payload is not passed on")
+		assert.LessOrEqual(t, src.read, 64*1024, "reader must not be drained")
+		assert.Equal(t, "cc-tools: event ignored: input exceeds hook.max_input_bytes (1024 bytes)\n", warn.String())
+	})
+
+	t.Run("input within limit is untouched", func(t *testing.T) {
+		var warn bytes.Buffer
+
+		data, err := readLimited(bytes.NewReader([]byte(`{"a":1}`)), 7, &warn)
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(data))
+		assert.Empty(t, warn.String())
+	})
+}
+
+func TestPrintHookList(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.Drift.Enabled = false
+
+	var out bytes.Buffer
+	printHookList(&out, handler.NewDefaultRegistry(cfg))
+	text := out.String()
+
+	assert.Contains(t, text, "SessionStart\n  superpowers\n  pkg-manager\n  session-context\n")
+	assert.Contains(t, text, "UserPromptSubmit\n  drift-detection (disabled)\n")
+	assert.Contains(t, text, "  pre-commit-reminder\n")
+	assert.NotContains(t, text, "PermissionRequest", "events without handlers are omitted")
+}
+
+// countingHandler counts the events it handles.
+type countingHandler struct {
+	name  string
+	calls int
+	err   error
+}
+
+func (c *countingHandler) Name() string { return c.name }
+
+func (c *countingHandler) Handle(_ context.Context, _ *hookcmd.HookInput) (*handler.Response, error) {
+	c.calls++
+	return nil, c.err
+}
+
+func TestBenchHook(t *testing.T) {
+	first := &countingHandler{name: "first", calls: 0, err: nil}
+	second := &countingHandler{name: "second-handler", calls: 0, err: errors.New("boom")}
+	newRegistry := func(opts ...handler.RegistryOption) *handler.Registry {
+		registry := handler.NewRegistry(opts...)
+		registry.Register(hookcmd.EventSessionStart, first, second)
+		return registry
+	}
+	input := &hookcmd.HookInput{HookEventName: hookcmd.EventSessionStart}
+
+	var out bytes.Buffer
+	require.NoError(t, benchHook(context.Background(), &out, newRegistry, input, 7))
+
+	assert.Equal(t, 7, first.calls)
+	assert.Equal(t, 7, second.calls)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "SessionStart: 7 iterations", lines[0])
+	assert.Equal(t, []string{"HANDLER", "MEAN", "P95", "ERRORS"}, strings.Fields(lines[1]))
+	assert.Equal(t, "first", strings.Fields(lines[2])[0])
+	assert.Equal(t, []string{"second-handler", "7"}, slices.Delete(strings.Fields(lines[3]), 1, 3))
+
+	t.Run("no handlers", func(t *testing.T) {
+		input := &hookcmd.HookInput{HookEventName: hookcmd.EventStop}
+		err := benchHook(context.Background(), &out, newRegistry, input, 1)
+		require.ErrorContains(t, err, "no handlers registered for Stop")
+	})
+
+	t.Run("invalid iterations", func(t *testing.T) {
+		require.Error(t, benchHook(context.Background(), &out, newRegistry, input, 0))
+	})
+}
+
+func TestBenchInput(t *testing.T) {
+	input, err := benchInput(hookcmd.EventSessionStart, "")
+	require.NoError(t, err)
+	assert.Equal(t, hookcmd.EventSessionStart, input.HookEventName)
+	assert.NotEmpty(t, input.Cwd)
+
+	path := filepath.Join(t.TempDir(), "edit.json")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`{"hook_event_name":"PreToolUse","tool_name":"Edit","cwd":"/project"}`), 0o600))
+	input, err = benchInput(hookcmd.EventPostToolUse, path)
+	require.NoError(t, err)
+	assert.Equal(t, hookcmd.EventPostToolUse, input.HookEventName, "the event argument wins")
+	assert.Equal(t, "Edit", input.ToolName)
+
+	_, err = benchInput("NoSuchEvent", "")
+	require.ErrorContains(t, err, "unknown hook event")
+}
@@ -2,10 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -13,6 +13,7 @@ import (
 
 	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/instinct"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 const (
@@ -30,9 +31,12 @@ func newInstinctCmd() *cobra.Command {
 	}
 	cmd.AddCommand(
 		newInstinctStatusCmd(),
+		newInstinctAddCmd(),
+		newInstinctRemoveCmd(),
 		newInstinctExportCmd(),
 		newInstinctImportCmd(),
 		newInstinctEvolveCmd(),
+		newInstinctClusterCmd(),
 	)
 	return cmd
 }
@@ -58,6 +62,60 @@ func newInstinctStatusCmd() *cobra.Command {
 	return cmd
 }
 
+func newInstinctAddCmd() *cobra.Command {
+	var (
+		name       string
+		pattern    string
+		confidence float64
+		domain     string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "add",
+		Short:   "Add a personal instinct",
+		Args:    cobra.NoArgs,
+		Example: `  cc-tools instinct add --name prefer-table-tests --pattern "writing Go tests" --confidence 0.8`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cfg := loadInstinctConfig()
+			store := newInstinctStoreFromConfig(cfg)
+			now := time.Now().UTC()
+			inst := instinct.Instinct{
+				ID:         name,
+				Trigger:    pattern,
+				Confidence: confidence,
+				Domain:     domain,
+				Source:     instinct.SourceManual,
+				SourceRepo: "",
+				Content:    "",
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			return runInstinctAdd(statusOut(), store, inst, cfg.Instinct.MaxInstincts)
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "instinct name, used as its ID")
+	cmd.Flags().StringVar(&pattern, "pattern", "", "trigger pattern the instinct applies to")
+	cmd.Flags().Float64Var(&confidence, "confidence", 0, "confidence between 0 and 1")
+	cmd.Flags().StringVar(&domain, "domain", "general", "domain the instinct belongs to")
+	_ = cmd.MarkFlagRequired("name")
+	_ = cmd.MarkFlagRequired("pattern")
+	_ = cmd.MarkFlagRequired("confidence")
+	return cmd
+}
+
+func newInstinctRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <name>",
+		Short:   "Remove a personal instinct",
+		Args:    cobra.ExactArgs(1),
+		Example: "  cc-tools instinct remove prefer-table-tests",
+		RunE: func(_ *cobra.Command, args []string) error {
+			store := newInstinctStoreFromConfig(loadInstinctConfig())
+			return runInstinctRemove(statusOut(), store, args[0])
+		},
+	}
+}
+
 func newInstinctExportCmd() *cobra.Command {
 	var (
 		output        string
@@ -98,7 +156,7 @@ func newInstinctImportCmd() *cobra.Command {
 		RunE: func(_ *cobra.Command, args []string) error {
 			cfg := loadInstinctConfig()
 			store := newInstinctStoreFromConfig(cfg)
-			return runInstinctImport(os.Stdout, store, args[0], dryRun, force, minConfidence, cfg.Instinct.DecayRate)
+			return runInstinctImport(statusOut(), store, args[0], dryRun, force, minConfidence, cfg.Instinct.DecayRate)
 		},
 	}
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be imported without saving")
@@ -120,10 +178,39 @@ func newInstinctEvolveCmd() *cobra.Command {
 	}
 }
 
+func newInstinctClusterCmd() *cobra.Command {
+	var (
+		maxDistance int
+		merge       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Report near-duplicate instincts, optionally merging them",
+		Long: "Groups instincts whose normalized triggers are within --max-distance edits of each other. " +
+			"The distance defaults to instinct.cluster_threshold. With --merge, each group is folded into " +
+			"its most confident member, which keeps the combined confidence; the other personal members " +
+			"are deleted and inherited ones are left in place.",
+		Example: "  cc-tools instinct cluster --max-distance 5\n  cc-tools instinct cluster --merge",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg := loadInstinctConfig()
+			store := newInstinctStoreFromConfig(cfg)
+			if !cmd.Flags().Changed("max-distance") {
+				maxDistance = cfg.Instinct.ClusterThreshold
+			}
+			return runInstinctCluster(os.Stdout, store, maxDistance, merge, cfg.Instinct.DecayRate)
+		},
+	}
+	cmd.Flags().IntVar(&maxDistance, "max-distance", 0,
+		"maximum trigger edit distance (default: instinct.cluster_threshold)")
+	cmd.Flags().BoolVar(&merge, "merge", false, "merge each cluster into its most confident member")
+	return cmd
+}
+
 // loadInstinctConfig resolves runtime config via the manager, falling back to
 // defaults if the config file cannot be loaded.
 func loadInstinctConfig() *config.Values {
-	mgr := config.NewManager()
+	mgr := newConfigManager()
 
 	cfg, err := mgr.GetConfig(context.Background())
 	if err != nil {
@@ -135,15 +222,15 @@ func loadInstinctConfig() *config.Values {
 
 // newInstinctStoreFromConfig creates a FileStore using the given config values.
 func newInstinctStoreFromConfig(cfg *config.Values) *instinct.FileStore {
-	personalPath := expandTilde(cfg.Instinct.PersonalPath)
-	inheritedPath := expandTilde(cfg.Instinct.InheritedPath)
+	personalPath := expandConfigPath(cfg.Instinct.PersonalPath)
+	inheritedPath := expandConfigPath(cfg.Instinct.InheritedPath)
 	return instinct.NewFileStore(personalPath, inheritedPath)
 }
 
 // newInheritedStore creates a FileStore that writes to the inherited directory.
 func newInheritedStore() *instinct.FileStore {
 	cfg := loadInstinctConfig()
-	inheritedPath := expandTilde(cfg.Instinct.InheritedPath)
+	inheritedPath := expandConfigPath(cfg.Instinct.InheritedPath)
 	return instinct.NewFileStore(inheritedPath, "")
 }
 
@@ -174,6 +261,32 @@ func runInstinctStatus(w io.Writer, store *instinct.FileStore, domain string, mi
 	return nil
 }
 
+// runInstinctAdd saves inst as a personal instinct, refusing duplicates and
+// additions beyond maxInstincts.
+func runInstinctAdd(w io.Writer, store *instinct.FileStore, inst instinct.Instinct, maxInstincts int) error {
+	if strings.TrimSpace(inst.Trigger) == "" {
+		return errors.New("--pattern must not be empty")
+	}
+
+	if err := store.Add(inst, maxInstincts); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Added %s (%.2f) [%s]\n", inst.ID, inst.Confidence, inst.Domain)
+	return nil
+}
+
+// runInstinctRemove deletes a personal instinct. Inherited instincts are
+// left alone.
+func runInstinctRemove(w io.Writer, store *instinct.FileStore, name string) error {
+	if err := store.Delete(name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Removed %s\n", name)
+	return nil
+}
+
 // runInstinctExport exports filtered instincts to a file or stdout.
 // Decay is applied before export without mutating stored files.
 func runInstinctExport(
@@ -289,6 +402,78 @@ func runInstinctEvolve(w io.Writer, store *instinct.FileStore, clusterThreshold
 	return nil
 }
 
+// runInstinctCluster reports groups of near-duplicate instincts and, with
+// merge set, folds each group into its most confident member. Decay is
+// applied before grouping, so merged confidences are stored decayed.
+func runInstinctCluster(w io.Writer, store *instinct.FileStore, maxDistance int, merge bool, decayRate float64) error {
+	allInstincts, err := store.List(instinct.ListOptions{Domain: "", MinConfidence: 0, Source: ""})
+	if err != nil {
+		return fmt.Errorf("list instincts: %w", err)
+	}
+
+	allInstincts = instinct.ApplyDecayToSlice(allInstincts, time.Now(), decayRate)
+
+	groups := instinct.GroupSimilar(allInstincts, maxDistance)
+	if len(groups) == 0 {
+		fmt.Fprintf(w, "No instincts within %d edits of each other.\n", maxDistance)
+		return nil
+	}
+
+	for i, group := range groups {
+		fmt.Fprintf(w, "\nCluster %d (combined %.2f):\n", i+1, instinct.CombineConfidence(group))
+		for _, inst := range group {
+			fmt.Fprintf(w, "  %.2f  %s  %s\n", inst.Confidence, inst.ID, inst.Trigger)
+		}
+	}
+
+	if !merge {
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	for _, group := range groups {
+		if mergeErr := mergeInstinctGroup(w, store, group); mergeErr != nil {
+			return mergeErr
+		}
+	}
+
+	return nil
+}
+
+// mergeInstinctGroup saves the most confident member of group with the
+// group's combined confidence and deletes the other personal members.
+func mergeInstinctGroup(w io.Writer, store *instinct.FileStore, group []instinct.Instinct) error {
+	keep := group[0]
+	for _, inst := range group[1:] {
+		if inst.Confidence > keep.Confidence {
+			keep = inst
+		}
+	}
+
+	keep.Confidence = instinct.CombineConfidence(group)
+	keep.UpdatedAt = time.Now().UTC()
+	if err := store.Save(keep); err != nil {
+		return err
+	}
+
+	for _, inst := range group {
+		if inst.ID == keep.ID {
+			continue
+		}
+		err := store.Delete(inst.ID)
+		switch {
+		case errors.Is(err, instinct.ErrNotFound):
+			fmt.Fprintf(w, "Kept inherited %s\n", inst.ID)
+		case err != nil:
+			return err
+		default:
+			fmt.Fprintf(w, "Merged %s into %s (%.2f)\n", inst.ID, keep.ID, keep.Confidence)
+		}
+	}
+
+	return nil
+}
+
 // printSkillCandidates prints clusters that could become skills.
 func printSkillCandidates(w io.Writer, skills []instinct.SkillCandidate) {
 	fmt.Fprintln(w, "\nSkill candidates (3+ related instincts):")
@@ -362,18 +547,15 @@ func resolveExportDest(w io.Writer, outputPath string) (io.Writer, error) {
 	return f, nil
 }
 
-// expandTilde replaces a leading ~ with the user's home directory.
-func expandTilde(path string) string {
-	if !strings.HasPrefix(path, "~") {
-		return path
-	}
-
-	home, err := os.UserHomeDir()
+// expandConfigPath expands ~ and environment variables in a configured
+// path. A path that cannot be expanded is used as written.
+func expandConfigPath(path string) string {
+	expanded, err := shared.ExpandPath(path)
 	if err != nil {
 		return path
 	}
 
-	return filepath.Join(home, path[1:])
+	return expanded
 }
 
 // confidenceBar returns a visual confidence indicator.
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/learning"
+	"github.com/riddopic/cc-tools/internal/session"
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+func newLearningCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "learning",
+		Short: "Extract and list learned skills",
+	}
+	cmd.AddCommand(
+		newLearningExtractCmd(),
+		newLearningListCmd(),
+	)
+	return cmd
+}
+
+func newLearningExtractCmd() *cobra.Command {
+	var sessionID string
+
+	cmd := &cobra.Command{
+		Use:   "extract",
+		Short: "Write learned skill files from recorded sessions",
+		Long: "Derives a skill file from each session with at least learning.min_session_length " +
+			"messages and writes it under learning.learned_skills_path. The steps come from the " +
+			"tools the session used; no model is called.",
+		Args:    cobra.NoArgs,
+		Example: "  cc-tools learning extract\n  cc-tools learning extract --session abc123",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cfg := loadLearningConfig()
+			dir, err := learnedSkillsDir(cfg, "")
+			if err != nil {
+				return err
+			}
+
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("get home directory: %w", err)
+			}
+			store := session.NewStore(filepath.Join(homeDir, ".claude", "sessions"))
+			aliases := session.NewAliasManager(filepath.Join(homeDir, ".claude", "session-aliases.json"))
+			return extractLearnedSkills(statusOut(), store, aliases, dir, cfg.Learning.MinSessionLength, sessionID)
+		},
+	}
+	cmd.Flags().StringVar(&sessionID, "session", "", "extract only this session ID or alias")
+	return cmd
+}
+
+func newLearningListCmd() *cobra.Command {
+	var (
+		path       string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List learned skill files",
+		Long:    "Lists the skill files under learning.learned_skills_path with their title and size.",
+		Args:    cobra.NoArgs,
+		Example: "  cc-tools learning list\n  cc-tools learning list --path ~/skills --json",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			dir, err := learnedSkillsDir(loadLearningConfig(), path)
+			if err != nil {
+				return err
+			}
+			return listLearnedSkills(os.Stdout, dir, jsonOutput)
+		},
+	}
+	cmd.Flags().StringVar(&path, "path", "", "skills directory (default: learning.learned_skills_path)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	return cmd
+}
+
+// loadLearningConfig resolves runtime config via the manager, falling back
+// to defaults if the config file cannot be loaded.
+func loadLearningConfig() *config.Values {
+	cfg, err := newConfigManager().GetConfig(context.Background())
+	if err != nil {
+		return config.GetDefaultConfig()
+	}
+	return cfg
+}
+
+// learnedSkillsDir expands override, or learning.learned_skills_path when
+// override is empty.
+func learnedSkillsDir(cfg *config.Values, override string) (string, error) {
+	if override != "" {
+		dir, err := shared.ExpandPath(override)
+		if err != nil {
+			return "", fmt.Errorf("resolve --path: %w", err)
+		}
+		return dir, nil
+	}
+
+	dir, err := shared.ExpandPath(cfg.Learning.LearnedSkillsPath)
+	if err != nil {
+		return "", fmt.Errorf("resolve learning.learned_skills_path: %w", err)
+	}
+	return dir, nil
+}
+
+// extractLearnedSkills writes a skill file to dir for each session in store
+// with at least minLength messages, or for the one session named by
+// idOrAlias when it is set. A named session that is too short or has
+// nothing to learn is an error; when scanning every session those are
+// skipped.
+func extractLearnedSkills(
+	w io.Writer,
+	store *session.Store,
+	aliases *session.AliasManager,
+	dir string,
+	minLength int,
+	idOrAlias string,
+) error {
+	if idOrAlias != "" {
+		if resolved, resolveErr := aliases.Resolve(idOrAlias); resolveErr == nil {
+			idOrAlias = resolved
+		}
+
+		sess, err := store.Load(idOrAlias)
+		if err != nil {
+			if errors.Is(err, session.ErrNotFound) {
+				return fmt.Errorf("session not found: %s", idOrAlias)
+			}
+			return fmt.Errorf("load session: %w", err)
+		}
+
+		skill, err := learning.Extract(sess, minLength)
+		if err != nil {
+			return fmt.Errorf("extract session %s: %w", sess.ID, err)
+		}
+		return writeLearnedSkill(w, dir, skill)
+	}
+
+	sessions, _, err := store.Scan()
+	if err != nil {
+		return fmt.Errorf("scan sessions: %w", err)
+	}
+
+	count := 0
+	for _, sess := range sessions {
+		skill, extractErr := learning.Extract(sess, minLength)
+		if extractErr != nil {
+			continue
+		}
+		if writeErr := writeLearnedSkill(w, dir, skill); writeErr != nil {
+			return writeErr
+		}
+		count++
+	}
+
+	fmt.Fprintf(w, "Extracted %d skill(s) from %d session(s)\n", count, len(sessions))
+	return nil
+}
+
+// writeLearnedSkill writes skill under dir and reports the path.
+func writeLearnedSkill(w io.Writer, dir string, skill *learning.Skill) error {
+	path, err := learning.Write(dir, skill)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s: %s\n", skill.SessionID, path)
+	return nil
+}
+
+// listLearnedSkills writes the skill files in dir to w as a table, or as
+// an indented JSON array when jsonOutput is set.
+func listLearnedSkills(w io.Writer, dir string, jsonOutput bool) error {
+	skills, err := learning.List(dir)
+	if err != nil {
+		return fmt.Errorf("list learned skills: %w", err)
+	}
+
+	if jsonOutput {
+		data, marshalErr := json.MarshalIndent(skills, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("marshal skills: %w", marshalErr)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	if len(skills) == 0 {
+		fmt.Fprintln(w, "No learned skills yet.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "%-32s  %8s  %s\n", "FILE", "SIZE", "TITLE")
+	fmt.Fprintf(w, "%-32s  %8s  %s\n", "----", "----", "-----")
+	for _, skill := range skills {
+		fmt.Fprintf(w, "%-32s  %8d  %s\n", skill.File, skill.Size, skill.Title)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+//go:build testmode
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/learning"
+	"github.com/riddopic/cc-tools/internal/session"
+)
+
+func seedLearningSession(t *testing.T, store *session.Store, id string, messages int) {
+	t.Helper()
+	sess := &session.Session{
+		Version:       "1",
+		ID:            id,
+		Date:          "2026-02-20",
+		Started:       time.Now(),
+		Title:         "Session 10:00",
+		ToolsUsed:     []string{"Bash", "Edit"},
+		FilesModified: []string{"/repo/main.go"},
+		MessageCount:  messages,
+	}
+	require.NoError(t, store.Save(sess))
+}
+
+func TestExtractLearnedSkills(t *testing.T) {
+	t.Run("all sessions meeting the minimum", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		seedLearningSession(t, store, "long1", 12)
+		seedLearningSession(t, store, "short", 3)
+		seedLearningSession(t, store, "long2", 10)
+		dir := filepath.Join(t.TempDir(), "learned")
+
+		var buf bytes.Buffer
+		require.NoError(t, extractLearnedSkills(&buf, store, newTestAliasManager(t), dir, 10, ""))
+		assert.Contains(t, buf.String(), "Extracted 2 skill(s) from 3 session(s)")
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		names := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		assert.Equal(t, []string{"2026-02-20-long1.md", "2026-02-20-long2.md"}, names)
+
+		data, err := os.ReadFile(filepath.Join(dir, "2026-02-20-long1.md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "# Changes to main.go\n")
+		assert.Contains(t, string(data), "1. Make the changes with Edit.\n")
+		assert.Contains(t, string(data), "2. Build and run the tests with Bash to verify the change.\n")
+	})
+
+	t.Run("one session by alias", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		aliases := newTestAliasManager(t)
+		seedLearningSession(t, store, "abc123", 12)
+		seedLearningSession(t, store, "other", 12)
+		require.NoError(t, aliases.Set("mywork", "abc123"))
+		dir := t.TempDir()
+
+		var buf bytes.Buffer
+		require.NoError(t, extractLearnedSkills(&buf, store, aliases, dir, 10, "mywork"))
+		assert.Equal(t, "abc123: "+filepath.Join(dir, "2026-02-20-abc123.md")+"\n", buf.String())
+
+		assert.NoFileExists(t, filepath.Join(dir, "2026-02-20-other.md"))
+	})
+
+	t.Run("named session below the minimum", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		seedLearningSession(t, store, "short", 3)
+		dir := filepath.Join(t.TempDir(), "learned")
+
+		var buf bytes.Buffer
+		err := extractLearnedSkills(&buf, store, newTestAliasManager(t), dir, 10, "short")
+		require.ErrorIs(t, err, learning.ErrSessionTooShort)
+		assert.NoDirExists(t, dir)
+	})
+
+	t.Run("missing session", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := extractLearnedSkills(&buf, newTestSessionStore(t), newTestAliasManager(t), t.TempDir(), 10, "ghost")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "session not found")
+	})
+}
+
+func TestListLearnedSkills(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deploy.md"), []byte("# Deploy the service\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "review.md"), []byte("# Review a PR\n\nSteps.\n"), 0o600))
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, listLearnedSkills(&buf, dir, false))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.Contains(t, lines[0], "FILE")
+		assert.Regexp(t, `^deploy\.md\s+21\s+Deploy the service$`, lines[2])
+		assert.Regexp(t, `^review\.md\s+22\s+Review a PR$`, lines[3])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, listLearnedSkills(&buf, dir, true))
+
+		var skills []learning.SkillFile
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &skills))
+		assert.Equal(t, []learning.SkillFile{
+			{File: "deploy.md", Title: "Deploy the service", Size: 21},
+			{File: "review.md", Title: "Review a PR", Size: 22},
+		}, skills)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, listLearnedSkills(&buf, filepath.Join(dir, "missing"), false))
+		assert.Equal(t, "No learned skills yet.\n", buf.String())
+
+		buf.Reset()
+		require.NoError(t, listLearnedSkills(&buf, filepath.Join(dir, "missing"), true))
+		assert.Equal(t, "[]\n", buf.String())
+	})
+}
+
+func TestLearningListCmd_Path(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deploy.md"), []byte("# Deploy the service\n"), 0o600))
+
+	cmd := newLearningListCmd()
+	cmd.SetArgs([]string{"--path", dir})
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.Execute())
+	})
+	assert.Contains(t, out, "Deploy the service")
+}
@@ -2,22 +2,62 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/riddopic/cc-tools/internal/debug"
+	"github.com/riddopic/cc-tools/internal/output"
 	"github.com/riddopic/cc-tools/internal/shared"
 )
 
+// debugLogEnv turns on the invocation log in every directory when set to
+// "1". Without it, the log is written only where `cc-tools debug enable`
+// has been run.
+const debugLogEnv = "CC_TOOLS_DEBUG"
+
+// maxDebugLogSize is the size at which the invocation log is rotated to a
+// single ".1" backup before the next entry is written.
+const maxDebugLogSize = 5 << 20
+
+// timeoutFlag names the per-invocation validate timeout override shared by
+// the validate and hook commands.
+const timeoutFlag = "timeout"
+
 // Build-time variables.
 var version = "dev"
 
+// quiet is set by the global --quiet flag and suppresses informational
+// stdout output.
+var quiet bool
+
+// colorFlag is set by the global --color flag, and colorMode is its parsed
+// value.
+var (
+	colorFlag = string(output.ColorAuto)
+	colorMode = output.ColorAuto
+)
+
+// configPath is set by the global --config flag and overrides the config
+// file location.
+var configPath string
+
 func main() {
+	// Claude stops a hook it no longer wants with SIGTERM. Cancelling the
+	// root context kills running lint, test, and handler commands instead
+	// of leaving them to finish on their own.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
 	root := newRootCmd()
-	if err := root.Execute(); err != nil {
+	err := root.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		code := 1
 		if exitErr, ok := errors.AsType[*exitError](err); ok {
 			code = exitErr.code
@@ -31,33 +71,73 @@ func newRootCmd() *cobra.Command {
 		Use:     "cc-tools",
 		Short:   "Claude Code Tools",
 		Version: version,
-		PersistentPreRun: func(_ *cobra.Command, _ []string) {
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
 			writeDebugLog(os.Args, nil)
+
+			mode, err := output.ParseColorMode(colorFlag)
+			if err != nil {
+				return err
+			}
+			colorMode = mode
+			output.SetDefaultColorMode(mode)
+			return nil
 		},
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
 
+	root.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress informational output; errors are still printed")
+	root.PersistentFlags().StringVar(
+		&colorFlag, "color", string(output.ColorAuto), "color output: auto, always, or never",
+	)
+	root.PersistentFlags().StringVar(
+		&configPath, "config", "", "path to the config file (overrides $"+configPathEnv+")",
+	)
+
 	root.AddCommand(
 		newHookCmd(),
 		newSessionCmd(),
 		newConfigCmd(),
+		newDoctorCmd(),
 		newSkipCmd(),
 		newUnskipCmd(),
 		newDebugCmd(),
 		newMCPCmd(),
 		newValidateCmd(),
+		newDiscoverCmd(),
 		newInstinctCmd(),
+		newLearningCmd(),
+		newObserveCmd(),
+		newNotifyCmd(),
+		newSelfUpdateCmd(),
 	)
 
 	return root
 }
 
-func writeDebugLog(args []string, stdinData []byte) {
-	debugFile := getDebugLogPath()
+// timeoutOverride returns the --timeout value when it was given on the
+// command line, or 0 when it was not. A zero or negative value is an error.
+func timeoutOverride(cmd *cobra.Command) (int, error) {
+	if !cmd.Flags().Changed(timeoutFlag) {
+		return 0, nil
+	}
 
-	f, err := os.OpenFile(debugFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	secs, err := cmd.Flags().GetInt(timeoutFlag)
 	if err != nil {
+		return 0, fmt.Errorf("read --%s: %w", timeoutFlag, err)
+	}
+	if secs <= 0 {
+		return 0, fmt.Errorf("--%s must be a positive number of seconds, got %d", timeoutFlag, secs)
+	}
+	return secs, nil
+}
+
+// writeDebugLog appends an invocation record to the directory's debug log
+// when debug logging is enabled for the working directory or through
+// CC_TOOLS_DEBUG.
+func writeDebugLog(args []string, stdinData []byte) {
+	f := openDebugLog()
+	if f == nil {
 		return
 	}
 	defer func() { _ = f.Close() }()
@@ -80,9 +160,55 @@ func writeDebugLog(args []string, stdinData []byte) {
 	}
 }
 
-// getDebugLogPath returns the debug log path for the current directory.
+// openDebugLog opens the directory's debug log for appending, rotating it
+// first when it has grown too large. It returns nil when debug logging is
+// disabled or the log cannot be opened.
+func openDebugLog() *os.File {
+	if !debugLogEnabled() {
+		return nil
+	}
+
+	debugFile := getDebugLogPath()
+	rotateDebugLog(debugFile)
+
+	f, err := os.OpenFile(debugFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// debugLogEnabled reports whether invocations should be logged.
+func debugLogEnabled() bool {
+	if os.Getenv(debugLogEnv) == "1" {
+		return true
+	}
+
+	// debug enable stores the symlink-resolved directory, so the lookup
+	// must resolve it too.
+	wd, err := workingDir()
+	if err != nil {
+		return false
+	}
+
+	enabled, _ := debug.NewManager().IsEnabled(context.Background(), wd)
+	return enabled
+}
+
+// rotateDebugLog moves the log at path aside once it reaches
+// maxDebugLogSize, replacing any previous backup.
+func rotateDebugLog(path string) {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() < maxDebugLogSize {
+		return
+	}
+	_ = os.Rename(path, path+".1")
+}
+
+// getDebugLogPath returns the debug log path for the current directory,
+// the same path debug filename reports.
 func getDebugLogPath() string {
-	wd, err := os.Getwd()
+	wd, err := workingDir()
 	if err != nil {
 		return "/tmp/cc-tools.debug"
 	}
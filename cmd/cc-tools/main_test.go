@@ -3,12 +3,18 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/output"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 func TestNewRootCmd(t *testing.T) {
@@ -52,9 +58,32 @@ func TestNewRootCmd_VersionFlag(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestNewRootCmd_ColorFlag(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	profile := lipgloss.ColorProfile()
+	t.Cleanup(func() {
+		colorFlag = string(output.ColorAuto)
+		colorMode = output.ColorAuto
+		lipgloss.SetColorProfile(profile)
+	})
+
+	cmd := newRootCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--color", "never", "hook", "list"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, output.ColorNever, colorMode)
+
+	cmd = newRootCmd()
+	cmd.SetArgs([]string{"--color", "sometimes", "hook", "list"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid color mode")
+}
+
 func TestWriteDebugLog(t *testing.T) {
 	tmpDir := t.TempDir()
 	t.Chdir(tmpDir)
+	t.Setenv(debugLogEnv, "1")
 
 	// writeDebugLog uses getDebugLogPath() which derives the path from cwd.
 	writeDebugLog([]string{"cc-tools", "hook"}, nil)
@@ -72,6 +101,7 @@ func TestWriteDebugLog(t *testing.T) {
 func TestWriteDebugLog_WithStdin(t *testing.T) {
 	tmpDir := t.TempDir()
 	t.Chdir(tmpDir)
+	t.Setenv(debugLogEnv, "1")
 
 	writeDebugLog([]string{"cc-tools", "validate"}, []byte(`{"tool_input":{}}`))
 
@@ -84,6 +114,74 @@ func TestWriteDebugLog_WithStdin(t *testing.T) {
 	assert.Contains(t, content, `{"tool_input":{}}`)
 }
 
+func TestWriteDebugLog_DisabledForDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(debugLogEnv, "")
+
+	writeDebugLog([]string{"cc-tools", "hook"}, []byte(`{"secret":"value"}`))
+
+	assert.NoFileExists(t, getDebugLogPath())
+}
+
+func TestWriteDebugLog_EnabledForDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	t.Setenv(debugLogEnv, "")
+
+	mgr := newIsolatedDebugManager(t)
+	_, err := mgr.Enable(context.Background(), tmpDir)
+	require.NoError(t, err)
+
+	writeDebugLog([]string{"cc-tools", "hook"}, nil)
+
+	data, err := os.ReadFile(getDebugLogPath())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "cc-tools invoked")
+}
+
+func TestWriteDebugLog_EnabledThroughSymlink(t *testing.T) {
+	project := t.TempDir()
+	link := filepath.Join(t.TempDir(), "project")
+	require.NoError(t, os.Symlink(project, link))
+	t.Chdir(link)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(debugLogEnv, "")
+
+	logPath := shared.GetDebugLogPathForDir(shared.ResolvePath(project))
+	t.Cleanup(func() { _ = os.Remove(logPath) })
+
+	enableCmd := newDebugEnableCmd()
+	require.NoError(t, enableCmd.RunE(enableCmd, nil))
+
+	writeDebugLog([]string{"cc-tools", "hook"}, nil)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "cc-tools invoked")
+}
+
+func TestWriteDebugLog_RotatesLargeLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	t.Setenv(debugLogEnv, "1")
+
+	logPath := getDebugLogPath()
+	t.Cleanup(func() {
+		_ = os.Remove(logPath)
+		_ = os.Remove(logPath + ".1")
+	})
+	require.NoError(t, os.WriteFile(logPath, make([]byte, maxDebugLogSize), 0o600))
+
+	writeDebugLog([]string{"cc-tools", "hook"}, nil)
+
+	info, err := os.Stat(logPath)
+	require.NoError(t, err)
+	assert.Less(t, info.Size(), int64(maxDebugLogSize))
+	assert.FileExists(t, logPath+".1")
+}
+
 func TestGetDebugLogPath(t *testing.T) {
 	tmpDir := t.TempDir()
 	t.Chdir(tmpDir)
@@ -2,15 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/riddopic/cc-tools/internal/mcp"
+	"github.com/riddopic/cc-tools/internal/output"
 )
 
 const mcpTimeout = 30 * time.Second
 
+// defaultMCPLogLines is how many lines mcp logs prints before following.
+const defaultMCPLogLines = 50
+
 func newMCPCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "mcp",
@@ -22,6 +32,12 @@ func newMCPCmd() *cobra.Command {
 		newMCPDisableCmd(),
 		newMCPEnableAllCmd(),
 		newMCPDisableAllCmd(),
+		newMCPCheckCmd(),
+		newMCPAddCmd(),
+		newMCPRemoveCmd(),
+		newMCPImportCmd(),
+		newMCPExportCmd(),
+		newMCPLogsCmd(),
 	)
 	return cmd
 }
@@ -41,18 +57,23 @@ func newMCPListCmd() *cobra.Command {
 }
 
 func newMCPEnableCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "enable <name>",
-		Short:   "Enable an MCP server",
-		Args:    cobra.ExactArgs(1),
-		Example: "  cc-tools mcp enable jira",
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "enable <name>",
+		Short: "Enable an MCP server",
+		Args:  cobra.ExactArgs(1),
+		Example: `  cc-tools mcp enable jira
+  cc-tools mcp enable jira --dry-run`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			out := newTerminal()
 			ctx, cancel := context.WithTimeout(context.Background(), mcpTimeout)
 			defer cancel()
-			return enableMCPServer(ctx, newMCPManager(out), args[0])
+			return enableMCPServer(ctx, selectMCPManager(out, dryRun), args[0])
 		},
 	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the claude commands without running them")
+	return cmd
 }
 
 func newMCPDisableCmd() *cobra.Command {
@@ -71,31 +92,170 @@ func newMCPDisableCmd() *cobra.Command {
 }
 
 func newMCPEnableAllCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "enable-all",
-		Short:   "Enable all MCP servers from settings",
-		Example: "  cc-tools mcp enable-all",
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "enable-all",
+		Short: "Enable all MCP servers from settings",
+		Example: `  cc-tools mcp enable-all
+  cc-tools mcp enable-all --dry-run`,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			out := newTerminal()
 			ctx, cancel := context.WithTimeout(context.Background(), mcpTimeout)
 			defer cancel()
-			return enableAllMCPServers(ctx, newMCPManager(out))
+			return enableAllMCPServers(ctx, out, selectMCPManager(out, dryRun))
 		},
 	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the claude commands without running them")
+	return cmd
 }
 
 func newMCPDisableAllCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "disable-all",
-		Short:   "Disable all MCP servers",
-		Example: "  cc-tools mcp disable-all",
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "disable-all",
+		Short: "Disable all MCP servers",
+		Example: `  cc-tools mcp disable-all
+  cc-tools mcp disable-all --dry-run`,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			out := newTerminal()
 			ctx, cancel := context.WithTimeout(context.Background(), mcpTimeout)
 			defer cancel()
-			return disableAllMCPServers(ctx, newMCPManager(out))
+			return disableAllMCPServers(ctx, selectMCPManager(out, dryRun))
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the claude commands without running them")
+	return cmd
+}
+
+func newMCPCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check [name]",
+		Short: "Check that enabled MCP servers are running",
+		Args:  cobra.MaximumNArgs(1),
+		Example: `  cc-tools mcp check
+  cc-tools mcp check jira`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			out := newTerminal()
+			ctx, cancel := context.WithTimeout(context.Background(), mcpTimeout)
+			defer cancel()
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			return checkMCPServers(ctx, out, newMCPManager(out), name)
+		},
+	}
+}
+
+func newMCPAddCmd() *cobra.Command {
+	var (
+		server = mcp.Server{Type: mcp.ServerTypeLocal, Command: "", Args: nil, Env: nil, URL: ""}
+		env    []string
+		local  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add an MCP server definition to settings",
+		Args:  cobra.ExactArgs(1),
+		Example: `  cc-tools mcp add jira --command jira-mcp --arg --stdio --env JIRA_TOKEN=abc
+  cc-tools mcp add docs --type remote --url https://mcp.example.com --local`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			out := newTerminal()
+			return addMCPServer(out, newMCPManager(out), args[0], server, env, local)
+		},
+	}
+	cmd.Flags().StringVar(&server.Type, "type", mcp.ServerTypeLocal, "server type: local or remote")
+	cmd.Flags().StringVar(&server.Command, "command", "", "command to run (local servers)")
+	cmd.Flags().StringArrayVar(&server.Args, "arg", nil, "argument passed to the command (repeatable)")
+	cmd.Flags().StringArrayVar(&env, "env", nil, "environment variable as KEY=VALUE (repeatable)")
+	cmd.Flags().StringVar(&server.URL, "url", "", "server URL (remote servers)")
+	cmd.Flags().BoolVar(&local, "local", false, "write to settings.local.json instead of settings.json")
+	return cmd
+}
+
+func newMCPRemoveCmd() *cobra.Command {
+	var local bool
+
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove an MCP server definition from settings",
+		Args:  cobra.ExactArgs(1),
+		Example: `  cc-tools mcp remove jira
+  cc-tools mcp remove docs --local`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			out := newTerminal()
+			return removeMCPServer(out, newMCPManager(out), args[0], local)
+		},
+	}
+	cmd.Flags().BoolVar(&local, "local", false, "edit settings.local.json instead of settings.json")
+	return cmd
+}
+
+func newMCPImportCmd() *cobra.Command {
+	var local bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import MCP server definitions from a JSON or YAML file",
+		Long: "Add the servers under mcpServers in a JSON or YAML file to settings, replacing " +
+			"servers with the same name. Files ending in .yaml or .yml are read as YAML; " +
+			"anything else is read as JSON.",
+		Args: cobra.ExactArgs(1),
+		Example: `  cc-tools mcp import team-servers.yaml
+  cc-tools mcp import servers.json --local`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			out := newTerminal()
+			return importMCPServers(out, newMCPManager(out), args[0], local)
 		},
 	}
+	cmd.Flags().BoolVar(&local, "local", false, "write to settings.local.json instead of settings.json")
+	return cmd
+}
+
+func newMCPExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export <file>",
+		Short: "Export MCP server definitions to a JSON or YAML file",
+		Long: "Write every server definition, with settings.local.json merged in, to a file. " +
+			"Files ending in .yaml or .yml are written as YAML; anything else is written as JSON.",
+		Args: cobra.ExactArgs(1),
+		Example: `  cc-tools mcp export team-servers.yaml
+  cc-tools mcp export servers.json`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			out := newTerminal()
+			return exportMCPServers(out, newMCPManager(out), args[0])
+		},
+	}
+}
+
+func newMCPLogsCmd() *cobra.Command {
+	var (
+		follow bool
+		lines  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "logs <name>",
+		Short: "Show the log file for an MCP server",
+		Long: "Print the tail of the most recent log Claude wrote for an MCP server. " +
+			"The name is matched the same way as enable and disable.",
+		Args: cobra.ExactArgs(1),
+		Example: `  cc-tools mcp logs jira
+  cc-tools mcp logs jira --follow`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newTerminal()
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			return showMCPLogs(ctx, cmd.OutOrStdout(), out, newMCPManager(out), mcp.DefaultLogPath,
+				args[0], lines, follow)
+		},
+	}
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "keep printing new log lines as they are written")
+	cmd.Flags().IntVarP(&lines, "lines", "n", defaultMCPLogLines, "number of lines to show")
+	return cmd
 }
 
 // listMCPServers shows all available MCP servers and their status.
@@ -113,12 +273,186 @@ func disableMCPServer(ctx context.Context, mgr *mcp.Manager, name string) error
 	return mgr.Disable(ctx, name)
 }
 
-// enableAllMCPServers enables all MCP servers from settings.
-func enableAllMCPServers(ctx context.Context, mgr *mcp.Manager) error {
-	return mgr.EnableAll(ctx)
+// enableAllMCPServers enables all MCP servers from settings and prints
+// which were enabled, skipped, or failed.
+func enableAllMCPServers(ctx context.Context, out *output.Terminal, mgr *mcp.Manager) error {
+	summary, err := mgr.EnableAll(ctx)
+	if summary == nil {
+		return err
+	}
+
+	if len(summary.Enabled) > 0 {
+		_ = out.Success("✓ Enabled: %s", strings.Join(summary.Enabled, ", "))
+	}
+	if len(summary.Skipped) > 0 {
+		_ = out.Info("Already enabled: %s", strings.Join(summary.Skipped, ", "))
+	}
+	if len(summary.Failed) > 0 {
+		_ = out.Error("Failed: %s", strings.Join(summary.Failed, ", "))
+	}
+
+	return err
 }
 
 // disableAllMCPServers disables all MCP servers.
 func disableAllMCPServers(ctx context.Context, mgr *mcp.Manager) error {
 	return mgr.DisableAll(ctx)
 }
+
+// addMCPServer writes a server definition with the given KEY=VALUE
+// environment entries into the settings file, or the local overlay.
+func addMCPServer(
+	out *output.Terminal, mgr *mcp.Manager, name string, server mcp.Server, env []string, local bool,
+) error {
+	parsed, err := parseEnvPairs(env)
+	if err != nil {
+		return err
+	}
+	server.Env = parsed
+
+	if err = mgr.Add(name, server, local); err != nil {
+		return err
+	}
+
+	_ = out.Success("✓ Added MCP server '%s' to %s", name, mcpSettingsFile(mgr, local))
+	return nil
+}
+
+// removeMCPServer deletes a server definition from the settings file, or
+// the local overlay.
+func removeMCPServer(out *output.Terminal, mgr *mcp.Manager, name string, local bool) error {
+	if err := mgr.Remove(name, local); err != nil {
+		return err
+	}
+
+	_ = out.Success("✓ Removed MCP server '%s' from %s", name, mcpSettingsFile(mgr, local))
+	return nil
+}
+
+// importMCPServers adds the server definitions in file to the settings
+// file, or the local overlay.
+func importMCPServers(out *output.Terminal, mgr *mcp.Manager, file string, local bool) error {
+	names, err := mgr.Import(file, local)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		_ = out.Info("No MCP servers found in %s", file)
+		return nil
+	}
+	_ = out.Success("✓ Imported %s into %s", strings.Join(names, ", "), mcpSettingsFile(mgr, local))
+	return nil
+}
+
+// exportMCPServers writes every server definition to file.
+func exportMCPServers(out *output.Terminal, mgr *mcp.Manager, file string) error {
+	names, err := mgr.Export(file)
+	if err != nil {
+		return err
+	}
+
+	_ = out.Success("✓ Exported %d MCP server(s) to %s", len(names), file)
+	return nil
+}
+
+// mcpSettingsFile returns the settings file that add and remove edit.
+func mcpSettingsFile(mgr *mcp.Manager, local bool) string {
+	if local {
+		return mgr.LocalSettingsPath()
+	}
+	return mgr.SettingsPath()
+}
+
+// parseEnvPairs converts KEY=VALUE flag values into a server env map.
+func parseEnvPairs(pairs []string) (map[string]any, error) {
+	env := make(map[string]any, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --env %q: want KEY=VALUE", pair)
+		}
+		env[key] = value
+	}
+	return env, nil
+}
+
+// checkMCPServers reports the health of enabled MCP servers, optionally
+// limited to a single server, and fails if any of them is down.
+func checkMCPServers(ctx context.Context, out *output.Terminal, mgr *mcp.Manager, name string) error {
+	statuses, err := mgr.CheckHealth(ctx)
+	if err != nil {
+		return err
+	}
+
+	if name != "" {
+		statuses = filterServerStatuses(statuses, name)
+		if len(statuses) == 0 {
+			return fmt.Errorf("%w: '%s' is not enabled", mcp.ErrServerNotFound, name)
+		}
+	}
+
+	if len(statuses) == 0 {
+		_ = out.Info("No MCP servers are currently enabled")
+		return nil
+	}
+
+	down := 0
+	for _, st := range statuses {
+		if st.Healthy {
+			_ = out.Success("✓ %s: %s", st.Name, st.Status)
+			continue
+		}
+		_ = out.Error("✗ %s: %s", st.Name, st.Status)
+		down++
+	}
+
+	if down > 0 {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+// filterServerStatuses returns the statuses whose name matches name,
+// ignoring case.
+func filterServerStatuses(statuses []mcp.ServerStatus, name string) []mcp.ServerStatus {
+	var matched []mcp.ServerStatus
+	for _, st := range statuses {
+		if strings.EqualFold(st.Name, name) {
+			matched = append(matched, st)
+		}
+	}
+	return matched
+}
+
+// showMCPLogs resolves name to a configured server and tails the log file
+// returned by resolve. When no log can be found it explains where it looked
+// and exits non-zero.
+func showMCPLogs(
+	ctx context.Context,
+	w io.Writer,
+	out *output.Terminal,
+	mgr *mcp.Manager,
+	resolve mcp.LogPathResolver,
+	name string,
+	lines int,
+	follow bool,
+) error {
+	actualName, err := mgr.ResolveName(name)
+	if err != nil {
+		return err
+	}
+
+	path, err := resolve(actualName)
+	if errors.Is(err, mcp.ErrLogNotFound) {
+		_ = out.Warning("No log file found for MCP server '%s'", actualName)
+		_ = out.Info("%v", err)
+		_ = out.Info("Logs are written once Claude has started the server; try running a session first.")
+		return &exitError{code: 1}
+	}
+	if err != nil {
+		return err
+	}
+
+	return mcp.TailLog(ctx, w, path, lines, follow)
+}
@@ -6,6 +6,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -27,15 +29,20 @@ type testCommandExecutor struct {
 
 // CommandContext returns a command that runs "echo" with the mock output,
 // or "false" to simulate an error.
-func (e *testCommandExecutor) CommandContext(_ context.Context, _ string, _ ...string) *exec.Cmd {
+func (e *testCommandExecutor) CommandContext(_ context.Context, _ string, _ ...string) (*exec.Cmd, error) {
 	if e.err != nil {
 		// Use a command guaranteed to fail.
-		return exec.Command("false")
+		return exec.Command("false"), nil
 	}
 	if len(e.output) > 0 {
-		return exec.Command("echo", "-n", string(e.output))
+		return exec.Command("echo", "-n", string(e.output)), nil
 	}
-	return exec.Command("true")
+	return exec.Command("true"), nil
+}
+
+// LookPath reports every executable as found.
+func (e *testCommandExecutor) LookPath(file string) (string, error) {
+	return file, nil
 }
 
 // newTestMCPManager creates an isolated MCP manager rooted in a temp directory
@@ -108,7 +115,7 @@ func TestEnableMCPServer(t *testing.T) {
 		ctx := context.Background()
 
 		err := enableMCPServer(ctx, mgr, "nonexistent")
-		require.Error(t, err)
+		require.ErrorIs(t, err, mcp.ErrServerNotFound)
 		assert.Contains(t, err.Error(), "nonexistent")
 	})
 
@@ -157,9 +164,10 @@ func TestEnableAllMCPServers(t *testing.T) {
 	t.Run("no settings file", func(t *testing.T) {
 		executor := &testCommandExecutor{}
 		mgr, _ := newTestMCPManager(t, executor)
+		out, _ := newTestTerminal(t)
 		ctx := context.Background()
 
-		err := enableAllMCPServers(ctx, mgr)
+		err := enableAllMCPServers(ctx, out, mgr)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "reading settings")
 	})
@@ -170,9 +178,10 @@ func TestEnableAllMCPServers(t *testing.T) {
 		writeSettings(t, claudeDir, &mcp.Settings{
 			MCPServers: map[string]mcp.Server{},
 		})
+		out, _ := newTestTerminal(t)
 		ctx := context.Background()
 
-		err := enableAllMCPServers(ctx, mgr)
+		err := enableAllMCPServers(ctx, out, mgr)
 		require.NoError(t, err)
 	})
 
@@ -185,10 +194,31 @@ func TestEnableAllMCPServers(t *testing.T) {
 				"server-b": {Type: "stdio", Command: "b-mcp", Args: []string{}},
 			},
 		})
+		out, stdout := newTestTerminal(t)
 		ctx := context.Background()
 
-		err := enableAllMCPServers(ctx, mgr)
+		err := enableAllMCPServers(ctx, out, mgr)
 		require.NoError(t, err)
+		assert.Contains(t, stdout.String(), "Enabled: server-a, server-b")
+	})
+
+	t.Run("prints failures", func(t *testing.T) {
+		executor := &testCommandExecutor{err: errors.New("add failed")}
+		mgr, claudeDir := newTestMCPManager(t, executor)
+		writeSettings(t, claudeDir, &mcp.Settings{
+			MCPServers: map[string]mcp.Server{
+				"server-a": {Type: "stdio", Command: "a-mcp", Args: []string{}},
+			},
+		})
+		var stdout, stderr bytes.Buffer
+		out := output.NewTerminal(&stdout, &stderr)
+		ctx := context.Background()
+
+		err := enableAllMCPServers(ctx, out, mgr)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 of 1 MCP servers failed to enable: server-a")
+		assert.Contains(t, stderr.String(), "Failed: server-a")
+		assert.NotContains(t, stdout.String(), "Enabled:")
 	})
 }
 
@@ -212,3 +242,187 @@ func TestDisableAllMCPServers(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestCheckMCPServers(t *testing.T) {
+	t.Run("all running", func(t *testing.T) {
+		executor := &testCommandExecutor{output: []byte("jira: Running\ngithub: Running\n")}
+		mgr, _ := newTestMCPManager(t, executor)
+		var stdout, stderr bytes.Buffer
+		out := output.NewTerminal(&stdout, &stderr)
+
+		err := checkMCPServers(context.Background(), out, mgr, "")
+		require.NoError(t, err)
+		assert.Contains(t, stdout.String(), "jira: Running")
+		assert.Empty(t, stderr.String())
+	})
+
+	t.Run("server down exits non-zero", func(t *testing.T) {
+		executor := &testCommandExecutor{output: []byte("jira: Running\ngithub: Stopped\n")}
+		mgr, _ := newTestMCPManager(t, executor)
+		var stdout, stderr bytes.Buffer
+		out := output.NewTerminal(&stdout, &stderr)
+
+		err := checkMCPServers(context.Background(), out, mgr, "")
+		var exitErr *exitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 1, exitErr.code)
+		assert.Contains(t, stderr.String(), "github: Stopped")
+	})
+
+	t.Run("filters by name", func(t *testing.T) {
+		executor := &testCommandExecutor{output: []byte("jira: Running\ngithub: Stopped\n")}
+		mgr, _ := newTestMCPManager(t, executor)
+		var stdout, stderr bytes.Buffer
+		out := output.NewTerminal(&stdout, &stderr)
+
+		err := checkMCPServers(context.Background(), out, mgr, "JIRA")
+		require.NoError(t, err)
+		assert.NotContains(t, stderr.String(), "github")
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		executor := &testCommandExecutor{output: []byte("jira: Running\n")}
+		mgr, _ := newTestMCPManager(t, executor)
+		out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+
+		err := checkMCPServers(context.Background(), out, mgr, "slack")
+		require.ErrorIs(t, err, mcp.ErrServerNotFound)
+		assert.Contains(t, err.Error(), "not enabled")
+	})
+}
+
+func TestAddRemoveMCPServer(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		mgr, claudeDir := newTestMCPManager(t, &testCommandExecutor{})
+		var stdout bytes.Buffer
+		out := output.NewTerminal(&stdout, &bytes.Buffer{})
+		server := mcp.Server{Type: mcp.ServerTypeLocal, Command: "jira-mcp", Args: []string{"--stdio"}}
+
+		require.NoError(t, addMCPServer(out, mgr, "jira", server, []string{"TOKEN=a=b"}, false))
+		assert.Contains(t, stdout.String(), "Added MCP server 'jira'")
+
+		data, err := os.ReadFile(filepath.Join(claudeDir, "settings.json"))
+		require.NoError(t, err)
+		var settings mcp.Settings
+		require.NoError(t, json.Unmarshal(data, &settings))
+		assert.Equal(t, "jira-mcp", settings.MCPServers["jira"].Command)
+		assert.Equal(t, []string{"--stdio"}, settings.MCPServers["jira"].Args)
+		assert.Equal(t, map[string]any{"TOKEN": "a=b"}, settings.MCPServers["jira"].Env)
+
+		require.NoError(t, removeMCPServer(out, mgr, "jira", false))
+		assert.Contains(t, stdout.String(), "Removed MCP server 'jira'")
+
+		err = removeMCPServer(out, mgr, "jira", false)
+		require.ErrorIs(t, err, mcp.ErrServerNotFound)
+	})
+
+	t.Run("local writes the overlay", func(t *testing.T) {
+		mgr, claudeDir := newTestMCPManager(t, &testCommandExecutor{})
+		out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+		server := mcp.Server{Type: mcp.ServerTypeRemote, URL: "https://mcp.example.com"}
+
+		require.NoError(t, addMCPServer(out, mgr, "docs", server, nil, true))
+		assert.FileExists(t, filepath.Join(claudeDir, "settings.local.json"))
+		assert.NoFileExists(t, filepath.Join(claudeDir, "settings.json"))
+	})
+
+	t.Run("invalid env pair", func(t *testing.T) {
+		mgr, _ := newTestMCPManager(t, &testCommandExecutor{})
+		out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+		server := mcp.Server{Type: mcp.ServerTypeLocal, Command: "cmd"}
+
+		err := addMCPServer(out, mgr, "bad", server, []string{"NOVALUE"}, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "KEY=VALUE")
+	})
+
+	t.Run("missing required field", func(t *testing.T) {
+		mgr, _ := newTestMCPManager(t, &testCommandExecutor{})
+		out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+		server := mcp.Server{Type: mcp.ServerTypeRemote}
+
+		err := addMCPServer(out, mgr, "docs", server, nil, false)
+		require.ErrorIs(t, err, mcp.ErrInvalidServer)
+	})
+}
+
+func TestImportExportMCPServers(t *testing.T) {
+	mgr, claudeDir := newTestMCPManager(t, &testCommandExecutor{})
+	var stdout bytes.Buffer
+	out := output.NewTerminal(&stdout, &bytes.Buffer{})
+
+	src := filepath.Join(t.TempDir(), "team.yaml")
+	require.NoError(t, os.WriteFile(src, []byte(
+		"mcpServers:\n  jira:\n    type: stdio\n    command: jira-mcp\n    args: [--stdio]\n"), 0o600))
+
+	require.NoError(t, importMCPServers(out, mgr, src, true))
+	assert.Contains(t, stdout.String(), "Imported jira into")
+	assert.FileExists(t, filepath.Join(claudeDir, "settings.local.json"))
+
+	dst := filepath.Join(t.TempDir(), "out.yml")
+	require.NoError(t, exportMCPServers(out, mgr, dst))
+	assert.Contains(t, stdout.String(), "Exported 1 MCP server(s)")
+
+	data, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"mcpServers:\n  jira:\n    type: stdio\n    command: jira-mcp\n    args:\n      - --stdio\n",
+		string(data))
+}
+
+func TestShowMCPLogs(t *testing.T) {
+	setup := func(t *testing.T) *mcp.Manager {
+		t.Helper()
+		mgr, claudeDir := newTestMCPManager(t, &testCommandExecutor{})
+		writeSettings(t, claudeDir, &mcp.Settings{MCPServers: map[string]mcp.Server{
+			"targetprocess": {Command: "tp-mcp"},
+		}})
+		return mgr
+	}
+
+	t.Run("tails the resolved log", func(t *testing.T) {
+		mgr := setup(t)
+		logPath := filepath.Join(t.TempDir(), "tp.log")
+		require.NoError(t, os.WriteFile(logPath, []byte("first\nsecond\nthird\n"), 0o600))
+
+		var resolved string
+		resolve := func(name string) (string, error) {
+			resolved = name
+			return logPath, nil
+		}
+
+		var logs bytes.Buffer
+		out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+		err := showMCPLogs(context.Background(), &logs, out, mgr, resolve, "target", 2, false)
+		require.NoError(t, err)
+		assert.Equal(t, "targetprocess", resolved)
+		assert.Equal(t, "second\nthird\n", logs.String())
+	})
+
+	t.Run("log not found explains where it looked", func(t *testing.T) {
+		mgr := setup(t)
+		resolve := func(name string) (string, error) {
+			return "", fmt.Errorf("%w: '%s' (searched /tmp/mcp-logs-%s)", mcp.ErrLogNotFound, name, name)
+		}
+
+		var stdout, stderr bytes.Buffer
+		out := output.NewTerminal(&stdout, &stderr)
+		err := showMCPLogs(context.Background(), &bytes.Buffer{}, out, mgr, resolve, "targetprocess", 10, false)
+		var exitErr *exitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 1, exitErr.code)
+		assert.Contains(t, stdout.String()+stderr.String(), "/tmp/mcp-logs-targetprocess")
+	})
+
+	t.Run("unknown server", func(t *testing.T) {
+		mgr := setup(t)
+		resolve := func(string) (string, error) {
+			t.Fatal("resolver should not be called for an unknown server")
+			return "", nil
+		}
+
+		out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+		err := showMCPLogs(context.Background(), &bytes.Buffer{}, out, mgr, resolve, "ghost", 10, false)
+		require.ErrorIs(t, err, mcp.ErrServerNotFound)
+	})
+}
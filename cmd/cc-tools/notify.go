@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/notify"
+)
+
+// quietStatusTimeFormat is the layout used to print the next transition.
+const quietStatusTimeFormat = "2006-01-02 15:04 MST"
+
+func newNotifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Inspect notification settings",
+	}
+	cmd.AddCommand(newNotifyQuietStatusCmd())
+	return cmd
+}
+
+func newNotifyQuietStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "quiet-status",
+		Short: "Report whether quiet hours are active (exit 0 active, 1 inactive)",
+		Example: `  cc-tools notify quiet-status
+  if cc-tools notify quiet-status -q; then echo "shh"; fi`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cfg, err := newConfigManager().GetConfig(context.Background())
+			if err != nil {
+				cfg = config.GetDefaultConfig()
+			}
+			qh := notify.QuietHours{
+				Enabled: cfg.Notify.QuietHours.Enabled,
+				Start:   cfg.Notify.QuietHours.Start,
+				End:     cfg.Notify.QuietHours.End,
+			}
+			return runNotifyQuietStatus(statusOut(), qh, time.Now())
+		},
+	}
+}
+
+// runNotifyQuietStatus prints whether quiet hours are active at now and when
+// they next start or end. It returns an exitError with code 1 when quiet
+// hours are inactive so scripts can branch on the exit status.
+func runNotifyQuietStatus(w io.Writer, qh notify.QuietHours, now time.Time) error {
+	active := qh.IsActive(now)
+
+	switch {
+	case !qh.Enabled:
+		fmt.Fprintln(w, "Quiet hours: disabled")
+	case active:
+		fmt.Fprintf(w, "Quiet hours: active (%s-%s)\n", qh.Start, qh.End)
+	default:
+		fmt.Fprintf(w, "Quiet hours: inactive (%s-%s)\n", qh.Start, qh.End)
+	}
+
+	if next, ok := qh.NextTransition(now); ok {
+		verb := "start"
+		if active {
+			verb = "end"
+		}
+		fmt.Fprintf(w, "Next transition: %s at %s\n", verb, next.Format(quietStatusTimeFormat))
+	}
+
+	if !active {
+		return &exitError{code: 1}
+	}
+	return nil
+}
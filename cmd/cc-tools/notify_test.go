@@ -0,0 +1,64 @@
+//go:build testmode
+
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/notify"
+)
+
+func TestRunNotifyQuietStatus(t *testing.T) {
+	overnight := notify.QuietHours{Enabled: true, Start: "21:00", End: "07:30"}
+
+	tests := []struct {
+		name     string
+		qh       notify.QuietHours
+		now      time.Time
+		wantCode int
+		wantOut  string
+	}{
+		{
+			name:     "inside window is active",
+			qh:       overnight,
+			now:      time.Date(2025, 1, 15, 23, 0, 0, 0, time.UTC),
+			wantCode: 0,
+			wantOut:  "Quiet hours: active (21:00-07:30)\nNext transition: end at 2025-01-16 07:30 UTC\n",
+		},
+		{
+			name:     "outside window is inactive",
+			qh:       overnight,
+			now:      time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC),
+			wantCode: 1,
+			wantOut:  "Quiet hours: inactive (21:00-07:30)\nNext transition: start at 2025-01-15 21:00 UTC\n",
+		},
+		{
+			name:     "disabled is inactive",
+			qh:       notify.QuietHours{Enabled: false, Start: "21:00", End: "07:30"},
+			now:      time.Date(2025, 1, 15, 23, 0, 0, 0, time.UTC),
+			wantCode: 1,
+			wantOut:  "Quiet hours: disabled\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runNotifyQuietStatus(&out, tt.qh, tt.now)
+
+			if tt.wantCode == 0 {
+				require.NoError(t, err)
+			} else {
+				var exitErr *exitError
+				require.ErrorAs(t, err, &exitErr)
+				assert.Equal(t, tt.wantCode, exitErr.code)
+			}
+			assert.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/riddopic/cc-tools/internal/observe"
+)
+
+const defaultPurgeOlderThan = "30d"
+
+func newObserveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "observe",
+		Short: "Manage tool-use observation logs",
+	}
+	cmd.AddCommand(newObservePurgeCmd(), newObserveQueryCmd())
+	return cmd
+}
+
+func newObservePurgeCmd() *cobra.Command {
+	var olderThan string
+
+	cmd := &cobra.Command{
+		Use:     "purge",
+		Short:   "Delete observations older than a retention window",
+		Example: "  cc-tools observe purge --older-than 30d",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			dir, err := observe.DefaultDir()
+			if err != nil {
+				return fmt.Errorf("resolve observe directory: %w", err)
+			}
+			return runObservePurge(statusOut(), dir, olderThan, time.Now())
+		},
+	}
+	cmd.Flags().StringVar(&olderThan, "older-than", defaultPurgeOlderThan, "retention window, e.g. 30d or 12h")
+	return cmd
+}
+
+// runObservePurge removes observations in dir older than the olderThan
+// window measured back from now and reports what was freed.
+func runObservePurge(w io.Writer, dir, olderThan string, now time.Time) error {
+	window, err := parseRetention(olderThan)
+	if err != nil {
+		return err
+	}
+
+	result, err := observe.Purge(dir, now.Add(-window))
+	if err != nil {
+		return fmt.Errorf("purge observations: %w", err)
+	}
+
+	fmt.Fprintf(w, "Removed %d rotated file(s) and %d event(s); freed %d bytes.\n",
+		result.FilesRemoved, result.EventsRemoved, result.BytesFreed)
+
+	return nil
+}
+
+func newObserveQueryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "query <expression>",
+		Short: "Print observations matching a filter expression",
+		Long: "Print each recorded event, oldest first, that matches the expression. Fields " +
+			"(tool, phase, session, error, input, output) are compared with ==, !=, or contains, " +
+			"and comparisons are joined with && and ||. Parentheses group; values with spaces " +
+			"are quoted.",
+		Args: cobra.ExactArgs(1),
+		Example: `  cc-tools observe query 'tool==Bash && phase==failure'
+  cc-tools observe query 'error contains "permission denied" || tool==Edit'`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			dir, err := observe.DefaultDir()
+			if err != nil {
+				return fmt.Errorf("resolve observe directory: %w", err)
+			}
+			return runObserveQuery(os.Stdout, dir, args[0])
+		},
+	}
+}
+
+// runObserveQuery writes the JSONL line of every observation in dir that
+// matches expr to w.
+func runObserveQuery(w io.Writer, dir, expr string) error {
+	query, err := observe.ParseQuery(expr)
+	if err != nil {
+		return err
+	}
+
+	files, err := observe.EventFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		// #nosec G304 -- path comes from the observations directory listing.
+		f, openErr := os.Open(path)
+		if openErr != nil {
+			return fmt.Errorf("open observations: %w", openErr)
+		}
+		_, filterErr := query.Filter(f, w)
+		_ = f.Close()
+		if filterErr != nil {
+			return filterErr
+		}
+	}
+
+	return nil
+}
+
+// parseRetention parses a retention window. A "d" suffix counts days;
+// anything else is parsed by [time.ParseDuration].
+func parseRetention(s string) (time.Duration, error) {
+	var window time.Duration
+
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --older-than %q: %w", s, err)
+		}
+		window = time.Duration(n) * observe.HoursPerDay * time.Hour
+	} else {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --older-than %q: %w", s, err)
+		}
+		window = d
+	}
+
+	if window <= 0 {
+		return 0, errors.New("--older-than must be positive")
+	}
+
+	return window, nil
+}
@@ -0,0 +1,83 @@
+//go:build testmode
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunObservePurge(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	oldArchive := filepath.Join(dir, "observations-20240101-000000.jsonl")
+	newArchive := filepath.Join(dir, "observations-20240601-000000.jsonl")
+	require.NoError(t, os.WriteFile(oldArchive, []byte("0123456789"), 0o600))
+	require.NoError(t, os.WriteFile(newArchive, []byte("keep"), 0o600))
+	oldTime := now.Add(-40 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(oldArchive, oldTime, oldTime))
+
+	var out bytes.Buffer
+	require.NoError(t, runObservePurge(&out, dir, "30d", now))
+
+	assert.NoFileExists(t, oldArchive)
+	assert.FileExists(t, newArchive)
+	assert.Equal(t, "Removed 1 rotated file(s) and 0 event(s); freed 10 bytes.\n", out.String())
+}
+
+func TestRunObserveQuery(t *testing.T) {
+	dir := t.TempDir()
+	archived := `{"phase":"failure","tool_name":"Bash","session_id":"old"}`
+	active := `{"phase":"failure","tool_name":"Bash","session_id":"new"}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "observations-20240101-000000.jsonl"),
+		[]byte(archived+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "observations.jsonl"),
+		[]byte(`{"phase":"post","tool_name":"Bash","session_id":"new"}`+"\n"+active+"\n"), 0o600))
+
+	t.Run("prints matches oldest first", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runObserveQuery(&out, dir, "tool==Bash && phase==failure"))
+		assert.Equal(t, archived+"\n"+active+"\n", out.String())
+	})
+
+	t.Run("rejects a malformed expression", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runObserveQuery(&out, dir, "tool==Bash &&")
+		require.Error(t, err)
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestParseRetention(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "30d", want: 30 * 24 * time.Hour, wantErr: false},
+		{input: "12h", want: 12 * time.Hour, wantErr: false},
+		{input: "0d", want: 0, wantErr: true},
+		{input: "-1h", want: 0, wantErr: true},
+		{input: "xd", want: 0, wantErr: true},
+		{input: "soon", want: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseRetention(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
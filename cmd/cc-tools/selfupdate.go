@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/riddopic/cc-tools/internal/output"
+	"github.com/riddopic/cc-tools/internal/selfupdate"
+)
+
+func newSelfUpdateCmd() *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update cc-tools to the latest GitHub release",
+		Long: "Downloads the latest release for this platform, verifies it against the release's " +
+			"checksums.txt, and replaces the running binary. Use --check to only report whether " +
+			"an update is available.",
+		Args: cobra.NoArgs,
+		Example: `  cc-tools self-update --check
+  cc-tools self-update`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			exePath, err := executablePath()
+			if err != nil {
+				return err
+			}
+			return runSelfUpdate(cmd.Context(), newTerminal(), selfupdate.NewUpdater(), version, exePath, check)
+		},
+	}
+	cmd.Flags().BoolVar(&check, "check", false, "report whether an update is available without installing it")
+	return cmd
+}
+
+// runSelfUpdate compares current with the latest release and, unless check
+// is set, installs the release over exePath when it is newer.
+func runSelfUpdate(
+	ctx context.Context,
+	out *output.Terminal,
+	updater *selfupdate.Updater,
+	current, exePath string,
+	check bool,
+) error {
+	release, err := updater.Latest(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmp, err := selfupdate.CompareVersions(current, release.TagName)
+	if err != nil {
+		return fmt.Errorf("cannot compare this build (%s) with %s; install a release build first: %w",
+			current, release.TagName, err)
+	}
+
+	if cmp >= 0 {
+		_ = out.Info("cc-tools %s is up to date", current)
+		return nil
+	}
+
+	if check {
+		_ = out.Info("Update available: %s -> %s", current, release.TagName)
+		return nil
+	}
+
+	if err = updater.Install(ctx, release, exePath); err != nil {
+		return err
+	}
+
+	_ = out.Success("✓ Updated cc-tools %s -> %s", current, release.TagName)
+	return nil
+}
+
+// executablePath returns the path of the running binary with symlinks
+// resolved, so the update replaces the real file rather than a link to it.
+func executablePath() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("locate running binary: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(exe)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", exe, err)
+	}
+	return resolved, nil
+}
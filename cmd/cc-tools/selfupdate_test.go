@@ -0,0 +1,100 @@
+//go:build testmode
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/output"
+	"github.com/riddopic/cc-tools/internal/selfupdate"
+)
+
+// newLatestServer serves a latest-release response tagged tag with no
+// assets.
+func newLatestServer(t *testing.T, tag string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"tag_name":%q,"assets":[]}`, tag)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRunSelfUpdate(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    string
+		latest     string
+		check      bool
+		wantErr    bool
+		wantStdout string
+	}{
+		{
+			name:       "up to date",
+			current:    "v1.2.0",
+			latest:     "v1.2.0",
+			check:      false,
+			wantErr:    false,
+			wantStdout: "cc-tools v1.2.0 is up to date",
+		},
+		{
+			name:       "check reports newer release",
+			current:    "v1.2.0",
+			latest:     "v1.3.0",
+			check:      true,
+			wantErr:    false,
+			wantStdout: "Update available: v1.2.0 -> v1.3.0",
+		},
+		{
+			name:       "dev build cannot compare",
+			current:    "dev",
+			latest:     "v1.3.0",
+			check:      true,
+			wantErr:    true,
+			wantStdout: "",
+		},
+		{
+			name:       "install fails without platform asset",
+			current:    "v1.2.0",
+			latest:     "v1.3.0",
+			check:      false,
+			wantErr:    true,
+			wantStdout: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newLatestServer(t, tt.latest)
+			updater := selfupdate.NewUpdater(selfupdate.WithAPIURL(srv.URL))
+
+			exePath := filepath.Join(t.TempDir(), "cc-tools")
+			require.NoError(t, os.WriteFile(exePath, []byte("old"), 0o600))
+
+			var stdout bytes.Buffer
+			out := output.NewTerminal(&stdout, &bytes.Buffer{})
+
+			err := runSelfUpdate(context.Background(), out, updater, tt.current, exePath, tt.check)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Contains(t, stdout.String(), tt.wantStdout)
+
+			data, readErr := os.ReadFile(exePath)
+			require.NoError(t, readErr)
+			assert.Equal(t, "old", string(data), "binary must not change")
+		})
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -17,6 +20,7 @@ import (
 const (
 	defaultSessionLimit = 10
 	sessionAliasSetArgs = 2
+	sessionTagArgs      = 2
 )
 
 func newSessionCmd() *cobra.Command {
@@ -28,37 +32,51 @@ func newSessionCmd() *cobra.Command {
 		newSessionListCmd(),
 		newSessionInfoCmd(),
 		newSessionAliasCmd(),
+		newSessionTagCmd(),
 		newSessionSearchCmd(),
+		newSessionSummarizeCmd(),
+		newSessionStatsCmd(),
 	)
 	return cmd
 }
 
 func newSessionListCmd() *cobra.Command {
-	var limit int
+	var (
+		limit int
+		tag   string
+	)
 
 	cmd := &cobra.Command{
-		Use:     "list",
-		Short:   "List recent sessions",
-		Example: "  cc-tools session list --limit 20",
+		Use:   "list",
+		Short: "List recent sessions",
+		Example: `  cc-tools session list --limit 20
+  cc-tools session list --tag auth`,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			homeDir, err := os.UserHomeDir()
 			if err != nil {
 				return fmt.Errorf("get home directory: %w", err)
 			}
 			store := session.NewStore(filepath.Join(homeDir, ".claude", "sessions"))
-			return listSessions(os.Stdout, store, limit)
+			tags := session.NewTagManager(filepath.Join(homeDir, ".claude", "session-tags.json"))
+			return listSessions(os.Stdout, store, tags, tag, limit)
 		},
 	}
 	cmd.Flags().IntVar(&limit, "limit", defaultSessionLimit, "maximum number of sessions to show")
+	cmd.Flags().StringVar(&tag, "tag", "", "only show sessions with this tag")
 	return cmd
 }
 
 func newSessionInfoCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		compact bool
+		fields  []string
+	)
+
+	cmd := &cobra.Command{
 		Use:     "info <id-or-alias>",
 		Short:   "Show session details",
 		Args:    cobra.ExactArgs(1),
-		Example: "  cc-tools session info abc123",
+		Example: "  cc-tools session info abc123 --compact --fields id,title",
 		RunE: func(_ *cobra.Command, args []string) error {
 			homeDir, err := os.UserHomeDir()
 			if err != nil {
@@ -66,9 +84,12 @@ func newSessionInfoCmd() *cobra.Command {
 			}
 			store := session.NewStore(filepath.Join(homeDir, ".claude", "sessions"))
 			aliases := session.NewAliasManager(filepath.Join(homeDir, ".claude", "session-aliases.json"))
-			return showSessionInfo(os.Stdout, store, aliases, args[0])
+			return showSessionInfo(os.Stdout, store, aliases, args[0], compact, fields)
 		},
 	}
+	cmd.Flags().BoolVar(&compact, "compact", false, "print single-line JSON without indentation")
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "only include these JSON fields (comma-separated)")
+	return cmd
 }
 
 func newSessionAliasCmd() *cobra.Command {
@@ -96,7 +117,7 @@ func newSessionAliasSetCmd() *cobra.Command {
 				return fmt.Errorf("get home directory: %w", err)
 			}
 			aliases := session.NewAliasManager(filepath.Join(homeDir, ".claude", "session-aliases.json"))
-			return setSessionAlias(os.Stdout, aliases, args[0], args[1])
+			return setSessionAlias(statusOut(), aliases, args[0], args[1])
 		},
 	}
 }
@@ -113,7 +134,7 @@ func newSessionAliasRemoveCmd() *cobra.Command {
 				return fmt.Errorf("get home directory: %w", err)
 			}
 			aliases := session.NewAliasManager(filepath.Join(homeDir, ".claude", "session-aliases.json"))
-			return removeSessionAlias(os.Stdout, aliases, args[0])
+			return removeSessionAlias(statusOut(), aliases, args[0])
 		},
 	}
 }
@@ -133,6 +154,56 @@ func newSessionAliasListCmd() *cobra.Command {
 	}
 }
 
+func newSessionTagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Manage session tags",
+	}
+	cmd.AddCommand(
+		newSessionTagAddCmd(),
+		newSessionTagRemoveCmd(),
+	)
+	return cmd
+}
+
+func newSessionTagAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "add <id-or-alias> <tag>",
+		Short:   "Tag a session",
+		Args:    cobra.ExactArgs(sessionTagArgs),
+		Example: "  cc-tools session tag add abc123 auth",
+		RunE: func(_ *cobra.Command, args []string) error {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("get home directory: %w", err)
+			}
+			store := session.NewStore(filepath.Join(homeDir, ".claude", "sessions"))
+			aliases := session.NewAliasManager(filepath.Join(homeDir, ".claude", "session-aliases.json"))
+			tags := session.NewTagManager(filepath.Join(homeDir, ".claude", "session-tags.json"))
+			return addSessionTag(statusOut(), store, aliases, tags, args[0], args[1])
+		},
+	}
+}
+
+func newSessionTagRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <id-or-alias> <tag>",
+		Short:   "Remove a tag from a session",
+		Args:    cobra.ExactArgs(sessionTagArgs),
+		Example: "  cc-tools session tag remove abc123 auth",
+		RunE: func(_ *cobra.Command, args []string) error {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("get home directory: %w", err)
+			}
+			store := session.NewStore(filepath.Join(homeDir, ".claude", "sessions"))
+			aliases := session.NewAliasManager(filepath.Join(homeDir, ".claude", "session-aliases.json"))
+			tags := session.NewTagManager(filepath.Join(homeDir, ".claude", "session-tags.json"))
+			return removeSessionTag(statusOut(), store, aliases, tags, args[0], args[1])
+		},
+	}
+}
+
 func newSessionSearchCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:     "search <query>",
@@ -150,13 +221,86 @@ func newSessionSearchCmd() *cobra.Command {
 	}
 }
 
-// listSessions writes a formatted table of recent sessions to w.
-func listSessions(w io.Writer, store *session.Store, limit int) error {
-	sessions, err := store.List(limit)
+func newSessionSummarizeCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "summarize [id-or-alias]",
+		Short: "Fill in missing session summaries",
+		Long: "Derives a summary from a session's stored title, modified files, tools, and message count, " +
+			"and saves it. Sessions that already have a summary are left unchanged.",
+		Args:    cobra.MaximumNArgs(1),
+		Example: "  cc-tools session summarize abc123\n  cc-tools session summarize --all",
+		RunE: func(_ *cobra.Command, args []string) error {
+			if all == (len(args) == 1) {
+				return errors.New("specify a session ID or --all, but not both")
+			}
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("get home directory: %w", err)
+			}
+			store := session.NewStore(filepath.Join(homeDir, ".claude", "sessions"))
+			if all {
+				return summarizeAllSessions(os.Stdout, store)
+			}
+			aliases := session.NewAliasManager(filepath.Join(homeDir, ".claude", "session-aliases.json"))
+			return summarizeSession(os.Stdout, store, aliases, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "summarize every session without a summary")
+	return cmd
+}
+
+func newSessionStatsCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize session history",
+		Long: "Reports the number of sessions, sessions per day over the last week, the average " +
+			"summary length, and the most recent session. Unreadable session files are skipped " +
+			"and counted.",
+		Args:    cobra.NoArgs,
+		Example: "  cc-tools session stats\n  cc-tools session stats --json",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("get home directory: %w", err)
+			}
+			store := session.NewStore(filepath.Join(homeDir, ".claude", "sessions"))
+			return showSessionStats(os.Stdout, store, time.Now(), jsonOutput)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	return cmd
+}
+
+// listSessions writes a formatted table of recent sessions to w. A
+// non-empty tag limits the table to sessions carrying that tag.
+func listSessions(w io.Writer, store *session.Store, tags *session.TagManager, tag string, limit int) error {
+	storeLimit := limit
+	if tag != "" {
+		storeLimit = 0
+	}
+	sessions, err := store.List(storeLimit)
 	if err != nil {
 		return fmt.Errorf("list sessions: %w", err)
 	}
 
+	if tag != "" {
+		tagged, tagErr := tags.Sessions(tag)
+		if tagErr != nil {
+			return fmt.Errorf("list tags: %w", tagErr)
+		}
+		sessions = slices.DeleteFunc(sessions, func(s *session.Session) bool {
+			_, found := slices.BinarySearch(tagged, s.ID)
+			return !found
+		})
+		if limit > 0 && len(sessions) > limit {
+			sessions = sessions[:limit]
+		}
+	}
+
 	if len(sessions) == 0 {
 		fmt.Fprintln(w, "No sessions found.")
 		return nil
@@ -170,8 +314,67 @@ func listSessions(w io.Writer, store *session.Store, limit int) error {
 	return nil
 }
 
-// showSessionInfo resolves an ID or alias and writes session details as JSON to w.
-func showSessionInfo(w io.Writer, store *session.Store, aliases *session.AliasManager, idOrAlias string) error {
+// showSessionInfo resolves an ID or alias and writes session details as JSON
+// to w. Output is indented unless compact is set; a non-empty fields list
+// limits it to those JSON fields.
+func showSessionInfo(
+	w io.Writer,
+	store *session.Store,
+	aliases *session.AliasManager,
+	idOrAlias string,
+	compact bool,
+	fields []string,
+) error {
+	if err := validateSessionFields(fields); err != nil {
+		return err
+	}
+
+	sess, err := loadSession(store, aliases, idOrAlias)
+	if err != nil {
+		return err
+	}
+
+	names, err := sessionAliasNames(aliases, sess.ID)
+	if err != nil {
+		return err
+	}
+
+	var value any = &sessionInfo{Session: sess, Aliases: names}
+	if len(fields) > 0 {
+		projected, projectErr := projectSessionFields(value, fields)
+		if projectErr != nil {
+			return projectErr
+		}
+		value = projected
+	}
+
+	var (
+		data       []byte
+		marshalErr error
+	)
+	if compact {
+		data, marshalErr = json.Marshal(value)
+	} else {
+		data, marshalErr = json.MarshalIndent(value, "", "  ")
+	}
+	if marshalErr != nil {
+		return fmt.Errorf("marshal session: %w", marshalErr)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// sessionInfo is the JSON printed by session info: the session's fields
+// plus the aliases that point at it.
+type sessionInfo struct {
+	*session.Session
+
+	Aliases []string `json:"aliases"`
+}
+
+// loadSession loads the session named by an ID or alias, returning an
+// error naming it when no such session exists.
+func loadSession(store *session.Store, aliases *session.AliasManager, idOrAlias string) (*session.Session, error) {
 	if resolved, resolveErr := aliases.Resolve(idOrAlias); resolveErr == nil {
 		idOrAlias = resolved
 	}
@@ -179,19 +382,78 @@ func showSessionInfo(w io.Writer, store *session.Store, aliases *session.AliasMa
 	sess, err := store.Load(idOrAlias)
 	if err != nil {
 		if errors.Is(err, session.ErrNotFound) {
-			return fmt.Errorf("session not found: %s", idOrAlias)
+			return nil, fmt.Errorf("session not found: %s", idOrAlias)
 		}
-		return fmt.Errorf("load session: %w", err)
+		return nil, fmt.Errorf("load session: %w", err)
 	}
+	return sess, nil
+}
 
-	data, marshalErr := json.MarshalIndent(sess, "", "  ")
-	if marshalErr != nil {
-		return fmt.Errorf("marshal session: %w", marshalErr)
+// sessionAliasNames returns the sorted names of the aliases that resolve to
+// sessionID. It returns an empty, non-nil slice when there are none.
+func sessionAliasNames(aliases *session.AliasManager, sessionID string) ([]string, error) {
+	all, err := aliases.List()
+	if err != nil {
+		return nil, fmt.Errorf("list aliases: %w", err)
+	}
+
+	names := []string{}
+	for name, id := range all {
+		if id == sessionID {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// sessionFieldNames returns the JSON field names of [session.Session],
+// followed by the aliases field added by session info.
+func sessionFieldNames() []string {
+	t := reflect.TypeFor[session.Session]()
+	names := make([]string, 0, t.NumField()+1)
+	for field := range t.Fields() {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return append(names, "aliases")
+}
+
+// validateSessionFields rejects field names that are not session JSON fields.
+func validateSessionFields(fields []string) error {
+	known := sessionFieldNames()
+	for _, f := range fields {
+		if !slices.Contains(known, f) {
+			return fmt.Errorf("unknown session field %q (valid: %s)", f, strings.Join(known, ", "))
+		}
 	}
-	fmt.Fprintln(w, string(data))
 	return nil
 }
 
+// projectSessionFields returns the selected JSON fields of info. Fields that
+// are omitted from the session's JSON because they are empty are left out.
+func projectSessionFields(info any, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, fmt.Errorf("marshal session: %w", err)
+	}
+
+	var all map[string]json.RawMessage
+	if unmarshalErr := json.Unmarshal(data, &all); unmarshalErr != nil {
+		return nil, fmt.Errorf("decode session: %w", unmarshalErr)
+	}
+
+	projected := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		if v, ok := all[f]; ok {
+			projected[f] = v
+		}
+	}
+	return projected, nil
+}
+
 // setSessionAlias creates or overwrites a named alias for a session ID.
 func setSessionAlias(w io.Writer, aliases *session.AliasManager, name, sessionID string) error {
 	if err := aliases.Set(name, sessionID); err != nil {
@@ -228,6 +490,44 @@ func listSessionAliases(w io.Writer, aliases *session.AliasManager) error {
 	return nil
 }
 
+// addSessionTag resolves an ID or alias and labels the session with a tag.
+func addSessionTag(
+	w io.Writer,
+	store *session.Store,
+	aliases *session.AliasManager,
+	tags *session.TagManager,
+	idOrAlias, tag string,
+) error {
+	sess, err := loadSession(store, aliases, idOrAlias)
+	if err != nil {
+		return err
+	}
+	if addErr := tags.Add(sess.ID, tag); addErr != nil {
+		return fmt.Errorf("add tag: %w", addErr)
+	}
+	fmt.Fprintf(w, "Tagged session %s with %q\n", sess.ID, tag)
+	return nil
+}
+
+// removeSessionTag resolves an ID or alias and takes a tag off the session.
+func removeSessionTag(
+	w io.Writer,
+	store *session.Store,
+	aliases *session.AliasManager,
+	tags *session.TagManager,
+	idOrAlias, tag string,
+) error {
+	sess, err := loadSession(store, aliases, idOrAlias)
+	if err != nil {
+		return err
+	}
+	if removeErr := tags.Remove(sess.ID, tag); removeErr != nil {
+		return fmt.Errorf("remove tag: %w", removeErr)
+	}
+	fmt.Fprintf(w, "Tag %q removed from session %s\n", tag, sess.ID)
+	return nil
+}
+
 // searchSessions searches sessions by query and writes matches as a formatted table to w.
 func searchSessions(w io.Writer, store *session.Store, query string) error {
 	sessions, err := store.Search(query)
@@ -245,3 +545,103 @@ func searchSessions(w io.Writer, store *session.Store, query string) error {
 	}
 	return nil
 }
+
+// summarizeSession resolves an ID or alias and fills in the session's
+// summary if it is empty.
+func summarizeSession(w io.Writer, store *session.Store, aliases *session.AliasManager, idOrAlias string) error {
+	sess, err := loadSession(store, aliases, idOrAlias)
+	if err != nil {
+		return err
+	}
+
+	if sess.Summary != "" {
+		fmt.Fprintf(w, "Session %s already has a summary\n", sess.ID)
+		return nil
+	}
+
+	written, err := backfillSummary(store, sess)
+	if err != nil {
+		return err
+	}
+	if !written {
+		fmt.Fprintf(w, "Session %s has nothing to summarize\n", sess.ID)
+		return nil
+	}
+	fmt.Fprintf(w, "%s: %s\n", sess.ID, sess.Summary)
+	return nil
+}
+
+// summarizeAllSessions fills in the summary of every session that lacks
+// one.
+func summarizeAllSessions(w io.Writer, store *session.Store) error {
+	sessions, err := store.List(0)
+	if err != nil {
+		return fmt.Errorf("list sessions: %w", err)
+	}
+
+	count := 0
+	for _, sess := range sessions {
+		if sess.Summary != "" {
+			continue
+		}
+		written, backfillErr := backfillSummary(store, sess)
+		if backfillErr != nil {
+			return backfillErr
+		}
+		if written {
+			fmt.Fprintf(w, "%s: %s\n", sess.ID, sess.Summary)
+			count++
+		}
+	}
+
+	fmt.Fprintf(w, "Summarized %d session(s)\n", count)
+	return nil
+}
+
+// showSessionStats writes aggregate statistics for every session in store
+// to w, as indented JSON when jsonOutput is set.
+func showSessionStats(w io.Writer, store *session.Store, now time.Time, jsonOutput bool) error {
+	sessions, skipped, err := store.Scan()
+	if err != nil {
+		return fmt.Errorf("scan sessions: %w", err)
+	}
+	stats := session.ComputeStats(sessions, skipped, now)
+
+	if jsonOutput {
+		data, marshalErr := json.MarshalIndent(stats, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("marshal stats: %w", marshalErr)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(w, "%-24s%d\n", "Sessions:", stats.Total)
+	fmt.Fprintf(w, "%-24s%.0f characters\n", "Average summary length:", stats.AverageSummaryLength)
+	if stats.MostRecent != nil {
+		fmt.Fprintf(w, "%-24s%s  %s  %s\n", "Most recent:",
+			stats.MostRecent.Date, stats.MostRecent.ID, stats.MostRecent.Title)
+	}
+	fmt.Fprintln(w, "Last 7 days:")
+	for _, day := range stats.PerDay {
+		fmt.Fprintf(w, "  %s  %d\n", day.Date, day.Sessions)
+	}
+	if stats.Skipped > 0 {
+		fmt.Fprintf(w, "Skipped %d unreadable session file(s)\n", stats.Skipped)
+	}
+	return nil
+}
+
+// backfillSummary derives and saves a summary for sess. It reports false
+// without writing when the session holds nothing to summarize.
+func backfillSummary(store *session.Store, sess *session.Session) (bool, error) {
+	summary := session.Summarize(sess)
+	if summary == "" {
+		return false, nil
+	}
+	sess.Summary = summary
+	if err := store.Save(sess); err != nil {
+		return false, fmt.Errorf("save session %s: %w", sess.ID, err)
+	}
+	return true, nil
+}
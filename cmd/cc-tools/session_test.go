@@ -4,7 +4,11 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -41,7 +45,7 @@ func TestListSessions(t *testing.T) {
 		store := newTestSessionStore(t)
 		var buf bytes.Buffer
 
-		err := listSessions(&buf, store, defaultSessionLimit)
+		err := listSessions(&buf, store, nil, "", defaultSessionLimit)
 		require.NoError(t, err)
 		assert.Contains(t, buf.String(), "No sessions found.")
 	})
@@ -52,7 +56,7 @@ func TestListSessions(t *testing.T) {
 		seedSession(t, store, "def456", "2026-02-21", "Add session tracking")
 
 		var buf bytes.Buffer
-		err := listSessions(&buf, store, defaultSessionLimit)
+		err := listSessions(&buf, store, nil, "", defaultSessionLimit)
 		require.NoError(t, err)
 
 		output := buf.String()
@@ -71,7 +75,7 @@ func TestListSessions(t *testing.T) {
 		seedSession(t, store, "s3", "2026-02-03", "Third")
 
 		var buf bytes.Buffer
-		err := listSessions(&buf, store, 2)
+		err := listSessions(&buf, store, nil, "", 2)
 		require.NoError(t, err)
 
 		output := buf.String()
@@ -82,6 +86,75 @@ func TestListSessions(t *testing.T) {
 	})
 }
 
+func TestSessionTags(t *testing.T) {
+	store := newTestSessionStore(t)
+	aliases := newTestAliasManager(t)
+	tags := session.NewTagManager(filepath.Join(t.TempDir(), "session-tags.json"))
+	seedSession(t, store, "s1", "2026-02-01", "First")
+	seedSession(t, store, "s2", "2026-02-02", "Second")
+	seedSession(t, store, "s3", "2026-02-03", "Third")
+
+	var buf bytes.Buffer
+	require.NoError(t, addSessionTag(&buf, store, aliases, tags, "s1", "auth"))
+	require.NoError(t, addSessionTag(&buf, store, aliases, tags, "s3", "auth"))
+	require.NoError(t, addSessionTag(&buf, store, aliases, tags, "s2", "docs"))
+	assert.Contains(t, buf.String(), `Tagged session s1 with "auth"`)
+
+	buf.Reset()
+	require.NoError(t, listSessions(&buf, store, tags, "auth", defaultSessionLimit))
+	assert.Contains(t, buf.String(), "First")
+	assert.Contains(t, buf.String(), "Third")
+	assert.NotContains(t, buf.String(), "Second")
+
+	buf.Reset()
+	require.NoError(t, listSessions(&buf, store, tags, "auth", 1))
+	assert.Contains(t, buf.String(), "Third", "the limit applies after filtering")
+	assert.NotContains(t, buf.String(), "First")
+
+	require.NoError(t, removeSessionTag(&buf, store, aliases, tags, "s3", "auth"))
+	buf.Reset()
+	require.NoError(t, listSessions(&buf, store, tags, "auth", defaultSessionLimit))
+	assert.Contains(t, buf.String(), "First")
+	assert.NotContains(t, buf.String(), "Third")
+
+	buf.Reset()
+	require.NoError(t, listSessions(&buf, store, tags, "none", defaultSessionLimit))
+	assert.Contains(t, buf.String(), "No sessions found.")
+
+	err := removeSessionTag(&buf, store, aliases, tags, "s3", "auth")
+	require.ErrorIs(t, err, session.ErrTagNotFound)
+}
+
+func TestSessionTags_ResolvesAliases(t *testing.T) {
+	store := newTestSessionStore(t)
+	aliases := newTestAliasManager(t)
+	tags := session.NewTagManager(filepath.Join(t.TempDir(), "session-tags.json"))
+	seedSession(t, store, "s1", "2026-02-01", "First")
+	require.NoError(t, aliases.Set("mywork", "s1"))
+
+	var buf bytes.Buffer
+	require.NoError(t, addSessionTag(&buf, store, aliases, tags, "mywork", "auth"))
+	assert.Contains(t, buf.String(), `Tagged session s1 with "auth"`)
+
+	buf.Reset()
+	require.NoError(t, listSessions(&buf, store, tags, "auth", defaultSessionLimit))
+	assert.Contains(t, buf.String(), "First", "the tag is stored under the session ID")
+
+	require.NoError(t, removeSessionTag(&buf, store, aliases, tags, "mywork", "auth"))
+	buf.Reset()
+	require.NoError(t, listSessions(&buf, store, tags, "auth", defaultSessionLimit))
+	assert.Contains(t, buf.String(), "No sessions found.")
+
+	err := addSessionTag(&buf, store, aliases, tags, "s9", "auth")
+	require.EqualError(t, err, "session not found: s9")
+	err = removeSessionTag(&buf, store, aliases, tags, "s9", "auth")
+	require.EqualError(t, err, "session not found: s9")
+
+	sessions, err := tags.Sessions("auth")
+	require.NoError(t, err)
+	assert.Empty(t, sessions, "an unknown session leaves no orphan tag")
+}
+
 func TestShowSessionInfo(t *testing.T) {
 	t.Run("session found", func(t *testing.T) {
 		store := newTestSessionStore(t)
@@ -89,7 +162,7 @@ func TestShowSessionInfo(t *testing.T) {
 		seedSession(t, store, "abc123", "2026-02-20", "Test session")
 
 		var buf bytes.Buffer
-		err := showSessionInfo(&buf, store, aliases, "abc123")
+		err := showSessionInfo(&buf, store, aliases, "abc123", false, nil)
 		require.NoError(t, err)
 		assert.Contains(t, buf.String(), "abc123")
 		assert.Contains(t, buf.String(), "Test session")
@@ -100,7 +173,7 @@ func TestShowSessionInfo(t *testing.T) {
 		aliases := newTestAliasManager(t)
 
 		var buf bytes.Buffer
-		err := showSessionInfo(&buf, store, aliases, "nonexistent")
+		err := showSessionInfo(&buf, store, aliases, "nonexistent", false, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "session not found")
 	})
@@ -112,11 +185,69 @@ func TestShowSessionInfo(t *testing.T) {
 		require.NoError(t, aliases.Set("mywork", "abc123"))
 
 		var buf bytes.Buffer
-		err := showSessionInfo(&buf, store, aliases, "mywork")
+		err := showSessionInfo(&buf, store, aliases, "mywork", false, nil)
 		require.NoError(t, err)
 		assert.Contains(t, buf.String(), "abc123")
 		assert.Contains(t, buf.String(), "Aliased session")
 	})
+
+	t.Run("lists the aliases that point at the session", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		aliases := newTestAliasManager(t)
+		seedSession(t, store, "abc123", "2026-02-20", "Aliased session")
+		seedSession(t, store, "def456", "2026-02-21", "Other session")
+		require.NoError(t, aliases.Set("mywork", "abc123"))
+		require.NoError(t, aliases.Set("bugfix", "abc123"))
+		require.NoError(t, aliases.Set("elsewhere", "def456"))
+
+		var buf bytes.Buffer
+		require.NoError(t, showSessionInfo(&buf, store, aliases, "mywork", true, []string{"id", "aliases"}))
+		assert.JSONEq(t, `{"id":"abc123","aliases":["bugfix","mywork"]}`, buf.String())
+	})
+
+	t.Run("empty aliases when none point at the session", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		aliases := newTestAliasManager(t)
+		seedSession(t, store, "abc123", "2026-02-20", "Test session")
+
+		var buf bytes.Buffer
+		require.NoError(t, showSessionInfo(&buf, store, aliases, "abc123", true, nil))
+		assert.Contains(t, buf.String(), `"aliases":[]`)
+	})
+
+	t.Run("compact output", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		aliases := newTestAliasManager(t)
+		seedSession(t, store, "abc123", "2026-02-20", "Test session")
+
+		var buf bytes.Buffer
+		require.NoError(t, showSessionInfo(&buf, store, aliases, "abc123", true, nil))
+		out := strings.TrimSuffix(buf.String(), "\n")
+		assert.NotContains(t, out, "\n")
+		assert.Contains(t, out, `"id":"abc123"`)
+	})
+
+	t.Run("field projection", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		aliases := newTestAliasManager(t)
+		seedSession(t, store, "abc123", "2026-02-20", "Test session")
+
+		var buf bytes.Buffer
+		require.NoError(t, showSessionInfo(&buf, store, aliases, "abc123", true, []string{"id", "title"}))
+		assert.JSONEq(t, `{"id":"abc123","title":"Test session"}`, buf.String())
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		aliases := newTestAliasManager(t)
+		seedSession(t, store, "abc123", "2026-02-20", "Test session")
+
+		var buf bytes.Buffer
+		err := showSessionInfo(&buf, store, aliases, "abc123", false, []string{"id", "bogus"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown session field "bogus"`)
+		assert.Empty(t, buf.String())
+	})
 }
 
 func TestSetSessionAlias(t *testing.T) {
@@ -240,6 +371,64 @@ func TestSessionListCmd(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSessionListCmd_Quiet(t *testing.T) {
+	t.Run("sessions are still listed", func(t *testing.T) {
+		homeDir := setupSessionHome(t)
+		store := session.NewStore(filepath.Join(homeDir, ".claude", "sessions"))
+		seedSession(t, store, "quiet-1", "2026-02-23", "Quiet listing")
+
+		out := captureStdout(t, func() {
+			root := newRootCmd()
+			root.SetArgs([]string{"session", "list", "--quiet"})
+			require.NoError(t, root.Execute())
+		})
+		assert.Contains(t, out, "quiet-1")
+	})
+
+	t.Run("alias confirmation is suppressed", func(t *testing.T) {
+		homeDir := setupSessionHome(t)
+		store := session.NewStore(filepath.Join(homeDir, ".claude", "sessions"))
+		seedSession(t, store, "quiet-2", "2026-02-23", "Quiet alias")
+
+		out := captureStdout(t, func() {
+			root := newRootCmd()
+			root.SetArgs([]string{"session", "alias", "set", "mywork", "quiet-2", "-q"})
+			require.NoError(t, root.Execute())
+		})
+		assert.Empty(t, out)
+	})
+
+	t.Run("bad store path still errors", func(t *testing.T) {
+		t.Setenv("HOME", filepath.Join(t.TempDir(), "bad["))
+
+		root := newRootCmd()
+		root.SetArgs([]string{"session", "list", "-q"})
+		err := root.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "list sessions")
+	})
+}
+
+// captureStdout runs fn with os.Stdout redirected and returns what was written.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+
+	require.NoError(t, w.Close())
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
 func TestSessionInfoCmd(t *testing.T) {
 	homeDir := setupSessionHome(t)
 
@@ -304,3 +493,125 @@ func TestSessionSearchCmd(t *testing.T) {
 	err := cmd.RunE(cmd, []string{"Searchable"})
 	require.NoError(t, err)
 }
+
+func TestSummarizeSessions(t *testing.T) {
+	seedActivity := func(t *testing.T, store *session.Store, id, summary string, files []string) {
+		t.Helper()
+		sess := &session.Session{
+			Version:       "1",
+			ID:            id,
+			Date:          "2026-02-20",
+			Started:       time.Now(),
+			Title:         "Session 10:00",
+			Summary:       summary,
+			FilesModified: files,
+			MessageCount:  4,
+		}
+		require.NoError(t, store.Save(sess))
+	}
+
+	t.Run("single session by alias", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		aliases := newTestAliasManager(t)
+		seedActivity(t, store, "abc123", "", []string{"/repo/main.go"})
+		require.NoError(t, aliases.Set("mywork", "abc123"))
+
+		var buf bytes.Buffer
+		require.NoError(t, summarizeSession(&buf, store, aliases, "mywork"))
+
+		loaded, err := store.Load("abc123")
+		require.NoError(t, err)
+		assert.Equal(t, "Modified main.go over 4 messages.", loaded.Summary)
+		assert.Contains(t, buf.String(), loaded.Summary)
+	})
+
+	t.Run("existing summary is kept", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		seedActivity(t, store, "abc123", "Hand written", []string{"main.go"})
+
+		var buf bytes.Buffer
+		require.NoError(t, summarizeSession(&buf, store, newTestAliasManager(t), "abc123"))
+
+		loaded, err := store.Load("abc123")
+		require.NoError(t, err)
+		assert.Equal(t, "Hand written", loaded.Summary)
+		assert.Contains(t, buf.String(), "already has a summary")
+	})
+
+	t.Run("missing session", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := summarizeSession(&buf, newTestSessionStore(t), newTestAliasManager(t), "ghost")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "session not found")
+	})
+
+	t.Run("all fills only empty summaries", func(t *testing.T) {
+		store := newTestSessionStore(t)
+		seedActivity(t, store, "empty1", "", []string{"a.go"})
+		seedActivity(t, store, "kept", "Hand written", []string{"b.go"})
+		seedSession(t, store, "bare", "2026-02-21", "Session 11:00")
+
+		var buf bytes.Buffer
+		require.NoError(t, summarizeAllSessions(&buf, store))
+		assert.Contains(t, buf.String(), "Summarized 1 session(s)")
+
+		filled, err := store.Load("empty1")
+		require.NoError(t, err)
+		assert.Equal(t, "Modified a.go over 4 messages.", filled.Summary)
+
+		kept, err := store.Load("kept")
+		require.NoError(t, err)
+		assert.Equal(t, "Hand written", kept.Summary)
+
+		bare, err := store.Load("bare")
+		require.NoError(t, err)
+		assert.Empty(t, bare.Summary)
+	})
+}
+
+func TestShowSessionStats(t *testing.T) {
+	now := time.Date(2026, 2, 21, 12, 0, 0, 0, time.UTC)
+
+	newFixtureStore := func(t *testing.T) *session.Store {
+		t.Helper()
+		dir := filepath.Join(t.TempDir(), "sessions")
+		store := session.NewStore(dir)
+		seedSession(t, store, "abc123", "2026-02-20", "Refactor auth module")
+		seedSession(t, store, "def456", "2026-02-21", "Add session tracking")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "2026-02-21-broken.json"), []byte("{"), 0o600))
+		return store
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, showSessionStats(&buf, newFixtureStore(t), now, false))
+
+		output := buf.String()
+		assert.Contains(t, output, "Sessions:               2")
+		assert.Contains(t, output, "  2026-02-20  1")
+		assert.Contains(t, output, "  2026-02-15  0")
+		assert.Contains(t, output, "Most recent:")
+		assert.Contains(t, output, "Skipped 1 unreadable session file(s)")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, showSessionStats(&buf, newFixtureStore(t), now, true))
+
+		var stats session.Stats
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &stats))
+		assert.Equal(t, 2, stats.Total)
+		assert.Equal(t, 1, stats.Skipped)
+		assert.Len(t, stats.PerDay, 7)
+		assert.Equal(t, session.DayCount{Date: "2026-02-21", Sessions: 1}, stats.PerDay[6])
+	})
+
+	t.Run("empty store", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, showSessionStats(&buf, newTestSessionStore(t), now, false))
+
+		assert.Contains(t, buf.String(), "Sessions:               0")
+		assert.NotContains(t, buf.String(), "Most recent:")
+		assert.NotContains(t, buf.String(), "Skipped")
+	})
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
@@ -138,7 +137,7 @@ func addSkip(
 	registry skipregistry.Registry,
 	skipType skipregistry.SkipType,
 ) error {
-	dir, err := os.Getwd()
+	dir, err := workingDir()
 	if err != nil {
 		return fmt.Errorf("get current directory: %w", err)
 	}
@@ -165,7 +164,7 @@ func removeSkip(
 	registry skipregistry.Registry,
 	skipType skipregistry.SkipType,
 ) error {
-	dir, err := os.Getwd()
+	dir, err := workingDir()
 	if err != nil {
 		return fmt.Errorf("get current directory: %w", err)
 	}
@@ -191,7 +190,7 @@ func clearSkips(
 	out *output.Terminal,
 	registry skipregistry.Registry,
 ) error {
-	dir, err := os.Getwd()
+	dir, err := workingDir()
 	if err != nil {
 		return fmt.Errorf("get current directory: %w", err)
 	}
@@ -250,7 +249,7 @@ func showStatus(
 	out *output.Terminal,
 	registry skipregistry.Registry,
 ) error {
-	dir, err := os.Getwd()
+	dir, err := workingDir()
 	if err != nil {
 		return fmt.Errorf("get current directory: %w", err)
 	}
@@ -1,54 +1,208 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/hookcmd"
 	"github.com/riddopic/cc-tools/internal/hooks"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
+// noCooldownFlag is the name of the validate flag that bypasses the cooldown.
+const noCooldownFlag = "no-cooldown"
+
 func newValidateCmd() *cobra.Command {
 	var timeout int
 	var cooldown int
+	var format string
+	var exitZero bool
+	var only string
+	var watch bool
+	var noCooldown bool
+	var projectRoot string
 
 	defaults := config.GetDefaultConfig()
 
 	cmd := &cobra.Command{
-		Use:   "validate",
+		Use:   "validate [dir]",
 		Short: "Run lint and test validation in parallel",
-		Long:  "Discovers and runs lint and test commands in parallel, reporting results. Used as a PostToolUse hook for Claude Code.",
+		Long: "Discovers and runs lint and test commands in parallel, reporting results. Used as a PostToolUse hook for Claude Code.\n\n" +
+			"With --watch, polls dir (default: the current directory) for file changes and validates after each one " +
+			"until interrupted.",
 		Example: `  echo '{"tool_input":{"file_path":"main.go"}}' | cc-tools validate
-  cc-tools validate --timeout 120`,
-		RunE: func(cmd *cobra.Command, _ []string) error {
-			timeout, cooldown = resolveValidateConfig(
-				defaults, timeout, cooldown,
-			)
-			return runValidate(cmd, timeout, cooldown)
+  cc-tools validate --timeout 120
+  cc-tools validate --format json < event.json
+  cc-tools validate --format github < event.json
+  cc-tools validate --exit-zero < event.json
+  cc-tools validate --only lint < event.json
+  cc-tools validate --watch .
+  cc-tools validate --no-cooldown < event.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 && !watch {
+				return errors.New("a directory argument requires --watch")
+			}
+			validateFormat, err := parseValidateFormat(format)
+			if err != nil {
+				return err
+			}
+			onlyPhase, err := parseValidateOnly(only)
+			if err != nil {
+				return err
+			}
+			override, err := timeoutOverride(cmd)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed(noCooldownFlag) {
+				noCooldown = inCI()
+			}
+			rootOverride, err := projectRootOverride(projectRoot)
+			if err != nil {
+				return err
+			}
+
+			// Settings come from the config of the project being validated,
+			// loaded once for the run.
+			buildOptions := func(cfg *config.Values) *hooks.ValidateOptions {
+				opts := validateOptions(cfg)
+				opts.TimeoutSecs, opts.CooldownSecs = resolveValidateConfig(defaults, cfg, timeout, cooldown, override)
+				opts.Format = validateFormat
+				opts.Only = onlyPhase
+				opts.NoCooldown = noCooldown
+				opts.Quiet = quiet
+				opts.ProjectRoot = rootOverride
+				return opts
+			}
+
+			if watch {
+				dir := "."
+				if len(args) > 0 {
+					dir = args[0]
+				}
+				configDir := rootOverride
+				if configDir == "" {
+					configDir = dir
+				}
+				return runValidateWatch(cmd, dir, hooks.DefaultWatchInterval, buildOptions(loadConfigForDir(configDir)))
+			}
+
+			var stdinData []byte
+			if in := cmd.InOrStdin(); !isTerminal(in) {
+				stdinData, _ = readLimited(in, maxInputBytes(), cmd.ErrOrStderr())
+			}
+			configDir := rootOverride
+			if configDir == "" {
+				configDir = eventDir(stdinData)
+			}
+			return runValidate(cmd, stdinData, buildOptions(loadConfigForDir(configDir)), exitZero)
 		},
 	}
 
-	cmd.Flags().IntVarP(&timeout, "timeout", "t", defaults.Validate.Timeout, "timeout in seconds")
+	cmd.Flags().IntVarP(&timeout, timeoutFlag, "t", defaults.Validate.Timeout,
+		"timeout in seconds; overrides the config file and environment")
 	cmd.Flags().IntVarP(&cooldown, "cooldown", "c", defaults.Validate.Cooldown, "cooldown between runs in seconds")
+	cmd.Flags().StringVar(&format, "format", string(hooks.ValidateFormatText),
+		"result format: text, json to also print results to stdout, or github for Actions annotations")
+	cmd.Flags().BoolVar(&exitZero, "exit-zero", false,
+		"always exit 0, still reporting failures; for runners that misread exit code 2")
+	cmd.Flags().StringVar(&only, "only", "", "run only one phase: lint or test")
+	cmd.Flags().BoolVar(&watch, "watch", false, "validate after each file change under dir until interrupted")
+	cmd.Flags().BoolVar(&noCooldown, noCooldownFlag, false,
+		"run every time, without the cooldown lock or result cache (default true when CI is set)")
+	cmd.PersistentFlags().StringVar(&projectRoot, projectRootFlag, "",
+		"use this directory as the project root instead of detecting it (env: "+projectRootEnv+")")
+
+	cmd.AddCommand(newValidateReportCmd())
 
 	return cmd
 }
 
+func newValidateReportCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "List files with outstanding lint, test, or extra command failures",
+		Long: "Lists the files in the current project whose last validation failed, with the command " +
+			"type, when it failed, and the first line of its output. An entry is cleared when the " +
+			"command next passes from the project root, or from the file's directory after that file is edited.",
+		Args:    cobra.NoArgs,
+		Example: "  cc-tools validate report\n  cc-tools validate report --json",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			flag, _ := cmd.Flags().GetString(projectRootFlag)
+			override, err := projectRootOverride(flag)
+			if err != nil {
+				return err
+			}
+			root, err := shared.ResolveProjectRoot(override, "", nil)
+			if err != nil {
+				return fmt.Errorf("find project root: %w", err)
+			}
+			return printValidateReport(cmd.OutOrStdout(), root, hooks.NewFailureLog(root, nil).List(), jsonOutput)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	return cmd
+}
+
+// printValidateReport writes failures to w as a table with paths relative
+// to root, or as an indented JSON array when jsonOutput is set.
+func printValidateReport(w io.Writer, root string, failures []hooks.Failure, jsonOutput bool) error {
+	if jsonOutput {
+		if failures == nil {
+			failures = []hooks.Failure{}
+		}
+		data, err := json.MarshalIndent(failures, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal failures: %w", err)
+		}
+		_, _ = fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	if len(failures) == 0 {
+		_, _ = fmt.Fprintln(w, "No outstanding validation failures.")
+		return nil
+	}
+
+	for _, f := range failures {
+		file := f.File
+		if rel, err := filepath.Rel(root, f.File); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+		_, _ = fmt.Fprintf(w, "%s  %s  %s  %s\n", file, f.Type, f.Timestamp.Local().Format(time.DateTime), f.Command)
+		if f.Summary != "" {
+			_, _ = fmt.Fprintf(w, "    %s\n", f.Summary)
+		}
+	}
+	return nil
+}
+
 // resolveValidateConfig applies config file and env var overrides to the
-// flag defaults. Precedence: env vars > config file > flag defaults.
-func resolveValidateConfig(defaults *config.Values, timeout, cooldown int) (int, int) {
+// flag defaults. Precedence: --timeout > env vars > config file > project
+// .cc-tools.json > flag defaults. timeoutOverride is 0 when --timeout was
+// not given. A nil cfg leaves the flag values in place.
+func resolveValidateConfig(defaults, cfg *config.Values, timeout, cooldown, timeoutOverride int) (int, int) {
 	// Config file overrides flag defaults.
-	mgr := config.NewManager()
-	cfg, err := mgr.GetConfig(context.Background())
-	if err == nil && cfg != nil {
+	if cfg != nil {
 		if timeout == defaults.Validate.Timeout && cfg.Validate.Timeout > 0 {
 			timeout = cfg.Validate.Timeout
 		}
-		if cooldown == defaults.Validate.Cooldown && cfg.Validate.Cooldown > 0 {
+		if cooldown == defaults.Validate.Cooldown && cfg.Validate.Cooldown >= 0 {
 			cooldown = cfg.Validate.Cooldown
 		}
 	}
@@ -65,29 +219,146 @@ func resolveValidateConfig(defaults *config.Values, timeout, cooldown int) (int,
 		}
 	}
 
+	if timeoutOverride > 0 {
+		timeout = timeoutOverride
+	}
+
 	return timeout, cooldown
 }
 
-func runValidate(cmd *cobra.Command, timeout, cooldown int) error {
-	debug := os.Getenv("CLAUDE_HOOKS_DEBUG") == "1"
+// validateOptions returns the validation options set in cfg: the
+// discovery working directory, extra commands, command environment, and
+// skip settings. A nil cfg, as when the config cannot be read, gives the
+// defaults. The timeout and cooldown are left for resolveValidateConfig.
+func validateOptions(cfg *config.Values) *hooks.ValidateOptions {
+	if cfg == nil {
+		cfg = config.GetDefaultConfig()
+	}
+
+	opts := hooks.NewValidateOptions(cfg.Validate.Timeout, cfg.Validate.Cooldown)
+	opts.Debug = os.Getenv("CLAUDE_HOOKS_DEBUG") == "1"
+	if cfg.Discovery.RunFrom == string(hooks.RunFromFileDir) {
+		opts.RunFrom = hooks.RunFromFileDir
+	}
+	opts.ExtraCommands = cfg.Validate.ExtraCommands
+	opts.Env = hooks.EnvPolicy{
+		Clean: cfg.Validate.CleanEnv,
+		Extra: cfg.Validate.Env,
+		NoCI:  !cfg.Validate.InjectCI,
+	}
+	opts.SkipDuringGitOps = cfg.Validate.SkipDuringGitOps
+	opts.WarnDirty = cfg.Validate.WarnDirty
+	opts.CaseInsensitiveSkip = cfg.Validate.CaseInsensitiveSkip
+	return opts
+}
+
+// inCI reports whether the CI environment variable is set to a true value,
+// as CI services do, so that validate runs without a cooldown by default.
+func inCI() bool {
+	ci, err := strconv.ParseBool(os.Getenv("CI"))
+	return err == nil && ci
+}
 
-	var stdinData []byte
-	if stat, _ := os.Stdin.Stat(); (stat.Mode() & os.ModeCharDevice) == 0 {
-		stdinData, _ = io.ReadAll(os.Stdin)
+// parseValidateFormat checks the --format flag value.
+func parseValidateFormat(format string) (hooks.ValidateFormat, error) {
+	switch hooks.ValidateFormat(format) {
+	case hooks.ValidateFormatText, hooks.ValidateFormatJSON, hooks.ValidateFormatGitHub:
+		return hooks.ValidateFormat(format), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q (want %s, %s, or %s)",
+			format, hooks.ValidateFormatText, hooks.ValidateFormatJSON, hooks.ValidateFormatGitHub)
 	}
+}
 
-	exitCode := hooks.ValidateWithSkipCheck(
-		cmd.Context(),
-		stdinData,
-		os.Stdout,
-		os.Stderr,
-		debug,
-		timeout,
-		cooldown,
-	)
+// parseValidateOnly checks the --only flag value. Empty runs every phase.
+func parseValidateOnly(only string) (hooks.CommandType, error) {
+	if only == "" {
+		return "", nil
+	}
 
-	if exitCode != 0 {
+	phase, err := hooks.ParseCommandType(only)
+	if err != nil || (phase != hooks.CommandTypeLint && phase != hooks.CommandTypeTest) {
+		return "", fmt.Errorf("unsupported phase %q (want %s or %s)",
+			only, hooks.CommandTypeLint, hooks.CommandTypeTest)
+	}
+	return phase, nil
+}
+
+func runValidate(cmd *cobra.Command, stdinData []byte, opts *hooks.ValidateOptions, exitZero bool) error {
+	exitCode := hooks.ValidateWithSkipCheck(cmd.Context(), stdinData, cmd.OutOrStdout(), cmd.ErrOrStderr(), opts)
+
+	if exitCode != 0 && !exitZero {
 		return &exitError{code: exitCode}
 	}
 	return nil
 }
+
+// runValidateWatch polls dir for file changes and validates after each
+// batch as if the first changed file had been edited in Claude Code. Skipped
+// files and the cooldown apply as they do for the hook. It returns when the
+// command's context is cancelled, for example by Ctrl-C.
+func runValidateWatch(
+	cmd *cobra.Command,
+	dir string,
+	interval time.Duration,
+	opts *hooks.ValidateOptions,
+) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("resolve watch directory: %w", err)
+	}
+	if info, statErr := os.Stat(root); statErr != nil || !info.IsDir() {
+		return fmt.Errorf("watch directory %s: not a directory", dir)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	stderr := cmd.ErrOrStderr()
+
+	_, _ = fmt.Fprintf(stderr, "Watching %s for changes (Ctrl-C to stop)\n", root)
+	events := hooks.NewPoller(root, opts.CaseInsensitiveSkip).Run(ctx, interval)
+	hooks.Watch(ctx, events, opts.CaseInsensitiveSkip, func(ctx context.Context, path string) {
+		_, _ = fmt.Fprintf(stderr, "Changed: %s\n", path)
+		hooks.ValidateWithSkipCheck(ctx, watchEventInput(path), cmd.OutOrStdout(), stderr, opts)
+	})
+	return nil
+}
+
+// eventDir returns the directory whose project config applies to the hook
+// event in data: the edited file's directory, else the event's cwd. It
+// returns "", the current directory, when data names neither.
+func eventDir(data []byte) string {
+	input, err := hookcmd.ParseInput(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	if filePath := input.GetFilePath(); filePath != "" {
+		return filepath.Dir(filePath)
+	}
+	return input.Cwd
+}
+
+// watchEventInput returns the PostToolUse event validate would receive
+// after path was written.
+func watchEventInput(path string) []byte {
+	toolInput, _ := json.Marshal(map[string]string{"file_path": path})
+	data, _ := json.Marshal(map[string]any{
+		"hook_event_name": hookcmd.EventPostToolUse,
+		"tool_name":       "Write",
+		"tool_input":      json.RawMessage(toolInput),
+	})
+	return data
+}
+
+// isTerminal reports whether r is a character device, such as an
+// interactive terminal, which validate does not read input from.
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
@@ -3,15 +3,23 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/hooks"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 func TestResolveValidateConfig(t *testing.T) {
@@ -23,6 +31,7 @@ func TestResolveValidateConfig(t *testing.T) {
 		cooldown    int
 		envTimeout  string
 		envCooldown string
+		override    int
 		setupConfig func(t *testing.T)
 		wantTimeout int
 		wantCool    int
@@ -88,8 +97,15 @@ func TestResolveValidateConfig(t *testing.T) {
 
 				cfg := &config.Values{
 					Validate: config.ValidateValues{
-						Timeout:  120,
-						Cooldown: 30,
+						Timeout:             120,
+						Cooldown:            30,
+						ExtraCommands:       nil,
+						Env:                 nil,
+						CleanEnv:            false,
+						InjectCI:            true,
+						SkipDuringGitOps:    true,
+						WarnDirty:           false,
+						CaseInsensitiveSkip: false,
 					},
 				}
 				data, err := json.Marshal(cfg)
@@ -101,6 +117,24 @@ func TestResolveValidateConfig(t *testing.T) {
 			wantTimeout: 120,
 			wantCool:    30,
 		},
+		{
+			name:     "zero config cooldown accepted",
+			timeout:  defaults.Validate.Timeout,
+			cooldown: defaults.Validate.Cooldown,
+			setupConfig: func(t *testing.T) {
+				t.Helper()
+				tmpDir := t.TempDir()
+				t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+				configDir := filepath.Join(tmpDir, "cc-tools")
+				require.NoError(t, os.MkdirAll(configDir, 0o750))
+				require.NoError(t, os.WriteFile(
+					filepath.Join(configDir, "config.json"), []byte(`{"validate":{"cooldown":0}}`), 0o600,
+				))
+			},
+			wantTimeout: defaults.Validate.Timeout,
+			wantCool:    0,
+		},
 		{
 			name:     "env var overrides config file",
 			timeout:  defaults.Validate.Timeout,
@@ -115,8 +149,15 @@ func TestResolveValidateConfig(t *testing.T) {
 
 				cfg := &config.Values{
 					Validate: config.ValidateValues{
-						Timeout:  120,
-						Cooldown: 30,
+						Timeout:             120,
+						Cooldown:            30,
+						ExtraCommands:       nil,
+						Env:                 nil,
+						CleanEnv:            false,
+						InjectCI:            true,
+						SkipDuringGitOps:    true,
+						WarnDirty:           false,
+						CaseInsensitiveSkip: false,
 					},
 				}
 				data, err := json.Marshal(cfg)
@@ -129,6 +170,26 @@ func TestResolveValidateConfig(t *testing.T) {
 			wantTimeout: 500,
 			wantCool:    30,
 		},
+		{
+			name:     "timeout flag overrides env var and config file",
+			timeout:  defaults.Validate.Timeout,
+			cooldown: defaults.Validate.Cooldown,
+			setupConfig: func(t *testing.T) {
+				t.Helper()
+				tmpDir := t.TempDir()
+				t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+				configDir := filepath.Join(tmpDir, "cc-tools")
+				require.NoError(t, os.MkdirAll(configDir, 0o750))
+				require.NoError(t, os.WriteFile(
+					filepath.Join(configDir, "config.json"), []byte(`{"validate":{"timeout":120}}`), 0o600,
+				))
+			},
+			envTimeout:  "500",
+			override:    15,
+			wantTimeout: 15,
+			wantCool:    defaults.Validate.Cooldown,
+		},
 	}
 
 	for _, tt := range tests {
@@ -147,9 +208,349 @@ func TestResolveValidateConfig(t *testing.T) {
 				t.Setenv("CC_TOOLS_HOOKS_VALIDATE_COOLDOWN_SECONDS", tt.envCooldown)
 			}
 
-			gotTimeout, gotCooldown := resolveValidateConfig(defaults, tt.timeout, tt.cooldown)
+			gotTimeout, gotCooldown := resolveValidateConfig(defaults, loadConfigForDir(""), tt.timeout, tt.cooldown, tt.override)
 			assert.Equal(t, tt.wantTimeout, gotTimeout)
 			assert.Equal(t, tt.wantCool, gotCooldown)
 		})
 	}
 }
+
+func TestValidateOptions_EnvPolicy(t *testing.T) {
+	t.Run("defaults without a config file", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		assert.Equal(t, hooks.DefaultEnvPolicy(), validateOptions(loadConfigForDir("")).Env)
+	})
+
+	t.Run("reads validate env settings", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+		configDir := filepath.Join(tmpDir, "cc-tools")
+		require.NoError(t, os.MkdirAll(configDir, 0o750))
+		data := []byte(`{"validate":{"env":["GOFLAGS=-mod=mod"],"clean_env":true,"inject_ci":false}}`)
+		require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), data, 0o600))
+
+		want := hooks.EnvPolicy{Clean: true, Extra: []string{"GOFLAGS=-mod=mod"}, NoCI: true}
+		assert.Equal(t, want, validateOptions(loadConfigForDir("")).Env)
+	})
+}
+
+func TestParseValidateFormat(t *testing.T) {
+	for _, format := range []string{"text", "json"} {
+		got, err := parseValidateFormat(format)
+		require.NoError(t, err)
+		assert.Equal(t, hooks.ValidateFormat(format), got)
+	}
+
+	_, err := parseValidateFormat("xml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported format")
+}
+
+func TestParseValidateOnly(t *testing.T) {
+	for _, only := range []string{"", "lint", "test"} {
+		got, err := parseValidateOnly(only)
+		require.NoError(t, err)
+		assert.Equal(t, hooks.CommandType(only), got)
+	}
+
+	_, err := parseValidateOnly("format")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported phase")
+}
+
+func TestTimeoutFlagRejectsNonPositive(t *testing.T) {
+	for _, newCmd := range []func() *cobra.Command{newValidateCmd, newHookCmd} {
+		for _, value := range []string{"0", "-5"} {
+			cmd := newCmd()
+			cmd.SetIn(bytes.NewReader(nil))
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs([]string{"--timeout", value})
+
+			err := cmd.Execute()
+			require.Error(t, err, "%s --timeout %s", cmd.Name(), value)
+			assert.Contains(t, err.Error(), "positive number of seconds")
+		}
+	}
+}
+
+// newValidateCmdRunner writes makefile and main.go to a new project and
+// returns a function that runs the validate command with args on an Edit
+// event for main.go, returning its stderr and error.
+func newValidateCmdRunner(t *testing.T, makefile string) func(args ...string) (string, error) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("CLAUDE_HOOKS_DEBUG", "")
+
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "Makefile"), []byte(makefile), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n"), 0o600))
+
+	input, err := json.Marshal(map[string]any{
+		"hook_event_name": "PostToolUse",
+		"tool_name":       "Edit",
+		"tool_input":      map[string]any{"file_path": filepath.Join(projectDir, "main.go")},
+	})
+	require.NoError(t, err)
+
+	return func(args ...string) (string, error) {
+		var stderr bytes.Buffer
+		cmd := newValidateCmd()
+		cmd.SetIn(bytes.NewReader(input))
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&stderr)
+		cmd.SetArgs(append([]string{"--cooldown", "0"}, args...))
+		execErr := cmd.Execute()
+		return stderr.String(), execErr
+	}
+}
+
+func TestValidateCmd_ExitZero(t *testing.T) {
+	run := newValidateCmdRunner(t, "lint:\n\t@echo 'main.go:1: unused variable'; exit 1\n")
+
+	stderr, err := run()
+	var exitErr *exitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 2, exitErr.code)
+	assert.Contains(t, stderr, "make lint")
+
+	stderr, err = run("--exit-zero")
+	require.NoError(t, err)
+	assert.Contains(t, stderr, "make lint")
+}
+
+func TestValidateCmd_Only(t *testing.T) {
+	run := newValidateCmdRunner(t, "lint:\n\t@exit 1\ntest:\n\t@exit 0\n")
+
+	// A pass also exits 2 so Claude sees the message; the text tells them apart.
+	stderr, _ := run("--only", "test")
+	assert.Contains(t, stderr, "Validations pass", "the failing lint phase should not run")
+	assert.NotContains(t, stderr, "make lint")
+
+	stderr, _ = run("--only", "lint")
+	assert.Contains(t, stderr, "make lint")
+	assert.NotContains(t, stderr, "Validations pass")
+}
+
+func TestValidateCmd_DirRequiresWatch(t *testing.T) {
+	cmd := newValidateCmd()
+	cmd.SetIn(bytes.NewReader(nil))
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{t.TempDir()})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires --watch")
+}
+
+// syncBuffer is a bytes.Buffer safe to write from the watch loop while the
+// test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRunValidateWatch(t *testing.T) {
+	t.Setenv("CLAUDE_HOOKS_DEBUG", "")
+
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "Makefile"),
+		[]byte("lint:\n\t@echo 'main.go:1: unused variable'; exit 1\n"), 0o600))
+	mainGo := filepath.Join(projectDir, "main.go")
+	require.NoError(t, os.WriteFile(mainGo, []byte("package main\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var stderr syncBuffer
+	cmd := newValidateCmd()
+	cmd.SetContext(ctx)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&stderr)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runValidateWatch(cmd, projectDir, 10*time.Millisecond, hooks.NewValidateOptions(30, 0))
+	}()
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(stderr.String(), "Watching")
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NotContains(t, stderr.String(), "make lint", "nothing runs before a change")
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(mainGo, later, later))
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(stderr.String(), "make lint")
+	}, 10*time.Second, 20*time.Millisecond, "a change should trigger validation")
+	assert.Contains(t, stderr.String(), "Changed: "+mainGo)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestValidateCmd_NoCooldown(t *testing.T) {
+	countRuns := func(t *testing.T, ci string, args ...string) int {
+		t.Helper()
+		t.Setenv("CI", ci)
+
+		log := filepath.Join(t.TempDir(), "runs.log")
+		run := newValidateCmdRunner(t, "lint:\n\t@echo run >> "+log+"; exit 1\n")
+		args = append([]string{"--cooldown", "60"}, args...)
+		for range 2 {
+			_, _ = run(args...)
+		}
+
+		data, err := os.ReadFile(log)
+		require.NoError(t, err)
+		return strings.Count(string(data), "run")
+	}
+
+	assert.Equal(t, 1, countRuns(t, ""), "the cooldown skips the second run")
+	assert.Equal(t, 2, countRuns(t, "", "--no-cooldown"), "back-to-back runs both execute")
+	assert.Equal(t, 2, countRuns(t, "true"), "CI turns the cooldown off by default")
+	assert.Equal(t, 1, countRuns(t, "1", "--no-cooldown=false"), "the flag overrides CI")
+}
+
+func TestValidateReport(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("CLAUDE_HOOKS_DEBUG", "")
+	t.Setenv("CI", "")
+
+	projectDir := t.TempDir()
+	makefile := filepath.Join(projectDir, "Makefile")
+	require.NoError(t, os.WriteFile(makefile, []byte("lint:\n\t@echo 'main.go:1: unused variable'; exit 1\n"), 0o600))
+	mainGo := filepath.Join(projectDir, "main.go")
+	require.NoError(t, os.WriteFile(mainGo, []byte("package main\n"), 0o600))
+	t.Chdir(projectDir)
+
+	validate := func() {
+		input, err := json.Marshal(map[string]any{
+			"hook_event_name": "PostToolUse",
+			"tool_name":       "Edit",
+			"tool_input":      map[string]any{"file_path": mainGo},
+		})
+		require.NoError(t, err)
+
+		cmd := newValidateCmd()
+		cmd.SetIn(bytes.NewReader(input))
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--no-cooldown"})
+		_ = cmd.Execute()
+	}
+	report := func() string {
+		var out bytes.Buffer
+		cmd := newValidateCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"report"})
+		require.NoError(t, cmd.Execute())
+		return out.String()
+	}
+
+	validate()
+	out := report()
+	assert.Contains(t, out, "main.go  lint  ")
+	assert.Contains(t, out, "make lint")
+	assert.Contains(t, out, "    main.go:1: unused variable")
+
+	require.NoError(t, os.WriteFile(makefile, []byte("lint:\n\t@exit 0\n"), 0o600))
+	validate()
+	assert.Equal(t, "No outstanding validation failures.\n", report(), "a pass clears the entry")
+}
+
+func TestPrintValidateReport_JSON(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, printValidateReport(&out, "/project", nil, true))
+	assert.Equal(t, "[]\n", out.String())
+}
+
+func TestValidateCmd_Summary(t *testing.T) {
+	run := newValidateCmdRunner(t, "lint:\n\t@exit 0\ntest:\n\t@exit 0\n")
+	saved := quiet
+	t.Cleanup(func() { quiet = saved })
+
+	quiet = false
+	stderr, _ := run()
+	assert.Contains(t, stderr, "Validations pass")
+	assert.Regexp(t, `✓ lint \(make lint\) \(via Makefile\) \d+\.\ds · ✓ test \(make test\) \(via Makefile\) \d+\.\ds`, stderr)
+
+	quiet = true
+	stderr, _ = run()
+	assert.Contains(t, stderr, "Validations pass")
+	assert.NotContains(t, stderr, "make lint", "--quiet drops the summary")
+}
+
+func TestProjectRootOverride(t *testing.T) {
+	flagDir, envDir := t.TempDir(), t.TempDir()
+
+	t.Setenv(projectRootEnv, "")
+	root, err := projectRootOverride("")
+	require.NoError(t, err)
+	assert.Empty(t, root, "detected when neither is set")
+
+	t.Setenv(projectRootEnv, envDir)
+	root, err = projectRootOverride("")
+	require.NoError(t, err)
+	assert.Equal(t, shared.ResolvePath(envDir), root)
+
+	root, err = projectRootOverride(flagDir)
+	require.NoError(t, err)
+	assert.Equal(t, shared.ResolvePath(flagDir), root, "the flag wins over the environment")
+
+	_, err = projectRootOverride(filepath.Join(flagDir, "missing"))
+	require.Error(t, err)
+}
+
+func TestValidateCmd_ProjectConfigOfEditedFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("CLAUDE_HOOKS_DEBUG", "")
+
+	// The project is not the working directory, so its .cc-tools.json is
+	// only seen when config is loaded for the edited file.
+	projectDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                 "module example.com/p\n",
+		"Makefile":               "lint:\n\t@sleep 5\n",
+		"main.go":                "package main\n",
+		config.ProjectConfigFile: `{"validate": {"timeout": 1}}`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(projectDir, name), []byte(content), 0o600))
+	}
+	input, err := json.Marshal(map[string]any{
+		"hook_event_name": "PostToolUse",
+		"tool_name":       "Edit",
+		"tool_input":      map[string]any{"file_path": filepath.Join(projectDir, "main.go")},
+	})
+	require.NoError(t, err)
+
+	var stderr bytes.Buffer
+	cmd := newValidateCmd()
+	cmd.SetIn(bytes.NewReader(input))
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"--cooldown", "0"})
+
+	start := time.Now()
+	require.Error(t, cmd.Execute())
+	assert.Less(t, time.Since(start), 4*time.Second, "the project's 1s timeout applies")
+	assert.Contains(t, stderr.String(), "make lint")
+}
@@ -4,15 +4,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"time"
+
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // logFileName is the name of the compaction log file.
 const logFileName = "compaction-log.txt"
 
-// LogCompaction appends a timestamped compaction entry to the log file
-// in logDir.
-func LogCompaction(logDir string) error {
+// LogCompaction appends a compaction entry to the log file in logDir,
+// timestamped with clock. A nil clock uses the system clock.
+func LogCompaction(logDir string, clock shared.Clock) error {
+	if clock == nil {
+		clock = shared.RealClock{}
+	}
+
 	if err := os.MkdirAll(logDir, 0o750); err != nil {
 		return fmt.Errorf("create log directory: %w", err)
 	}
@@ -26,7 +31,7 @@ func LogCompaction(logDir string) error {
 	defer f.Close()
 
 	entry := fmt.Sprintf("[%s] compaction triggered\n",
-		time.Now().Format("2006-01-02 15:04:05"))
+		clock.Now().Format("2006-01-02 15:04:05"))
 
 	if _, writeErr := f.WriteString(entry); writeErr != nil {
 		return fmt.Errorf("write compaction log entry: %w", writeErr)
@@ -6,11 +6,13 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/riddopic/cc-tools/internal/compact"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 func TestLogCompaction(t *testing.T) {
@@ -44,7 +46,7 @@ func TestLogCompaction(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			err := compact.LogCompaction(logDir)
+			err := compact.LogCompaction(logDir, nil)
 			require.NoError(t, err)
 
 			data, readErr := os.ReadFile(logFile)
@@ -66,7 +68,7 @@ func TestLogCompaction(t *testing.T) {
 func TestLogCompaction_CreatesDirectory(t *testing.T) {
 	logDir := filepath.Join(t.TempDir(), "nested", "log", "dir")
 
-	err := compact.LogCompaction(logDir)
+	err := compact.LogCompaction(logDir, nil)
 	require.NoError(t, err)
 
 	logFile := filepath.Join(logDir, "compaction-log.txt")
@@ -74,3 +76,14 @@ func TestLogCompaction_CreatesDirectory(t *testing.T) {
 	require.NoError(t, readErr)
 	assert.Contains(t, string(data), "compaction triggered")
 }
+
+func TestLogCompaction_UsesClock(t *testing.T) {
+	logDir := t.TempDir()
+	clock := shared.NewFakeClock(time.Date(2026, 3, 14, 15, 9, 26, 0, time.Local))
+
+	require.NoError(t, compact.LogCompaction(logDir, clock))
+
+	data, err := os.ReadFile(filepath.Join(logDir, "compaction-log.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "[2026-03-14 15:09:26] compaction triggered\n", string(data))
+}
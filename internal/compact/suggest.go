@@ -10,43 +10,115 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
+// maxHintRunes bounds the context hint appended to a suggestion.
+const maxHintRunes = 500
+
+// SuggestorOption configures a Suggestor.
+type SuggestorOption func(*Suggestor)
+
+// WithMessageTemplate overrides the suggestion message template. An empty
+// template keeps the default.
+func WithMessageTemplate(tmpl string) SuggestorOption {
+	return func(s *Suggestor) {
+		if tmpl != "" {
+			s.template = tmpl
+		}
+	}
+}
+
+// WithContextHint appends the text returned by hint to each suggestion, on
+// its own line. hint is called only when a suggestion is shown; an error or
+// blank result adds nothing, and long text is cut to a few hundred
+// characters.
+func WithContextHint(hint func() (string, error)) SuggestorOption {
+	return func(s *Suggestor) {
+		s.hint = hint
+	}
+}
+
 // Suggestor tracks tool call counts per session and suggests running /compact
 // when a threshold is reached.
 type Suggestor struct {
 	stateDir         string
 	threshold        int
 	reminderInterval int
+	template         string
+	hint             func() (string, error)
 }
 
 // NewSuggestor creates a new Suggestor that stores per-session counters in
 // stateDir and triggers suggestions at the given threshold and reminder interval.
-func NewSuggestor(stateDir string, threshold, reminderInterval int) *Suggestor {
-	return &Suggestor{
+func NewSuggestor(stateDir string, threshold, reminderInterval int, opts ...SuggestorOption) *Suggestor {
+	s := &Suggestor{
 		stateDir:         stateDir,
 		threshold:        threshold,
 		reminderInterval: reminderInterval,
+		template:         config.DefaultCompactMessageTemplate,
+		hint:             nil,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
 // RecordCall increments the tool call counter for the given session and writes
 // a /compact suggestion to errOut when the threshold or reminder interval is hit.
+// When the state directory cannot be written, the counter is kept in its
+// temp-directory fallback and the switch is reported on errOut once.
 func (s *Suggestor) RecordCall(id hookcmd.SessionID, errOut io.Writer) {
+	if dir, err := shared.WritableDir(s.stateDir, errOut); err == nil {
+		s.stateDir = dir
+	}
+
 	count := s.readCount(id)
 	count++
 	s.writeCount(id, count)
 
 	if s.shouldSuggest(count) {
-		fmt.Fprintf(errOut,
-			"[cc-tools] You have made %d tool calls in this session. "+
-				"Consider running /compact to reduce context usage.\n",
-			count,
-		)
+		message := s.renderMessage(count)
+		if hint := s.contextHint(); hint != "" {
+			message += "\n" + hint
+		}
+		fmt.Fprintln(errOut, message)
 	}
 }
 
+// contextHint returns the trimmed hint text, or "" when there is no hint
+// source or it fails.
+func (s *Suggestor) contextHint() string {
+	if s.hint == nil {
+		return ""
+	}
+
+	text, err := s.hint()
+	if err != nil {
+		return ""
+	}
+
+	text = strings.TrimSpace(text)
+	if runes := []rune(text); len(runes) > maxHintRunes {
+		text = string(runes[:maxHintRunes]) + "..."
+	}
+	return text
+}
+
+// renderMessage fills the {count} and {threshold} placeholders in the
+// message template.
+func (s *Suggestor) renderMessage(count int) string {
+	r := strings.NewReplacer(
+		"{count}", strconv.Itoa(count),
+		"{threshold}", strconv.Itoa(s.threshold),
+	)
+	return r.Replace(s.template)
+}
+
 func (s *Suggestor) shouldSuggest(count int) bool {
 	if count == s.threshold {
 		return true
@@ -78,9 +150,6 @@ func (s *Suggestor) readCount(id hookcmd.SessionID) int {
 }
 
 func (s *Suggestor) writeCount(id hookcmd.SessionID, count int) {
-	// Ensure the state directory exists.
-	_ = os.MkdirAll(s.stateDir, 0o750)
-
 	_ = os.WriteFile(
 		s.counterPath(id),
 		[]byte(strconv.Itoa(count)),
@@ -2,8 +2,10 @@ package compact_test
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -84,6 +86,94 @@ func TestSuggestor_RecordCall(t *testing.T) {
 	}
 }
 
+func TestSuggestor_MessageTemplate(t *testing.T) {
+	t.Run("default template matches built-in message", func(t *testing.T) {
+		s := compact.NewSuggestor(t.TempDir(), 2, 0)
+
+		var buf bytes.Buffer
+		for range 2 {
+			buf.Reset()
+			s.RecordCall("default-session", &buf)
+		}
+
+		assert.Equal(t,
+			"[cc-tools] You have made 2 tool calls in this session. "+
+				"Consider running /compact to reduce context usage.\n",
+			buf.String())
+	})
+
+	t.Run("custom template renders count and threshold", func(t *testing.T) {
+		s := compact.NewSuggestor(t.TempDir(), 3, 2,
+			compact.WithMessageTemplate("{count} Aufrufe (Grenze {threshold}) - bitte /compact"))
+
+		var buf bytes.Buffer
+		for range 5 {
+			buf.Reset()
+			s.RecordCall("custom-session", &buf)
+		}
+
+		assert.Equal(t, "5 Aufrufe (Grenze 3) - bitte /compact\n", buf.String())
+	})
+
+	t.Run("empty template keeps default", func(t *testing.T) {
+		s := compact.NewSuggestor(t.TempDir(), 1, 0, compact.WithMessageTemplate(""))
+
+		var buf bytes.Buffer
+		s.RecordCall("empty-session", &buf)
+
+		assert.Contains(t, buf.String(), "You have made 1 tool calls")
+	})
+}
+
+func TestSuggestor_ContextHint(t *testing.T) {
+	t.Run("hint is appended on its own line", func(t *testing.T) {
+		s := compact.NewSuggestor(t.TempDir(), 1, 0,
+			compact.WithContextHint(func() (string, error) { return "  branch: feature/x\n", nil }))
+
+		var buf bytes.Buffer
+		s.RecordCall("hint-session", &buf)
+
+		assert.Equal(t,
+			"[cc-tools] You have made 1 tool calls in this session. "+
+				"Consider running /compact to reduce context usage.\nbranch: feature/x\n",
+			buf.String())
+	})
+
+	t.Run("hint is not read without a suggestion", func(t *testing.T) {
+		called := false
+		s := compact.NewSuggestor(t.TempDir(), 5, 0,
+			compact.WithContextHint(func() (string, error) { called = true; return "x", nil }))
+
+		var buf bytes.Buffer
+		s.RecordCall("quiet-session", &buf)
+
+		assert.Empty(t, buf.String())
+		assert.False(t, called)
+	})
+
+	t.Run("failing hint adds nothing", func(t *testing.T) {
+		s := compact.NewSuggestor(t.TempDir(), 1, 0,
+			compact.WithContextHint(func() (string, error) { return "partial", errors.New("boom") }))
+
+		var buf bytes.Buffer
+		s.RecordCall("error-session", &buf)
+
+		assert.NotContains(t, buf.String(), "partial")
+		assert.Contains(t, buf.String(), "/compact")
+	})
+
+	t.Run("long hint is truncated", func(t *testing.T) {
+		s := compact.NewSuggestor(t.TempDir(), 1, 0,
+			compact.WithContextHint(func() (string, error) { return strings.Repeat("é", 600), nil }))
+
+		var buf bytes.Buffer
+		s.RecordCall("long-session", &buf)
+
+		assert.Contains(t, buf.String(), strings.Repeat("é", 500)+"...\n")
+		assert.NotContains(t, buf.String(), strings.Repeat("é", 501))
+	})
+}
+
 func TestSuggestor_IndependentSessions(t *testing.T) {
 	stateDir := t.TempDir()
 
@@ -159,3 +249,21 @@ func TestSuggestor_MissingStateDir(t *testing.T) {
 	require.NoError(t, statErr)
 	assert.True(t, info.IsDir())
 }
+
+func TestSuggestor_UnwritableStateDir(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".cache"), nil, 0o600))
+	stateDir := filepath.Join(home, ".cache", "cc-tools")
+
+	var first bytes.Buffer
+	compact.NewSuggestor(stateDir, 2, 0).RecordCall("session-ro", &first)
+	assert.Contains(t, first.String(), "cannot write to "+stateDir)
+	assert.NotContains(t, first.String(), "/compact")
+
+	// A later process finds the count in the fallback and warns no more.
+	var second bytes.Buffer
+	compact.NewSuggestor(stateDir, 2, 0).RecordCall("session-ro", &second)
+	assert.NotContains(t, second.String(), "cannot write")
+	assert.Contains(t, second.String(), "/compact", "the count carried over to the second call")
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// UnknownKeys returns the dotted paths in the config file that do not name a
+// configuration key, sorted. Such entries are ignored when the file is
+// loaded, so they are usually typos or keys from another cc-tools version.
+// Only the top of an unknown subtree is reported. A missing file has no
+// unknown keys.
+func (m *Manager) UnknownKeys(_ context.Context) ([]string, error) {
+	raw, err := m.readRawConfig()
+	if err != nil || raw == nil {
+		return nil, err
+	}
+
+	return unknownPaths(raw), nil
+}
+
+// PruneUnknownKeys removes the entries reported by UnknownKeys from the
+// config file and returns them. Every other entry is kept as written. The
+// file is not rewritten when nothing is unknown.
+func (m *Manager) PruneUnknownKeys(_ context.Context) ([]string, error) {
+	if m.noFile {
+		return nil, ErrConfigFileDisabled
+	}
+
+	raw, err := m.readRawConfig()
+	if err != nil || raw == nil {
+		return nil, err
+	}
+
+	unknown := unknownPaths(raw)
+	if len(unknown) == 0 {
+		return nil, nil
+	}
+
+	for _, path := range unknown {
+		deletePath(raw, strings.Split(path, "."))
+	}
+
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	if writeErr := os.WriteFile(m.configPath, data, 0o600); writeErr != nil {
+		return nil, fmt.Errorf("write config file: %w", writeErr)
+	}
+
+	// Drop the cached values so the next read sees the pruned file.
+	m.config = nil
+
+	return unknown, nil
+}
+
+// readRawConfig reads the config file as a generic JSON object. It returns
+// nil when there is no file to read.
+func (m *Manager) readRawConfig() (map[string]any, error) {
+	if m.noFile {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(m.configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	var raw map[string]any
+	if unmarshalErr := json.Unmarshal(data, &raw); unmarshalErr != nil {
+		return nil, fmt.Errorf("parse config file: %w", unmarshalErr)
+	}
+
+	return raw, nil
+}
+
+// unknownPaths walks raw and returns the sorted dotted paths that are
+// neither a configuration key nor a section leading to one.
+func unknownPaths(raw map[string]any) []string {
+	keys := make(map[string]bool)
+	sections := make(map[string]bool)
+	for _, key := range allKeys() {
+		keys[key] = true
+		for i := range len(key) {
+			if key[i] == '.' {
+				sections[key[:i]] = true
+			}
+		}
+	}
+
+	var unknown []string
+	var walk func(prefix string, node map[string]any)
+	walk = func(prefix string, node map[string]any) {
+		for name, value := range node {
+			path := prefix + name
+			switch {
+			case keys[path]:
+			case sections[path]:
+				// A section holding a non-object value is a type error,
+				// not an unknown key; loading reports nothing for it either.
+				if child, ok := value.(map[string]any); ok {
+					walk(path+".", child)
+				}
+			default:
+				unknown = append(unknown, path)
+			}
+		}
+	}
+	walk("", raw)
+
+	slices.Sort(unknown)
+	return unknown
+}
+
+// deletePath removes the entry at path from raw.
+func deletePath(raw map[string]any, path []string) {
+	node := raw
+	for _, name := range path[:len(path)-1] {
+		child, ok := node[name].(map[string]any)
+		if !ok {
+			return
+		}
+		node = child
+	}
+	delete(node, path[len(path)-1])
+}
@@ -0,0 +1,104 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/config"
+)
+
+const staleConfig = `{
+  "validate": {"timeout": 90, "timout": 30},
+  "notify": {
+    "quite_hours": {"enabled": true},
+    "quiet_hours": {"enabled": true, "start": "22:00"}
+  },
+  "legacy_feature": {"on": true}
+}`
+
+func writeDoctorConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestUnknownKeys(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports bogus keys", func(t *testing.T) {
+		m := config.NewManagerWithPath(writeDoctorConfig(t, staleConfig))
+
+		unknown, err := m.UnknownKeys(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"legacy_feature", "notify.quite_hours", "validate.timout"}, unknown)
+	})
+
+	t.Run("saved defaults have no unknown keys", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		m := config.NewManagerWithPath(path)
+		require.NoError(t, m.EnsureConfig(ctx))
+
+		unknown, err := m.UnknownKeys(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, unknown)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		m := config.NewManagerWithPath(filepath.Join(t.TempDir(), "missing.json"))
+
+		unknown, err := m.UnknownKeys(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, unknown)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		m := config.NewManagerWithPath(writeDoctorConfig(t, "{not json"))
+
+		_, err := m.UnknownKeys(ctx)
+		require.Error(t, err)
+	})
+}
+
+func TestPruneUnknownKeys(t *testing.T) {
+	ctx := context.Background()
+	path := writeDoctorConfig(t, staleConfig)
+	m := config.NewManagerWithPath(path)
+
+	pruned, err := m.PruneUnknownKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy_feature", "notify.quite_hours", "validate.timout"}, pruned)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, map[string]any{
+		"validate": map[string]any{"timeout": float64(90)},
+		"notify": map[string]any{
+			"quiet_hours": map[string]any{"enabled": true, "start": "22:00"},
+		},
+	}, raw, "known entries should be kept as written")
+
+	unknown, err := m.UnknownKeys(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, unknown)
+
+	timeout, _, err := m.GetInt(ctx, "validate.timeout")
+	require.NoError(t, err)
+	assert.Equal(t, 90, timeout)
+}
+
+func TestPruneUnknownKeys_NoConfigFile(t *testing.T) {
+	t.Setenv(config.NoConfigFileEnv, "1")
+	m := config.NewManagerWithPath(writeDoctorConfig(t, staleConfig))
+
+	_, err := m.PruneUnknownKeys(context.Background())
+	require.ErrorIs(t, err, config.ErrConfigFileDisabled)
+}
@@ -1,5 +1,7 @@
 package config
 
+import "io"
+
 // Exports for use by config_test package.
 
 // ExportKeyValidateTimeout returns the unexported keyValidateTimeout constant.
@@ -8,6 +10,27 @@ func ExportKeyValidateTimeout() string { return keyValidateTimeout }
 // ExportKeyValidateCooldown returns the unexported keyValidateCooldown constant.
 func ExportKeyValidateCooldown() string { return keyValidateCooldown }
 
+// ExportKeyValidateExtraCommands returns the unexported key constant.
+func ExportKeyValidateExtraCommands() string { return keyValidateExtraCommands }
+
+// ExportKeyValidateEnv returns the unexported key constant.
+func ExportKeyValidateEnv() string { return keyValidateEnv }
+
+// ExportKeyValidateCleanEnv returns the unexported key constant.
+func ExportKeyValidateCleanEnv() string { return keyValidateCleanEnv }
+
+// ExportKeyValidateInjectCI returns the unexported key constant.
+func ExportKeyValidateInjectCI() string { return keyValidateInjectCI }
+
+// ExportKeyValidateSkipGitOps returns the unexported key constant.
+func ExportKeyValidateSkipGitOps() string { return keyValidateSkipGitOps }
+
+// ExportKeyValidateWarnDirty returns the unexported key constant.
+func ExportKeyValidateWarnDirty() string { return keyValidateWarnDirty }
+
+// ExportKeyValidateFoldSkip returns the unexported key constant.
+func ExportKeyValidateFoldSkip() string { return keyValidateFoldSkip }
+
 // ExportKeyNotificationsNtfyTopic returns the unexported keyNotificationsNtfyTopic constant.
 func ExportKeyNotificationsNtfyTopic() string { return keyNotificationsNtfyTopic }
 
@@ -32,6 +55,9 @@ func ExportKeyNotifyAudioEnabled() string { return keyNotifyAudioEnabled }
 // ExportKeyNotifyAudioDirectory returns the unexported key constant.
 func ExportKeyNotifyAudioDirectory() string { return keyNotifyAudioDirectory }
 
+// ExportKeyNotifyAudioPlayer returns the unexported key constant.
+func ExportKeyNotifyAudioPlayer() string { return keyNotifyAudioPlayer }
+
 // ExportKeyNotifyDesktopEnabled returns the unexported key constant.
 func ExportKeyNotifyDesktopEnabled() string { return keyNotifyDesktopEnabled }
 
@@ -41,6 +67,18 @@ func ExportKeyObserveEnabled() string { return keyObserveEnabled }
 // ExportKeyObserveMaxFileSizeMB returns the unexported key constant.
 func ExportKeyObserveMaxFileSizeMB() string { return keyObserveMaxFileSizeMB }
 
+// ExportKeyObserveAnonymize returns the unexported key constant.
+func ExportKeyObserveAnonymize() string { return keyObserveAnonymize }
+
+// ExportKeyObserveMaxInputBytes returns the unexported key constant.
+func ExportKeyObserveMaxInputBytes() string { return keyObserveMaxInputBytes }
+
+// ExportKeyObserveRetentionDays returns the unexported key constant.
+func ExportKeyObserveRetentionDays() string { return keyObserveRetentionDays }
+
+// ExportKeyObserveDedup returns the unexported key constant.
+func ExportKeyObserveDedup() string { return keyObserveDedup }
+
 // ExportKeyLearningMinSessionLength returns the unexported key constant.
 func ExportKeyLearningMinSessionLength() string { return keyLearningMinSessionLength }
 
@@ -80,15 +118,36 @@ func ExportDefaultNotifyAudioEnabled() bool { return defaultNotifyAudioEnabled }
 // ExportDefaultNotifyAudioDirectory returns the unexported default constant.
 func ExportDefaultNotifyAudioDirectory() string { return defaultNotifyAudioDirectory }
 
+// ExportDefaultNotifyAudioPlayer returns the unexported default constant.
+func ExportDefaultNotifyAudioPlayer() string { return defaultNotifyAudioPlayer }
+
 // ExportDefaultNotifyDesktopEnabled returns the unexported default constant.
 func ExportDefaultNotifyDesktopEnabled() bool { return defaultNotifyDesktopEnabled }
 
+// ExportKeyNotifyMinSeverity returns the unexported key constant.
+func ExportKeyNotifyMinSeverity() string { return keyNotifyMinSeverity }
+
+// ExportDefaultNotifyMinSeverity returns the unexported default constant.
+func ExportDefaultNotifyMinSeverity() string { return defaultNotifyMinSeverity }
+
 // ExportDefaultObserveEnabled returns the unexported default constant.
 func ExportDefaultObserveEnabled() bool { return defaultObserveEnabled }
 
 // ExportDefaultObserveMaxFileSizeMB returns the unexported default constant.
 func ExportDefaultObserveMaxFileSizeMB() int { return defaultObserveMaxFileSizeMB }
 
+// ExportDefaultObserveAnonymize returns the unexported default constant.
+func ExportDefaultObserveAnonymize() bool { return defaultObserveAnonymize }
+
+// ExportDefaultObserveMaxInputBytes returns the unexported default constant.
+func ExportDefaultObserveMaxInputBytes() int { return defaultObserveMaxInputBytes }
+
+// ExportDefaultObserveRetentionDays returns the unexported default constant.
+func ExportDefaultObserveRetentionDays() int { return defaultObserveRetentionDays }
+
+// ExportDefaultObserveDedup returns the unexported default constant.
+func ExportDefaultObserveDedup() bool { return defaultObserveDedup }
+
 // ExportDefaultLearningMinSessionLength returns the unexported default constant.
 func ExportDefaultLearningMinSessionLength() int { return defaultLearningMinSessionLength }
 
@@ -185,6 +244,24 @@ func ExportDefaultInstinctMaxInstincts() int { return defaultInstinctMaxInstinct
 // ExportDefaultInstinctClusterThreshold returns the unexported default constant.
 func ExportDefaultInstinctClusterThreshold() int { return defaultInstinctClusterThreshold }
 
+// ExportKeyFormatOnEdit returns the unexported key constant.
+func ExportKeyFormatOnEdit() string { return keyFormatOnEdit }
+
+// ExportDefaultFormatOnEdit returns the unexported default constant.
+func ExportDefaultFormatOnEdit() bool { return defaultFormatOnEdit }
+
+// ExportKeyCompactMessageTemplate returns the unexported key constant.
+func ExportKeyCompactMessageTemplate() string { return keyCompactMessageTemplate }
+
+// ExportDefaultCompactMessageTemplate returns the unexported default constant.
+func ExportDefaultCompactMessageTemplate() string { return defaultCompactMessageTemplate }
+
+// ExportKeyDiscoveryRunFrom returns the unexported key constant.
+func ExportKeyDiscoveryRunFrom() string { return keyDiscoveryRunFrom }
+
+// ExportDefaultDiscoveryRunFrom returns the unexported default constant.
+func ExportDefaultDiscoveryRunFrom() string { return defaultDiscoveryRunFrom }
+
 // ExportGetDefaultConfig exposes GetDefaultConfig for testing.
 func ExportGetDefaultConfig() *Values { return GetDefaultConfig() }
 
@@ -199,14 +276,32 @@ func ExportGetConfigFilePath() string { return getConfigFilePath() }
 // ExportAllKeys exposes allKeys for testing.
 func ExportAllKeys() []string { return allKeys() }
 
-// NewTestManager creates a Manager with the given config path and values for testing.
+// ExportFileKeys exposes fileKeys for testing.
+func ExportFileKeys(data []byte) (map[string]bool, error) { return fileKeys(data) }
+
+// NewTestManager creates a Manager with the given config path and values for
+// testing. Every key counts as set in the config file, so all are saved.
 func NewTestManager(configPath string, cfg *Values) *Manager {
+	keys := make(map[string]bool)
+	for _, key := range allKeys() {
+		keys[key] = true
+	}
 	return &Manager{
 		configPath: configPath,
 		config:     cfg,
+		noFile:     false,
+		warnOut:    io.Discard,
+		httpClient: nil,
+		remoteURL:  "",
+		fileKeys:   keys,
 	}
 }
 
+// SetManagerWarnOutput redirects the manager's warnings to w.
+func SetManagerWarnOutput(m *Manager, w io.Writer) {
+	m.warnOut = w
+}
+
 // ManagerConfig returns the unexported config field from a Manager.
 func ManagerConfig(m *Manager) *Values {
 	return m.config
@@ -236,3 +331,24 @@ func ManagerEnsureDefaults(m *Manager) {
 func ManagerConvertFromMap(m *Manager, mapConfig map[string]any) {
 	m.convertFromMap(mapConfig)
 }
+
+// ExportKeyCompactSnapshot returns the unexported key constant.
+func ExportKeyCompactSnapshot() string { return keyCompactSnapshot }
+
+// ExportKeyCompactStateDir returns the unexported key constant.
+func ExportKeyCompactStateDir() string { return keyCompactStateDir }
+
+// ExportKeyCompactContextHint returns the unexported key constant.
+func ExportKeyCompactContextHint() string { return keyCompactContextHint }
+
+// ExportKeySessionContextSources returns the unexported key constant.
+func ExportKeySessionContextSources() string { return keySessionContextSources }
+
+// ExportKeyHookMaxInputBytes returns the unexported key constant.
+func ExportKeyHookMaxInputBytes() string { return keyHookMaxInputBytes }
+
+// ExportDefaultHookMaxInputBytes returns the unexported defaultHookMaxInputBytes constant.
+func ExportDefaultHookMaxInputBytes() int { return defaultHookMaxInputBytes }
+
+// ExportKeyHookSessionStartMinInterval returns the unexported key constant.
+func ExportKeyHookSessionStartMinInterval() string { return keyHookSessionStartMinInterval }
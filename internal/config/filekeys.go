@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// fileKeys parses the config file in data, in either the structured or the
+// legacy format, and returns the set of configuration keys it contains,
+// whatever their values.
+func fileKeys(data []byte) (map[string]bool, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse config file: %w", err)
+	}
+
+	known := make(map[string]bool)
+	for _, key := range allKeys() {
+		known[key] = true
+	}
+
+	keys := make(map[string]bool)
+	collectKeys(raw, "", known, keys)
+	return keys, nil
+}
+
+// collectKeys adds to keys every path in node, joined with dots under
+// prefix, that names a known configuration key.
+func collectKeys(node map[string]any, prefix string, known, keys map[string]bool) {
+	for name, value := range node {
+		path := prefix + name
+		if known[path] {
+			keys[path] = true
+			continue
+		}
+		if child, ok := value.(map[string]any); ok {
+			collectKeys(child, path+".", known, keys)
+		}
+	}
+}
+
+// marshalKeys renders the given keys of values as an indented config file.
+// Keys not in the set are left out, so the file records only what the user
+// chose and every other key keeps taking its value from the overlays and
+// defaults.
+func marshalKeys(values *Values, keys map[string]bool) ([]byte, error) {
+	full, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+
+	var source map[string]any
+	if unmarshalErr := json.Unmarshal(full, &source); unmarshalErr != nil {
+		return nil, fmt.Errorf("marshal config: %w", unmarshalErr)
+	}
+
+	sparse := make(map[string]any)
+	for key := range keys {
+		value, ok := lookupPath(source, strings.Split(key, "."))
+		if ok {
+			setPath(sparse, strings.Split(key, "."), value)
+		}
+	}
+
+	data, err := json.MarshalIndent(sparse, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	return data, nil
+}
+
+// lookupPath returns the value at path in node.
+func lookupPath(node map[string]any, path []string) (any, bool) {
+	value, ok := node[path[0]]
+	if !ok || len(path) == 1 {
+		return value, ok
+	}
+	child, ok := value.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return lookupPath(child, path[1:])
+}
+
+// setPath stores value at path in node, creating the maps along the way.
+func setPath(node map[string]any, path []string, value any) {
+	if len(path) == 1 {
+		node[path[0]] = value
+		return
+	}
+	child, ok := node[path[0]].(map[string]any)
+	if !ok {
+		child = make(map[string]any)
+		node[path[0]] = child
+	}
+	setPath(child, path[1:], value)
+}
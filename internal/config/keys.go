@@ -1,25 +1,53 @@
 package config
 
-import "strconv"
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultCompactMessageTemplate is the /compact suggestion shown when no
+// custom template is configured. {count} and {threshold} are replaced with
+// the session's tool call count and the configured threshold.
+const DefaultCompactMessageTemplate = "[cc-tools] You have made {count} tool calls in this session. " +
+	"Consider running /compact to reduce context usage."
 
 // Configuration keys.
 const (
 	keyValidateTimeout        = "validate.timeout"
 	keyValidateCooldown       = "validate.cooldown"
+	keyValidateExtraCommands  = "validate.extra_commands"
+	keyValidateEnv            = "validate.env"
+	keyValidateCleanEnv       = "validate.clean_env"
+	keyValidateInjectCI       = "validate.inject_ci"
+	keyValidateSkipGitOps     = "validate.skip_during_git_ops"
+	keyValidateWarnDirty      = "validate.warn_dirty"
+	keyValidateFoldSkip       = "validate.case_insensitive_skip"
 	keyNotificationsNtfyTopic = "notifications.ntfy_topic"
 
 	keyCompactThreshold        = "compact.threshold"
 	keyCompactReminderInterval = "compact.reminder_interval"
+	keyCompactMessageTemplate  = "compact.message_template"
+	keyCompactSnapshot         = "compact.snapshot"
+	keyCompactStateDir         = "compact.state_dir"
+	keyCompactContextHint      = "compact.context_hint"
 
 	keyNotifyQuietHoursEnabled = "notify.quiet_hours.enabled"
 	keyNotifyQuietHoursStart   = "notify.quiet_hours.start"
 	keyNotifyQuietHoursEnd     = "notify.quiet_hours.end"
 	keyNotifyAudioEnabled      = "notify.audio.enabled"
 	keyNotifyAudioDirectory    = "notify.audio.directory"
+	keyNotifyAudioPlayer       = "notify.audio.player"
 	keyNotifyDesktopEnabled    = "notify.desktop.enabled"
+	keyNotifyMinSeverity       = "notify.min_severity"
 
 	keyObserveEnabled       = "observe.enabled"
 	keyObserveMaxFileSizeMB = "observe.max_file_size_mb"
+	keyObserveAnonymize     = "observe.anonymize_paths"
+	keyObserveMaxInputBytes = "observe.max_input_bytes"
+	keyObserveRetentionDays = "observe.retention_days"
+	keyObserveDedup         = "observe.dedup"
 
 	keyLearningMinSessionLength  = "learning.min_session_length"
 	keyLearningLearnedSkillsPath = "learning.learned_skills_path"
@@ -44,24 +72,48 @@ const (
 	keyInstinctDecayRate        = "instinct.decay_rate"
 	keyInstinctMaxInstincts     = "instinct.max_instincts"
 	keyInstinctClusterThreshold = "instinct.cluster_threshold"
+
+	keyFormatOnEdit = "format.on_edit"
+
+	keyDiscoveryRunFrom = "discovery.run_from"
+
+	keySessionContextSources = "session.context_sources"
+
+	keyHookMaxInputBytes           = "hook.max_input_bytes"
+	keyHookSessionStartMinInterval = "hook.session_start_min_interval_seconds"
 )
 
 const (
-	defaultValidateTimeout  = 60
-	defaultValidateCooldown = 5
+	defaultValidateTimeout    = 60
+	defaultValidateCooldown   = 5
+	defaultValidateCleanEnv   = false
+	defaultValidateInjectCI   = true
+	defaultValidateSkipGitOps = true
+	defaultValidateWarnDirty  = false
+	defaultValidateFoldSkip   = false
 
 	defaultCompactThreshold        = 50
 	defaultCompactReminderInterval = 25
+	defaultCompactSnapshot         = false
+	defaultCompactStateDir         = ""
+	defaultCompactContextHint      = ""
+	defaultCompactMessageTemplate  = DefaultCompactMessageTemplate
 
 	defaultNotifyQuietHoursEnabled = true
 	defaultNotifyQuietHoursStart   = "21:00"
 	defaultNotifyQuietHoursEnd     = "07:30"
 	defaultNotifyAudioEnabled      = true
 	defaultNotifyAudioDirectory    = "~/.claude/audio"
+	defaultNotifyAudioPlayer       = "auto"
 	defaultNotifyDesktopEnabled    = true
+	defaultNotifyMinSeverity       = "info"
 
 	defaultObserveEnabled       = true
 	defaultObserveMaxFileSizeMB = 10
+	defaultObserveAnonymize     = false
+	defaultObserveMaxInputBytes = 4096
+	defaultObserveRetentionDays = 0
+	defaultObserveDedup         = false
 
 	defaultLearningMinSessionLength  = 10
 	defaultLearningLearnedSkillsPath = ".claude/skills/learned"
@@ -86,14 +138,326 @@ const (
 	defaultInstinctDecayRate        = 0.02
 	defaultInstinctMaxInstincts     = 100
 	defaultInstinctClusterThreshold = 3
+
+	defaultFormatOnEdit = false
+
+	defaultDiscoveryRunFrom = "project_root"
+
+	defaultHookMaxInputBytes           = 4 << 20
+	defaultHookSessionStartMinInterval = 0
 )
 
+// ValueType is the value type of a configuration key.
+type ValueType string
+
+// Value types accepted by Manager.SetTyped.
+const (
+	TypeString ValueType = "string"
+	TypeInt    ValueType = "int"
+	TypeFloat  ValueType = "float"
+	TypeBool   ValueType = "bool"
+	TypeList   ValueType = "list"
+)
+
+// ParseValueType converts a type name such as "int" into a ValueType.
+func ParseValueType(name string) (ValueType, error) {
+	switch t := ValueType(name); t {
+	case TypeString, TypeInt, TypeFloat, TypeBool, TypeList:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown value type %q (want string, int, float, bool, or list)", name)
+	}
+}
+
+// keyMetadata describes the value type and purpose of each configuration key.
+type keyMetadata struct {
+	valueType   ValueType
+	description string
+}
+
+// keyMetadataTable holds metadata for every key returned by allKeys.
+var keyMetadataTable = map[string]keyMetadata{
+	keyValidateTimeout: {
+		valueType:   TypeInt,
+		description: "Validation timeout in seconds",
+	},
+	keyValidateCooldown: {
+		valueType:   TypeInt,
+		description: "Cooldown between validation runs in seconds",
+	},
+	keyValidateExtraCommands: {
+		valueType:   TypeList,
+		description: "Extra commands run from the project root after lint and test",
+	},
+	keyValidateEnv: {
+		valueType:   TypeList,
+		description: "KEY=VALUE environment entries added to validation commands",
+	},
+	keyValidateCleanEnv: {
+		valueType:   TypeBool,
+		description: "Run validation commands with a minimal environment instead of inheriting it",
+	},
+	keyValidateInjectCI: {
+		valueType:   TypeBool,
+		description: "Set CI=1 for validation commands",
+	},
+	keyValidateSkipGitOps: {
+		valueType:   TypeBool,
+		description: "Skip validation while a git rebase or merge is in progress",
+	},
+	keyValidateWarnDirty: {
+		valueType:   TypeBool,
+		description: "Note uncommitted changes in the working tree after validation passes",
+	},
+	keyValidateFoldSkip: {
+		valueType:   TypeBool,
+		description: "Match skipped directories and test or generated file suffixes regardless of case",
+	},
+	keyNotificationsNtfyTopic: {
+		valueType:   TypeString,
+		description: "ntfy.sh topic for push notifications",
+	},
+	keyCompactThreshold: {
+		valueType:   TypeInt,
+		description: "Tool-call count that triggers a compact suggestion",
+	},
+	keyCompactReminderInterval: {
+		valueType:   TypeInt,
+		description: "Tool calls between subsequent compact reminders",
+	},
+	keyCompactMessageTemplate: {
+		valueType:   TypeString,
+		description: "Suggestion text; {count} is required and {threshold} is optional",
+	},
+	keyCompactSnapshot: {
+		valueType:   TypeBool,
+		description: "Save a snapshot of the session before each compaction",
+	},
+	keyCompactStateDir: {
+		valueType:   TypeString,
+		description: "Directory for tool-call counters; empty uses $XDG_CACHE_HOME/cc-tools/compact",
+	},
+	keyCompactContextHint: {
+		valueType:   TypeString,
+		description: "File to read, or command to run, for a hint appended to the suggestion",
+	},
+	keyNotifyQuietHoursEnabled: {
+		valueType:   TypeBool,
+		description: "Suppress notifications during quiet hours",
+	},
+	keyNotifyQuietHoursStart: {
+		valueType:   TypeString,
+		description: "Quiet hours start time (HH:MM, 24-hour format)",
+	},
+	keyNotifyQuietHoursEnd: {
+		valueType:   TypeString,
+		description: "Quiet hours end time (HH:MM, 24-hour format)",
+	},
+	keyNotifyAudioEnabled: {
+		valueType:   TypeBool,
+		description: "Enable audio notification sounds",
+	},
+	keyNotifyAudioDirectory: {
+		valueType:   TypeString,
+		description: "Directory containing MP3 files, or a comma-separated list of directories",
+	},
+	keyNotifyAudioPlayer: {
+		valueType:   TypeString,
+		description: "Audio player command, or auto to pick afplay, paplay, aplay, or powershell by OS",
+	},
+	keyNotifyDesktopEnabled: {
+		valueType:   TypeBool,
+		description: "Enable macOS desktop notifications",
+	},
+	keyNotifyMinSeverity: {
+		valueType:   TypeString,
+		description: "Lowest notification severity to send: info, warning, or attention",
+	},
+	keyObserveEnabled: {
+		valueType:   TypeBool,
+		description: "Enable tool-use observation logging",
+	},
+	keyObserveMaxFileSizeMB: {
+		valueType:   TypeInt,
+		description: "Max observation file size in MB before rotation",
+	},
+	keyObserveAnonymize: {
+		valueType:   TypeBool,
+		description: "Replace home and project paths in recorded tool input, output, and errors with ~ and $PROJECT",
+	},
+	keyObserveMaxInputBytes: {
+		valueType:   TypeInt,
+		description: "Truncate recorded tool input longer than this many bytes (0 for unlimited)",
+	},
+	keyObserveRetentionDays: {
+		valueType:   TypeInt,
+		description: "Purge observations older than this many days at session start (0 to keep forever)",
+	},
+	keyObserveDedup: {
+		valueType:   TypeBool,
+		description: "Skip events identical to the session's previous event when retried within a few seconds",
+	},
+	keyLearningMinSessionLength: {
+		valueType:   TypeInt,
+		description: "Minimum session length (in tool calls) for learning extraction",
+	},
+	keyLearningLearnedSkillsPath: {
+		valueType:   TypeString,
+		description: "Path for learned skill files",
+	},
+	keyPreCommitEnabled: {
+		valueType:   TypeBool,
+		description: "Remind to run checks before git commit",
+	},
+	keyPreCommitCommand: {
+		valueType:   TypeString,
+		description: "Command to suggest before commits",
+	},
+	keyPackageManagerPreferred: {
+		valueType:   TypeString,
+		description: "Preferred package manager (overrides auto-detection)",
+	},
+	keyDriftEnabled: {
+		valueType:   TypeBool,
+		description: "Enable drift detection on prompts",
+	},
+	keyDriftMinEdits: {
+		valueType:   TypeInt,
+		description: "Minimum prompt count before checking for drift",
+	},
+	keyDriftThreshold: {
+		valueType:   TypeFloat,
+		description: "Keyword overlap ratio below which drift is flagged",
+	},
+	keyStopReminderEnabled: {
+		valueType:   TypeBool,
+		description: "Enable periodic session reminders",
+	},
+	keyStopReminderInterval: {
+		valueType:   TypeInt,
+		description: "Responses between reminders",
+	},
+	keyStopReminderWarnAt: {
+		valueType:   TypeInt,
+		description: "Response count that triggers a strong wrap-up warning",
+	},
+	keyInstinctPersonalPath: {
+		valueType:   TypeString,
+		description: "Directory for personal instincts",
+	},
+	keyInstinctInheritedPath: {
+		valueType:   TypeString,
+		description: "Directory for imported instincts",
+	},
+	keyInstinctMinConfidence: {
+		valueType:   TypeFloat,
+		description: "Minimum confidence for instinct activation",
+	},
+	keyInstinctAutoApprove: {
+		valueType:   TypeFloat,
+		description: "Confidence threshold for automatic approval",
+	},
+	keyInstinctDecayRate: {
+		valueType:   TypeFloat,
+		description: "Confidence decay per week without reinforcement",
+	},
+	keyInstinctMaxInstincts: {
+		valueType:   TypeInt,
+		description: "Maximum number of instincts to retain",
+	},
+	keyInstinctClusterThreshold: {
+		valueType:   TypeInt,
+		description: "Minimum instincts in a cluster for evolve analysis",
+	},
+	keyFormatOnEdit: {
+		valueType:   TypeBool,
+		description: "Format edited files after each tool call",
+	},
+	keyDiscoveryRunFrom: {
+		valueType:   TypeString,
+		description: "Working directory for discovered commands: project_root or file_dir",
+	},
+	keySessionContextSources: {
+		valueType:   TypeList,
+		description: "Files or cmd:<command> entries whose output is added as context at session start",
+	},
+	keyHookMaxInputBytes: {
+		valueType:   TypeInt,
+		description: "Maximum bytes read from stdin by hook and validate; larger payloads are ignored with a warning",
+	},
+	keyHookSessionStartMinInterval: {
+		valueType:   TypeInt,
+		description: "Seconds after a SessionStart in the same directory during which another one skips the heavy handlers (0 = off)",
+	},
+}
+
+// sensitiveKeys are the keys whose values run commands, read files into
+// the session, reach the environment of validation commands, send session
+// details elsewhere, or approve changes on the user's behalf. config set
+// asks before changing them.
+var sensitiveKeys = map[string]bool{
+	keyValidateExtraCommands:  true,
+	keyValidateEnv:            true,
+	keyValidateCleanEnv:       true,
+	keyNotificationsNtfyTopic: true,
+	keyCompactContextHint:     true,
+	keyNotifyAudioPlayer:      true,
+	keyInstinctAutoApprove:    true,
+	keySessionContextSources:  true,
+}
+
+// IsSensitive reports whether key is security-relevant, so that a change to
+// it should be confirmed.
+func IsSensitive(key string) bool {
+	return sensitiveKeys[key]
+}
+
+// KeyType returns the value type of a configuration key.
+func KeyType(key string) (ValueType, bool) {
+	meta, ok := keyMetadataTable[key]
+	return meta.valueType, ok
+}
+
+// KeyInfo describes a configuration key for display.
+type KeyInfo struct {
+	Key         string    `json:"key"`
+	Type        ValueType `json:"type"`
+	Default     string    `json:"default"`
+	Description string    `json:"description"`
+}
+
+// Keys returns metadata for every configuration key, sorted by key.
+func Keys() []KeyInfo {
+	defaults := GetDefaultConfig()
+	keys := allKeys()
+	sort.Strings(keys)
+
+	infos := make([]KeyInfo, 0, len(keys))
+	for _, key := range keys {
+		meta := keyMetadataTable[key]
+		infos = append(infos, KeyInfo{
+			Key:         key,
+			Type:        meta.valueType,
+			Default:     getDefaultValue(defaults, key),
+			Description: meta.description,
+		})
+	}
+	return infos
+}
+
 // GetDefaultConfig returns the default configuration values.
 func GetDefaultConfig() *Values {
 	return &Values{
 		Validate: ValidateValues{
-			Timeout:  defaultValidateTimeout,
-			Cooldown: defaultValidateCooldown,
+			Timeout:             defaultValidateTimeout,
+			Cooldown:            defaultValidateCooldown,
+			ExtraCommands:       nil,
+			Env:                 nil,
+			CleanEnv:            defaultValidateCleanEnv,
+			InjectCI:            defaultValidateInjectCI,
+			SkipDuringGitOps:    defaultValidateSkipGitOps,
+			WarnDirty:           defaultValidateWarnDirty,
+			CaseInsensitiveSkip: defaultValidateFoldSkip,
 		},
 		Notifications: NotificationsValues{
 			NtfyTopic: "",
@@ -101,6 +465,10 @@ func GetDefaultConfig() *Values {
 		Compact: CompactValues{
 			Threshold:        defaultCompactThreshold,
 			ReminderInterval: defaultCompactReminderInterval,
+			MessageTemplate:  defaultCompactMessageTemplate,
+			Snapshot:         defaultCompactSnapshot,
+			StateDir:         defaultCompactStateDir,
+			ContextHint:      defaultCompactContextHint,
 		},
 		Notify: NotifyValues{
 			QuietHours: QuietHoursValues{
@@ -111,14 +479,20 @@ func GetDefaultConfig() *Values {
 			Audio: AudioValues{
 				Enabled:   defaultNotifyAudioEnabled,
 				Directory: defaultNotifyAudioDirectory,
+				Player:    defaultNotifyAudioPlayer,
 			},
 			Desktop: DesktopValues{
 				Enabled: defaultNotifyDesktopEnabled,
 			},
+			MinSeverity: defaultNotifyMinSeverity,
 		},
 		Observe: ObserveValues{
 			Enabled:       defaultObserveEnabled,
 			MaxFileSizeMB: defaultObserveMaxFileSizeMB,
+			Anonymize:     defaultObserveAnonymize,
+			MaxInputBytes: defaultObserveMaxInputBytes,
+			RetentionDays: defaultObserveRetentionDays,
+			Dedup:         defaultObserveDedup,
 		},
 		Learning: LearningValues{
 			MinSessionLength:  defaultLearningMinSessionLength,
@@ -150,6 +524,19 @@ func GetDefaultConfig() *Values {
 			MaxInstincts:     defaultInstinctMaxInstincts,
 			ClusterThreshold: defaultInstinctClusterThreshold,
 		},
+		Format: FormatValues{
+			OnEdit: defaultFormatOnEdit,
+		},
+		Discovery: DiscoveryValues{
+			RunFrom: defaultDiscoveryRunFrom,
+		},
+		Session: SessionValues{
+			ContextSources: nil,
+		},
+		Hook: HookValues{
+			MaxInputBytes:           defaultHookMaxInputBytes,
+			SessionStartMinInterval: defaultHookSessionStartMinInterval,
+		},
 	}
 }
 
@@ -160,12 +547,34 @@ func getDefaultValue(defaults *Values, key string) string {
 		return strconv.Itoa(defaults.Validate.Timeout)
 	case keyValidateCooldown:
 		return strconv.Itoa(defaults.Validate.Cooldown)
+	case keyValidateExtraCommands:
+		return strings.Join(defaults.Validate.ExtraCommands, ",")
+	case keyValidateEnv:
+		return strings.Join(defaults.Validate.Env, ",")
+	case keyValidateCleanEnv:
+		return strconv.FormatBool(defaults.Validate.CleanEnv)
+	case keyValidateInjectCI:
+		return strconv.FormatBool(defaults.Validate.InjectCI)
+	case keyValidateSkipGitOps:
+		return strconv.FormatBool(defaults.Validate.SkipDuringGitOps)
+	case keyValidateWarnDirty:
+		return strconv.FormatBool(defaults.Validate.WarnDirty)
+	case keyValidateFoldSkip:
+		return strconv.FormatBool(defaults.Validate.CaseInsensitiveSkip)
 	case keyNotificationsNtfyTopic:
 		return defaults.Notifications.NtfyTopic
 	case keyCompactThreshold:
 		return strconv.Itoa(defaults.Compact.Threshold)
 	case keyCompactReminderInterval:
 		return strconv.Itoa(defaults.Compact.ReminderInterval)
+	case keyCompactMessageTemplate:
+		return defaults.Compact.MessageTemplate
+	case keyCompactSnapshot:
+		return strconv.FormatBool(defaults.Compact.Snapshot)
+	case keyCompactStateDir:
+		return defaults.Compact.StateDir
+	case keyCompactContextHint:
+		return defaults.Compact.ContextHint
 	case keyNotifyQuietHoursEnabled:
 		return strconv.FormatBool(defaults.Notify.QuietHours.Enabled)
 	case keyNotifyQuietHoursStart:
@@ -176,12 +585,24 @@ func getDefaultValue(defaults *Values, key string) string {
 		return strconv.FormatBool(defaults.Notify.Audio.Enabled)
 	case keyNotifyAudioDirectory:
 		return defaults.Notify.Audio.Directory
+	case keyNotifyAudioPlayer:
+		return defaults.Notify.Audio.Player
 	case keyNotifyDesktopEnabled:
 		return strconv.FormatBool(defaults.Notify.Desktop.Enabled)
+	case keyNotifyMinSeverity:
+		return defaults.Notify.MinSeverity
 	case keyObserveEnabled:
 		return strconv.FormatBool(defaults.Observe.Enabled)
 	case keyObserveMaxFileSizeMB:
 		return strconv.Itoa(defaults.Observe.MaxFileSizeMB)
+	case keyObserveAnonymize:
+		return strconv.FormatBool(defaults.Observe.Anonymize)
+	case keyObserveMaxInputBytes:
+		return strconv.Itoa(defaults.Observe.MaxInputBytes)
+	case keyObserveRetentionDays:
+		return strconv.Itoa(defaults.Observe.RetentionDays)
+	case keyObserveDedup:
+		return strconv.FormatBool(defaults.Observe.Dedup)
 	case keyLearningMinSessionLength:
 		return strconv.Itoa(defaults.Learning.MinSessionLength)
 	case keyLearningLearnedSkillsPath:
@@ -206,17 +627,34 @@ func allKeys() []string {
 	return []string{
 		keyValidateTimeout,
 		keyValidateCooldown,
+		keyValidateExtraCommands,
+		keyValidateEnv,
+		keyValidateCleanEnv,
+		keyValidateInjectCI,
+		keyValidateSkipGitOps,
+		keyValidateWarnDirty,
+		keyValidateFoldSkip,
 		keyNotificationsNtfyTopic,
 		keyCompactThreshold,
 		keyCompactReminderInterval,
+		keyCompactMessageTemplate,
+		keyCompactSnapshot,
+		keyCompactStateDir,
+		keyCompactContextHint,
 		keyNotifyQuietHoursEnabled,
 		keyNotifyQuietHoursStart,
 		keyNotifyQuietHoursEnd,
 		keyNotifyAudioEnabled,
 		keyNotifyAudioDirectory,
+		keyNotifyAudioPlayer,
 		keyNotifyDesktopEnabled,
+		keyNotifyMinSeverity,
 		keyObserveEnabled,
 		keyObserveMaxFileSizeMB,
+		keyObserveAnonymize,
+		keyObserveMaxInputBytes,
+		keyObserveRetentionDays,
+		keyObserveDedup,
 		keyLearningMinSessionLength,
 		keyLearningLearnedSkillsPath,
 		keyPreCommitEnabled,
@@ -235,5 +673,10 @@ func allKeys() []string {
 		keyInstinctDecayRate,
 		keyInstinctMaxInstincts,
 		keyInstinctClusterThreshold,
+		keyFormatOnEdit,
+		keyDiscoveryRunFrom,
+		keySessionContextSources,
+		keyHookMaxInputBytes,
+		keyHookSessionStartMinInterval,
 	}
 }
@@ -3,17 +3,50 @@ package config
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
+
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+var (
+	// ErrUnknownKey is returned when a configuration key does not exist.
+	ErrUnknownKey = errors.New("unknown configuration key")
+	// ErrInvalidValue is returned when a value cannot be assigned to a key.
+	ErrInvalidValue = errors.New("invalid configuration value")
+	// ErrConfigFileDisabled is returned by writes when NoConfigFileEnv is set.
+	ErrConfigFileDisabled = errors.New("config file disabled")
 )
 
+// NoConfigFileEnv, when set to "1", runs the manager without a config file:
+// nothing is read from or written to disk, settings come from defaults, and
+// writes fail with ErrConfigFileDisabled.
+const NoConfigFileEnv = "CC_TOOLS_NO_CONFIG_FILE"
+
+// legacyWarnedFile is the marker, kept next to the config file, recording
+// that the legacy format warning has been shown.
+const legacyWarnedFile = ".legacy-config-warned"
+
 // Manager handles configuration read/write operations.
 type Manager struct {
 	configPath string
 	config     *Values
+	noFile     bool
+	warnOut    io.Writer
+	httpClient HTTPClient
+	remoteURL  string
+	// fileKeys holds the keys the config file sets, plus those changed
+	// since it was read. Only these are saved, and only these take
+	// precedence over the project file and the remote overlay.
+	fileKeys map[string]bool
 }
 
 // Info contains information about a configuration value.
@@ -23,32 +56,39 @@ type Info struct {
 }
 
 // NewManager creates a new configuration manager.
-func NewManager() *Manager {
-	return &Manager{
-		configPath: getConfigFilePath(),
-		config:     nil,
-	}
+func NewManager(opts ...ManagerOption) *Manager {
+	return NewManagerWithPath(getConfigFilePath(), opts...)
 }
 
 // NewManagerWithPath creates a new configuration manager with a specific config file path.
-func NewManagerWithPath(path string) *Manager {
-	return &Manager{
+func NewManagerWithPath(path string, opts ...ManagerOption) *Manager {
+	m := &Manager{
 		configPath: path,
 		config:     nil,
+		noFile:     os.Getenv(NoConfigFileEnv) == "1",
+		warnOut:    os.Stderr,
+		httpClient: &http.Client{Timeout: remoteFetchTimeout},
+		remoteURL:  os.Getenv(RemoteConfigURLEnv),
+		fileKeys:   nil,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
-// EnsureConfig ensures the configuration file exists with defaults.
+// EnsureConfig ensures the configuration file exists. A new file sets no
+// keys, so every key takes its default. It does nothing when the config
+// file is disabled.
 func (m *Manager) EnsureConfig(_ context.Context) error {
-	// Check if config file exists
-	if _, err := os.Stat(m.configPath); os.IsNotExist(err) {
-		// Create config directory if it doesn't exist
-		configDir := filepath.Dir(m.configPath)
-		if mkErr := os.MkdirAll(configDir, 0o750); mkErr != nil {
-			return fmt.Errorf("create config directory: %w", mkErr)
-		}
+	if m.noFile {
+		return nil
+	}
 
-		// Create default config
+	// Check if config file exists, here or in the temp-directory fallback
+	m.configPath = shared.ResolveFallbackFile(m.configPath)
+	if _, err := os.Stat(m.configPath); shared.IsMissing(err) {
+		// Create default config, along with its directory
 		if createErr := m.createDefaultConfig(); createErr != nil {
 			return fmt.Errorf("create default config: %w", createErr)
 		}
@@ -109,12 +149,18 @@ func (m *Manager) GetString(_ context.Context, key string) (string, bool, error)
 	switch key {
 	case keyNotificationsNtfyTopic:
 		return m.config.Notifications.NtfyTopic, true, nil
+	case keyCompactMessageTemplate:
+		return m.config.Compact.MessageTemplate, true, nil
 	case keyNotifyQuietHoursStart:
 		return m.config.Notify.QuietHours.Start, true, nil
 	case keyNotifyQuietHoursEnd:
 		return m.config.Notify.QuietHours.End, true, nil
 	case keyNotifyAudioDirectory:
 		return m.config.Notify.Audio.Directory, true, nil
+	case keyNotifyAudioPlayer:
+		return m.config.Notify.Audio.Player, true, nil
+	case keyNotifyMinSeverity:
+		return m.config.Notify.MinSeverity, true, nil
 	case keyLearningLearnedSkillsPath:
 		return m.config.Learning.LearnedSkillsPath, true, nil
 	case keyPreCommitCommand:
@@ -125,6 +171,8 @@ func (m *Manager) GetString(_ context.Context, key string) (string, bool, error)
 		return m.config.Instinct.PersonalPath, true, nil
 	case keyInstinctInheritedPath:
 		return m.config.Instinct.InheritedPath, true, nil
+	case keyDiscoveryRunFrom:
+		return m.config.Discovery.RunFrom, true, nil
 	default:
 		return "", false, nil
 	}
@@ -144,12 +192,34 @@ func (m *Manager) GetValue(_ context.Context, key string) (string, bool, error)
 		return strconv.Itoa(m.config.Validate.Timeout), true, nil
 	case keyValidateCooldown:
 		return strconv.Itoa(m.config.Validate.Cooldown), true, nil
+	case keyValidateExtraCommands:
+		return strings.Join(m.config.Validate.ExtraCommands, ","), true, nil
+	case keyValidateEnv:
+		return strings.Join(m.config.Validate.Env, ","), true, nil
+	case keyValidateCleanEnv:
+		return strconv.FormatBool(m.config.Validate.CleanEnv), true, nil
+	case keyValidateInjectCI:
+		return strconv.FormatBool(m.config.Validate.InjectCI), true, nil
+	case keyValidateSkipGitOps:
+		return strconv.FormatBool(m.config.Validate.SkipDuringGitOps), true, nil
+	case keyValidateWarnDirty:
+		return strconv.FormatBool(m.config.Validate.WarnDirty), true, nil
+	case keyValidateFoldSkip:
+		return strconv.FormatBool(m.config.Validate.CaseInsensitiveSkip), true, nil
 	case keyNotificationsNtfyTopic:
 		return m.config.Notifications.NtfyTopic, true, nil
 	case keyCompactThreshold:
 		return strconv.Itoa(m.config.Compact.Threshold), true, nil
 	case keyCompactReminderInterval:
 		return strconv.Itoa(m.config.Compact.ReminderInterval), true, nil
+	case keyCompactMessageTemplate:
+		return m.config.Compact.MessageTemplate, true, nil
+	case keyCompactSnapshot:
+		return strconv.FormatBool(m.config.Compact.Snapshot), true, nil
+	case keyCompactStateDir:
+		return m.config.Compact.StateDir, true, nil
+	case keyCompactContextHint:
+		return m.config.Compact.ContextHint, true, nil
 	case keyNotifyQuietHoursEnabled:
 		return strconv.FormatBool(m.config.Notify.QuietHours.Enabled), true, nil
 	case keyNotifyQuietHoursStart:
@@ -160,12 +230,24 @@ func (m *Manager) GetValue(_ context.Context, key string) (string, bool, error)
 		return strconv.FormatBool(m.config.Notify.Audio.Enabled), true, nil
 	case keyNotifyAudioDirectory:
 		return m.config.Notify.Audio.Directory, true, nil
+	case keyNotifyAudioPlayer:
+		return m.config.Notify.Audio.Player, true, nil
 	case keyNotifyDesktopEnabled:
 		return strconv.FormatBool(m.config.Notify.Desktop.Enabled), true, nil
+	case keyNotifyMinSeverity:
+		return m.config.Notify.MinSeverity, true, nil
 	case keyObserveEnabled:
 		return strconv.FormatBool(m.config.Observe.Enabled), true, nil
 	case keyObserveMaxFileSizeMB:
 		return strconv.Itoa(m.config.Observe.MaxFileSizeMB), true, nil
+	case keyObserveAnonymize:
+		return strconv.FormatBool(m.config.Observe.Anonymize), true, nil
+	case keyObserveMaxInputBytes:
+		return strconv.Itoa(m.config.Observe.MaxInputBytes), true, nil
+	case keyObserveRetentionDays:
+		return strconv.Itoa(m.config.Observe.RetentionDays), true, nil
+	case keyObserveDedup:
+		return strconv.FormatBool(m.config.Observe.Dedup), true, nil
 	case keyLearningMinSessionLength:
 		return strconv.Itoa(m.config.Learning.MinSessionLength), true, nil
 	case keyLearningLearnedSkillsPath:
@@ -183,6 +265,10 @@ func (m *Manager) GetValue(_ context.Context, key string) (string, bool, error)
 
 // Set updates a configuration value.
 func (m *Manager) Set(_ context.Context, key string, value string) error {
+	if m.noFile {
+		return ErrConfigFileDisabled
+	}
+
 	if m.config == nil {
 		if err := m.loadConfig(); err != nil {
 			return fmt.Errorf("load config: %w", err)
@@ -192,6 +278,7 @@ func (m *Manager) Set(_ context.Context, key string, value string) error {
 	if err := m.setField(key, value); err != nil {
 		return err
 	}
+	m.markSet(key)
 
 	// Save to file
 	if err := m.saveConfig(); err != nil {
@@ -201,6 +288,105 @@ func (m *Manager) Set(_ context.Context, key string, value string) error {
 	return nil
 }
 
+// SetTyped updates a configuration value after checking that the key has
+// the requested type and that the value parses as that type.
+func (m *Manager) SetTyped(ctx context.Context, key, value string, valueType ValueType) error {
+	keyType, ok := KeyType(key)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, key)
+	}
+
+	if keyType != valueType {
+		return fmt.Errorf("%w: key %s has type %s, not %s", ErrInvalidValue, key, keyType, valueType)
+	}
+
+	normalized, err := normalizeTypedValue(value, valueType)
+	if err != nil {
+		return err
+	}
+
+	return m.Set(ctx, key, normalized)
+}
+
+// KeyValue is a single key and value for SetMany.
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// SetMany applies every pair in order and saves the file once. If any pair
+// is invalid, or saving fails, the configuration is left unchanged and
+// nothing is written.
+func (m *Manager) SetMany(_ context.Context, pairs []KeyValue) error {
+	if m.noFile {
+		return ErrConfigFileDisabled
+	}
+
+	if m.config == nil {
+		if err := m.loadConfig(); err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+	}
+
+	original := m.config
+	updated := *original
+	m.config = &updated
+	originalKeys := maps.Clone(m.fileKeys)
+
+	for _, pair := range pairs {
+		if err := m.setField(pair.Key, pair.Value); err != nil {
+			m.config = original
+			m.fileKeys = originalKeys
+			return fmt.Errorf("%s: %w", pair.Key, err)
+		}
+		m.markSet(pair.Key)
+	}
+
+	if err := m.saveConfig(); err != nil {
+		m.config = original
+		m.fileKeys = originalKeys
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	return nil
+}
+
+// normalizeTypedValue checks that value parses as valueType and returns it
+// in canonical form. List values are comma-separated and trimmed.
+func normalizeTypedValue(value string, valueType ValueType) (string, error) {
+	switch valueType {
+	case TypeString:
+		return value, nil
+	case TypeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", fmt.Errorf("%w: must be an integer: %w", ErrInvalidValue, err)
+		}
+		return value, nil
+	case TypeFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", fmt.Errorf("%w: must be a number: %w", ErrInvalidValue, err)
+		}
+		return value, nil
+	case TypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: must be a boolean: %w", ErrInvalidValue, err)
+		}
+		return strconv.FormatBool(b), nil
+	case TypeList:
+		parts := strings.Split(value, ",")
+		items := make([]string, 0, len(parts))
+		for _, p := range parts {
+			if item := strings.TrimSpace(p); item != "" {
+				items = append(items, item)
+			}
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("unknown value type %q", valueType)
+	}
+}
+
 // setField dispatches the value assignment to the correct config field.
 func (m *Manager) setField(key string, value string) error {
 	switch key {
@@ -208,12 +394,34 @@ func (m *Manager) setField(key string, value string) error {
 		return setIntField(&m.config.Validate.Timeout, value)
 	case keyValidateCooldown:
 		return setIntField(&m.config.Validate.Cooldown, value)
+	case keyValidateExtraCommands:
+		setListField(&m.config.Validate.ExtraCommands, value)
+	case keyValidateEnv:
+		return setEnvListField(&m.config.Validate.Env, value)
+	case keyValidateCleanEnv:
+		return setBoolField(&m.config.Validate.CleanEnv, value)
+	case keyValidateInjectCI:
+		return setBoolField(&m.config.Validate.InjectCI, value)
+	case keyValidateSkipGitOps:
+		return setBoolField(&m.config.Validate.SkipDuringGitOps, value)
+	case keyValidateWarnDirty:
+		return setBoolField(&m.config.Validate.WarnDirty, value)
+	case keyValidateFoldSkip:
+		return setBoolField(&m.config.Validate.CaseInsensitiveSkip, value)
 	case keyNotificationsNtfyTopic:
 		m.config.Notifications.NtfyTopic = value
 	case keyCompactThreshold:
 		return setIntField(&m.config.Compact.Threshold, value)
 	case keyCompactReminderInterval:
 		return setIntField(&m.config.Compact.ReminderInterval, value)
+	case keyCompactMessageTemplate:
+		return setMessageTemplateField(&m.config.Compact.MessageTemplate, value)
+	case keyCompactSnapshot:
+		return setBoolField(&m.config.Compact.Snapshot, value)
+	case keyCompactStateDir:
+		m.config.Compact.StateDir = value
+	case keyCompactContextHint:
+		m.config.Compact.ContextHint = value
 	case keyNotifyQuietHoursEnabled:
 		return setBoolField(&m.config.Notify.QuietHours.Enabled, value)
 	case keyNotifyQuietHoursStart:
@@ -224,12 +432,24 @@ func (m *Manager) setField(key string, value string) error {
 		return setBoolField(&m.config.Notify.Audio.Enabled, value)
 	case keyNotifyAudioDirectory:
 		m.config.Notify.Audio.Directory = value
+	case keyNotifyAudioPlayer:
+		m.config.Notify.Audio.Player = value
 	case keyNotifyDesktopEnabled:
 		return setBoolField(&m.config.Notify.Desktop.Enabled, value)
+	case keyNotifyMinSeverity:
+		return setSeverityField(&m.config.Notify.MinSeverity, value)
 	case keyObserveEnabled:
 		return setBoolField(&m.config.Observe.Enabled, value)
 	case keyObserveMaxFileSizeMB:
 		return setIntField(&m.config.Observe.MaxFileSizeMB, value)
+	case keyObserveAnonymize:
+		return setBoolField(&m.config.Observe.Anonymize, value)
+	case keyObserveMaxInputBytes:
+		return setIntField(&m.config.Observe.MaxInputBytes, value)
+	case keyObserveRetentionDays:
+		return setIntField(&m.config.Observe.RetentionDays, value)
+	case keyObserveDedup:
+		return setBoolField(&m.config.Observe.Dedup, value)
 	case keyLearningMinSessionLength:
 		return setIntField(&m.config.Learning.MinSessionLength, value)
 	case keyLearningLearnedSkillsPath:
@@ -244,7 +464,7 @@ func (m *Manager) setField(key string, value string) error {
 		if handled, err := m.config.setExtendedField(key, value); handled {
 			return err
 		}
-		return fmt.Errorf("unknown configuration key: %s", key)
+		return fmt.Errorf("%w: %s", ErrUnknownKey, key)
 	}
 	return nil
 }
@@ -253,7 +473,7 @@ func (m *Manager) setField(key string, value string) error {
 func setFloatField(field *float64, value string) error {
 	floatVal, err := strconv.ParseFloat(value, 64)
 	if err != nil {
-		return fmt.Errorf("value must be a number: %w", err)
+		return fmt.Errorf("%w: must be a number: %w", ErrInvalidValue, err)
 	}
 	*field = floatVal
 	return nil
@@ -263,7 +483,7 @@ func setFloatField(field *float64, value string) error {
 func setIntField(field *int, value string) error {
 	intVal, err := strconv.Atoi(value)
 	if err != nil {
-		return fmt.Errorf("value must be an integer: %w", err)
+		return fmt.Errorf("%w: must be an integer: %w", ErrInvalidValue, err)
 	}
 	*field = intVal
 	return nil
@@ -273,12 +493,48 @@ func setIntField(field *int, value string) error {
 func setBoolField(field *bool, value string) error {
 	boolVal, err := strconv.ParseBool(value)
 	if err != nil {
-		return fmt.Errorf("value must be a boolean: %w", err)
+		return fmt.Errorf("%w: must be a boolean: %w", ErrInvalidValue, err)
 	}
 	*field = boolVal
 	return nil
 }
 
+// setListField assigns a comma-separated list, trimming whitespace and
+// dropping empty items. An empty value clears the list.
+func setListField(field *[]string, value string) {
+	var items []string
+	for part := range strings.SplitSeq(value, ",") {
+		if item := strings.TrimSpace(part); item != "" {
+			items = append(items, item)
+		}
+	}
+	*field = items
+}
+
+// setEnvListField assigns a comma-separated list of KEY=VALUE entries.
+// Every entry must have a non-empty key.
+func setEnvListField(field *[]string, value string) error {
+	var items []string
+	setListField(&items, value)
+	for _, item := range items {
+		if key, _, ok := strings.Cut(item, "="); !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("%w: %q must be in KEY=VALUE form", ErrInvalidValue, item)
+		}
+	}
+	*field = items
+	return nil
+}
+
+// setMessageTemplateField validates and assigns a message template. The
+// template must contain the {count} placeholder.
+func setMessageTemplateField(field *string, value string) error {
+	if !strings.Contains(value, "{count}") {
+		return fmt.Errorf("%w: template must contain the {count} placeholder", ErrInvalidValue)
+	}
+	*field = value
+	return nil
+}
+
 // GetAll retrieves all configuration values with their metadata.
 func (m *Manager) GetAll(ctx context.Context) (map[string]Info, error) {
 	if m.config == nil {
@@ -315,6 +571,10 @@ func (m *Manager) GetAllKeys(_ context.Context) ([]string, error) {
 
 // Reset resets a specific configuration key to its default value.
 func (m *Manager) Reset(_ context.Context, key string) error {
+	if m.noFile {
+		return ErrConfigFileDisabled
+	}
+
 	if m.config == nil {
 		if err := m.loadConfig(); err != nil {
 			return fmt.Errorf("load config: %w", err)
@@ -329,12 +589,34 @@ func (m *Manager) Reset(_ context.Context, key string) error {
 		m.config.Validate.Timeout = defaults.Validate.Timeout
 	case keyValidateCooldown:
 		m.config.Validate.Cooldown = defaults.Validate.Cooldown
+	case keyValidateExtraCommands:
+		m.config.Validate.ExtraCommands = defaults.Validate.ExtraCommands
+	case keyValidateEnv:
+		m.config.Validate.Env = defaults.Validate.Env
+	case keyValidateCleanEnv:
+		m.config.Validate.CleanEnv = defaults.Validate.CleanEnv
+	case keyValidateInjectCI:
+		m.config.Validate.InjectCI = defaults.Validate.InjectCI
+	case keyValidateSkipGitOps:
+		m.config.Validate.SkipDuringGitOps = defaults.Validate.SkipDuringGitOps
+	case keyValidateWarnDirty:
+		m.config.Validate.WarnDirty = defaults.Validate.WarnDirty
+	case keyValidateFoldSkip:
+		m.config.Validate.CaseInsensitiveSkip = defaults.Validate.CaseInsensitiveSkip
 	case keyNotificationsNtfyTopic:
 		m.config.Notifications.NtfyTopic = defaults.Notifications.NtfyTopic
 	case keyCompactThreshold:
 		m.config.Compact.Threshold = defaults.Compact.Threshold
 	case keyCompactReminderInterval:
 		m.config.Compact.ReminderInterval = defaults.Compact.ReminderInterval
+	case keyCompactMessageTemplate:
+		m.config.Compact.MessageTemplate = defaults.Compact.MessageTemplate
+	case keyCompactSnapshot:
+		m.config.Compact.Snapshot = defaults.Compact.Snapshot
+	case keyCompactStateDir:
+		m.config.Compact.StateDir = defaults.Compact.StateDir
+	case keyCompactContextHint:
+		m.config.Compact.ContextHint = defaults.Compact.ContextHint
 	case keyNotifyQuietHoursEnabled:
 		m.config.Notify.QuietHours.Enabled = defaults.Notify.QuietHours.Enabled
 	case keyNotifyQuietHoursStart:
@@ -345,12 +627,24 @@ func (m *Manager) Reset(_ context.Context, key string) error {
 		m.config.Notify.Audio.Enabled = defaults.Notify.Audio.Enabled
 	case keyNotifyAudioDirectory:
 		m.config.Notify.Audio.Directory = defaults.Notify.Audio.Directory
+	case keyNotifyAudioPlayer:
+		m.config.Notify.Audio.Player = defaults.Notify.Audio.Player
 	case keyNotifyDesktopEnabled:
 		m.config.Notify.Desktop.Enabled = defaults.Notify.Desktop.Enabled
+	case keyNotifyMinSeverity:
+		m.config.Notify.MinSeverity = defaults.Notify.MinSeverity
 	case keyObserveEnabled:
 		m.config.Observe.Enabled = defaults.Observe.Enabled
 	case keyObserveMaxFileSizeMB:
 		m.config.Observe.MaxFileSizeMB = defaults.Observe.MaxFileSizeMB
+	case keyObserveAnonymize:
+		m.config.Observe.Anonymize = defaults.Observe.Anonymize
+	case keyObserveMaxInputBytes:
+		m.config.Observe.MaxInputBytes = defaults.Observe.MaxInputBytes
+	case keyObserveRetentionDays:
+		m.config.Observe.RetentionDays = defaults.Observe.RetentionDays
+	case keyObserveDedup:
+		m.config.Observe.Dedup = defaults.Observe.Dedup
 	case keyLearningMinSessionLength:
 		m.config.Learning.MinSessionLength = defaults.Learning.MinSessionLength
 	case keyLearningLearnedSkillsPath:
@@ -363,9 +657,10 @@ func (m *Manager) Reset(_ context.Context, key string) error {
 		m.config.PackageManager.Preferred = defaults.PackageManager.Preferred
 	default:
 		if !m.config.resetExtended(key, defaults) {
-			return fmt.Errorf("unknown configuration key: %s", key)
+			return fmt.Errorf("%w: %s", ErrUnknownKey, key)
 		}
 	}
+	delete(m.fileKeys, key)
 
 	// Save to file
 	if err := m.saveConfig(); err != nil {
@@ -377,8 +672,13 @@ func (m *Manager) Reset(_ context.Context, key string) error {
 
 // ResetAll resets all configuration to defaults.
 func (m *Manager) ResetAll(_ context.Context) error {
+	if m.noFile {
+		return ErrConfigFileDisabled
+	}
+
 	// Create new config with defaults
 	m.config = GetDefaultConfig()
+	m.fileKeys = make(map[string]bool)
 
 	// Save to file
 	if err := m.saveConfig(); err != nil {
@@ -403,21 +703,43 @@ func (m *Manager) GetConfigPath() string {
 	return m.configPath
 }
 
-// loadConfig loads the configuration from file.
+// loadConfig loads the configuration from file, then applies the remote
+// overlay under it.
 func (m *Manager) loadConfig() error {
 	// Initialize with defaults
 	m.config = GetDefaultConfig()
+	m.fileKeys = make(map[string]bool)
+	if m.noFile {
+		return nil
+	}
 
-	// Read file if it exists
+	if err := m.loadConfigFile(); err != nil {
+		return err
+	}
+
+	m.applyRemoteOverlay()
+	return nil
+}
+
+// loadConfigFile reads the config file over the defaults in m.config.
+func (m *Manager) loadConfigFile() error {
+	// Read file if it exists, here or in the temp-directory fallback
+	m.configPath = shared.ResolveFallbackFile(m.configPath)
 	data, err := os.ReadFile(m.configPath)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if shared.IsMissing(err) {
 			// File doesn't exist, use defaults
 			return nil
 		}
 		return fmt.Errorf("read config file: %w", err)
 	}
 
+	keys, err := fileKeys(data)
+	if err != nil {
+		return err
+	}
+	m.fileKeys = keys
+
 	// Try to parse as structured config first, unmarshaling into defaults
 	// so that missing fields retain their default values (especially booleans).
 	if unmarshalErr := json.Unmarshal(data, m.config); unmarshalErr == nil {
@@ -434,22 +756,44 @@ func (m *Manager) loadConfig() error {
 	// Convert from map to structured config
 	m.convertFromMap(mapConfig)
 	m.ensureDefaults()
+	m.warnLegacyOnce()
 
 	return nil
 }
 
-// saveConfig saves the current configuration to file.
+// warnLegacyOnce tells the user, once per config directory, that the config
+// file uses the legacy format. A marker file next to the config keeps hooks,
+// which load the config on every event, from repeating it.
+func (m *Manager) warnLegacyOnce() {
+	marker := filepath.Join(filepath.Dir(m.configPath), legacyWarnedFile)
+	if _, err := os.Stat(marker); err == nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(m.warnOut,
+		"cc-tools: %s uses the legacy config format, which a future release will stop reading. "+
+			"Convert it with 'cc-tools config migrate-legacy %s --output <new-file>' "+
+			"and replace the old file with the result.\n",
+		m.configPath, m.configPath)
+
+	_ = os.WriteFile(marker, nil, 0o600)
+}
+
+// saveConfig saves the current configuration to file. When the config
+// directory cannot be written, the file is saved in its temp-directory
+// fallback instead and the manager uses that path from then on.
 func (m *Manager) saveConfig() error {
 	// Ensure directory exists
-	configDir := filepath.Dir(m.configPath)
-	if mkErr := os.MkdirAll(configDir, 0o750); mkErr != nil {
-		return fmt.Errorf("create config directory: %w", mkErr)
+	configDir, dirErr := shared.WritableDir(filepath.Dir(m.configPath), m.warnOut)
+	if dirErr != nil {
+		return fmt.Errorf("create config directory: %w", dirErr)
 	}
+	m.configPath = filepath.Join(configDir, filepath.Base(m.configPath))
 
-	// Marshal to JSON with indentation
-	data, err := json.MarshalIndent(m.config, "", "  ")
+	// Marshal the keys the user set, leaving out overlay values and defaults
+	data, err := marshalKeys(m.config, m.fileKeys)
 	if err != nil {
-		return fmt.Errorf("marshal config: %w", err)
+		return err
 	}
 
 	// Write to file
@@ -460,29 +804,42 @@ func (m *Manager) saveConfig() error {
 	return nil
 }
 
-// createDefaultConfig creates a configuration file with default values.
+// createDefaultConfig creates a configuration file that sets no keys.
 func (m *Manager) createDefaultConfig() error {
 	m.config = GetDefaultConfig()
+	m.fileKeys = make(map[string]bool)
 	return m.saveConfig()
 }
 
+// markSet records that key now has a value chosen by the user, so it is
+// saved to the config file.
+func (m *Manager) markSet(key string) {
+	if m.fileKeys == nil {
+		m.fileKeys = make(map[string]bool)
+	}
+	m.fileKeys[key] = true
+}
+
 // ensureDefaults ensures all fields have values, using defaults for missing fields.
 // Boolean fields are not checked here because we unmarshal into a defaults struct,
-// which preserves default true values when the field is absent from JSON.
+// which preserves default true values when the field is absent from JSON. The
+// same applies to ints where zero means "off", such as cooldowns and reminder
+// intervals: a zero there was written by the user and is kept. Only fields
+// that cannot work at zero, like timeouts, are reset.
 func (m *Manager) ensureDefaults() {
 	defaults := GetDefaultConfig()
 
 	if m.config.Validate.Timeout == 0 {
 		m.config.Validate.Timeout = defaults.Validate.Timeout
 	}
-	if m.config.Validate.Cooldown == 0 {
-		m.config.Validate.Cooldown = defaults.Validate.Cooldown
-	}
 	if m.config.Compact.Threshold == 0 {
 		m.config.Compact.Threshold = defaults.Compact.Threshold
 	}
-	if m.config.Compact.ReminderInterval == 0 {
-		m.config.Compact.ReminderInterval = defaults.Compact.ReminderInterval
+	if m.config.Hook.MaxInputBytes == 0 {
+		m.config.Hook.MaxInputBytes = defaults.Hook.MaxInputBytes
+	}
+	if m.config.Compact.MessageTemplate == "" {
+		m.config.Compact.MessageTemplate = defaults.Compact.MessageTemplate
 	}
 	if m.config.Notify.QuietHours.Start == "" {
 		m.config.Notify.QuietHours.Start = defaults.Notify.QuietHours.Start
@@ -493,6 +850,12 @@ func (m *Manager) ensureDefaults() {
 	if m.config.Notify.Audio.Directory == "" {
 		m.config.Notify.Audio.Directory = defaults.Notify.Audio.Directory
 	}
+	if m.config.Notify.Audio.Player == "" {
+		m.config.Notify.Audio.Player = defaults.Notify.Audio.Player
+	}
+	if m.config.Notify.MinSeverity == "" {
+		m.config.Notify.MinSeverity = defaults.Notify.MinSeverity
+	}
 	if m.config.Observe.MaxFileSizeMB == 0 {
 		m.config.Observe.MaxFileSizeMB = defaults.Observe.MaxFileSizeMB
 	}
@@ -505,17 +868,11 @@ func (m *Manager) ensureDefaults() {
 	if m.config.PreCommit.Command == "" {
 		m.config.PreCommit.Command = defaults.PreCommit.Command
 	}
-	if m.config.Drift.MinEdits == 0 {
-		m.config.Drift.MinEdits = defaults.Drift.MinEdits
-	}
 	if m.config.Drift.Threshold == 0 {
 		m.config.Drift.Threshold = defaults.Drift.Threshold
 	}
-	if m.config.StopReminder.Interval == 0 {
-		m.config.StopReminder.Interval = defaults.StopReminder.Interval
-	}
-	if m.config.StopReminder.WarnAt == 0 {
-		m.config.StopReminder.WarnAt = defaults.StopReminder.WarnAt
+	if m.config.Discovery.RunFrom == "" {
+		m.config.Discovery.RunFrom = defaults.Discovery.RunFrom
 	}
 	ensureInstinctDefaults(&m.config.Instinct, &defaults.Instinct)
 }
@@ -560,12 +917,39 @@ func (m *Manager) convertFromMap(mapConfig map[string]any) {
 	convertDriftFromMap(&m.config.Drift, mapConfig)
 	convertStopReminderFromMap(&m.config.StopReminder, mapConfig)
 	convertInstinctFromMap(&m.config.Instinct, mapConfig)
+	convertFormatFromMap(&m.config.Format, mapConfig)
+	convertDiscoveryFromMap(&m.config.Discovery, mapConfig)
+	convertSessionFromMap(&m.config.Session, mapConfig)
+	convertHookFromMap(&m.config.Hook, mapConfig)
 
 	if notifyMap, notifyOk := mapConfig["notify"].(map[string]any); notifyOk {
 		convertNotifyFromMap(&m.config.Notify, notifyMap)
 	}
 }
 
+// ConvertLegacy parses a legacy map-based config file and returns the
+// equivalent structured configuration, with defaults for missing fields.
+// No config file is read or written.
+func ConvertLegacy(data []byte) (*Values, error) {
+	var mapConfig map[string]any
+	if err := json.Unmarshal(data, &mapConfig); err != nil {
+		return nil, fmt.Errorf("parse legacy config: %w", err)
+	}
+
+	m := &Manager{
+		configPath: "",
+		config:     nil,
+		noFile:     true,
+		warnOut:    io.Discard,
+		httpClient: nil,
+		remoteURL:  "",
+		fileKeys:   nil,
+	}
+	m.convertFromMap(mapConfig)
+	m.ensureDefaults()
+	return m.config, nil
+}
+
 // getConfigFilePath returns the path to the configuration file.
 func getConfigFilePath() string {
 	// Check XDG_CONFIG_HOME first
@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // assertConfigSavedToFile reads the config file and unmarshals it, failing the
@@ -85,8 +87,15 @@ func writeTestConfig(t *testing.T, configPath string, v any) {
 func newTestValues(timeout, cooldown int) *config.Values {
 	return &config.Values{
 		Validate: config.ValidateValues{
-			Timeout:  timeout,
-			Cooldown: cooldown,
+			Timeout:             timeout,
+			Cooldown:            cooldown,
+			ExtraCommands:       nil,
+			Env:                 nil,
+			CleanEnv:            false,
+			InjectCI:            true,
+			SkipDuringGitOps:    true,
+			WarnDirty:           false,
+			CaseInsensitiveSkip: false,
 		},
 		Notifications: config.NotificationsValues{
 			NtfyTopic: "",
@@ -104,14 +113,20 @@ func newTestValues(timeout, cooldown int) *config.Values {
 			Audio: config.AudioValues{
 				Enabled:   config.ExportDefaultNotifyAudioEnabled(),
 				Directory: config.ExportDefaultNotifyAudioDirectory(),
+				Player:    config.ExportDefaultNotifyAudioPlayer(),
 			},
 			Desktop: config.DesktopValues{
 				Enabled: config.ExportDefaultNotifyDesktopEnabled(),
 			},
+			MinSeverity: config.ExportDefaultNotifyMinSeverity(),
 		},
 		Observe: config.ObserveValues{
 			Enabled:       config.ExportDefaultObserveEnabled(),
 			MaxFileSizeMB: config.ExportDefaultObserveMaxFileSizeMB(),
+			Anonymize:     config.ExportDefaultObserveAnonymize(),
+			MaxInputBytes: config.ExportDefaultObserveMaxInputBytes(),
+			RetentionDays: config.ExportDefaultObserveRetentionDays(),
+			Dedup:         config.ExportDefaultObserveDedup(),
 		},
 		Learning: config.LearningValues{
 			MinSessionLength:  config.ExportDefaultLearningMinSessionLength(),
@@ -232,6 +247,29 @@ func TestEnsureConfig(t *testing.T) {
 	})
 }
 
+func TestNoConfigFileMode(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(config.NoConfigFileEnv, "1")
+
+	configPath := filepath.Join(t.TempDir(), "cc-tools", "config.json")
+	m := config.NewManagerWithPath(configPath)
+
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	cfg, err := m.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, config.ExportDefaultValidateTimeout(), cfg.Validate.Timeout)
+
+	require.ErrorIs(t, m.Set(ctx, "validate.timeout", "120"), config.ErrConfigFileDisabled)
+	require.ErrorIs(t, m.SetMany(ctx, []config.KeyValue{{Key: "validate.timeout", Value: "120"}}),
+		config.ErrConfigFileDisabled)
+	require.ErrorIs(t, m.Reset(ctx, "validate.timeout"), config.ErrConfigFileDisabled)
+	require.ErrorIs(t, m.ResetAll(ctx), config.ErrConfigFileDisabled)
+
+	_, statErr := os.Stat(filepath.Dir(configPath))
+	assert.True(t, os.IsNotExist(statErr), "config directory should not be created")
+}
+
 func TestGetInt(t *testing.T) {
 	ctx := context.Background()
 
@@ -547,10 +585,9 @@ func TestResetAll(t *testing.T) {
 		t.Errorf("cooldown not reset to default")
 	}
 
-	saved := assertConfigSavedToFile(t, config.ManagerConfigPath(m))
-	if saved.Validate.Timeout != defaults.Validate.Timeout {
-		t.Error("saved config not reset to defaults")
-	}
+	data, err := os.ReadFile(config.ManagerConfigPath(m))
+	require.NoError(t, err)
+	assert.JSONEq(t, "{}", string(data), "a reset config file sets no keys")
 }
 
 func TestLoadConfig(t *testing.T) {
@@ -610,7 +647,9 @@ func TestLoadConfig(t *testing.T) {
 	t.Run("fills in missing fields with defaults", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		configPath := filepath.Join(tmpDir, "config.json")
-		writeTestConfig(t, configPath, newTestValues(100, 0))
+		writeTestConfig(t, configPath, map[string]any{
+			"validate": map[string]any{"timeout": 100},
+		})
 		m := config.NewTestManager(configPath, nil)
 
 		if err := config.ManagerLoadConfig(m); err != nil {
@@ -626,6 +665,42 @@ func TestLoadConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("keeps explicit zero values", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.json")
+		writeTestConfig(t, configPath, map[string]any{
+			"validate":      map[string]any{"cooldown": 0},
+			"compact":       map[string]any{"reminder_interval": 0},
+			"stop_reminder": map[string]any{"interval": 0, "warn_at": 0},
+			"drift":         map[string]any{"min_edits": 0},
+		})
+		m := config.NewTestManager(configPath, nil)
+
+		if err := config.ManagerLoadConfig(m); err != nil {
+			t.Fatalf("loadConfig() error = %v", err)
+		}
+
+		cfg := config.ManagerConfig(m)
+		defaults := config.ExportGetDefaultConfig()
+		for name, got := range map[string]int{
+			"validate.cooldown":         cfg.Validate.Cooldown,
+			"compact.reminder_interval": cfg.Compact.ReminderInterval,
+			"stop_reminder.interval":    cfg.StopReminder.Interval,
+			"stop_reminder.warn_at":     cfg.StopReminder.WarnAt,
+			"drift.min_edits":           cfg.Drift.MinEdits,
+		} {
+			if got != 0 {
+				t.Errorf("%s = %d, want explicit 0 kept", name, got)
+			}
+		}
+		if cfg.Compact.Threshold != defaults.Compact.Threshold {
+			t.Errorf("compact.threshold = %d, want default %d", cfg.Compact.Threshold, defaults.Compact.Threshold)
+		}
+		if cfg.StopReminder.Enabled != defaults.StopReminder.Enabled {
+			t.Errorf("stop_reminder.enabled = %v, want default %v", cfg.StopReminder.Enabled, defaults.StopReminder.Enabled)
+		}
+	})
+
 	t.Run("handles corrupt JSON", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		configPath := filepath.Join(tmpDir, "config.json")
@@ -639,6 +714,41 @@ func TestLoadConfig(t *testing.T) {
 	})
 }
 
+func TestLoadConfig_LegacyWarning(t *testing.T) {
+	// "enabled" as a string cannot unmarshal into the structured format, so
+	// this file is read through the legacy map conversion.
+	legacy := `{"validate": {"timeout": 90}, "observe": {"enabled": "yes"}}`
+	structured := `{"validate": {"timeout": 90}}`
+
+	load := func(t *testing.T, configPath string) string {
+		t.Helper()
+		var warnings bytes.Buffer
+		m := config.NewTestManager(configPath, nil)
+		config.SetManagerWarnOutput(m, &warnings)
+		require.NoError(t, config.ManagerLoadConfig(m))
+		assert.Equal(t, 90, config.ManagerConfig(m).Validate.Timeout)
+		return warnings.String()
+	}
+
+	t.Run("warns once on the map path", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(configPath, []byte(legacy), 0o600))
+
+		first := load(t, configPath)
+		assert.Contains(t, first, "legacy config format")
+		assert.Contains(t, first, "cc-tools config migrate-legacy "+configPath)
+
+		assert.Empty(t, load(t, configPath), "the warning should not repeat")
+	})
+
+	t.Run("silent on the structured path", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(configPath, []byte(structured), 0o600))
+
+		assert.Empty(t, load(t, configPath))
+	})
+}
+
 func TestSaveConfig(t *testing.T) {
 	t.Run("saves config successfully", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -664,22 +774,81 @@ func TestSaveConfig(t *testing.T) {
 		assertSavedConfigValid(t, configPath)
 	})
 
-	t.Run("handles permission error", func(t *testing.T) {
-		tmpDir := t.TempDir()
-		configPath := filepath.Join(tmpDir, "subdir", "config.json")
-		dir := filepath.Dir(configPath)
-		os.MkdirAll(dir, 0o755)
-		os.Chmod(dir, 0o444)
-		t.Cleanup(func() {
-			os.Chmod(dir, 0o755)
-		})
+	t.Run("falls back to the temp dir when the directory is unwritable", func(t *testing.T) {
+		t.Setenv("TMPDIR", t.TempDir())
+		home := t.TempDir()
+		// A regular file where the directory should be makes it unwritable,
+		// even for root.
+		require.NoError(t, os.WriteFile(filepath.Join(home, ".config"), nil, 0o600))
+		configPath := filepath.Join(home, ".config", "cc-tools", "config.json")
+
+		m := config.NewTestManager(configPath, newTestValues(90, 0))
+		var warnings bytes.Buffer
+		config.SetManagerWarnOutput(m, &warnings)
+
+		require.NoError(t, config.ManagerSaveConfig(m))
+		assert.Contains(t, warnings.String(), "cannot write to")
+
+		fallback := filepath.Join(shared.FallbackDir(filepath.Dir(configPath)), "config.json")
+		assert.Equal(t, fallback, m.GetConfigPath())
+		assertSavedConfigValid(t, fallback)
+
+		// A new manager for the original path finds the saved file.
+		reloaded := config.NewManagerWithPath(configPath)
+		require.NoError(t, reloaded.EnsureConfig(context.Background()))
+		assert.Equal(t, fallback, reloaded.GetConfigPath())
+		cfg, err := reloaded.GetConfig(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 90, cfg.Validate.Timeout)
+	})
+}
 
-		m := config.NewTestManager(configPath, newTestValues(0, 0))
+func TestLoadConfig_IgnoresFallbackWhenDirWritable(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	configPath := filepath.Join(t.TempDir(), "cc-tools", "config.json")
 
-		if err := config.ManagerSaveConfig(m); err == nil {
-			t.Error("saveConfig() expected error for permission denied")
-		}
-	})
+	// A config another process left in the fallback must not be read while
+	// the real config directory can be written.
+	fallback := shared.FallbackDir(filepath.Dir(configPath))
+	require.NoError(t, os.MkdirAll(fallback, 0o700))
+	planted := `{"validate": {"extra_commands": ["touch /tmp/pwned"]}}`
+	require.NoError(t, os.WriteFile(filepath.Join(fallback, "config.json"), []byte(planted), 0o600))
+
+	m := config.NewManagerWithPath(configPath)
+	cfg, err := m.GetConfig(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Validate.ExtraCommands)
+	assert.Equal(t, configPath, m.GetConfigPath())
+}
+
+func TestSaveConfig_OnlyKeysSet(t *testing.T) {
+	ctx := context.Background()
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+
+	require.NoError(t, m.Set(ctx, "validate.timeout", "90"))
+	require.NoError(t, m.Set(ctx, "notify.audio.enabled", "true"))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"validate": {"timeout": 90}, "notify": {"audio": {"enabled": true}}}`, string(data),
+		"a key set to its default is still recorded")
+
+	require.NoError(t, m.Reset(ctx, "validate.timeout"))
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"notify": {"audio": {"enabled": true}}}`, string(data))
+}
+
+func TestFileKeys_CoversEveryKey(t *testing.T) {
+	data, err := json.Marshal(config.GetDefaultConfig())
+	require.NoError(t, err)
+
+	keys, err := config.ExportFileKeys(data)
+	require.NoError(t, err)
+	for _, key := range config.ExportAllKeys() {
+		assert.True(t, keys[key], "%s is found in a full config file", key)
+	}
 }
 
 func TestGetConfig(t *testing.T) {
@@ -728,7 +897,7 @@ func TestManagerGetConfigPath(t *testing.T) {
 }
 
 func TestEnsureDefaults(t *testing.T) {
-	t.Run("fills in zero values with defaults", func(t *testing.T) {
+	t.Run("fills in unusable zero values with defaults", func(t *testing.T) {
 		m := config.NewTestManager("", newTestValues(0, 0))
 		config.ManagerEnsureDefaults(m)
 		cfg := config.ManagerConfig(m)
@@ -736,8 +905,8 @@ func TestEnsureDefaults(t *testing.T) {
 		if cfg.Validate.Timeout != config.ExportDefaultValidateTimeout() {
 			t.Errorf("timeout = %d, want %d", cfg.Validate.Timeout, config.ExportDefaultValidateTimeout())
 		}
-		if cfg.Validate.Cooldown != config.ExportDefaultValidateCooldown() {
-			t.Errorf("cooldown = %d, want %d", cfg.Validate.Cooldown, config.ExportDefaultValidateCooldown())
+		if cfg.Validate.Cooldown != 0 {
+			t.Errorf("cooldown = %d, want explicit 0 kept", cfg.Validate.Cooldown)
 		}
 	})
 
@@ -2491,3 +2660,613 @@ func TestInstinctConfigSetGet(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatConfigSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	cfg, err := m.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, config.ExportDefaultFormatOnEdit(), cfg.Format.OnEdit)
+
+	require.NoError(t, m.Set(ctx, config.ExportKeyFormatOnEdit(), "true"))
+
+	m2 := config.NewManagerWithPath(configPath)
+	require.NoError(t, m2.EnsureConfig(ctx))
+
+	value, found, err := m2.GetValue(ctx, config.ExportKeyFormatOnEdit())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "true", value)
+
+	require.NoError(t, m2.Reset(ctx, config.ExportKeyFormatOnEdit()))
+
+	value, _, err = m2.GetValue(ctx, config.ExportKeyFormatOnEdit())
+	require.NoError(t, err)
+	assert.Equal(t, "false", value)
+}
+
+func TestObserveAnonymizeSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	cfg, err := m.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, config.ExportDefaultObserveAnonymize(), cfg.Observe.Anonymize)
+
+	require.NoError(t, m.Set(ctx, config.ExportKeyObserveAnonymize(), "true"))
+
+	m2 := config.NewManagerWithPath(configPath)
+	value, found, err := m2.GetValue(ctx, config.ExportKeyObserveAnonymize())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "true", value)
+}
+
+func TestObserveMaxInputBytesSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	cfg, err := m.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, config.ExportDefaultObserveMaxInputBytes(), cfg.Observe.MaxInputBytes)
+
+	// Zero means unlimited and must survive a reload rather than being
+	// replaced by the default.
+	require.NoError(t, m.Set(ctx, config.ExportKeyObserveMaxInputBytes(), "0"))
+
+	m2 := config.NewManagerWithPath(configPath)
+	cfg2, err := m2.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg2.Observe.MaxInputBytes)
+}
+
+func TestValidateExtraCommandsSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	key := config.ExportKeyValidateExtraCommands()
+	require.NoError(t, m.SetTyped(ctx, key, "make vet-custom, go vet ./... ,", config.TypeList))
+
+	m2 := config.NewManagerWithPath(configPath)
+	cfg, err := m2.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"make vet-custom", "go vet ./..."}, cfg.Validate.ExtraCommands)
+
+	value, found, err := m2.GetValue(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "make vet-custom,go vet ./...", value)
+
+	require.NoError(t, m2.Reset(ctx, key))
+	cfg, err = m2.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Validate.ExtraCommands)
+}
+
+func TestNotifyAudioPlayerSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	value, found, err := m.GetValue(ctx, config.ExportKeyNotifyAudioPlayer())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, config.ExportDefaultNotifyAudioPlayer(), value)
+
+	require.NoError(t, m.Set(ctx, config.ExportKeyNotifyAudioPlayer(), "mpv --really-quiet"))
+
+	m2 := config.NewManagerWithPath(configPath)
+	cfg, err := m2.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "mpv --really-quiet", cfg.Notify.Audio.Player)
+
+	require.NoError(t, m2.Reset(ctx, config.ExportKeyNotifyAudioPlayer()))
+	value, _, err = m2.GetValue(ctx, config.ExportKeyNotifyAudioPlayer())
+	require.NoError(t, err)
+	assert.Equal(t, "auto", value)
+}
+
+func TestObserveRetentionDaysSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	cfg, err := m.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, config.ExportDefaultObserveRetentionDays(), cfg.Observe.RetentionDays)
+
+	require.NoError(t, m.Set(ctx, config.ExportKeyObserveRetentionDays(), "30"))
+
+	m2 := config.NewManagerWithPath(configPath)
+	value, found, err := m2.GetValue(ctx, config.ExportKeyObserveRetentionDays())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "30", value)
+}
+
+func TestObserveDedupSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	cfg, err := m.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.False(t, cfg.Observe.Dedup)
+
+	require.NoError(t, m.Set(ctx, config.ExportKeyObserveDedup(), "true"))
+
+	m2 := config.NewManagerWithPath(configPath)
+	value, found, err := m2.GetValue(ctx, config.ExportKeyObserveDedup())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "true", value)
+}
+
+func TestHookMaxInputBytesSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	cfg, err := m.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, config.ExportDefaultHookMaxInputBytes(), cfg.Hook.MaxInputBytes)
+
+	require.NoError(t, m.Set(ctx, config.ExportKeyHookMaxInputBytes(), "1024"))
+
+	m2 := config.NewManagerWithPath(configPath)
+	value, found, err := m2.GetValue(ctx, config.ExportKeyHookMaxInputBytes())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "1024", value)
+}
+
+func TestHookSessionStartMinIntervalSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	cfg, err := m.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.Hook.SessionStartMinInterval, "debounce is off by default")
+
+	require.NoError(t, m.Set(ctx, config.ExportKeyHookSessionStartMinInterval(), "30"))
+
+	m2 := config.NewManagerWithPath(configPath)
+	value, found, err := m2.GetValue(ctx, config.ExportKeyHookSessionStartMinInterval())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "30", value)
+
+	cfg2, err := m2.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 30, cfg2.Hook.SessionStartMinInterval)
+}
+
+func TestSetMany(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("saves all pairs", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.json")
+		m := config.NewManagerWithPath(configPath)
+
+		err := m.SetMany(ctx, []config.KeyValue{
+			{Key: config.ExportKeyValidateTimeout(), Value: "90"},
+			{Key: config.ExportKeyValidateCooldown(), Value: "10"},
+		})
+		require.NoError(t, err)
+
+		cfg, err := config.NewManagerWithPath(configPath).GetConfig(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 90, cfg.Validate.Timeout)
+		assert.Equal(t, 10, cfg.Validate.Cooldown)
+	})
+
+	t.Run("invalid pair leaves config unchanged", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.json")
+		m := config.NewManagerWithPath(configPath)
+		require.NoError(t, m.EnsureConfig(ctx))
+		before, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+
+		err = m.SetMany(ctx, []config.KeyValue{
+			{Key: config.ExportKeyValidateTimeout(), Value: "90"},
+			{Key: "no.such.key", Value: "1"},
+		})
+		require.ErrorIs(t, err, config.ErrUnknownKey)
+
+		after, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+
+		value, _, err := m.GetValue(ctx, config.ExportKeyValidateTimeout())
+		require.NoError(t, err)
+		assert.Equal(t, "60", value, "in-memory config must be rolled back too")
+	})
+}
+
+func TestValidateEnvSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	cfg, err := m.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Validate.Env)
+	assert.False(t, cfg.Validate.CleanEnv)
+	assert.True(t, cfg.Validate.InjectCI)
+	assert.True(t, cfg.Validate.SkipDuringGitOps)
+	assert.False(t, cfg.Validate.WarnDirty)
+	assert.False(t, cfg.Validate.CaseInsensitiveSkip)
+
+	key := config.ExportKeyValidateEnv()
+	require.NoError(t, m.SetTyped(ctx, key, "GOFLAGS=-mod=mod, NO_COLOR=", config.TypeList))
+	require.NoError(t, m.Set(ctx, config.ExportKeyValidateCleanEnv(), "true"))
+	require.NoError(t, m.Set(ctx, config.ExportKeyValidateInjectCI(), "false"))
+	require.NoError(t, m.Set(ctx, config.ExportKeyValidateSkipGitOps(), "false"))
+	require.NoError(t, m.Set(ctx, config.ExportKeyValidateWarnDirty(), "true"))
+	require.NoError(t, m.Set(ctx, config.ExportKeyValidateFoldSkip(), "true"))
+
+	err = m.SetTyped(ctx, key, "GOFLAGS", config.TypeList)
+	require.ErrorIs(t, err, config.ErrInvalidValue)
+
+	m2 := config.NewManagerWithPath(configPath)
+	cfg, err = m2.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GOFLAGS=-mod=mod", "NO_COLOR="}, cfg.Validate.Env)
+	assert.True(t, cfg.Validate.CleanEnv)
+	assert.False(t, cfg.Validate.InjectCI)
+	assert.False(t, cfg.Validate.SkipDuringGitOps)
+	assert.True(t, cfg.Validate.WarnDirty)
+	assert.True(t, cfg.Validate.CaseInsensitiveSkip)
+
+	require.NoError(t, m2.Reset(ctx, config.ExportKeyValidateInjectCI()))
+	value, _, err := m2.GetValue(ctx, config.ExportKeyValidateInjectCI())
+	require.NoError(t, err)
+	assert.Equal(t, "true", value)
+}
+
+func TestDiscoveryRunFromSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	value, found, err := m.GetString(ctx, config.ExportKeyDiscoveryRunFrom())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, config.ExportDefaultDiscoveryRunFrom(), value)
+
+	require.NoError(t, m.Set(ctx, config.ExportKeyDiscoveryRunFrom(), "file_dir"))
+
+	err = m.Set(ctx, config.ExportKeyDiscoveryRunFrom(), "cwd")
+	require.Error(t, err)
+
+	m2 := config.NewManagerWithPath(configPath)
+	cfg, err := m2.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "file_dir", cfg.Discovery.RunFrom)
+
+	require.NoError(t, m2.Reset(ctx, config.ExportKeyDiscoveryRunFrom()))
+
+	value, _, err = m2.GetValue(ctx, config.ExportKeyDiscoveryRunFrom())
+	require.NoError(t, err)
+	assert.Equal(t, "project_root", value)
+}
+
+func TestNotifyMinSeveritySetGet(t *testing.T) {
+	ctx := context.Background()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	value, found, err := m.GetString(ctx, config.ExportKeyNotifyMinSeverity())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, config.ExportDefaultNotifyMinSeverity(), value)
+
+	require.NoError(t, m.Set(ctx, config.ExportKeyNotifyMinSeverity(), "attention"))
+
+	err = m.Set(ctx, config.ExportKeyNotifyMinSeverity(), "critical")
+	require.ErrorIs(t, err, config.ErrInvalidValue)
+
+	m2 := config.NewManagerWithPath(configPath)
+	cfg, err := m2.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "attention", cfg.Notify.MinSeverity)
+
+	require.NoError(t, m2.Reset(ctx, config.ExportKeyNotifyMinSeverity()))
+
+	value, _, err = m2.GetValue(ctx, config.ExportKeyNotifyMinSeverity())
+	require.NoError(t, err)
+	assert.Equal(t, "info", value)
+}
+
+func TestCompactMessageTemplateSetGet(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	m := config.NewManagerWithPath(configPath)
+	require.NoError(t, m.EnsureConfig(ctx))
+
+	value, found, err := m.GetString(ctx, config.ExportKeyCompactMessageTemplate())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, config.ExportDefaultCompactMessageTemplate(), value)
+
+	err = m.Set(ctx, config.ExportKeyCompactMessageTemplate(), "Compact now at {threshold}")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "{count}")
+
+	custom := "Sie haben {count} Werkzeugaufrufe gemacht (Grenze {threshold})."
+	require.NoError(t, m.Set(ctx, config.ExportKeyCompactMessageTemplate(), custom))
+
+	m2 := config.NewManagerWithPath(configPath)
+	cfg, err := m2.GetConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, custom, cfg.Compact.MessageTemplate)
+
+	require.NoError(t, m2.Reset(ctx, config.ExportKeyCompactMessageTemplate()))
+
+	value, _, err = m2.GetValue(ctx, config.ExportKeyCompactMessageTemplate())
+	require.NoError(t, err)
+	assert.Equal(t, config.ExportDefaultCompactMessageTemplate(), value)
+}
+
+func TestSetTyped(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		value     string
+		valueType config.ValueType
+		wantErr   string
+		wantValue string
+	}{
+		{
+			name:      "int key with int type",
+			key:       "validate.timeout",
+			value:     "90",
+			valueType: config.TypeInt,
+			wantErr:   "",
+			wantValue: "90",
+		},
+		{
+			name:      "bool key normalizes value",
+			key:       "format.on_edit",
+			value:     "1",
+			valueType: config.TypeBool,
+			wantErr:   "",
+			wantValue: "true",
+		},
+		{
+			name:      "string key with string type",
+			key:       "pre_commit_reminder.command",
+			value:     "make check",
+			valueType: config.TypeString,
+			wantErr:   "",
+			wantValue: "make check",
+		},
+		{
+			name:      "type mismatch",
+			key:       "validate.timeout",
+			value:     "90",
+			valueType: config.TypeBool,
+			wantErr:   "has type int, not bool",
+			wantValue: "",
+		},
+		{
+			name:      "list type on scalar key",
+			key:       "notifications.ntfy_topic",
+			value:     "a,b",
+			valueType: config.TypeList,
+			wantErr:   "has type string, not list",
+			wantValue: "",
+		},
+		{
+			name:      "value does not parse",
+			key:       "validate.timeout",
+			value:     "soon",
+			valueType: config.TypeInt,
+			wantErr:   "must be an integer",
+			wantValue: "",
+		},
+		{
+			name:      "unknown key",
+			key:       "nonexistent.key",
+			value:     "x",
+			valueType: config.TypeString,
+			wantErr:   "unknown configuration key",
+			wantValue: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			m := config.NewManagerWithPath(filepath.Join(t.TempDir(), "config.json"))
+			require.NoError(t, m.EnsureConfig(ctx))
+
+			err := m.SetTyped(ctx, tt.key, tt.value, tt.valueType)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			got, _, err := m.GetValue(ctx, tt.key)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantValue, got)
+		})
+	}
+}
+
+func TestParseValueType(t *testing.T) {
+	for _, name := range []string{"string", "int", "float", "bool", "list"} {
+		got, err := config.ParseValueType(name)
+		require.NoError(t, err)
+		assert.Equal(t, config.ValueType(name), got)
+	}
+
+	_, err := config.ParseValueType("map")
+	require.Error(t, err)
+}
+
+func TestKeyTypeCoversAllKeys(t *testing.T) {
+	keys, err := config.NewManagerWithPath(filepath.Join(t.TempDir(), "config.json")).GetAllKeys(context.Background())
+	require.NoError(t, err)
+
+	for _, key := range keys {
+		_, ok := config.KeyType(key)
+		assert.True(t, ok, "key %s has no type", key)
+	}
+}
+
+func TestKeysHaveMetadata(t *testing.T) {
+	infos := config.Keys()
+	require.Len(t, infos, len(config.ExportAllKeys()))
+
+	for _, info := range infos {
+		assert.NotEmpty(t, info.Type, "key %s has no type", info.Key)
+		assert.NotEmpty(t, info.Description, "key %s has no description", info.Key)
+	}
+}
+
+func TestIsSensitive(t *testing.T) {
+	assert.True(t, config.IsSensitive("validate.env"))
+	assert.True(t, config.IsSensitive("session.context_sources"))
+	assert.False(t, config.IsSensitive("validate.timeout"))
+	assert.False(t, config.IsSensitive("nonexistent.key"))
+}
+
+func TestIsSensitive_CommandAndFileKeys(t *testing.T) {
+	// Keys whose values are run as commands or read as files into the
+	// session must never be taken from a repository's project config.
+	keys := []string{
+		"validate.extra_commands",
+		"notify.audio.player",
+		"session.context_sources",
+		"compact.context_hint",
+	}
+	for _, key := range keys {
+		assert.True(t, config.IsSensitive(key), "key %s runs a command or reads a file", key)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr error
+	}{
+		{name: "unknown key", key: "unknown.key", value: "1", wantErr: config.ErrUnknownKey},
+		{name: "unknown extended key", key: "drift.unknown", value: "1", wantErr: config.ErrUnknownKey},
+		{name: "invalid integer", key: "validate.timeout", value: "soon", wantErr: config.ErrInvalidValue},
+		{name: "invalid boolean", key: "drift.enabled", value: "maybe", wantErr: config.ErrInvalidValue},
+		{name: "invalid float", key: "drift.threshold", value: "high", wantErr: config.ErrInvalidValue},
+		{name: "invalid run_from", key: "discovery.run_from", value: "cwd", wantErr: config.ErrInvalidValue},
+		{name: "invalid template", key: "compact.message_template", value: "no count", wantErr: config.ErrInvalidValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := config.NewManagerWithPath(filepath.Join(t.TempDir(), "config.json"))
+			err := m.Set(context.Background(), tt.key, tt.value)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("reset unknown key", func(t *testing.T) {
+		m := config.NewManagerWithPath(filepath.Join(t.TempDir(), "config.json"))
+		require.ErrorIs(t, m.Reset(context.Background(), "unknown.key"), config.ErrUnknownKey)
+	})
+
+	t.Run("typed mismatch", func(t *testing.T) {
+		m := config.NewManagerWithPath(filepath.Join(t.TempDir(), "config.json"))
+		err := m.SetTyped(context.Background(), "validate.timeout", "1", config.TypeBool)
+		require.ErrorIs(t, err, config.ErrInvalidValue)
+	})
+}
+
+func TestConvertLegacy(t *testing.T) {
+	legacy := []byte(`{
+		"validate": {"timeout": 90, "extra_commands": ["make vet"]},
+		"compact": {"threshold": 75},
+		"notify": {"quiet_hours": {"enabled": true, "start": "22:00"}},
+		"unknown": {"ignored": true}
+	}`)
+
+	cfg, err := config.ConvertLegacy(legacy)
+	require.NoError(t, err)
+
+	defaults := config.ExportGetDefaultConfig()
+	assert.Equal(t, 90, cfg.Validate.Timeout)
+	assert.Equal(t, defaults.Validate.Cooldown, cfg.Validate.Cooldown)
+	assert.Equal(t, []string{"make vet"}, cfg.Validate.ExtraCommands)
+	assert.Equal(t, 75, cfg.Compact.Threshold)
+	assert.Equal(t, defaults.Compact.MessageTemplate, cfg.Compact.MessageTemplate)
+	assert.True(t, cfg.Notify.QuietHours.Enabled)
+	assert.Equal(t, "22:00", cfg.Notify.QuietHours.Start)
+	assert.Equal(t, defaults.Notify.QuietHours.End, cfg.Notify.QuietHours.End)
+
+	_, err = config.ConvertLegacy([]byte("not json"))
+	require.Error(t, err)
+}
+
+func TestTemplate(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		data, err := config.Template(false)
+		require.NoError(t, err)
+
+		var values config.Values
+		require.NoError(t, json.Unmarshal(data, &values))
+		assert.Equal(t, config.GetDefaultConfig(), &values)
+	})
+
+	t.Run("with comments", func(t *testing.T) {
+		data, err := config.Template(true)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "    // Validation timeout in seconds\n    \"timeout\": 60,")
+		assert.Contains(t, string(data), "      // Directory containing MP3 files, or a comma-separated list of directories")
+
+		var plain strings.Builder
+		for line := range strings.Lines(string(data)) {
+			if !strings.HasPrefix(strings.TrimSpace(line), "//") {
+				plain.WriteString(line)
+			}
+		}
+		var values config.Values
+		require.NoError(t, json.Unmarshal([]byte(plain.String()), &values))
+		assert.Equal(t, config.GetDefaultConfig(), &values, "without its comments it parses back")
+	})
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// ProjectConfigFile is the per-project config file read from the root of
+// the project containing the working directory.
+const ProjectConfigFile = ".cc-tools.json"
+
+// LoadForDir returns the configuration for work in dir, or the current
+// directory when dir is empty. See [Manager.ConfigForDir] for how the
+// project file is merged.
+func LoadForDir(ctx context.Context, dir string, opts ...ManagerOption) (*Values, error) {
+	return NewManager(opts...).ConfigForDir(ctx, dir)
+}
+
+// ConfigForDir returns the configuration for work in dir, or the current
+// directory when dir is empty. The ProjectConfigFile at the root of the
+// project containing dir, found with [shared.FindProjectRoot], is merged
+// under the config file and above the defaults: each key the project file
+// sets is used unless the config file sets that key too. Project values
+// also replace the remote overlay. Sensitive keys (see [IsSensitive]) are
+// not taken from the project file, since it comes with the repository
+// rather than from the user; each one it sets is reported on the manager's
+// warning writer. A project file that cannot be read or parsed is reported
+// there too and ignored. The manager's own configuration is not changed, so
+// project values are never saved to the config file.
+func (m *Manager) ConfigForDir(ctx context.Context, dir string) (*Values, error) {
+	cfg, err := m.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := shared.FindProjectRoot(dir, nil)
+	if err != nil {
+		return cfg, nil //nolint:nilerr // outside a project there is nothing to merge
+	}
+
+	path := filepath.Join(root, ProjectConfigFile)
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the fixed file name at the project root
+	if shared.IsMissing(err) {
+		return cfg, nil
+	}
+	if err == nil {
+		var merged *Values
+		if merged, err = m.mergeProject(data, path); err == nil {
+			return merged, nil
+		}
+	}
+
+	_, _ = fmt.Fprintf(m.warnOut, "cc-tools: ignoring project config %s: %v\n", path, err)
+	return cfg, nil
+}
+
+// mergeProject returns a copy of m.config with the project overlay in data,
+// read from path, applied to every key the config file does not set. Each
+// sensitive key the overlay sets is reported on warnOut and skipped.
+func (m *Manager) mergeProject(data []byte, path string) (*Values, error) {
+	values, err := overlayValues(data)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := *m.config
+	project := &Manager{
+		configPath: "",
+		config:     &merged,
+		noFile:     true,
+		warnOut:    io.Discard,
+		httpClient: nil,
+		remoteURL:  "",
+		fileKeys:   nil,
+	}
+
+	for _, key := range allKeys() {
+		projectValue, ok := values[key]
+		if !ok || m.fileKeys[key] {
+			continue
+		}
+		if IsSensitive(key) {
+			_, _ = fmt.Fprintf(m.warnOut,
+				"cc-tools: ignoring %s from project config %s; set it in the config file instead\n", key, path)
+			continue
+		}
+		if setErr := project.setField(key, projectValue); setErr != nil {
+			return nil, fmt.Errorf("%s: %w", key, setErr)
+		}
+	}
+	return &merged, nil
+}
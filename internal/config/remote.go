@@ -0,0 +1,247 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// RemoteConfigURLEnv names a URL serving a JSON config overlay. When set,
+// the overlay is fetched at load time and applied under the config file.
+const RemoteConfigURLEnv = "CC_TOOLS_CONFIG_URL"
+
+const (
+	// remoteFetchTimeout bounds the overlay request so a slow server does
+	// not hold up hooks.
+	remoteFetchTimeout = 3 * time.Second
+	// remoteCacheTTL is how long a fetched overlay, or a failed fetch, is
+	// reused. Every hook event is a new process, so the result is kept in
+	// the cache directory rather than in memory.
+	remoteCacheTTL = 15 * time.Minute
+	// remoteMaxBytes caps the size of an overlay response.
+	remoteMaxBytes = 1 << 20
+)
+
+// ErrInsecureRemoteURL indicates a remote overlay URL that does not use
+// https, so its content could be changed in transit.
+var ErrInsecureRemoteURL = errors.New("remote config URL must use https")
+
+// HTTPClient sends HTTP requests. *http.Client satisfies it.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ManagerOption configures a Manager.
+type ManagerOption func(*Manager)
+
+// WithHTTPClient overrides the client used to fetch the remote overlay.
+func WithHTTPClient(client HTTPClient) ManagerOption {
+	return func(m *Manager) {
+		m.httpClient = client
+	}
+}
+
+// remoteFetch is a cached overlay response or failure.
+type remoteFetch struct {
+	Body      string `json:"body,omitempty"`
+	Error     string `json:"error,omitempty"`
+	FetchedAt int64  `json:"fetched_at"`
+}
+
+// remoteCachePath returns the file in the cache directory that holds the
+// last fetch from url.
+func remoteCachePath(url string) (string, error) {
+	dir, err := shared.CacheDir("remote-config")
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, fmt.Sprintf("%x.json", sum[:8])), nil
+}
+
+// fetchRemote returns the overlay body served at url, from the cache when
+// it, or a failure, was recorded within remoteCacheTTL. It also reports
+// whether the result is fresh, that is, came from a request made now.
+func (m *Manager) fetchRemote(url string) ([]byte, bool, error) {
+	path, pathErr := remoteCachePath(url)
+	if pathErr == nil {
+		if entry, ok := readRemoteCache(shared.ResolveFallbackFile(path)); ok {
+			if entry.Error != "" {
+				return nil, false, errors.New(entry.Error)
+			}
+			return []byte(entry.Body), false, nil
+		}
+	}
+
+	data, err := m.doFetchRemote(url)
+	if pathErr == nil {
+		m.writeRemoteCache(path, data, err)
+	}
+	return data, true, err
+}
+
+// readRemoteCache returns the fetch recorded at path when it is younger
+// than remoteCacheTTL.
+func readRemoteCache(path string) (remoteFetch, bool) {
+	var entry remoteFetch
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path is derived from the cache directory
+	if err != nil || json.Unmarshal(data, &entry) != nil {
+		return entry, false
+	}
+
+	return entry, time.Since(time.Unix(entry.FetchedAt, 0)) < remoteCacheTTL
+}
+
+// writeRemoteCache records the result of fetching the overlay at path. A
+// cache that cannot be written only costs a fetch on the next load.
+func (m *Manager) writeRemoteCache(path string, body []byte, fetchErr error) {
+	entry := remoteFetch{Body: string(body), Error: "", FetchedAt: time.Now().Unix()}
+	if fetchErr != nil {
+		entry.Body = ""
+		entry.Error = fetchErr.Error()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	dir, err := shared.WritableDir(filepath.Dir(path), m.warnOut)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(dir, filepath.Base(path)), data, 0o600)
+}
+
+func (m *Manager) doFetchRemote(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch: unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, remoteMaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return data, nil
+}
+
+// applyRemoteOverlay fetches the overlay named by RemoteConfigURLEnv and
+// applies it to m.config: each key the overlay sets is used unless the
+// config file sets that key. Sensitive keys are never taken from the
+// overlay. A URL that is not https is reported on warnOut and skipped. A
+// fetch or parse failure, and each sensitive key skipped, is reported on
+// warnOut when the overlay is fetched, not each time a cached copy is used.
+func (m *Manager) applyRemoteOverlay() {
+	url := m.remoteURL
+	if url == "" {
+		return
+	}
+
+	if err := checkRemoteURL(url); err != nil {
+		_, _ = fmt.Fprintf(m.warnOut, "cc-tools: ignoring remote config from %s: %v\n", url, err)
+		return
+	}
+
+	data, fresh, err := m.fetchRemote(url)
+	if err == nil {
+		err = m.mergeRemote(data, fresh)
+	}
+	if err != nil && fresh {
+		_, _ = fmt.Fprintf(m.warnOut, "cc-tools: ignoring remote config from %s: %v\n", url, err)
+	}
+}
+
+// checkRemoteURL returns an error unless rawURL is an absolute https URL.
+func checkRemoteURL(rawURL string) error {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parse URL: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return ErrInsecureRemoteURL
+	}
+	return nil
+}
+
+// mergeRemote applies the overlay in data to m.config. With report set,
+// each sensitive key the overlay tries to set is reported on warnOut.
+func (m *Manager) mergeRemote(data []byte, report bool) error {
+	values, err := overlayValues(data)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range allKeys() {
+		remoteValue, ok := values[key]
+		if !ok || m.fileKeys[key] {
+			continue
+		}
+		if IsSensitive(key) {
+			if report {
+				_, _ = fmt.Fprintf(m.warnOut,
+					"cc-tools: ignoring %s from remote config %s; set it in the config file instead\n",
+					key, m.remoteURL)
+			}
+			continue
+		}
+		if setErr := m.setField(key, remoteValue); setErr != nil {
+			return fmt.Errorf("%s: %w", key, setErr)
+		}
+	}
+	return nil
+}
+
+// overlayValues parses the JSON config overlay in data and returns each key
+// it sets to a value other than the default, with that value.
+func overlayValues(data []byte) (map[string]string, error) {
+	overlay := GetDefaultConfig()
+	if err := json.Unmarshal(data, overlay); err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+
+	overlayManager := &Manager{
+		configPath: "",
+		config:     overlay,
+		noFile:     true,
+		warnOut:    io.Discard,
+		httpClient: nil,
+		remoteURL:  "",
+		fileKeys:   nil,
+	}
+	overlayManager.ensureDefaults()
+
+	defaults := GetDefaultConfig()
+	values := make(map[string]string)
+	for _, key := range allKeys() {
+		value, _, _ := overlayManager.GetValue(context.Background(), key)
+		if value != getDefaultValue(defaults, key) {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
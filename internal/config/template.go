@@ -0,0 +1,56 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// templateField matches an object member on a line of indented JSON and
+// captures its indentation, name, and the rest of the line.
+var templateField = regexp.MustCompile(`^(\s*)"([^"\\]+)": (.*)$`)
+
+// Template returns the default configuration as indented JSON with every
+// key present. With comments, each setting is preceded by a // line holding
+// its description; that variant is JSONC (and valid JSON5), meant for
+// reading, and must have its comments removed before use as a config file.
+func Template(withComments bool) ([]byte, error) {
+	data, err := json.MarshalIndent(GetDefaultConfig(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal default config: %w", err)
+	}
+	if !withComments {
+		return append(data, '\n'), nil
+	}
+
+	var b strings.Builder
+	b.WriteString("// cc-tools default configuration.\n")
+	b.WriteString("// Remove these comments before saving it as config.json.\n")
+
+	// path holds the names of the objects enclosing the current line, so
+	// that joined with the member name it forms the dotted key.
+	var path []string
+	for line := range strings.Lines(string(data)) {
+		m := templateField.FindStringSubmatch(strings.TrimRight(line, "\n"))
+		if m == nil {
+			b.WriteString(line)
+			continue
+		}
+
+		depth := len(m[1]) / 2
+		if depth-1 > len(path) {
+			// A member of an object inside an array has no key of its own.
+			b.WriteString(line)
+			continue
+		}
+		path = append(path[:depth-1], m[2])
+		if meta, ok := keyMetadataTable[strings.Join(path, ".")]; ok {
+			_, _ = fmt.Fprintf(&b, "%s// %s\n", m[1], meta.description)
+		}
+		b.WriteString(line)
+	}
+	b.WriteByte('\n')
+
+	return []byte(b.String()), nil
+}
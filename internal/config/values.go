@@ -1,6 +1,10 @@
 package config
 
-import "strconv"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // Values represents the concrete configuration structure.
 type Values struct {
@@ -15,6 +19,10 @@ type Values struct {
 	Drift          DriftValues          `json:"drift"`
 	StopReminder   StopReminderValues   `json:"stop_reminder"`
 	Instinct       InstinctValues       `json:"instinct"`
+	Format         FormatValues         `json:"format"`
+	Discovery      DiscoveryValues      `json:"discovery"`
+	Session        SessionValues        `json:"session"`
+	Hook           HookValues           `json:"hook"`
 }
 
 // NotificationsValues represents notification-related settings.
@@ -24,21 +32,33 @@ type NotificationsValues struct {
 
 // ValidateValues represents validate-related settings.
 type ValidateValues struct {
-	Timeout  int `json:"timeout"`
-	Cooldown int `json:"cooldown"`
+	Timeout             int      `json:"timeout"`
+	Cooldown            int      `json:"cooldown"`
+	ExtraCommands       []string `json:"extra_commands"`
+	Env                 []string `json:"env"`
+	CleanEnv            bool     `json:"clean_env"`
+	InjectCI            bool     `json:"inject_ci"`
+	SkipDuringGitOps    bool     `json:"skip_during_git_ops"`
+	WarnDirty           bool     `json:"warn_dirty"`
+	CaseInsensitiveSkip bool     `json:"case_insensitive_skip"`
 }
 
 // CompactValues represents compact context reminder settings.
 type CompactValues struct {
-	Threshold        int `json:"threshold"`
-	ReminderInterval int `json:"reminder_interval"`
+	Threshold        int    `json:"threshold"`
+	ReminderInterval int    `json:"reminder_interval"`
+	MessageTemplate  string `json:"message_template"`
+	Snapshot         bool   `json:"snapshot"`
+	StateDir         string `json:"state_dir"`
+	ContextHint      string `json:"context_hint"`
 }
 
 // NotifyValues represents notification dispatch settings.
 type NotifyValues struct {
-	QuietHours QuietHoursValues `json:"quiet_hours"`
-	Audio      AudioValues      `json:"audio"`
-	Desktop    DesktopValues    `json:"desktop"`
+	QuietHours  QuietHoursValues `json:"quiet_hours"`
+	Audio       AudioValues      `json:"audio"`
+	Desktop     DesktopValues    `json:"desktop"`
+	MinSeverity string           `json:"min_severity"`
 }
 
 // QuietHoursValues represents quiet hours configuration.
@@ -52,6 +72,7 @@ type QuietHoursValues struct {
 type AudioValues struct {
 	Enabled   bool   `json:"enabled"`
 	Directory string `json:"directory"`
+	Player    string `json:"player"`
 }
 
 // DesktopValues represents desktop notification settings.
@@ -63,6 +84,10 @@ type DesktopValues struct {
 type ObserveValues struct {
 	Enabled       bool `json:"enabled"`
 	MaxFileSizeMB int  `json:"max_file_size_mb"`
+	Anonymize     bool `json:"anonymize_paths"`
+	MaxInputBytes int  `json:"max_input_bytes"`
+	RetentionDays int  `json:"retention_days"`
+	Dedup         bool `json:"dedup"`
 }
 
 // LearningValues represents learning extraction settings.
@@ -107,6 +132,27 @@ type InstinctValues struct {
 	ClusterThreshold int     `json:"cluster_threshold"`
 }
 
+// FormatValues represents format-on-edit settings.
+type FormatValues struct {
+	OnEdit bool `json:"on_edit"`
+}
+
+// DiscoveryValues represents command discovery settings.
+type DiscoveryValues struct {
+	RunFrom string `json:"run_from"`
+}
+
+// SessionValues represents session start settings.
+type SessionValues struct {
+	ContextSources []string `json:"context_sources"`
+}
+
+// HookValues represents hook input settings.
+type HookValues struct {
+	MaxInputBytes           int `json:"max_input_bytes"`
+	SessionStartMinInterval int `json:"session_start_min_interval_seconds"`
+}
+
 // convertValidateFromMap extracts validate settings from a map config.
 func convertValidateFromMap(v *ValidateValues, mapConfig map[string]any) {
 	section, sectionOk := mapConfig["validate"].(map[string]any)
@@ -119,6 +165,37 @@ func convertValidateFromMap(v *ValidateValues, mapConfig map[string]any) {
 	if cooldown, cooldownOk := section["cooldown"].(float64); cooldownOk {
 		v.Cooldown = int(cooldown)
 	}
+	if extra, extraOk := section["extra_commands"].([]any); extraOk {
+		v.ExtraCommands = make([]string, 0, len(extra))
+		for _, item := range extra {
+			if s, ok := item.(string); ok {
+				v.ExtraCommands = append(v.ExtraCommands, s)
+			}
+		}
+	}
+	if env, envOk := section["env"].([]any); envOk {
+		v.Env = make([]string, 0, len(env))
+		for _, item := range env {
+			if s, ok := item.(string); ok {
+				v.Env = append(v.Env, s)
+			}
+		}
+	}
+	if cleanEnv, cleanEnvOk := section["clean_env"].(bool); cleanEnvOk {
+		v.CleanEnv = cleanEnv
+	}
+	if injectCI, injectCIOk := section["inject_ci"].(bool); injectCIOk {
+		v.InjectCI = injectCI
+	}
+	if skipGitOps, skipGitOpsOk := section["skip_during_git_ops"].(bool); skipGitOpsOk {
+		v.SkipDuringGitOps = skipGitOps
+	}
+	if warnDirty, warnDirtyOk := section["warn_dirty"].(bool); warnDirtyOk {
+		v.WarnDirty = warnDirty
+	}
+	if foldSkip, foldSkipOk := section["case_insensitive_skip"].(bool); foldSkipOk {
+		v.CaseInsensitiveSkip = foldSkip
+	}
 }
 
 // convertNotificationsFromMap extracts notification settings from a map config.
@@ -144,9 +221,22 @@ func convertCompactFromMap(c *CompactValues, mapConfig map[string]any) {
 	if interval, intervalOk := section["reminder_interval"].(float64); intervalOk {
 		c.ReminderInterval = int(interval)
 	}
+	if tmpl, tmplOk := section["message_template"].(string); tmplOk {
+		c.MessageTemplate = tmpl
+	}
+	if snapshot, snapshotOk := section["snapshot"].(bool); snapshotOk {
+		c.Snapshot = snapshot
+	}
+	if stateDir, stateDirOk := section["state_dir"].(string); stateDirOk {
+		c.StateDir = stateDir
+	}
+	if hint, hintOk := section["context_hint"].(string); hintOk {
+		c.ContextHint = hint
+	}
 }
 
-// convertNotifyFromMap extracts notify settings (quiet hours, audio, desktop) from a map.
+// convertNotifyFromMap extracts notify settings (quiet hours, audio, desktop,
+// minimum severity) from a map.
 func convertNotifyFromMap(n *NotifyValues, notifyMap map[string]any) {
 	if qhMap, qhOk := notifyMap["quiet_hours"].(map[string]any); qhOk {
 		if enabled, enabledOk := qhMap["enabled"].(bool); enabledOk {
@@ -166,12 +256,18 @@ func convertNotifyFromMap(n *NotifyValues, notifyMap map[string]any) {
 		if dir, dirOk := audioMap["directory"].(string); dirOk {
 			n.Audio.Directory = dir
 		}
+		if player, playerOk := audioMap["player"].(string); playerOk {
+			n.Audio.Player = player
+		}
 	}
 	if desktopMap, desktopOk := notifyMap["desktop"].(map[string]any); desktopOk {
 		if enabled, enabledOk := desktopMap["enabled"].(bool); enabledOk {
 			n.Desktop.Enabled = enabled
 		}
 	}
+	if minSeverity, minSeverityOk := notifyMap["min_severity"].(string); minSeverityOk {
+		n.MinSeverity = minSeverity
+	}
 }
 
 // convertObserveFromMap extracts observe settings from a map config.
@@ -186,6 +282,18 @@ func convertObserveFromMap(o *ObserveValues, mapConfig map[string]any) {
 	if maxSize, maxSizeOk := section["max_file_size_mb"].(float64); maxSizeOk {
 		o.MaxFileSizeMB = int(maxSize)
 	}
+	if anonymize, anonymizeOk := section["anonymize_paths"].(bool); anonymizeOk {
+		o.Anonymize = anonymize
+	}
+	if maxInput, maxInputOk := section["max_input_bytes"].(float64); maxInputOk {
+		o.MaxInputBytes = int(maxInput)
+	}
+	if retention, retentionOk := section["retention_days"].(float64); retentionOk {
+		o.RetentionDays = int(retention)
+	}
+	if dedup, dedupOk := section["dedup"].(bool); dedupOk {
+		o.Dedup = dedup
+	}
 }
 
 // convertLearningFromMap extracts learning settings from a map config.
@@ -256,6 +364,16 @@ func (v *Values) getExtendedValue(key string) (string, bool, error) {
 		return strconv.Itoa(v.Instinct.MaxInstincts), true, nil
 	case keyInstinctClusterThreshold:
 		return strconv.Itoa(v.Instinct.ClusterThreshold), true, nil
+	case keyFormatOnEdit:
+		return strconv.FormatBool(v.Format.OnEdit), true, nil
+	case keyDiscoveryRunFrom:
+		return v.Discovery.RunFrom, true, nil
+	case keySessionContextSources:
+		return strings.Join(v.Session.ContextSources, ","), true, nil
+	case keyHookMaxInputBytes:
+		return strconv.Itoa(v.Hook.MaxInputBytes), true, nil
+	case keyHookSessionStartMinInterval:
+		return strconv.Itoa(v.Hook.SessionStartMinInterval), true, nil
 	default:
 		return "", false, nil
 	}
@@ -292,6 +410,17 @@ func (v *Values) setExtendedField(key, value string) (bool, error) {
 		return true, setIntField(&v.Instinct.MaxInstincts, value)
 	case keyInstinctClusterThreshold:
 		return true, setIntField(&v.Instinct.ClusterThreshold, value)
+	case keyFormatOnEdit:
+		return true, setBoolField(&v.Format.OnEdit, value)
+	case keyDiscoveryRunFrom:
+		return true, setRunFromField(&v.Discovery.RunFrom, value)
+	case keySessionContextSources:
+		setListField(&v.Session.ContextSources, value)
+		return true, nil
+	case keyHookMaxInputBytes:
+		return true, setIntField(&v.Hook.MaxInputBytes, value)
+	case keyHookSessionStartMinInterval:
+		return true, setIntField(&v.Hook.SessionStartMinInterval, value)
 	default:
 		return false, nil
 	}
@@ -326,6 +455,16 @@ func (v *Values) resetExtended(key string, defaults *Values) bool {
 		v.Instinct.MaxInstincts = defaults.Instinct.MaxInstincts
 	case keyInstinctClusterThreshold:
 		v.Instinct.ClusterThreshold = defaults.Instinct.ClusterThreshold
+	case keyFormatOnEdit:
+		v.Format.OnEdit = defaults.Format.OnEdit
+	case keyDiscoveryRunFrom:
+		v.Discovery.RunFrom = defaults.Discovery.RunFrom
+	case keySessionContextSources:
+		v.Session.ContextSources = defaults.Session.ContextSources
+	case keyHookMaxInputBytes:
+		v.Hook.MaxInputBytes = defaults.Hook.MaxInputBytes
+	case keyHookSessionStartMinInterval:
+		v.Hook.SessionStartMinInterval = defaults.Hook.SessionStartMinInterval
 	default:
 		return false
 	}
@@ -394,3 +533,77 @@ func convertInstinctFromMap(i *InstinctValues, mapConfig map[string]any) {
 		i.ClusterThreshold = int(clusterThreshold)
 	}
 }
+
+// convertFormatFromMap extracts format-on-edit settings from a map config.
+func convertFormatFromMap(f *FormatValues, mapConfig map[string]any) {
+	section, sectionOk := mapConfig["format"].(map[string]any)
+	if !sectionOk {
+		return
+	}
+	if onEdit, ok := section["on_edit"].(bool); ok {
+		f.OnEdit = onEdit
+	}
+}
+
+// convertDiscoveryFromMap extracts command discovery settings from a map config.
+func convertDiscoveryFromMap(d *DiscoveryValues, mapConfig map[string]any) {
+	section, sectionOk := mapConfig["discovery"].(map[string]any)
+	if !sectionOk {
+		return
+	}
+	if runFrom, ok := section["run_from"].(string); ok {
+		d.RunFrom = runFrom
+	}
+}
+
+// convertSessionFromMap extracts session start settings from a map config.
+func convertSessionFromMap(s *SessionValues, mapConfig map[string]any) {
+	section, sectionOk := mapConfig["session"].(map[string]any)
+	if !sectionOk {
+		return
+	}
+	if sources, ok := section["context_sources"].([]any); ok {
+		s.ContextSources = make([]string, 0, len(sources))
+		for _, item := range sources {
+			if source, isString := item.(string); isString {
+				s.ContextSources = append(s.ContextSources, source)
+			}
+		}
+	}
+}
+
+// convertHookFromMap extracts hook input settings from a map config.
+func convertHookFromMap(h *HookValues, mapConfig map[string]any) {
+	section, sectionOk := mapConfig["hook"].(map[string]any)
+	if !sectionOk {
+		return
+	}
+	if maxInputBytes, ok := section["max_input_bytes"].(float64); ok {
+		h.MaxInputBytes = int(maxInputBytes)
+	}
+	if interval, ok := section["session_start_min_interval_seconds"].(float64); ok {
+		h.SessionStartMinInterval = int(interval)
+	}
+}
+
+// setRunFromField validates and assigns a discovery.run_from value.
+func setRunFromField(field *string, value string) error {
+	switch value {
+	case "project_root", "file_dir":
+		*field = value
+		return nil
+	default:
+		return fmt.Errorf("%w: must be %q or %q", ErrInvalidValue, "project_root", "file_dir")
+	}
+}
+
+// setSeverityField validates and assigns a notify.min_severity value.
+func setSeverityField(field *string, value string) error {
+	switch value {
+	case "info", "warning", "attention":
+		*field = value
+		return nil
+	default:
+		return fmt.Errorf("%w: must be %q, %q, or %q", ErrInvalidValue, "info", "warning", "attention")
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 
@@ -16,6 +19,9 @@ import (
 // Config represents debug configuration settings.
 type Config struct {
 	EnabledDirs map[string]bool `json:"enabled_dirs"`
+	// EnabledGlobs holds absolute glob patterns, such as /home/me/work/*.
+	// A directory is enabled when it or any parent matches one.
+	EnabledGlobs map[string]bool `json:"enabled_globs,omitempty"`
 }
 
 // Manager handles debug configuration persistence.
@@ -23,6 +29,7 @@ type Manager struct {
 	mu       sync.RWMutex
 	config   *Config
 	filepath string
+	warnOut  io.Writer
 }
 
 // NewManager creates a new debug configuration manager.
@@ -32,19 +39,28 @@ func NewManager() *Manager {
 	configPath := filepath.Join(getConfigDir(), "debug-config.json")
 	return &Manager{
 		mu:       sync.RWMutex{},
-		config:   &Config{EnabledDirs: make(map[string]bool)},
+		config:   &Config{EnabledDirs: make(map[string]bool), EnabledGlobs: make(map[string]bool)},
 		filepath: configPath,
+		warnOut:  os.Stderr,
 	}
 }
 
-// Load reads debug configuration from disk.
+// ConfigPath returns the path to the debug configuration file.
+func (m *Manager) ConfigPath() string {
+	return m.filepath
+}
+
+// Load reads debug configuration from disk, from the temp-directory
+// fallback when an earlier Save could not write the config directory.
 func (m *Manager) Load(_ context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	m.filepath = shared.ResolveFallbackFile(m.filepath)
+
 	data, err := os.ReadFile(m.filepath)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if shared.IsMissing(err) {
 			return nil
 		}
 		return fmt.Errorf("read debug config: %w", err)
@@ -62,12 +78,16 @@ func (m *Manager) Load(_ context.Context) error {
 	if config.EnabledDirs == nil {
 		config.EnabledDirs = make(map[string]bool)
 	}
+	if config.EnabledGlobs == nil {
+		config.EnabledGlobs = make(map[string]bool)
+	}
 
 	m.config = &config
 	return nil
 }
 
-// Save writes debug configuration to disk.
+// Save writes debug configuration to disk. When the config directory cannot
+// be written, the file is saved in its temp-directory fallback instead.
 func (m *Manager) Save(_ context.Context) error {
 	m.mu.RLock()
 	data, err := json.MarshalIndent(m.config, "", "  ")
@@ -77,10 +97,11 @@ func (m *Manager) Save(_ context.Context) error {
 		return fmt.Errorf("marshal debug config: %w", err)
 	}
 
-	dir := filepath.Dir(m.filepath)
-	if mkdirErr := os.MkdirAll(dir, 0o750); mkdirErr != nil {
-		return fmt.Errorf("create config dir: %w", mkdirErr)
+	dir, dirErr := shared.WritableDir(filepath.Dir(m.filepath), m.warnOut)
+	if dirErr != nil {
+		return fmt.Errorf("create config dir: %w", dirErr)
 	}
+	m.filepath = filepath.Join(dir, filepath.Base(m.filepath))
 
 	data = append(data, '\n')
 
@@ -120,8 +141,41 @@ func (m *Manager) Enable(ctx context.Context, dir string) (string, error) {
 	return logFile, nil
 }
 
-// Disable turns off debug logging for a directory.
+// EnableGlob turns on debug logging for every directory matching pattern,
+// and their children, and returns the pattern as stored. A leading ~ is
+// expanded and the pattern is made absolute now, so it does not depend on
+// where it is later checked from.
+func (m *Manager) EnableGlob(ctx context.Context, pattern string) (string, error) {
+	absPattern, err := normalizeGlob(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	if loadErr := m.Load(ctx); loadErr != nil {
+		return "", loadErr
+	}
+
+	m.mu.Lock()
+	if m.config.EnabledGlobs == nil {
+		m.config.EnabledGlobs = make(map[string]bool)
+	}
+	m.config.EnabledGlobs[absPattern] = true
+	m.mu.Unlock()
+
+	if saveErr := m.Save(ctx); saveErr != nil {
+		return "", saveErr
+	}
+
+	return absPattern, nil
+}
+
+// Disable turns off debug logging for a directory, or for a glob pattern
+// previously passed to EnableGlob.
 func (m *Manager) Disable(ctx context.Context, dir string) error {
+	if IsGlob(dir) {
+		return m.disableGlob(ctx, dir)
+	}
+
 	absDir, err := filepath.Abs(dir)
 	if err != nil {
 		return fmt.Errorf("get absolute path: %w", err)
@@ -158,9 +212,82 @@ func (m *Manager) IsEnabled(ctx context.Context, dir string) (bool, error) {
 		}
 	}
 
+	for pattern := range m.config.EnabledGlobs {
+		if matchesDirOrParent(pattern, absDir) {
+			return true, nil
+		}
+	}
+
 	return false, nil
 }
 
+// GetEnabledGlobs returns all glob patterns with debug logging enabled,
+// sorted.
+func (m *Manager) GetEnabledGlobs(ctx context.Context) ([]string, error) {
+	if loadErr := m.Load(ctx); loadErr != nil {
+		return nil, loadErr
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(m.config.EnabledGlobs)), nil
+}
+
+// IsGlob reports whether path contains glob metacharacters and so should be
+// passed to EnableGlob rather than Enable.
+func IsGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// disableGlob removes a pattern added by EnableGlob.
+func (m *Manager) disableGlob(ctx context.Context, pattern string) error {
+	absPattern, err := normalizeGlob(pattern)
+	if err != nil {
+		return err
+	}
+
+	if loadErr := m.Load(ctx); loadErr != nil {
+		return loadErr
+	}
+
+	m.mu.Lock()
+	delete(m.config.EnabledGlobs, absPattern)
+	m.mu.Unlock()
+
+	return m.Save(ctx)
+}
+
+// normalizeGlob expands ~ and environment variables in pattern, makes it
+// absolute, and checks that it is a valid filepath.Match pattern.
+func normalizeGlob(pattern string) (string, error) {
+	absPattern, err := shared.ExpandPath(pattern)
+	if err != nil {
+		return "", fmt.Errorf("expand pattern: %w", err)
+	}
+
+	if _, matchErr := filepath.Match(absPattern, ""); matchErr != nil {
+		return "", fmt.Errorf("invalid pattern %q: %w", pattern, matchErr)
+	}
+
+	return absPattern, nil
+}
+
+// matchesDirOrParent reports whether dir or any of its parents matches
+// pattern.
+func matchesDirOrParent(pattern, dir string) bool {
+	for {
+		if ok, _ := filepath.Match(pattern, dir); ok {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
 // GetEnabledDirs returns all directories with debug logging enabled.
 func (m *Manager) GetEnabledDirs(ctx context.Context) ([]string, error) {
 	if loadErr := m.Load(ctx); loadErr != nil {
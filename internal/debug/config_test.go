@@ -1,6 +1,7 @@
 package debug_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"os"
@@ -275,6 +276,29 @@ func TestManagerSave(t *testing.T) {
 	}
 }
 
+func TestManagerSave_UnwritableDirFallsBack(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("TMPDIR", t.TempDir())
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".config"), nil, 0o600))
+	configPath := filepath.Join(home, ".config", "cc-tools", "debug-config.json")
+
+	var warn bytes.Buffer
+	m := debug.NewTestManager(configPath)
+	m.SetWarnOutput(&warn)
+	_, err := m.Enable(ctx, "/work/project")
+	require.NoError(t, err)
+
+	assert.Contains(t, warn.String(), "cannot write to")
+	assert.Equal(t, filepath.Join(shared.FallbackDir(filepath.Dir(configPath)), "debug-config.json"), m.ConfigPath())
+
+	// A new manager for the same path reads the fallback copy.
+	reloaded := debug.NewTestManager(configPath)
+	enabled, err := reloaded.IsEnabled(ctx, "/work/project")
+	require.NoError(t, err)
+	assert.True(t, enabled)
+}
+
 func TestManagerEnable(t *testing.T) {
 	ctx := context.Background()
 
@@ -531,6 +555,56 @@ func TestManagerIsEnabled(t *testing.T) {
 	}
 }
 
+func TestManagerEnableGlob(t *testing.T) {
+	ctx := context.Background()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	m := debug.NewTestManager(filepath.Join(t.TempDir(), "debug-config.json"))
+
+	pattern, err := m.EnableGlob(ctx, "~/work/*")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "work", "*"), pattern, "~ should be expanded when the glob is stored")
+
+	tests := []struct {
+		name string
+		dir  string
+		want bool
+	}{
+		{name: "first sibling repo", dir: filepath.Join(home, "work", "api"), want: true},
+		{name: "second sibling repo", dir: filepath.Join(home, "work", "web"), want: true},
+		{name: "child of a matching repo", dir: filepath.Join(home, "work", "web", "src", "app"), want: true},
+		{name: "glob parent itself", dir: filepath.Join(home, "work"), want: false},
+		{name: "non-matching path", dir: filepath.Join(home, "personal", "blog"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A fresh manager proves the pattern was persisted.
+			fresh := debug.NewTestManager(m.ConfigPath())
+			enabled, isErr := fresh.IsEnabled(ctx, tt.dir)
+			require.NoError(t, isErr)
+			assert.Equal(t, tt.want, enabled)
+		})
+	}
+
+	globs, err := m.GetEnabledGlobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{pattern}, globs)
+
+	require.NoError(t, m.Disable(ctx, "~/work/*"))
+	enabled, err := m.IsEnabled(ctx, filepath.Join(home, "work", "api"))
+	require.NoError(t, err)
+	assert.False(t, enabled, "disabling the glob should turn matching repos off")
+}
+
+func TestManagerEnableGlob_InvalidPattern(t *testing.T) {
+	m := debug.NewTestManager(filepath.Join(t.TempDir(), "debug-config.json"))
+
+	_, err := m.EnableGlob(context.Background(), "/work/[")
+	require.ErrorIs(t, err, filepath.ErrBadPattern)
+}
+
 func TestManagerGetEnabledDirs(t *testing.T) {
 	ctx := context.Background()
 
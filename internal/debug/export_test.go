@@ -1,16 +1,20 @@
 package debug
 
 import (
+	"io"
 	"os"
 	"sync"
+
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // NewTestManager creates a Manager with a custom config path for testing.
 func NewTestManager(configPath string) *Manager {
 	return &Manager{
 		mu:       sync.RWMutex{},
-		config:   &Config{EnabledDirs: make(map[string]bool)},
+		config:   &Config{EnabledDirs: make(map[string]bool), EnabledGlobs: make(map[string]bool)},
 		filepath: configPath,
+		warnOut:  io.Discard,
 	}
 }
 
@@ -20,9 +24,16 @@ func NewTestManagerWithConfig(configPath string, config *Config) *Manager {
 		mu:       sync.RWMutex{},
 		config:   config,
 		filepath: configPath,
+		warnOut:  io.Discard,
 	}
 }
 
+// SetWarnOutput sets where the manager reports a switch to a fallback
+// directory.
+func (m *Manager) SetWarnOutput(w io.Writer) {
+	m.warnOut = w
+}
+
 // ManagerConfig returns the manager's config for test assertions.
 func (m *Manager) ManagerConfig() *Config {
 	return m.config
@@ -35,6 +46,7 @@ func NewTestLogger(file *os.File, filePath string, enabled bool) *Logger {
 		file:     file,
 		filePath: filePath,
 		enabled:  enabled,
+		clock:    shared.RealClock{},
 	}
 }
 
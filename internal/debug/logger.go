@@ -5,7 +5,8 @@ import (
 	"fmt"
 	"os"
 	"sync"
-	"time"
+
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // Logger handles debug logging to files.
@@ -14,6 +15,7 @@ type Logger struct {
 	file     *os.File
 	filePath string
 	enabled  bool
+	clock    shared.Clock
 }
 
 // NewLogger creates a new debug logger.
@@ -24,7 +26,7 @@ func NewLogger(ctx context.Context, workingDir string) (*Logger, error) {
 	enabled, _ := manager.IsEnabled(ctx, workingDir)
 
 	if !enabled {
-		return &Logger{mu: sync.Mutex{}, file: nil, filePath: "", enabled: false}, nil
+		return &Logger{mu: sync.Mutex{}, file: nil, filePath: "", enabled: false, clock: shared.RealClock{}}, nil
 	}
 
 	// Get log file path
@@ -35,7 +37,13 @@ func NewLogger(ctx context.Context, workingDir string) (*Logger, error) {
 	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
 	if err != nil {
 		// If we can't open the file, create a disabled logger
-		return &Logger{mu: sync.Mutex{}, file: nil, filePath: "", enabled: false}, fmt.Errorf("open log file: %w", err)
+		return &Logger{
+			mu:       sync.Mutex{},
+			file:     nil,
+			filePath: "",
+			enabled:  false,
+			clock:    shared.RealClock{},
+		}, fmt.Errorf("open log file: %w", err)
 	}
 
 	return &Logger{
@@ -43,9 +51,17 @@ func NewLogger(ctx context.Context, workingDir string) (*Logger, error) {
 		file:     file,
 		filePath: logPath,
 		enabled:  true,
+		clock:    shared.RealClock{},
 	}, nil
 }
 
+// SetClock replaces the clock used to timestamp log lines.
+func (l *Logger) SetClock(clock shared.Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = clock
+}
+
 // Logf writes a debug message if logging is enabled.
 func (l *Logger) Logf(format string, args ...any) {
 	if !l.enabled || l.file == nil {
@@ -55,7 +71,7 @@ func (l *Logger) Logf(format string, args ...any) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
+	timestamp := l.clock.Now().Format("2006-01-02 15:04:05.000")
 	message := fmt.Sprintf(format, args...)
 
 	// Write to file with timestamp
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/riddopic/cc-tools/internal/debug"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // setupEnabledLogger creates a test logger with file output for writing tests.
@@ -421,3 +422,19 @@ func TestLoggerDisabledOperations(t *testing.T) {
 		t.Errorf("Close on disabled logger should not error: %v", err)
 	}
 }
+
+func TestLoggerUsesClock(t *testing.T) {
+	logger, logPath := setupEnabledLogger(t)
+	defer logger.Close()
+
+	logger.SetClock(shared.NewFakeClock(time.Date(2026, 3, 14, 15, 9, 26, 535000000, time.Local)))
+	logger.Logf("frozen")
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	if want := "[2026-03-14 15:09:26.535] frozen\n"; string(data) != want {
+		t.Errorf("log = %q, want %q", data, want)
+	}
+}
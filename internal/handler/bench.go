@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"slices"
+	"time"
+)
+
+// LatencyStats summarizes the latency of one handler over repeated
+// dispatches.
+type LatencyStats struct {
+	Name   string
+	Runs   int
+	Errors int
+	Mean   time.Duration
+	P95    time.Duration
+}
+
+// LatencyRecorder collects handler timings across dispatches. Pass its
+// Record method to WithTimingFunc.
+type LatencyRecorder struct {
+	names   []string
+	samples map[string][]time.Duration
+	errors  map[string]int
+}
+
+// NewLatencyRecorder creates an empty LatencyRecorder.
+func NewLatencyRecorder() *LatencyRecorder {
+	return &LatencyRecorder{
+		names:   nil,
+		samples: make(map[string][]time.Duration),
+		errors:  make(map[string]int),
+	}
+}
+
+// Record adds one handler run. It has the signature of a TimingFunc.
+func (l *LatencyRecorder) Record(name string, elapsed time.Duration, err error) {
+	if _, seen := l.samples[name]; !seen {
+		l.names = append(l.names, name)
+	}
+	l.samples[name] = append(l.samples[name], elapsed)
+	if err != nil {
+		l.errors[name]++
+	}
+}
+
+// Stats returns the latency of each recorded handler, in the order the
+// handlers first ran. P95 is the nearest-rank 95th percentile.
+func (l *LatencyRecorder) Stats() []LatencyStats {
+	stats := make([]LatencyStats, 0, len(l.names))
+	for _, name := range l.names {
+		sorted := slices.Sorted(slices.Values(l.samples[name]))
+
+		var total time.Duration
+		for _, d := range sorted {
+			total += d
+		}
+
+		rank := (len(sorted)*95 + 99) / 100
+		stats = append(stats, LatencyStats{
+			Name:   name,
+			Runs:   len(sorted),
+			Errors: l.errors[name],
+			Mean:   total / time.Duration(len(sorted)),
+			P95:    sorted[rank-1],
+		})
+	}
+	return stats
+}
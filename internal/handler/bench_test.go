@@ -0,0 +1,28 @@
+package handler_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riddopic/cc-tools/internal/handler"
+)
+
+func TestLatencyRecorder(t *testing.T) {
+	rec := handler.NewLatencyRecorder()
+	for i := 1; i <= 20; i++ {
+		rec.Record("slow", time.Duration(i)*time.Millisecond, nil)
+		if i <= 2 {
+			rec.Record("fast", time.Millisecond, errors.New("boom"))
+		}
+	}
+
+	assert.Equal(t, []handler.LatencyStats{
+		{Name: "slow", Runs: 20, Errors: 0, Mean: 10500 * time.Microsecond, P95: 19 * time.Millisecond},
+		{Name: "fast", Runs: 2, Errors: 2, Mean: time.Millisecond, P95: time.Millisecond},
+	}, rec.Stats(), "handlers are listed in the order they first ran")
+
+	assert.Empty(t, handler.NewLatencyRecorder().Stats())
+}
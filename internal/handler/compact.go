@@ -7,11 +7,17 @@ import (
 	"path/filepath"
 
 	"github.com/riddopic/cc-tools/internal/compact"
+	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/session"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
-// Compile-time interface check.
-var _ Handler = (*LogCompactionHandler)(nil)
+// Compile-time interface checks.
+var (
+	_ Handler = (*LogCompactionHandler)(nil)
+	_ Handler = (*SessionSnapshotHandler)(nil)
+)
 
 // LogCompactionOption configures a LogCompactionHandler.
 type LogCompactionOption func(*LogCompactionHandler)
@@ -23,15 +29,24 @@ func WithCompactLogDir(dir string) LogCompactionOption {
 	}
 }
 
+// WithCompactClock overrides the clock used to timestamp log entries.
+func WithCompactClock(clock shared.Clock) LogCompactionOption {
+	return func(h *LogCompactionHandler) {
+		h.clock = clock
+	}
+}
+
 // LogCompactionHandler logs PreCompact events for debugging.
 type LogCompactionHandler struct {
 	logDir string
+	clock  shared.Clock
 }
 
 // NewLogCompactionHandler creates a new LogCompactionHandler.
 func NewLogCompactionHandler(opts ...LogCompactionOption) *LogCompactionHandler {
 	h := &LogCompactionHandler{
 		logDir: "",
+		clock:  shared.RealClock{},
 	}
 	for _, opt := range opts {
 		opt(h)
@@ -47,17 +62,96 @@ func (h *LogCompactionHandler) Name() string { return "log-compaction" }
 func (h *LogCompactionHandler) Handle(_ context.Context, _ *hookcmd.HookInput) (*Response, error) {
 	logDir := h.logDir
 	if logDir == "" {
-		homeDir, err := os.UserHomeDir()
+		dir, err := shared.CacheDir()
 		if err != nil {
-			return nil, fmt.Errorf("get home directory: %w", err)
+			return nil, err
 		}
 
-		logDir = filepath.Join(homeDir, ".cache", "cc-tools")
+		logDir = dir
 	}
 
-	if err := compact.LogCompaction(logDir); err != nil {
+	if err := compact.LogCompaction(logDir, h.clock); err != nil {
 		return nil, fmt.Errorf("log compaction: %w", err)
 	}
 
 	return &Response{ExitCode: 0}, nil
 }
+
+// SessionSnapshotOption configures a SessionSnapshotHandler.
+type SessionSnapshotOption func(*SessionSnapshotHandler)
+
+// WithSnapshotHomeDir overrides the home directory for testing.
+func WithSnapshotHomeDir(dir string) SessionSnapshotOption {
+	return func(h *SessionSnapshotHandler) {
+		h.homeDir = dir
+	}
+}
+
+// WithSnapshotClock overrides the clock used to timestamp snapshots.
+func WithSnapshotClock(clock shared.Clock) SessionSnapshotOption {
+	return func(h *SessionSnapshotHandler) {
+		h.clock = clock
+	}
+}
+
+// SessionSnapshotHandler saves a copy of the stored session before a
+// compaction when compact.snapshot is enabled.
+type SessionSnapshotHandler struct {
+	cfg     *config.Values
+	homeDir string
+	clock   shared.Clock
+}
+
+// NewSessionSnapshotHandler creates a new SessionSnapshotHandler.
+func NewSessionSnapshotHandler(cfg *config.Values, opts ...SessionSnapshotOption) *SessionSnapshotHandler {
+	h := &SessionSnapshotHandler{
+		cfg:     cfg,
+		homeDir: "",
+		clock:   shared.RealClock{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Name returns the handler identifier.
+func (h *SessionSnapshotHandler) Name() string { return "session-snapshot" }
+
+// Enabled reports whether compact.snapshot is set.
+func (h *SessionSnapshotHandler) Enabled() bool { return h.cfg != nil && h.cfg.Compact.Snapshot }
+
+// Handle writes the session to ~/.claude/sessions/snapshots. Sessions that
+// have not been stored yet are skipped.
+func (h *SessionSnapshotHandler) Handle(_ context.Context, input *hookcmd.HookInput) (*Response, error) {
+	if !h.Enabled() || input.SessionID == "" {
+		return &Response{ExitCode: 0}, nil
+	}
+
+	homeDir := h.homeDir
+	if homeDir == "" {
+		var err error
+
+		homeDir, err = os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("get home directory: %w", err)
+		}
+	}
+
+	store := session.NewStore(filepath.Join(homeDir, ".claude", "sessions"))
+
+	sess, err := store.Load(string(input.SessionID))
+	if err != nil {
+		return &Response{ExitCode: 0}, nil //nolint:nilerr // a session that cannot be loaded is skipped
+	}
+
+	if _, snapErr := store.Snapshot(sess, h.clock.Now()); snapErr != nil {
+		return &Response{
+			ExitCode: 0,
+			Stderr:   fmt.Sprintf("[session-snapshot] %v\n", snapErr),
+		}, nil
+	}
+
+	return &Response{ExitCode: 0}, nil
+}
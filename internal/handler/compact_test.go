@@ -6,12 +6,16 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/handler"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/session"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // ---------------------------------------------------------------------
@@ -45,6 +49,20 @@ func TestLogCompactionHandler_Handle(t *testing.T) {
 	assert.Contains(t, string(data), "compaction triggered")
 }
 
+func TestLogCompactionHandler_Clock(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	clock := shared.NewFakeClock(time.Date(2026, 3, 14, 15, 9, 26, 0, time.Local))
+
+	h := handler.NewLogCompactionHandler(handler.WithCompactLogDir(tmpDir), handler.WithCompactClock(clock))
+	_, err := h.Handle(context.Background(), &hookcmd.HookInput{HookEventName: hookcmd.EventPreCompact})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "compaction-log.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "[2026-03-14 15:09:26] compaction triggered\n", string(data))
+}
+
 func TestLogCompactionHandler_ImplementsHandler(t *testing.T) {
 	t.Parallel()
 	var _ handler.Handler = handler.NewLogCompactionHandler()
@@ -101,3 +119,75 @@ func TestLogCompactionHandler_EntryFormat(t *testing.T) {
 	assert.Regexp(t, `^\[\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\] compaction triggered$`,
 		line, "log entry should match timestamp format")
 }
+
+// ---------------------------------------------------------------------
+// SessionSnapshotHandler
+// ---------------------------------------------------------------------
+
+func TestSessionSnapshotHandler_WritesSnapshot(t *testing.T) {
+	t.Parallel()
+	homeDir := t.TempDir()
+	sessionsDir := filepath.Join(homeDir, ".claude", "sessions")
+	require.NoError(t, session.NewStore(sessionsDir).Save(&session.Session{
+		Version: "1",
+		ID:      "abc123",
+		Date:    "2026-03-14",
+		Title:   "Snapshot me",
+	}))
+
+	cfg := &config.Values{Compact: config.CompactValues{Snapshot: true}}
+	clock := shared.NewFakeClock(time.Date(2026, 3, 14, 15, 9, 26, 0, time.Local))
+	h := handler.NewSessionSnapshotHandler(cfg,
+		handler.WithSnapshotHomeDir(homeDir), handler.WithSnapshotClock(clock))
+
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
+		HookEventName: hookcmd.EventPreCompact,
+		SessionID:     "abc123",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.ExitCode)
+	assert.Empty(t, resp.Stderr)
+
+	data, err := os.ReadFile(filepath.Join(sessionsDir, "snapshots", "abc123-20260314-150926.json"))
+	require.NoError(t, err, "snapshot file should be created")
+	assert.Contains(t, string(data), "Snapshot me")
+}
+
+func TestSessionSnapshotHandler_Skips(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		enabled bool
+		seed    bool
+	}{
+		{name: "disabled", enabled: false, seed: true},
+		{name: "session not stored", enabled: true, seed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			homeDir := t.TempDir()
+			sessionsDir := filepath.Join(homeDir, ".claude", "sessions")
+			if tt.seed {
+				require.NoError(t, session.NewStore(sessionsDir).Save(&session.Session{
+					Version: "1",
+					ID:      "abc123",
+					Date:    "2026-03-14",
+				}))
+			}
+
+			cfg := &config.Values{Compact: config.CompactValues{Snapshot: tt.enabled}}
+			h := handler.NewSessionSnapshotHandler(cfg, handler.WithSnapshotHomeDir(homeDir))
+
+			resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
+				HookEventName: hookcmd.EventPreCompact,
+				SessionID:     "abc123",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 0, resp.ExitCode)
+			assert.NoDirExists(t, filepath.Join(sessionsDir, "snapshots"))
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+// Compile-time interface check.
+var _ Handler = (*ContextSourcesHandler)(nil)
+
+const (
+	// contextSourceCmdPrefix marks a session.context_sources entry as a
+	// command line rather than a file path.
+	contextSourceCmdPrefix = "cmd:"
+
+	// contextSourceTimeout bounds a single context command.
+	contextSourceTimeout = 5 * time.Second
+
+	// maxContextSourceBytes caps how much of one source is added to the
+	// context so a large file or noisy command cannot flood it.
+	maxContextSourceBytes = 8 << 10
+)
+
+// ContextSourcesOption configures a ContextSourcesHandler.
+type ContextSourcesOption func(*ContextSourcesHandler)
+
+// WithContextSourcesRunner overrides the command runner for testing.
+func WithContextSourcesRunner(r hooks.CommandRunner) ContextSourcesOption {
+	return func(h *ContextSourcesHandler) {
+		h.runner = r
+	}
+}
+
+// ContextSourcesHandler adds the contents of configured files and the
+// output of configured commands as additional context on session start.
+type ContextSourcesHandler struct {
+	cfg    *config.Values
+	runner hooks.CommandRunner
+}
+
+// NewContextSourcesHandler creates a new ContextSourcesHandler.
+func NewContextSourcesHandler(cfg *config.Values, opts ...ContextSourcesOption) *ContextSourcesHandler {
+	h := &ContextSourcesHandler{
+		cfg:    cfg,
+		runner: hooks.NewDefaultDependencies().Runner,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Name returns the handler identifier.
+func (h *ContextSourcesHandler) Name() string { return "context-sources" }
+
+// Enabled reports whether session.context_sources lists at least one source.
+func (h *ContextSourcesHandler) Enabled() bool {
+	return h.cfg != nil && len(h.cfg.Session.ContextSources) > 0
+}
+
+// Handle gathers each entry in session.context_sources. File paths are
+// resolved against the session's working directory, and entries prefixed
+// with "cmd:" run there with a timeout. Sources that fail or produce no
+// output are skipped with a note on stderr.
+func (h *ContextSourcesHandler) Handle(ctx context.Context, input *hookcmd.HookInput) (*Response, error) {
+	if !h.Enabled() {
+		return &Response{ExitCode: 0}, nil
+	}
+
+	var (
+		additionalCtx []string
+		stderr        strings.Builder
+	)
+
+	for _, source := range h.cfg.Session.ContextSources {
+		label, text, err := h.gather(ctx, input.Cwd, source)
+		if err != nil {
+			fmt.Fprintf(&stderr, "[context-sources] skipped %s: %v\n", source, err)
+			continue
+		}
+
+		// Trim only blank lines at the start so that leading columns,
+		// such as those in `git status -s`, are kept.
+		text = strings.TrimLeft(strings.TrimRightFunc(text, unicode.IsSpace), "\r\n")
+		if text == "" {
+			continue
+		}
+		if len(text) > maxContextSourceBytes {
+			text = text[:maxContextSourceBytes] + "\n[truncated]"
+		}
+
+		additionalCtx = append(additionalCtx, label+":\n"+text)
+	}
+
+	resp := &Response{ExitCode: 0, Stderr: stderr.String()}
+	if len(additionalCtx) > 0 {
+		resp.Stdout = &HookOutput{
+			Continue:          true,
+			AdditionalContext: additionalCtx,
+		}
+	}
+
+	return resp, nil
+}
+
+// gather returns a label and the text for one context source.
+func (h *ContextSourcesHandler) gather(ctx context.Context, cwd, source string) (string, string, error) {
+	if line, ok := strings.CutPrefix(source, contextSourceCmdPrefix); ok {
+		text, err := h.runCommand(ctx, cwd, strings.TrimSpace(line))
+		return fmt.Sprintf("Output of `%s`", strings.TrimSpace(line)), text, err
+	}
+
+	path := source
+	if !filepath.IsAbs(path) && cwd != "" {
+		path = filepath.Join(cwd, path)
+	}
+
+	// #nosec G304 -- path comes from the user's own configuration.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("read file: %w", err)
+	}
+
+	return "Contents of " + source, string(data), nil
+}
+
+// runCommand runs a context command line from cwd and returns its stdout.
+func (h *ContextSourcesHandler) runCommand(ctx context.Context, cwd, line string) (string, error) {
+	words, err := hooks.SplitCommandLine(line)
+	if err != nil {
+		return "", fmt.Errorf("parse command: %w", err)
+	}
+	if len(words) == 0 {
+		return "", errors.New("empty command")
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, contextSourceTimeout)
+	defer cancel()
+
+	out, err := h.runner.RunContext(runCtx, cwd, words[0], words[1:]...)
+	if err != nil {
+		return "", fmt.Errorf("run command: %w", err)
+	}
+	if out == nil {
+		return "", nil
+	}
+
+	return string(out.Stdout), nil
+}
@@ -0,0 +1,97 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/handler"
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+// outputRunner returns canned stdout per command name and records the
+// working directory and arguments it was called with.
+type outputRunner struct {
+	stdout map[string]string
+	dirs   []string
+	calls  [][]string
+}
+
+func (r *outputRunner) RunContext(
+	_ context.Context, dir, name string, args ...string,
+) (*hooks.CommandOutput, error) {
+	r.dirs = append(r.dirs, dir)
+	r.calls = append(r.calls, append([]string{name}, args...))
+	out, ok := r.stdout[name]
+	if !ok {
+		return nil, errors.New("executable file not found")
+	}
+
+	return &hooks.CommandOutput{Stdout: []byte(out), Stderr: nil}, nil
+}
+
+func (r *outputRunner) LookPath(file string) (string, error) {
+	return "/usr/bin/" + file, nil
+}
+
+func newContextSourcesConfig(sources ...string) *config.Values {
+	return &config.Values{Session: config.SessionValues{ContextSources: sources}}
+}
+
+func TestContextSourcesHandler_Name(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "context-sources", handler.NewContextSourcesHandler(nil).Name())
+}
+
+func TestContextSourcesHandler_FileSource(t *testing.T) {
+	t.Parallel()
+	cwd := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(cwd, ".claude"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(cwd, ".claude", "context.md"), []byte("Use table tests.\n"), 0o600))
+
+	h := handler.NewContextSourcesHandler(newContextSourcesConfig(".claude/context.md"))
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
+		HookEventName: hookcmd.EventSessionStart,
+		Cwd:           cwd,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Stdout)
+	assert.Equal(t, []string{"Contents of .claude/context.md:\nUse table tests."}, resp.Stdout.AdditionalContext)
+	assert.Empty(t, resp.Stderr)
+}
+
+func TestContextSourcesHandler_CommandSource(t *testing.T) {
+	t.Parallel()
+	cwd := t.TempDir()
+	runner := &outputRunner{stdout: map[string]string{"git": " M main.go\n"}, dirs: nil, calls: nil}
+
+	h := handler.NewContextSourcesHandler(
+		newContextSourcesConfig("cmd: git status -s", "cmd:missing-tool --flag"),
+		handler.WithContextSourcesRunner(runner),
+	)
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
+		HookEventName: hookcmd.EventSessionStart,
+		Cwd:           cwd,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Stdout)
+	assert.Equal(t, []string{"Output of `git status -s`:\n M main.go"}, resp.Stdout.AdditionalContext)
+	assert.Equal(t, [][]string{{"git", "status", "-s"}, {"missing-tool", "--flag"}}, runner.calls)
+	assert.Equal(t, []string{cwd, cwd}, runner.dirs)
+	assert.Contains(t, resp.Stderr, "skipped cmd:missing-tool --flag")
+}
+
+func TestContextSourcesHandler_NoSources(t *testing.T) {
+	t.Parallel()
+	h := handler.NewContextSourcesHandler(newContextSourcesConfig())
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{HookEventName: hookcmd.EventSessionStart})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Stdout)
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// Compile-time interface checks.
+var (
+	_ Handler = (*debouncedHandler)(nil)
+	_ Enabler = (*debouncedHandler)(nil)
+)
+
+// SessionStartDebounceOption configures a SessionStartDebounce.
+type SessionStartDebounceOption func(*SessionStartDebounce)
+
+// WithDebounceStateDir overrides the state directory for testing.
+func WithDebounceStateDir(dir string) SessionStartDebounceOption {
+	return func(d *SessionStartDebounce) {
+		d.stateDir = dir
+	}
+}
+
+// WithDebounceClock sets the clock used to time SessionStart events.
+func WithDebounceClock(clock shared.Clock) SessionStartDebounceOption {
+	return func(d *SessionStartDebounce) {
+		d.clock = clock
+	}
+}
+
+// SessionStartDebounce skips the handlers it wraps when SessionStart fires
+// again in the same directory within hook.session_start_min_interval_seconds
+// of the last run, so rapid reconnects do not repeat the heavy work. The
+// last run time is kept per directory under the cache directory.
+type SessionStartDebounce struct {
+	cfg      *config.Values
+	stateDir string
+	clock    shared.Clock
+
+	mu sync.Mutex
+	// decided remembers the outcome per directory so every wrapped handler
+	// in one dispatch agrees, even though the first one records the run.
+	decided map[string]bool
+}
+
+// NewSessionStartDebounce creates a new SessionStartDebounce.
+func NewSessionStartDebounce(cfg *config.Values, opts ...SessionStartDebounceOption) *SessionStartDebounce {
+	d := &SessionStartDebounce{
+		cfg:      cfg,
+		stateDir: "",
+		clock:    shared.RealClock{},
+		mu:       sync.Mutex{},
+		decided:  make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Wrap returns h gated by the debounce. The wrapper keeps the name of h and
+// responds with exit code 0 and no output when the event is skipped.
+func (d *SessionStartDebounce) Wrap(h Handler) Handler {
+	return &debouncedHandler{inner: h, debounce: d}
+}
+
+// interval returns the configured window, or zero when debouncing is off.
+func (d *SessionStartDebounce) interval() time.Duration {
+	if d.cfg == nil || d.cfg.Hook.SessionStartMinInterval <= 0 {
+		return 0
+	}
+	return time.Duration(d.cfg.Hook.SessionStartMinInterval) * time.Second
+}
+
+// skip reports whether SessionStart in cwd falls inside the window. When it
+// does not, the current time is recorded as the last run.
+func (d *SessionStartDebounce) skip(cwd string) bool {
+	interval := d.interval()
+	if interval == 0 {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if skipped, ok := d.decided[cwd]; ok {
+		return skipped
+	}
+
+	stateDir := d.stateDir
+	if stateDir == "" {
+		dir, err := shared.CacheDir("session-start")
+		if err != nil {
+			d.decided[cwd] = false
+			return false
+		}
+		stateDir = dir
+	}
+
+	now := d.clock.Now()
+	path := d.statePath(stateDir, cwd)
+	last, ok := readLastRun(path)
+	skipped := ok && !now.Before(last) && now.Sub(last) < interval
+	if !skipped {
+		_ = os.MkdirAll(stateDir, 0o750)
+		_ = os.WriteFile(path, []byte(strconv.FormatInt(now.UnixNano(), 10)), 0o600)
+	}
+
+	d.decided[cwd] = skipped
+	return skipped
+}
+
+func (d *SessionStartDebounce) statePath(dir, cwd string) string {
+	hash := sha256.Sum256([]byte(cwd))
+	return filepath.Join(dir, fmt.Sprintf("last-%x", hash[:8]))
+}
+
+// readLastRun parses the time recorded at path.
+func readLastRun(path string) (time.Time, bool) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path built from stateDir
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	nanos, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, nanos), true
+}
+
+// debouncedHandler runs inner unless its SessionStartDebounce skips the
+// event.
+type debouncedHandler struct {
+	inner    Handler
+	debounce *SessionStartDebounce
+}
+
+// Name returns the wrapped handler's identifier.
+func (h *debouncedHandler) Name() string { return h.inner.Name() }
+
+// Enabled reports whether the wrapped handler is enabled.
+func (h *debouncedHandler) Enabled() bool {
+	if e, ok := h.inner.(Enabler); ok {
+		return e.Enabled()
+	}
+	return true
+}
+
+// Handle runs the wrapped handler unless the event is debounced.
+func (h *debouncedHandler) Handle(ctx context.Context, input *hookcmd.HookInput) (*Response, error) {
+	if h.debounce.skip(input.Cwd) {
+		return &Response{ExitCode: 0}, nil
+	}
+	return h.inner.Handle(ctx, input)
+}
@@ -0,0 +1,114 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/handler"
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// countingHandler counts how often Handle runs.
+type countingHandler struct {
+	name  string
+	calls int
+}
+
+func (c *countingHandler) Name() string { return c.name }
+
+func (c *countingHandler) Handle(_ context.Context, _ *hookcmd.HookInput) (*handler.Response, error) {
+	c.calls++
+	return &handler.Response{ExitCode: 0, Stderr: c.name + " ran\n"}, nil
+}
+
+// dispatchSessionStart runs one SessionStart hook invocation through a fresh
+// debounce, as each hook runs in its own process.
+func dispatchSessionStart(
+	t *testing.T,
+	cfg *config.Values,
+	stateDir string,
+	clock shared.Clock,
+	cwd string,
+	handlers ...handler.Handler,
+) *handler.Response {
+	t.Helper()
+
+	debounce := handler.NewSessionStartDebounce(cfg,
+		handler.WithDebounceStateDir(stateDir),
+		handler.WithDebounceClock(clock),
+	)
+	r := handler.NewRegistry()
+	for _, h := range handlers {
+		r.Register(hookcmd.EventSessionStart, debounce.Wrap(h))
+	}
+
+	return r.Dispatch(context.Background(), &hookcmd.HookInput{
+		HookEventName: hookcmd.EventSessionStart,
+		Cwd:           cwd,
+	})
+}
+
+func TestSessionStartDebounce(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.Hook.SessionStartMinInterval = 30
+	stateDir := t.TempDir()
+	cwd := t.TempDir()
+	clock := shared.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	first := &countingHandler{name: "first", calls: 0}
+	second := &countingHandler{name: "second", calls: 0}
+
+	resp := dispatchSessionStart(t, cfg, stateDir, clock, cwd, first, second)
+	assert.Equal(t, "first ran\nsecond ran\n", resp.Stderr, "every wrapped handler runs on the first start")
+
+	clock.Advance(5 * time.Second)
+	resp = dispatchSessionStart(t, cfg, stateDir, clock, cwd, first, second)
+	assert.Empty(t, resp.Stderr, "a rapid second start is a no-op")
+	assert.Equal(t, 1, first.calls)
+	assert.Equal(t, 1, second.calls)
+
+	other := t.TempDir()
+	dispatchSessionStart(t, cfg, stateDir, clock, other, first)
+	assert.Equal(t, 2, first.calls, "the window is per directory")
+
+	clock.Advance(25 * time.Second)
+	dispatchSessionStart(t, cfg, stateDir, clock, cwd, first, second)
+	assert.Equal(t, 3, first.calls, "a start after the window runs again")
+	assert.Equal(t, 2, second.calls)
+}
+
+func TestSessionStartDebounce_Off(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	stateDir := t.TempDir()
+	cwd := t.TempDir()
+	clock := shared.NewFakeClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	h := &countingHandler{name: "heavy", calls: 0}
+
+	dispatchSessionStart(t, cfg, stateDir, clock, cwd, h)
+	dispatchSessionStart(t, cfg, stateDir, clock, cwd, h)
+	assert.Equal(t, 2, h.calls, "interval 0 never skips")
+}
+
+func TestSessionStartDebounce_WrapKeepsName(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.StopReminder.Enabled = false
+	debounce := handler.NewSessionStartDebounce(cfg)
+
+	wrapped := debounce.Wrap(handler.NewStopReminderHandler(cfg))
+	assert.Equal(t, "stop-reminder", wrapped.Name())
+
+	enabler, ok := wrapped.(handler.Enabler)
+	require.True(t, ok)
+	assert.False(t, enabler.Enabled(), "Enabled is delegated to the wrapped handler")
+}
@@ -1,19 +1,26 @@
 package handler
 
 import (
+	"slices"
+
 	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
 	"github.com/riddopic/cc-tools/internal/notify"
 )
 
 // NewDefaultRegistry creates a registry with all default handlers wired.
-func NewDefaultRegistry(cfg *config.Values) *Registry {
-	r := NewRegistry()
+func NewDefaultRegistry(cfg *config.Values, opts ...RegistryOption) *Registry {
+	r := NewRegistry(opts...)
 
+	// Session context is cheap and always shown; the rest is skipped when a
+	// reconnect fires SessionStart again within the debounce window.
+	debounce := NewSessionStartDebounce(cfg)
 	r.Register(hookcmd.EventSessionStart,
-		NewSuperpowersHandler(),
-		NewPkgManagerHandler(cfg),
+		debounce.Wrap(NewSuperpowersHandler()),
+		debounce.Wrap(NewPkgManagerHandler(cfg)),
 		NewSessionContextHandler(),
+		debounce.Wrap(NewContextSourcesHandler(cfg)),
+		debounce.Wrap(NewObserveRetentionHandler(cfg)),
 	)
 
 	r.Register(hookcmd.EventSessionEnd,
@@ -28,6 +35,7 @@ func NewDefaultRegistry(cfg *config.Values) *Registry {
 
 	r.Register(hookcmd.EventPostToolUse,
 		NewObserveHandler(cfg, "post"),
+		NewFormatOnEditHandler(cfg),
 	)
 
 	r.Register(hookcmd.EventPostToolUseFailure,
@@ -36,6 +44,7 @@ func NewDefaultRegistry(cfg *config.Values) *Registry {
 
 	r.Register(hookcmd.EventPreCompact,
 		NewLogCompactionHandler(),
+		NewSessionSnapshotHandler(cfg),
 	)
 
 	r.Register(hookcmd.EventUserPromptSubmit,
@@ -47,14 +56,17 @@ func NewDefaultRegistry(cfg *config.Values) *Registry {
 	)
 
 	r.Register(hookcmd.EventNotification,
-		NewNotifyAudioHandler(cfg, WithAudioPlayer(&notify.AFPlayer{})),
-		NewNotifyDesktopHandler(cfg, WithCmdRunner(&notify.OSRunner{})),
-		NewNotifyNtfyHandler(cfg),
+		NewNotifyHandler(cfg, WithCmdRunner(&notify.OSRunner{})),
 	)
 
 	return r
 }
 
+// Handlers returns the handlers registered for event, in dispatch order.
+func (r *Registry) Handlers(event string) []Handler {
+	return slices.Clone(r.handlers[event])
+}
+
 // HasHandlers reports whether the registry has handlers for the given event.
 func (r *Registry) HasHandlers(event string) bool {
 	return len(r.handlers[event]) > 0
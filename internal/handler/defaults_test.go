@@ -33,3 +33,23 @@ func TestNewDefaultRegistry_NilConfig(t *testing.T) {
 	r := handler.NewDefaultRegistry(nil)
 	assert.True(t, r.HasHandlers(hookcmd.EventSessionStart))
 }
+
+func TestRegistry_Handlers(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultConfig()
+	cfg.PreCommit.Enabled = false
+	r := handler.NewDefaultRegistry(cfg)
+
+	var names []string
+	for _, h := range r.Handlers(hookcmd.EventPreToolUse) {
+		names = append(names, h.Name())
+	}
+	assert.Equal(t, []string{"suggest-compact", "observe-pre", "pre-commit-reminder"}, names)
+
+	reminder, ok := r.Handlers(hookcmd.EventPreToolUse)[2].(handler.Enabler)
+	assert.True(t, ok)
+	assert.False(t, reminder.Enabled())
+
+	assert.Empty(t, r.Handlers(hookcmd.EventPermissionRequest))
+}
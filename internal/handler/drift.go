@@ -11,6 +11,7 @@ import (
 
 	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // Compile-time interface check.
@@ -63,9 +64,12 @@ func NewDriftHandler(cfg *config.Values, opts ...DriftOption) *DriftHandler {
 // Name returns the handler identifier.
 func (h *DriftHandler) Name() string { return "drift-detection" }
 
+// Enabled reports whether drift.enabled is set.
+func (h *DriftHandler) Enabled() bool { return h.cfg != nil && h.cfg.Drift.Enabled }
+
 // Handle processes a UserPromptSubmit event, tracking intent and detecting drift.
 func (h *DriftHandler) Handle(_ context.Context, input *hookcmd.HookInput) (*Response, error) {
-	if h.cfg == nil || !h.cfg.Drift.Enabled {
+	if !h.Enabled() {
 		return &Response{ExitCode: 0}, nil
 	}
 
@@ -76,11 +80,11 @@ func (h *DriftHandler) Handle(_ context.Context, input *hookcmd.HookInput) (*Res
 
 	stateDir := h.stateDir
 	if stateDir == "" {
-		homeDir, err := os.UserHomeDir()
+		dir, err := shared.CacheDir("drift")
 		if err != nil {
-			return nil, fmt.Errorf("get home directory: %w", err)
+			return nil, err
 		}
-		stateDir = filepath.Join(homeDir, ".cache", "cc-tools", "drift")
+		stateDir = dir
 	}
 
 	state := h.loadState(stateDir, input.SessionID)
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+// Compile-time interface check.
+var _ Handler = (*ExternalHandler)(nil)
+
+// defaultExternalTimeout bounds an external hook command that does not set
+// its own timeout.
+const defaultExternalTimeout = 30 * time.Second
+
+// ErrInvalidExternalHooks is returned when .claude/hooks.json names an
+// unknown event or declares a hook without a command.
+var ErrInvalidExternalHooks = errors.New("invalid external hooks file")
+
+// ErrNoStdinRunner is returned when the default command runner cannot feed
+// the hook event to a command's standard input.
+var ErrNoStdinRunner = errors.New("command runner does not support stdin")
+
+// ExternalHooksPath returns the project-local file that declares external
+// hook commands.
+func ExternalHooksPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".claude", "hooks.json")
+}
+
+// ExternalHook is one command declared in .claude/hooks.json.
+type ExternalHook struct {
+	// Run is the command line, split with shell quoting rules and run from
+	// the project root.
+	Run string `json:"run"`
+	// Timeout is the time limit in seconds. Zero uses a 30-second default.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// LoadExternalHooks reads .claude/hooks.json under projectRoot, which maps
+// event names to the commands to run for them. A missing file declares no
+// hooks.
+func LoadExternalHooks(projectRoot string) (map[string][]ExternalHook, error) {
+	path := ExternalHooksPath(projectRoot)
+
+	// #nosec G304 -- path is fixed relative to the project root.
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var declared map[string][]ExternalHook
+	if unmarshalErr := json.Unmarshal(data, &declared); unmarshalErr != nil {
+		return nil, fmt.Errorf("%w: parse %s: %w", ErrInvalidExternalHooks, path, unmarshalErr)
+	}
+
+	events := hookcmd.AllEvents()
+	for event, declaredHooks := range declared {
+		if !slices.Contains(events, event) {
+			return nil, fmt.Errorf("%w: %s: unknown event %q", ErrInvalidExternalHooks, path, event)
+		}
+		for _, hook := range declaredHooks {
+			if hook.Run == "" {
+				return nil, fmt.Errorf("%w: %s: %s hook has no run command", ErrInvalidExternalHooks, path, event)
+			}
+			if hook.Timeout < 0 {
+				return nil, fmt.Errorf("%w: %s: %s hook has a negative timeout", ErrInvalidExternalHooks, path, event)
+			}
+		}
+	}
+
+	return declared, nil
+}
+
+// RegisterExternalHooks loads .claude/hooks.json under projectRoot and
+// registers an ExternalHandler for each declared command. They run after
+// the handlers already registered for the event, in file order.
+func RegisterExternalHooks(r *Registry, projectRoot string, opts ...ExternalOption) error {
+	declared, err := LoadExternalHooks(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range hookcmd.AllEvents() {
+		for _, hook := range declared[event] {
+			h, hErr := NewExternalHandler(hook, projectRoot, opts...)
+			if hErr != nil {
+				return hErr
+			}
+			r.Register(event, h)
+		}
+	}
+
+	return nil
+}
+
+// ExternalOption configures an ExternalHandler.
+type ExternalOption func(*ExternalHandler)
+
+// WithExternalRunner overrides the command runner for testing.
+func WithExternalRunner(runner hooks.StdinCommandRunner) ExternalOption {
+	return func(h *ExternalHandler) {
+		h.runner = runner
+	}
+}
+
+// ExternalHandler runs a command declared in .claude/hooks.json with the
+// hook event JSON, as Claude Code sent it, on its standard input. The command's exit code becomes
+// the handler's exit code and its stderr is passed through.
+type ExternalHandler struct {
+	hook   ExternalHook
+	dir    string
+	runner hooks.StdinCommandRunner
+}
+
+// NewExternalHandler creates a handler that runs hook from dir. It returns
+// [ErrNoStdinRunner] when no runner that can feed standard input is
+// available.
+func NewExternalHandler(hook ExternalHook, dir string, opts ...ExternalOption) (*ExternalHandler, error) {
+	h := &ExternalHandler{
+		hook:   hook,
+		dir:    dir,
+		runner: nil,
+	}
+	if runner, ok := hooks.NewDefaultDependencies().Runner.(hooks.StdinCommandRunner); ok {
+		h.runner = runner
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.runner == nil {
+		return nil, ErrNoStdinRunner
+	}
+
+	return h, nil
+}
+
+// Name returns the handler identifier.
+func (h *ExternalHandler) Name() string { return "external:" + h.hook.Run }
+
+// Handle runs the command. Standard output that parses as hook JSON output
+// is passed on; anything else is ignored. A command that cannot be started
+// or that times out is reported as an error.
+func (h *ExternalHandler) Handle(ctx context.Context, input *hookcmd.HookInput) (*Response, error) {
+	words, err := hooks.SplitCommandLine(h.hook.Run)
+	if err != nil {
+		return nil, fmt.Errorf("parse command: %w", err)
+	}
+	if len(words) == 0 {
+		return nil, errors.New("empty command")
+	}
+
+	// Prefer the event as received so commands see fields cc-tools does
+	// not model; inputs built in code have no raw form.
+	payload := []byte(input.Raw)
+	if len(payload) == 0 {
+		payload, err = json.Marshal(input)
+		if err != nil {
+			return nil, fmt.Errorf("marshal hook input: %w", err)
+		}
+	}
+
+	timeout := defaultExternalTimeout
+	if h.hook.Timeout > 0 {
+		timeout = time.Duration(h.hook.Timeout) * time.Second
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out, runErr := h.runner.RunContextStdin(runCtx, payload, h.dir, words[0], words[1:]...)
+
+	exitCode := 0
+	if runErr != nil {
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		exitErr, ok := errors.AsType[*exec.ExitError](runErr)
+		if !ok {
+			return nil, runErr
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	resp := &Response{ExitCode: exitCode}
+	if out == nil {
+		return resp, nil
+	}
+
+	resp.Stderr = string(out.Stderr)
+
+	var hookOut HookOutput
+	if len(out.Stdout) > 0 && json.Unmarshal(out.Stdout, &hookOut) == nil {
+		resp.Stdout = &hookOut
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,180 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/handler"
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+// stdinRunner records the stdin, directory, and command it was called with
+// and returns canned output.
+type stdinRunner struct {
+	stdin []byte
+	dir   string
+	argv  []string
+	out   *hooks.CommandOutput
+}
+
+func (r *stdinRunner) RunContextStdin(
+	_ context.Context, stdin []byte, dir, name string, args ...string,
+) (*hooks.CommandOutput, error) {
+	r.stdin = stdin
+	r.dir = dir
+	r.argv = append([]string{name}, args...)
+	return r.out, nil
+}
+
+// writeExternalHooks writes content to .claude/hooks.json under root.
+func writeExternalHooks(t *testing.T, root, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".claude"), 0o750))
+	require.NoError(t, os.WriteFile(handler.ExternalHooksPath(root), []byte(content), 0o600))
+}
+
+func TestLoadExternalHooks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    map[string][]handler.ExternalHook
+		wantErr bool
+	}{
+		{
+			name:    "declared commands",
+			content: `{"PostToolUse":[{"run":"./scripts/notify.sh"},{"run":"lint --fast","timeout":5}]}`,
+			want: map[string][]handler.ExternalHook{
+				hookcmd.EventPostToolUse: {
+					{Run: "./scripts/notify.sh", Timeout: 0},
+					{Run: "lint --fast", Timeout: 5},
+				},
+			},
+			wantErr: false,
+		},
+		{name: "unknown event", content: `{"PostToolUze":[{"run":"x"}]}`, want: nil, wantErr: true},
+		{name: "missing run", content: `{"Stop":[{"timeout":5}]}`, want: nil, wantErr: true},
+		{name: "malformed", content: `{"Stop":`, want: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			writeExternalHooks(t, root, tt.content)
+
+			got, err := handler.LoadExternalHooks(root)
+			if tt.wantErr {
+				require.ErrorIs(t, err, handler.ErrInvalidExternalHooks)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadExternalHooks_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	got, err := handler.LoadExternalHooks(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestExternalHandler_PassesInputOnStdin(t *testing.T) {
+	t.Parallel()
+	runner := &stdinRunner{
+		stdin: nil,
+		dir:   "",
+		argv:  nil,
+		out: &hooks.CommandOutput{
+			Stdout: []byte(`{"systemMessage":"from script"}`),
+			Stderr: []byte("note\n"),
+		},
+	}
+
+	h, err := handler.NewExternalHandler(
+		handler.ExternalHook{Run: "./scripts/notify.sh --loud", Timeout: 0},
+		"/project",
+		handler.WithExternalRunner(runner),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "external:./scripts/notify.sh --loud", h.Name())
+
+	input := &hookcmd.HookInput{
+		HookEventName: hookcmd.EventPostToolUse,
+		SessionID:     "abc123",
+		ToolName:      "Edit",
+	}
+	resp, err := h.Handle(context.Background(), input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/project", runner.dir)
+	assert.Equal(t, []string{"./scripts/notify.sh", "--loud"}, runner.argv)
+
+	var sent hookcmd.HookInput
+	require.NoError(t, json.Unmarshal(runner.stdin, &sent))
+	assert.Equal(t, input.ToolName, sent.ToolName)
+	assert.Equal(t, input.SessionID, sent.SessionID)
+
+	assert.Equal(t, 0, resp.ExitCode)
+	assert.Equal(t, "note\n", resp.Stderr)
+	require.NotNil(t, resp.Stdout)
+	assert.Equal(t, "from script", resp.Stdout.SystemMessage)
+}
+
+func TestExternalHandler_PassesRawEventOnStdin(t *testing.T) {
+	t.Parallel()
+	runner := &stdinRunner{stdin: nil, dir: "", argv: nil, out: nil}
+
+	h, err := handler.NewExternalHandler(
+		handler.ExternalHook{Run: "./scripts/notify.sh", Timeout: 0},
+		"/project",
+		handler.WithExternalRunner(runner),
+	)
+	require.NoError(t, err)
+
+	raw := `{"hook_event_name":"PostToolUse","tool_name":"Edit","future_field":{"kept":true}}`
+	input, err := hookcmd.ParseInput(strings.NewReader(raw))
+	require.NoError(t, err)
+
+	_, err = h.Handle(context.Background(), input)
+	require.NoError(t, err)
+	assert.JSONEq(t, raw, string(runner.stdin))
+}
+
+func TestRegisterExternalHooks_ExecutesDeclaredCommand(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+
+	script := filepath.Join(root, "scripts", "block.sh")
+	require.NoError(t, os.MkdirAll(filepath.Dir(script), 0o750))
+	// #nosec G306 -- the test script must be executable.
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\ncat > received.json\necho blocked >&2\nexit 2\n"), 0o700))
+	writeExternalHooks(t, root, `{"PreToolUse":[{"run":"./scripts/block.sh","timeout":10}]}`)
+
+	r := handler.NewRegistry()
+	require.NoError(t, handler.RegisterExternalHooks(r, root))
+	require.Len(t, r.Handlers(hookcmd.EventPreToolUse), 1)
+
+	resp := r.Dispatch(context.Background(), &hookcmd.HookInput{
+		HookEventName: hookcmd.EventPreToolUse,
+		ToolName:      "Bash",
+	})
+	assert.Equal(t, 2, resp.ExitCode)
+	assert.Equal(t, "blocked\n", resp.Stderr)
+
+	received, err := os.ReadFile(filepath.Join(root, "received.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(received), `"tool_name":"Bash"`)
+}
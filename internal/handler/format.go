@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// Compile-time interface check.
+var _ Handler = (*FormatOnEditHandler)(nil)
+
+// formatTimeout bounds a single formatter invocation.
+const formatTimeout = 30 * time.Second
+
+// FormatOnEditOption configures a FormatOnEditHandler.
+type FormatOnEditOption func(*FormatOnEditHandler)
+
+// WithFormatRunner overrides the command runner for testing.
+func WithFormatRunner(r hooks.CommandRunner) FormatOnEditOption {
+	return func(h *FormatOnEditHandler) {
+		h.runner = r
+	}
+}
+
+// FormatOnEditHandler runs the language formatter on files changed by
+// edit tools.
+type FormatOnEditHandler struct {
+	cfg    *config.Values
+	runner hooks.CommandRunner
+}
+
+// NewFormatOnEditHandler creates a new FormatOnEditHandler.
+func NewFormatOnEditHandler(cfg *config.Values, opts ...FormatOnEditOption) *FormatOnEditHandler {
+	h := &FormatOnEditHandler{
+		cfg:    cfg,
+		runner: hooks.NewDefaultDependencies().Runner,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Name returns the handler identifier.
+func (h *FormatOnEditHandler) Name() string { return "format-on-edit" }
+
+// Enabled reports whether format.on_edit is set.
+func (h *FormatOnEditHandler) Enabled() bool { return h.cfg != nil && h.cfg.Format.OnEdit }
+
+// Handle formats the edited file in place. Formatter failures are reported
+// on stderr but never block the tool call.
+func (h *FormatOnEditHandler) Handle(ctx context.Context, input *hookcmd.HookInput) (*Response, error) {
+	if !h.Enabled() || !input.IsEditTool() {
+		return &Response{ExitCode: 0}, nil
+	}
+
+	filePath := input.GetFilePath()
+	if filePath == "" || shared.ShouldSkipFileFold(filePath, h.cfg.Validate.CaseInsensitiveSkip) {
+		return &Response{ExitCode: 0}, nil
+	}
+
+	if !filepath.IsAbs(filePath) && input.Cwd != "" {
+		filePath = filepath.Join(input.Cwd, filePath)
+	}
+
+	cmd := hooks.DiscoverFormatter(filePath, h.runner)
+	if cmd == nil {
+		return &Response{ExitCode: 0}, nil
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, formatTimeout)
+	defer cancel()
+
+	out, err := h.runner.RunContext(runCtx, cmd.WorkingDir, cmd.Command, cmd.Args...)
+	if err != nil {
+		msg := fmt.Sprintf("format-on-edit: %s failed: %v\n", cmd.Command, err)
+		if out != nil && len(out.Stderr) > 0 {
+			msg += strings.TrimSpace(string(out.Stderr)) + "\n"
+		}
+
+		return &Response{ExitCode: 0, Stderr: msg}, nil
+	}
+
+	return &Response{ExitCode: 0}, nil
+}
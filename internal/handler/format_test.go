@@ -0,0 +1,162 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/handler"
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+// recordingRunner is a CommandRunner that records invocations.
+type recordingRunner struct {
+	calls  [][]string
+	dirs   []string
+	runErr error
+}
+
+func (r *recordingRunner) RunContext(
+	_ context.Context, dir, name string, args ...string,
+) (*hooks.CommandOutput, error) {
+	r.calls = append(r.calls, append([]string{name}, args...))
+	r.dirs = append(r.dirs, dir)
+	if r.runErr != nil {
+		return &hooks.CommandOutput{Stdout: nil, Stderr: []byte("syntax error")}, r.runErr
+	}
+
+	return &hooks.CommandOutput{Stdout: nil, Stderr: nil}, nil
+}
+
+func (r *recordingRunner) LookPath(file string) (string, error) {
+	return "/usr/bin/" + file, nil
+}
+
+func newFormatInput(t *testing.T, toolName, filePath string) *hookcmd.HookInput {
+	t.Helper()
+
+	raw, err := json.Marshal(map[string]string{"file_path": filePath})
+	require.NoError(t, err)
+
+	return &hookcmd.HookInput{
+		HookEventName: hookcmd.EventPostToolUse,
+		ToolName:      toolName,
+		ToolInput:     raw,
+		Cwd:           "/project",
+	}
+}
+
+func TestFormatOnEditHandler_Name(t *testing.T) {
+	t.Parallel()
+	h := handler.NewFormatOnEditHandler(nil)
+	assert.Equal(t, "format-on-edit", h.Name())
+}
+
+func TestFormatOnEditHandler_RunsFormatter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filePath string
+		want     []string
+	}{
+		{
+			name:     "go file",
+			filePath: "/project/main.go",
+			want:     []string{"gofmt", "-w", "/project/main.go"},
+		},
+		{
+			name:     "typescript file",
+			filePath: "/project/src/app.ts",
+			want:     []string{"prettier", "--write", "/project/src/app.ts"},
+		},
+		{
+			name:     "python file",
+			filePath: "/project/tool.py",
+			want:     []string{"ruff", "format", "/project/tool.py"},
+		},
+		{
+			name:     "relative path resolved against cwd",
+			filePath: "pkg/util.go",
+			want:     []string{"gofmt", "-w", "/project/pkg/util.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestConfig()
+			cfg.Format.OnEdit = true
+			runner := &recordingRunner{calls: nil, dirs: nil, runErr: nil}
+			h := handler.NewFormatOnEditHandler(cfg, handler.WithFormatRunner(runner))
+
+			resp, err := h.Handle(context.Background(), newFormatInput(t, "Edit", tt.filePath))
+			require.NoError(t, err)
+			assert.Equal(t, 0, resp.ExitCode)
+			require.Len(t, runner.calls, 1)
+			assert.Equal(t, tt.want, runner.calls[0])
+		})
+	}
+}
+
+func TestFormatOnEditHandler_Skips(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		foldSkip bool
+		toolName string
+		filePath string
+	}{
+		{name: "disabled", enabled: false, toolName: "Edit", filePath: "/project/main.go"},
+		{name: "non-edit tool", enabled: true, toolName: "Bash", filePath: "/project/main.go"},
+		{name: "unknown extension", enabled: true, toolName: "Write", filePath: "/project/notes.txt"},
+		{name: "vendored file", enabled: true, toolName: "Edit", filePath: "/project/vendor/lib/x.go"},
+		{
+			name:     "upper case vendored file with case-insensitive skip",
+			enabled:  true,
+			foldSkip: true,
+			toolName: "Edit",
+			filePath: "/project/VENDOR/lib/x.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestConfig()
+			cfg.Format.OnEdit = tt.enabled
+			cfg.Validate.CaseInsensitiveSkip = tt.foldSkip
+			runner := &recordingRunner{calls: nil, dirs: nil, runErr: nil}
+			h := handler.NewFormatOnEditHandler(cfg, handler.WithFormatRunner(runner))
+
+			resp, err := h.Handle(context.Background(), newFormatInput(t, tt.toolName, tt.filePath))
+			require.NoError(t, err)
+			assert.Equal(t, 0, resp.ExitCode)
+			assert.Empty(t, runner.calls)
+		})
+	}
+}
+
+func TestFormatOnEditHandler_FormatterFailureDoesNotBlock(t *testing.T) {
+	t.Parallel()
+
+	cfg := newTestConfig()
+	cfg.Format.OnEdit = true
+	runner := &recordingRunner{calls: nil, dirs: nil, runErr: errors.New("exit status 2")}
+	h := handler.NewFormatOnEditHandler(cfg, handler.WithFormatRunner(runner))
+
+	resp, err := h.Handle(context.Background(), newFormatInput(t, "Edit", "/project/main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.ExitCode)
+	assert.Contains(t, resp.Stderr, "gofmt failed")
+	assert.Contains(t, resp.Stderr, "syntax error")
+}
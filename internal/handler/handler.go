@@ -19,6 +19,12 @@ type Handler interface {
 	Handle(ctx context.Context, input *hookcmd.HookInput) (*Response, error)
 }
 
+// Enabler is implemented by handlers that configuration can switch off.
+// Enabled reports whether the handler will act on events.
+type Enabler interface {
+	Enabled() bool
+}
+
 // Response captures a handler's output for the Claude Code hooks protocol.
 // Exit code 0 = success, 2 = block with stderr feedback.
 type Response struct {
@@ -3,20 +3,34 @@ package handler
 import (
 	"context"
 	"os"
-	"path/filepath"
+	"os/exec"
+	"runtime"
 	"strings"
-	"time"
 
 	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
 	"github.com/riddopic/cc-tools/internal/notify"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // Compile-time interface checks.
 var (
-	_ Handler = (*NotifyAudioHandler)(nil)
-	_ Handler = (*NotifyDesktopHandler)(nil)
-	_ Handler = (*NotifyNtfyHandler)(nil)
+	_ Handler       = (*NotifyHandler)(nil)
+	_ notify.Sender = (*audioSender)(nil)
+	_ notify.Sender = (*desktopSender)(nil)
+)
+
+// Words that raise a notification's severity when they appear in its title
+// or message.
+var (
+	attentionWords = []string{"error", "fail", "permission", "denied", "approve"}
+	warningWords   = []string{"warning", "waiting", "idle", "input"}
+)
+
+// Default notification text used when the hook input carries none.
+const (
+	defaultNotifyTitle   = "Claude Code"
+	defaultNotifyMessage = "Task completed"
 )
 
 // AudioPlayer abstracts audio file playback for dependency injection.
@@ -34,123 +48,68 @@ type NtfySender interface {
 	Send(ctx context.Context, title, message string) error
 }
 
-// ---------------------------------------------------------------------
-// NotifyAudioHandler
-// ---------------------------------------------------------------------
-
-// NotifyAudioOption configures a NotifyAudioHandler.
-type NotifyAudioOption func(*NotifyAudioHandler)
+// NotifyOption configures a NotifyHandler.
+type NotifyOption func(*NotifyHandler)
 
-// WithAudioPlayer overrides the audio player for testing.
-func WithAudioPlayer(player AudioPlayer) NotifyAudioOption {
-	return func(h *NotifyAudioHandler) {
+// WithAudioPlayer overrides the audio player. It takes precedence over
+// notify.audio.player.
+func WithAudioPlayer(player AudioPlayer) NotifyOption {
+	return func(h *NotifyHandler) {
 		h.player = player
 	}
 }
 
-// NotifyAudioHandler plays an audio notification sound.
-type NotifyAudioHandler struct {
-	cfg    *config.Values
-	player AudioPlayer
-}
-
-// NewNotifyAudioHandler creates a new NotifyAudioHandler.
-func NewNotifyAudioHandler(
-	cfg *config.Values,
-	opts ...NotifyAudioOption,
-) *NotifyAudioHandler {
-	h := &NotifyAudioHandler{
-		cfg:    cfg,
-		player: nil,
-	}
-	for _, opt := range opts {
-		opt(h)
+// WithAudioRunner overrides the command runner used by the player built
+// from notify.audio.player.
+func WithAudioRunner(runner CmdRunner) NotifyOption {
+	return func(h *NotifyHandler) {
+		h.audioRunner = runner
 	}
-
-	return h
 }
 
-// Name returns the handler identifier.
-func (h *NotifyAudioHandler) Name() string { return "notify-audio" }
-
-// Handle plays a random audio notification if audio is enabled and quiet
-// hours are not active.
-func (h *NotifyAudioHandler) Handle(
-	_ context.Context,
-	_ *hookcmd.HookInput,
-) (*Response, error) {
-	if h.cfg == nil || !h.cfg.Notify.Audio.Enabled {
-		return &Response{ExitCode: 0}, nil
-	}
-
-	player := h.player
-	if player == nil {
-		return &Response{ExitCode: 0}, nil
+// WithCmdRunner sets the command runner for desktop notifications. Desktop
+// notifications are skipped when no runner is set.
+func WithCmdRunner(runner CmdRunner) NotifyOption {
+	return func(h *NotifyHandler) {
+		h.desktopRunner = runner
 	}
-
-	dir := expandHome(h.cfg.Notify.Audio.Directory)
-
-	// Skip silently when the audio directory does not exist.
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return &Response{ExitCode: 0}, nil
-	}
-
-	qh := notify.QuietHours{
-		Enabled: h.cfg.Notify.QuietHours.Enabled,
-		Start:   h.cfg.Notify.QuietHours.Start,
-		End:     h.cfg.Notify.QuietHours.End,
-	}
-
-	audio := notify.NewAudio(player, dir, qh, nil)
-	if err := audio.PlayRandom(); err != nil {
-		return nil, err
-	}
-
-	return &Response{ExitCode: 0}, nil
 }
 
-// expandHome replaces a leading ~ with the user's home directory.
-func expandHome(path string) string {
-	if !strings.HasPrefix(path, "~") {
-		return path
-	}
-
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return path
+// WithNtfySender overrides the ntfy sender for testing.
+func WithNtfySender(sender NtfySender) NotifyOption {
+	return func(h *NotifyHandler) {
+		h.ntfy = sender
 	}
-
-	return filepath.Join(home, strings.TrimPrefix(path, "~"))
 }
 
-// ---------------------------------------------------------------------
-// NotifyDesktopHandler
-// ---------------------------------------------------------------------
-
-// NotifyDesktopOption configures a NotifyDesktopHandler.
-type NotifyDesktopOption func(*NotifyDesktopHandler)
-
-// WithCmdRunner overrides the command runner for testing.
-func WithCmdRunner(runner CmdRunner) NotifyDesktopOption {
-	return func(h *NotifyDesktopHandler) {
-		h.runner = runner
+// WithNotifyClock overrides the clock used to check quiet hours.
+func WithNotifyClock(clock shared.Clock) NotifyOption {
+	return func(h *NotifyHandler) {
+		h.clock = clock
 	}
 }
 
-// NotifyDesktopHandler sends a desktop notification.
-type NotifyDesktopHandler struct {
-	cfg    *config.Values
-	runner CmdRunner
+// NotifyHandler sends a notification to every enabled sink: audio,
+// desktop, and ntfy. A failing sink does not stop the others; all failures
+// are reported together.
+type NotifyHandler struct {
+	cfg           *config.Values
+	player        AudioPlayer
+	audioRunner   CmdRunner
+	desktopRunner CmdRunner
+	ntfy          NtfySender
+	clock         shared.Clock
 }
 
-// NewNotifyDesktopHandler creates a new NotifyDesktopHandler.
-func NewNotifyDesktopHandler(
-	cfg *config.Values,
-	opts ...NotifyDesktopOption,
-) *NotifyDesktopHandler {
-	h := &NotifyDesktopHandler{
-		cfg:    cfg,
-		runner: nil,
+// NewNotifyHandler creates a new NotifyHandler.
+func NewNotifyHandler(cfg *config.Values, opts ...NotifyOption) *NotifyHandler {
+	h := &NotifyHandler{
+		cfg:           cfg,
+		player:        nil,
+		audioRunner:   nil,
+		desktopRunner: nil,
+		ntfy:          nil,
+		clock:         nil,
 	}
 	for _, opt := range opts {
 		opt(h)
@@ -160,37 +119,43 @@ func NewNotifyDesktopHandler(
 }
 
 // Name returns the handler identifier.
-func (h *NotifyDesktopHandler) Name() string { return "notify-desktop" }
+func (h *NotifyHandler) Name() string { return "notify" }
 
-// Handle sends a desktop notification if desktop notifications are enabled
-// and quiet hours are not active.
-func (h *NotifyDesktopHandler) Handle(
-	_ context.Context,
+// Enabled reports whether any sink is configured: notify.audio.enabled,
+// notify.desktop.enabled, or notifications.ntfy_topic.
+func (h *NotifyHandler) Enabled() bool {
+	return h.cfg != nil &&
+		(h.cfg.Notify.Audio.Enabled || h.cfg.Notify.Desktop.Enabled || h.cfg.Notifications.NtfyTopic != "")
+}
+
+// Handle sends the notification to every enabled sink unless quiet hours
+// are active or its severity is below notify.min_severity. Errors from
+// individual sinks are joined.
+func (h *NotifyHandler) Handle(
+	ctx context.Context,
 	input *hookcmd.HookInput,
 ) (*Response, error) {
-	if h.cfg == nil || !h.cfg.Notify.Desktop.Enabled {
+	if !h.Enabled() {
 		return &Response{ExitCode: 0}, nil
 	}
 
-	qh := notify.QuietHours{
-		Enabled: h.cfg.Notify.QuietHours.Enabled,
-		Start:   h.cfg.Notify.QuietHours.Start,
-		End:     h.cfg.Notify.QuietHours.End,
-	}
-
-	if qh.IsActive(time.Now()) {
+	if classifySeverity(input) < h.minSeverity() {
 		return &Response{ExitCode: 0}, nil
 	}
 
-	runner := h.runner
-	if runner == nil {
+	senders := h.senders()
+	if len(senders) == 0 {
 		return &Response{ExitCode: 0}, nil
 	}
 
-	desktop := notify.NewDesktop(runner)
+	qh := &notify.QuietHours{
+		Enabled: h.cfg.Notify.QuietHours.Enabled,
+		Start:   h.cfg.Notify.QuietHours.Start,
+		End:     h.cfg.Notify.QuietHours.End,
+	}
 
-	title := "Claude Code"
-	message := "Task completed"
+	title := defaultNotifyTitle
+	message := defaultNotifyMessage
 
 	if input.Title != "" {
 		title = input.Title
@@ -200,96 +165,154 @@ func (h *NotifyDesktopHandler) Handle(
 		message = input.Message
 	}
 
-	if err := desktop.Send(title, message); err != nil {
+	if err := notify.NewMultiNotifier(senders, qh, h.clock).Send(ctx, title, message); err != nil {
 		return nil, err
 	}
 
 	return &Response{ExitCode: 0}, nil
 }
 
-// ---------------------------------------------------------------------
-// NotifyNtfyHandler
-// ---------------------------------------------------------------------
+// minSeverity returns the notify.min_severity threshold. An unrecognized
+// value, which can only come from a hand-edited config file, suppresses
+// nothing.
+func (h *NotifyHandler) minSeverity() notify.Severity {
+	severity, err := notify.ParseSeverity(h.cfg.Notify.MinSeverity)
+	if err != nil {
+		return notify.SeverityInfo
+	}
 
-// NotifyNtfyOption configures a NotifyNtfyHandler.
-type NotifyNtfyOption func(*NotifyNtfyHandler)
+	return severity
+}
 
-// WithNtfySender overrides the ntfy sender for testing.
-func WithNtfySender(sender NtfySender) NotifyNtfyOption {
-	return func(h *NotifyNtfyHandler) {
-		h.sender = sender
+// classifySeverity derives a notification's severity from the hook input.
+// Permission and elicitation prompts, and text mentioning errors or
+// permissions, need attention; idle prompts and text about waiting are
+// warnings; everything else is informational.
+func classifySeverity(input *hookcmd.HookInput) notify.Severity {
+	switch input.NotificationType {
+	case "permission_prompt", "elicitation_dialog":
+		return notify.SeverityAttention
+	case "idle_prompt":
+		return notify.SeverityWarning
 	}
-}
 
-// NotifyNtfyHandler sends a push notification via ntfy.
-type NotifyNtfyHandler struct {
-	cfg    *config.Values
-	sender NtfySender
+	text := strings.ToLower(input.Title + " " + input.Message)
+	switch {
+	case containsAny(text, attentionWords):
+		return notify.SeverityAttention
+	case containsAny(text, warningWords):
+		return notify.SeverityWarning
+	default:
+		return notify.SeverityInfo
+	}
 }
 
-// NewNotifyNtfyHandler creates a new NotifyNtfyHandler.
-func NewNotifyNtfyHandler(
-	cfg *config.Values,
-	opts ...NotifyNtfyOption,
-) *NotifyNtfyHandler {
-	h := &NotifyNtfyHandler{
-		cfg:    cfg,
-		sender: nil,
-	}
-	for _, opt := range opts {
-		opt(h)
+// containsAny reports whether s contains any of words.
+func containsAny(s string, words []string) bool {
+	for _, word := range words {
+		if strings.Contains(s, word) {
+			return true
+		}
 	}
 
-	return h
+	return false
 }
 
-// Name returns the handler identifier.
-func (h *NotifyNtfyHandler) Name() string { return "notify-ntfy" }
+// senders returns a sender for each enabled sink that can run.
+func (h *NotifyHandler) senders() []notify.Sender {
+	var senders []notify.Sender
 
-// Handle sends a push notification via ntfy if a topic is configured
-// and quiet hours are not active.
-func (h *NotifyNtfyHandler) Handle(
-	ctx context.Context,
-	input *hookcmd.HookInput,
-) (*Response, error) {
-	if h.cfg == nil || h.cfg.Notifications.NtfyTopic == "" {
-		return &Response{ExitCode: 0}, nil
+	if h.cfg.Notify.Audio.Enabled {
+		if audio := h.audio(); audio != nil {
+			senders = append(senders, audio)
+		}
 	}
 
-	qh := notify.QuietHours{
-		Enabled: h.cfg.Notify.QuietHours.Enabled,
-		Start:   h.cfg.Notify.QuietHours.Start,
-		End:     h.cfg.Notify.QuietHours.End,
+	if h.cfg.Notify.Desktop.Enabled && h.desktopRunner != nil {
+		senders = append(senders, &desktopSender{desktop: notify.NewDesktop(h.desktopRunner)})
 	}
 
-	if qh.IsActive(time.Now()) {
-		return &Response{ExitCode: 0}, nil
+	if h.cfg.Notifications.NtfyTopic != "" {
+		sender := h.ntfy
+		if sender == nil {
+			sender = notify.NewNtfyNotifier(notify.NtfyConfig{
+				Topic:    h.cfg.Notifications.NtfyTopic,
+				Server:   "",
+				Token:    "",
+				Priority: 0,
+			})
+		}
+		senders = append(senders, sender)
 	}
 
-	sender := h.sender
-	if sender == nil {
-		sender = notify.NewNtfyNotifier(notify.NtfyConfig{
-			Topic:    h.cfg.Notifications.NtfyTopic,
-			Server:   "",
-			Token:    "",
-			Priority: 0,
-		})
-	}
+	return senders
+}
 
-	title := "Claude Code"
-	message := "Task completed"
+// audio returns the audio sink, or nil when no player is available or none
+// of the comma-separated audio directories can be expanded and exists.
+func (h *NotifyHandler) audio() *audioSender {
+	player := h.audioPlayer()
+	if player == nil {
+		return nil
+	}
 
-	if input.Title != "" {
-		title = input.Title
+	var dirs []string
+	for entry := range strings.SplitSeq(h.cfg.Notify.Audio.Directory, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		dir, err := shared.ExpandPath(entry)
+		if err != nil {
+			continue
+		}
+		if _, err = os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	if len(dirs) == 0 {
+		return nil
 	}
 
-	if input.Message != "" {
-		message = input.Message
+	// Quiet hours are checked once for all sinks by the multi-notifier.
+	noQuietHours := notify.QuietHours{Enabled: false, Start: "", End: ""}
+	return &audioSender{audio: notify.NewAudio(player, dirs, noQuietHours, nil)}
+}
+
+// audioPlayer returns the injected player, or one built from
+// notify.audio.player for the current OS. It returns nil when no player is
+// available.
+func (h *NotifyHandler) audioPlayer() AudioPlayer {
+	if h.player != nil {
+		return h.player
 	}
 
-	if err := sender.Send(ctx, title, message); err != nil {
-		return nil, err
+	command := notify.ResolvePlayer(h.cfg.Notify.Audio.Player, runtime.GOOS, exec.LookPath)
+	if command == "" {
+		return nil
 	}
 
-	return &Response{ExitCode: 0}, nil
+	return notify.NewCommandPlayer(command, h.audioRunner)
+}
+
+// audioSender adapts notify.Audio to notify.Sender. The title and message
+// are ignored; a random sound is played instead.
+type audioSender struct {
+	audio *notify.Audio
+}
+
+// Send plays a random sound from the audio directory.
+func (s *audioSender) Send(_ context.Context, _, _ string) error {
+	return s.audio.PlayRandom()
+}
+
+// desktopSender adapts notify.Desktop to notify.Sender.
+type desktopSender struct {
+	desktop *notify.Desktop
+}
+
+// Send shows a desktop notification.
+func (s *desktopSender) Send(_ context.Context, title, message string) error {
+	return s.desktop.Send(title, message)
 }
@@ -2,9 +2,11 @@ package handler_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,6 +14,7 @@ import (
 	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/handler"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // mockAudioPlayer records Play calls for assertion.
@@ -39,63 +42,55 @@ func (m *mockCmdRunner) Run(name string, args ...string) error {
 	return nil
 }
 
-// ---------------------------------------------------------------------
-// NotifyAudioHandler
-// ---------------------------------------------------------------------
-
-func TestNotifyAudioHandler_Name(t *testing.T) {
-	t.Parallel()
-	h := handler.NewNotifyAudioHandler(nil)
-	assert.Equal(t, "notify-audio", h.Name())
+// failingCmdRunner fails every Run call.
+type failingCmdRunner struct {
+	err error
 }
 
-func TestNotifyAudioHandler_NilConfig(t *testing.T) {
-	t.Parallel()
-	h := handler.NewNotifyAudioHandler(nil)
-	input := &hookcmd.HookInput{
-		HookEventName: hookcmd.EventNotification,
-	}
+func (f *failingCmdRunner) Run(_ string, _ ...string) error {
+	return f.err
+}
 
-	resp, err := h.Handle(context.Background(), input)
-	require.NoError(t, err)
-	require.NotNil(t, resp)
-	assert.Equal(t, 0, resp.ExitCode)
+// mockNtfySender records Send calls for assertion.
+type mockNtfySender struct {
+	calls []ntfySendCall
 }
 
-func TestNotifyAudioHandler_Disabled(t *testing.T) {
-	t.Parallel()
-	cfg := &config.Values{
-		Notify: config.NotifyValues{
-			Audio: config.AudioValues{
-				Enabled: false,
-			},
-		},
-	}
+type ntfySendCall struct {
+	title   string
+	message string
+}
 
-	h := handler.NewNotifyAudioHandler(cfg)
-	input := &hookcmd.HookInput{
-		HookEventName: hookcmd.EventNotification,
-	}
+func (m *mockNtfySender) Send(
+	_ context.Context,
+	title, message string,
+) error {
+	m.calls = append(m.calls, ntfySendCall{
+		title:   title,
+		message: message,
+	})
+	return nil
+}
 
-	resp, err := h.Handle(context.Background(), input)
-	require.NoError(t, err)
-	require.NotNil(t, resp)
-	assert.Equal(t, 0, resp.ExitCode)
+// newAudioDir creates a directory holding one MP3 file.
+func newAudioDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "beep.mp3"), []byte("fake-audio"), 0o600,
+	))
+	return dir
 }
 
-func TestNotifyAudioHandler_NoPlayerInjected(t *testing.T) {
+func TestNotifyHandler_Name(t *testing.T) {
 	t.Parallel()
-	cfg := &config.Values{
-		Notify: config.NotifyValues{
-			Audio: config.AudioValues{
-				Enabled:   true,
-				Directory: "/tmp/sounds",
-			},
-		},
-	}
+	h := handler.NewNotifyHandler(nil)
+	assert.Equal(t, "notify", h.Name())
+}
 
-	// No WithAudioPlayer option — player is nil.
-	h := handler.NewNotifyAudioHandler(cfg)
+func TestNotifyHandler_NilConfig(t *testing.T) {
+	t.Parallel()
+	h := handler.NewNotifyHandler(nil)
 	input := &hookcmd.HookInput{
 		HookEventName: hookcmd.EventNotification,
 	}
@@ -104,350 +99,362 @@ func TestNotifyAudioHandler_NoPlayerInjected(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 	assert.Equal(t, 0, resp.ExitCode)
+	assert.False(t, h.Enabled())
 }
 
-func TestNotifyAudioHandler_EnabledWithPlayer(t *testing.T) {
+func TestNotifyHandler_AllSinksDisabled(t *testing.T) {
 	t.Parallel()
-	tmpDir := t.TempDir()
-
-	// Create an MP3 file so PlayRandom has something to pick.
-	require.NoError(t, os.WriteFile(
-		filepath.Join(tmpDir, "beep.mp3"), []byte("fake-audio"), 0o600,
-	))
-
-	player := &mockAudioPlayer{played: []string{}}
+	runner := &mockCmdRunner{calls: nil}
+	sender := &mockNtfySender{calls: nil}
+	player := &mockAudioPlayer{played: nil}
 
 	cfg := &config.Values{
 		Notify: config.NotifyValues{
-			Audio: config.AudioValues{
-				Enabled:   true,
-				Directory: tmpDir,
-			},
+			Audio:   config.AudioValues{Enabled: false, Directory: newAudioDir(t)},
+			Desktop: config.DesktopValues{Enabled: false},
 		},
+		Notifications: config.NotificationsValues{NtfyTopic: ""},
 	}
 
-	h := handler.NewNotifyAudioHandler(cfg, handler.WithAudioPlayer(player))
-	input := &hookcmd.HookInput{
+	h := handler.NewNotifyHandler(cfg,
+		handler.WithAudioPlayer(player),
+		handler.WithCmdRunner(runner),
+		handler.WithNtfySender(sender),
+	)
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
 		HookEventName: hookcmd.EventNotification,
-	}
-
-	resp, err := h.Handle(context.Background(), input)
+	})
 	require.NoError(t, err)
-	require.NotNil(t, resp)
 	assert.Equal(t, 0, resp.ExitCode)
-	assert.NotEmpty(t, player.played, "should have played an audio file")
+	assert.False(t, h.Enabled())
+	assert.Empty(t, player.played)
+	assert.Empty(t, runner.calls)
+	assert.Empty(t, sender.calls)
 }
 
-func TestNotifyAudioHandler_QuietHoursSkipsPlay(t *testing.T) {
+func TestNotifyHandler_AllSinksFire(t *testing.T) {
 	t.Parallel()
-	player := &mockAudioPlayer{played: []string{}}
+	runner := &mockCmdRunner{calls: nil}
+	sender := &mockNtfySender{calls: nil}
+	player := &mockAudioPlayer{played: nil}
 
 	cfg := &config.Values{
 		Notify: config.NotifyValues{
-			Audio: config.AudioValues{
-				Enabled:   true,
-				Directory: "/tmp/sounds",
-			},
-			QuietHours: config.QuietHoursValues{
-				Enabled: true,
-				Start:   "00:00",
-				End:     "23:59",
-			},
+			Audio:   config.AudioValues{Enabled: true, Directory: newAudioDir(t)},
+			Desktop: config.DesktopValues{Enabled: true},
 		},
+		Notifications: config.NotificationsValues{NtfyTopic: "test-topic"},
 	}
 
-	h := handler.NewNotifyAudioHandler(cfg, handler.WithAudioPlayer(player))
-	input := &hookcmd.HookInput{
+	h := handler.NewNotifyHandler(cfg,
+		handler.WithAudioPlayer(player),
+		handler.WithCmdRunner(runner),
+		handler.WithNtfySender(sender),
+	)
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
 		HookEventName: hookcmd.EventNotification,
-	}
-
-	resp, err := h.Handle(context.Background(), input)
+		Title:         "Build Done",
+		Message:       "All tests passed",
+	})
 	require.NoError(t, err)
-	require.NotNil(t, resp)
 	assert.Equal(t, 0, resp.ExitCode)
-	assert.Empty(t, player.played, "should not play during quiet hours")
+	assert.Len(t, player.played, 1)
+	require.Len(t, runner.calls, 1)
+	assert.Equal(t, "osascript", runner.calls[0].name)
+	assert.Equal(t, []ntfySendCall{{title: "Build Done", message: "All tests passed"}}, sender.calls)
 }
 
-func TestNotifyAudioHandler_ImplementsHandler(t *testing.T) {
+func TestNotifyHandler_FailingSinkDoesNotStopOthers(t *testing.T) {
 	t.Parallel()
-	var _ handler.Handler = handler.NewNotifyAudioHandler(nil)
-}
-
-// ---------------------------------------------------------------------
-// NotifyDesktopHandler
-// ---------------------------------------------------------------------
+	desktopErr := errors.New("osascript not found")
+	sender := &mockNtfySender{calls: nil}
+	player := &mockAudioPlayer{played: nil}
 
-func TestNotifyDesktopHandler_Name(t *testing.T) {
-	t.Parallel()
-	h := handler.NewNotifyDesktopHandler(nil)
-	assert.Equal(t, "notify-desktop", h.Name())
-}
-
-func TestNotifyDesktopHandler_NilConfig(t *testing.T) {
-	t.Parallel()
-	h := handler.NewNotifyDesktopHandler(nil)
-	input := &hookcmd.HookInput{
-		HookEventName: hookcmd.EventNotification,
-	}
-
-	resp, err := h.Handle(context.Background(), input)
-	require.NoError(t, err)
-	require.NotNil(t, resp)
-	assert.Equal(t, 0, resp.ExitCode)
-}
-
-func TestNotifyDesktopHandler_Disabled(t *testing.T) {
-	t.Parallel()
 	cfg := &config.Values{
 		Notify: config.NotifyValues{
-			Desktop: config.DesktopValues{
-				Enabled: false,
-			},
+			Audio:   config.AudioValues{Enabled: true, Directory: newAudioDir(t)},
+			Desktop: config.DesktopValues{Enabled: true},
 		},
+		Notifications: config.NotificationsValues{NtfyTopic: "test-topic"},
 	}
 
-	h := handler.NewNotifyDesktopHandler(cfg)
-	input := &hookcmd.HookInput{
+	h := handler.NewNotifyHandler(cfg,
+		handler.WithAudioPlayer(player),
+		handler.WithCmdRunner(&failingCmdRunner{err: desktopErr}),
+		handler.WithNtfySender(sender),
+	)
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
 		HookEventName: hookcmd.EventNotification,
-	}
-
-	resp, err := h.Handle(context.Background(), input)
-	require.NoError(t, err)
-	require.NotNil(t, resp)
-	assert.Equal(t, 0, resp.ExitCode)
+	})
+	require.ErrorIs(t, err, desktopErr)
+	assert.Nil(t, resp)
+	assert.Len(t, player.played, 1, "audio should still play")
+	assert.Len(t, sender.calls, 1, "ntfy should still send")
 }
 
-func TestNotifyDesktopHandler_QuietHoursActive(t *testing.T) {
+func TestNotifyHandler_QuietHoursSuppressAllSinks(t *testing.T) {
 	t.Parallel()
+	runner := &mockCmdRunner{calls: nil}
+	sender := &mockNtfySender{calls: nil}
+	player := &mockAudioPlayer{played: nil}
+
 	cfg := &config.Values{
 		Notify: config.NotifyValues{
-			Desktop: config.DesktopValues{
-				Enabled: true,
-			},
+			Audio:   config.AudioValues{Enabled: true, Directory: newAudioDir(t)},
+			Desktop: config.DesktopValues{Enabled: true},
 			QuietHours: config.QuietHoursValues{
 				Enabled: true,
-				Start:   "00:00",
-				End:     "23:59",
+				Start:   "22:00",
+				End:     "07:00",
 			},
 		},
+		Notifications: config.NotificationsValues{NtfyTopic: "test-topic"},
 	}
 
-	h := handler.NewNotifyDesktopHandler(cfg)
-	input := &hookcmd.HookInput{
+	clock := shared.NewFakeClock(time.Date(2026, 3, 10, 23, 30, 0, 0, time.Local))
+	h := handler.NewNotifyHandler(cfg,
+		handler.WithAudioPlayer(player),
+		handler.WithCmdRunner(runner),
+		handler.WithNtfySender(sender),
+		handler.WithNotifyClock(clock),
+	)
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
 		HookEventName: hookcmd.EventNotification,
-		Title:         "Test",
-		Message:       "Hello",
-	}
-
-	resp, err := h.Handle(context.Background(), input)
+	})
 	require.NoError(t, err)
-	require.NotNil(t, resp)
 	assert.Equal(t, 0, resp.ExitCode)
+	assert.Empty(t, player.played, "should not play during quiet hours")
+	assert.Empty(t, runner.calls, "should not notify during quiet hours")
+	assert.Empty(t, sender.calls, "should not send during quiet hours")
 }
 
-func TestNotifyDesktopHandler_NoRunnerInjected(t *testing.T) {
+func TestNotifyHandler_MinSeverity(t *testing.T) {
 	t.Parallel()
-	cfg := &config.Values{
-		Notify: config.NotifyValues{
-			Desktop: config.DesktopValues{
-				Enabled: true,
-			},
+
+	inputs := map[string]*hookcmd.HookInput{
+		"info": {
+			HookEventName: hookcmd.EventNotification,
+			Message:       "Task completed",
+		},
+		"warning": {
+			HookEventName:    hookcmd.EventNotification,
+			Message:          "Claude is waiting for your input",
+			NotificationType: "idle_prompt",
+		},
+		"attention": {
+			HookEventName: hookcmd.EventNotification,
+			Message:       "Claude needs your permission to use Bash",
 		},
 	}
 
-	// No WithCmdRunner option — runner is nil.
-	h := handler.NewNotifyDesktopHandler(cfg)
-	input := &hookcmd.HookInput{
-		HookEventName: hookcmd.EventNotification,
-		Title:         "Test",
-		Message:       "Hello",
+	tests := []struct {
+		minSeverity string
+		sent        []string
+	}{
+		{minSeverity: "", sent: []string{"attention", "info", "warning"}},
+		{minSeverity: "info", sent: []string{"attention", "info", "warning"}},
+		{minSeverity: "warning", sent: []string{"attention", "warning"}},
+		{minSeverity: "attention", sent: []string{"attention"}},
 	}
 
-	resp, err := h.Handle(context.Background(), input)
-	require.NoError(t, err)
-	require.NotNil(t, resp)
-	assert.Equal(t, 0, resp.ExitCode)
+	for _, tt := range tests {
+		t.Run("min_"+tt.minSeverity, func(t *testing.T) {
+			t.Parallel()
+			cfg := &config.Values{
+				Notify: config.NotifyValues{
+					Audio:       config.AudioValues{Enabled: false},
+					Desktop:     config.DesktopValues{Enabled: false},
+					MinSeverity: tt.minSeverity,
+				},
+				Notifications: config.NotificationsValues{NtfyTopic: "test-topic"},
+			}
+
+			var sent []string
+			for _, severity := range []string{"attention", "info", "warning"} {
+				sender := &mockNtfySender{calls: nil}
+				h := handler.NewNotifyHandler(cfg, handler.WithNtfySender(sender))
+				resp, err := h.Handle(context.Background(), inputs[severity])
+				require.NoError(t, err)
+				assert.Equal(t, 0, resp.ExitCode)
+				if len(sender.calls) > 0 {
+					sent = append(sent, severity)
+				}
+			}
+			assert.Equal(t, tt.sent, sent)
+		})
+	}
 }
 
-func TestNotifyDesktopHandler_EnabledWithRunner(t *testing.T) {
+func TestNotifyHandler_MinSeveritySuppressesAllSinks(t *testing.T) {
 	t.Parallel()
-	runner := &mockCmdRunner{calls: []cmdRunnerCall{}}
+	runner := &mockCmdRunner{calls: nil}
+	sender := &mockNtfySender{calls: nil}
+	player := &mockAudioPlayer{played: nil}
 
 	cfg := &config.Values{
 		Notify: config.NotifyValues{
-			Desktop: config.DesktopValues{
-				Enabled: true,
-			},
+			Audio:       config.AudioValues{Enabled: true, Directory: newAudioDir(t)},
+			Desktop:     config.DesktopValues{Enabled: true},
+			MinSeverity: "attention",
 		},
+		Notifications: config.NotificationsValues{NtfyTopic: "test-topic"},
 	}
 
-	h := handler.NewNotifyDesktopHandler(cfg, handler.WithCmdRunner(runner))
-	input := &hookcmd.HookInput{
+	h := handler.NewNotifyHandler(cfg,
+		handler.WithAudioPlayer(player),
+		handler.WithCmdRunner(runner),
+		handler.WithNtfySender(sender),
+	)
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
 		HookEventName: hookcmd.EventNotification,
 		Title:         "Build Done",
 		Message:       "All tests passed",
-	}
-
-	resp, err := h.Handle(context.Background(), input)
+	})
 	require.NoError(t, err)
-	require.NotNil(t, resp)
 	assert.Equal(t, 0, resp.ExitCode)
-	assert.NotEmpty(t, runner.calls, "should have called runner")
-}
-
-func TestNotifyDesktopHandler_CustomTitleAndMessage(t *testing.T) {
-	t.Parallel()
-	runner := &mockCmdRunner{calls: []cmdRunnerCall{}}
-
-	cfg := &config.Values{
-		Notify: config.NotifyValues{
-			Desktop: config.DesktopValues{
-				Enabled: true,
-			},
-		},
-	}
-
-	h := handler.NewNotifyDesktopHandler(cfg, handler.WithCmdRunner(runner))
-	input := &hookcmd.HookInput{
-		HookEventName: hookcmd.EventNotification,
-		Title:         "Custom Title",
-		Message:       "Custom body text",
-	}
-
-	resp, err := h.Handle(context.Background(), input)
-	require.NoError(t, err)
-	require.NotNil(t, resp)
-	require.NotEmpty(t, runner.calls)
-	// The runner should receive the custom title/message (exact args depend on
-	// the notify.Desktop implementation, but it should be called).
+	assert.Empty(t, player.played)
+	assert.Empty(t, runner.calls)
+	assert.Empty(t, sender.calls)
 }
 
-func TestNotifyDesktopHandler_DefaultTitleAndMessage(t *testing.T) {
+func TestNotifyHandler_AudioSkippedWithoutDirectory(t *testing.T) {
 	t.Parallel()
-	runner := &mockCmdRunner{calls: []cmdRunnerCall{}}
+	player := &mockAudioPlayer{played: nil}
 
 	cfg := &config.Values{
 		Notify: config.NotifyValues{
-			Desktop: config.DesktopValues{
-				Enabled: true,
+			Audio: config.AudioValues{
+				Enabled:   true,
+				Directory: filepath.Join(t.TempDir(), "missing"),
 			},
 		},
 	}
 
-	h := handler.NewNotifyDesktopHandler(cfg, handler.WithCmdRunner(runner))
-	input := &hookcmd.HookInput{
+	h := handler.NewNotifyHandler(cfg, handler.WithAudioPlayer(player))
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
 		HookEventName: hookcmd.EventNotification,
-		// No Title or Message — should use defaults.
-	}
-
-	resp, err := h.Handle(context.Background(), input)
+	})
 	require.NoError(t, err)
-	require.NotNil(t, resp)
 	assert.Equal(t, 0, resp.ExitCode)
-	assert.NotEmpty(t, runner.calls,
-		"should still send notification with default title/message")
-}
-
-func TestNotifyDesktopHandler_ImplementsHandler(t *testing.T) {
-	t.Parallel()
-	var _ handler.Handler = handler.NewNotifyDesktopHandler(nil)
+	assert.Empty(t, player.played)
 }
 
-// ---------------------------------------------------------------------
-// NotifyNtfyHandler
-// ---------------------------------------------------------------------
-
-// mockNtfySender records Send calls for assertion.
-type mockNtfySender struct {
-	calls []ntfySendCall
-}
-
-type ntfySendCall struct {
-	title   string
-	message string
-}
-
-func (m *mockNtfySender) Send(
-	_ context.Context,
-	title, message string,
-) error {
-	m.calls = append(m.calls, ntfySendCall{
-		title:   title,
-		message: message,
-	})
-	return nil
-}
-
-func TestNotifyNtfyHandler_Name(t *testing.T) {
-	t.Parallel()
-	h := handler.NewNotifyNtfyHandler(nil)
-	assert.Equal(t, "notify-ntfy", h.Name())
-}
-
-func TestNotifyNtfyHandler_NilConfig(t *testing.T) {
-	t.Parallel()
-	h := handler.NewNotifyNtfyHandler(nil)
-	input := &hookcmd.HookInput{
-		HookEventName: hookcmd.EventNotification,
+func TestNotifyHandler_AudioDirectoryExpandsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	soundDir := filepath.Join(home, ".claude", "audio")
+	require.NoError(t, os.MkdirAll(soundDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(soundDir, "beep.mp3"), []byte("fake-audio"), 0o600))
+
+	for _, dir := range []string{"~/.claude/audio", "$HOME/.claude/audio", "${HOME}/.claude/audio"} {
+		t.Run(dir, func(t *testing.T) {
+			player := &mockAudioPlayer{played: nil}
+			cfg := &config.Values{
+				Notify: config.NotifyValues{
+					Audio: config.AudioValues{Enabled: true, Directory: dir},
+				},
+			}
+
+			h := handler.NewNotifyHandler(cfg, handler.WithAudioPlayer(player))
+			_, err := h.Handle(context.Background(), &hookcmd.HookInput{
+				HookEventName: hookcmd.EventNotification,
+			})
+			require.NoError(t, err)
+
+			require.Len(t, player.played, 1)
+			assert.Equal(t, filepath.Join(soundDir, "beep.mp3"), player.played[0])
+			assert.True(t, filepath.IsAbs(player.played[0]), "player should get an absolute path")
+		})
 	}
-
-	resp, err := h.Handle(context.Background(), input)
-	require.NoError(t, err)
-	require.NotNil(t, resp)
-	assert.Equal(t, 0, resp.ExitCode)
 }
 
-func TestNotifyNtfyHandler_EmptyTopic(t *testing.T) {
+func TestNotifyHandler_AudioPlayerFromConfig(t *testing.T) {
 	t.Parallel()
-	cfg := &config.Values{
-		Notifications: config.NotificationsValues{
-			NtfyTopic: "",
+	tmpDir := newAudioDir(t)
+
+	tests := []struct {
+		name     string
+		player   string
+		override bool
+		wantCall []cmdRunnerCall
+	}{
+		{
+			name:     "configured player is run",
+			player:   "paplay",
+			override: false,
+			wantCall: []cmdRunnerCall{{name: "paplay", args: []string{filepath.Join(tmpDir, "beep.mp3")}}},
+		},
+		{
+			name:     "configured command keeps its arguments",
+			player:   "mpv --really-quiet",
+			override: false,
+			wantCall: []cmdRunnerCall{
+				{name: "mpv", args: []string{"--really-quiet", filepath.Join(tmpDir, "beep.mp3")}},
+			},
+		},
+		{
+			name:     "explicit player wins over config",
+			player:   "paplay",
+			override: true,
+			wantCall: nil,
 		},
 	}
 
-	sender := &mockNtfySender{calls: []ntfySendCall{}}
-	h := handler.NewNotifyNtfyHandler(cfg, handler.WithNtfySender(sender))
-	input := &hookcmd.HookInput{
-		HookEventName: hookcmd.EventNotification,
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &config.Values{
+				Notify: config.NotifyValues{
+					Audio: config.AudioValues{
+						Enabled:   true,
+						Directory: tmpDir,
+						Player:    tt.player,
+					},
+				},
+			}
+
+			runner := &mockCmdRunner{calls: nil}
+			opts := []handler.NotifyOption{handler.WithAudioRunner(runner)}
+			explicit := &mockAudioPlayer{played: []string{}}
+			if tt.override {
+				opts = append(opts, handler.WithAudioPlayer(explicit))
+			}
+
+			h := handler.NewNotifyHandler(cfg, opts...)
+			resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
+				HookEventName: hookcmd.EventNotification,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 0, resp.ExitCode)
+			assert.Equal(t, tt.wantCall, runner.calls)
+			assert.Equal(t, tt.override, len(explicit.played) == 1)
+		})
 	}
-
-	resp, err := h.Handle(context.Background(), input)
-	require.NoError(t, err)
-	require.NotNil(t, resp)
-	assert.Equal(t, 0, resp.ExitCode)
-	assert.Empty(t, sender.calls, "should not send when topic is empty")
 }
 
-func TestNotifyNtfyHandler_QuietHoursActive(t *testing.T) {
+func TestNotifyHandler_DesktopSkippedWithoutRunner(t *testing.T) {
 	t.Parallel()
 	cfg := &config.Values{
-		Notifications: config.NotificationsValues{
-			NtfyTopic: "test-topic",
-		},
 		Notify: config.NotifyValues{
-			QuietHours: config.QuietHoursValues{
+			Desktop: config.DesktopValues{
 				Enabled: true,
-				Start:   "00:00",
-				End:     "23:59",
 			},
 		},
 	}
 
-	sender := &mockNtfySender{calls: []ntfySendCall{}}
-	h := handler.NewNotifyNtfyHandler(cfg, handler.WithNtfySender(sender))
-	input := &hookcmd.HookInput{
+	// No WithCmdRunner option — runner is nil.
+	h := handler.NewNotifyHandler(cfg)
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
 		HookEventName: hookcmd.EventNotification,
-	}
-
-	resp, err := h.Handle(context.Background(), input)
+		Title:         "Test",
+		Message:       "Hello",
+	})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 	assert.Equal(t, 0, resp.ExitCode)
-	assert.Empty(t, sender.calls,
-		"should not send during quiet hours")
 }
 
-func TestNotifyNtfyHandler_SendsWithDefaults(t *testing.T) {
+func TestNotifyHandler_DefaultTitleAndMessage(t *testing.T) {
 	t.Parallel()
 	cfg := &config.Values{
 		Notifications: config.NotificationsValues{
@@ -456,12 +463,10 @@ func TestNotifyNtfyHandler_SendsWithDefaults(t *testing.T) {
 	}
 
 	sender := &mockNtfySender{calls: []ntfySendCall{}}
-	h := handler.NewNotifyNtfyHandler(cfg, handler.WithNtfySender(sender))
-	input := &hookcmd.HookInput{
+	h := handler.NewNotifyHandler(cfg, handler.WithNtfySender(sender))
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
 		HookEventName: hookcmd.EventNotification,
-	}
-
-	resp, err := h.Handle(context.Background(), input)
+	})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 	assert.Equal(t, 0, resp.ExitCode)
@@ -470,31 +475,7 @@ func TestNotifyNtfyHandler_SendsWithDefaults(t *testing.T) {
 	assert.Equal(t, "Task completed", sender.calls[0].message)
 }
 
-func TestNotifyNtfyHandler_CustomTitleAndMessage(t *testing.T) {
-	t.Parallel()
-	cfg := &config.Values{
-		Notifications: config.NotificationsValues{
-			NtfyTopic: "test-topic",
-		},
-	}
-
-	sender := &mockNtfySender{calls: []ntfySendCall{}}
-	h := handler.NewNotifyNtfyHandler(cfg, handler.WithNtfySender(sender))
-	input := &hookcmd.HookInput{
-		HookEventName: hookcmd.EventNotification,
-		Title:         "Build Complete",
-		Message:       "All 766 tests passed",
-	}
-
-	resp, err := h.Handle(context.Background(), input)
-	require.NoError(t, err)
-	require.NotNil(t, resp)
-	require.Len(t, sender.calls, 1)
-	assert.Equal(t, "Build Complete", sender.calls[0].title)
-	assert.Equal(t, "All 766 tests passed", sender.calls[0].message)
-}
-
-func TestNotifyNtfyHandler_ImplementsHandler(t *testing.T) {
+func TestNotifyHandler_ImplementsHandler(t *testing.T) {
 	t.Parallel()
-	var _ handler.Handler = handler.NewNotifyNtfyHandler(nil)
+	var _ handler.Handler = handler.NewNotifyHandler(nil)
 }
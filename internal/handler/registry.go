@@ -2,7 +2,10 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/riddopic/cc-tools/internal/hookcmd"
 )
@@ -10,11 +13,52 @@ import (
 // Registry maps hook event names to handler slices.
 type Registry struct {
 	handlers map[string][]Handler
+	errLog   io.Writer
+	timingFn TimingFunc
+}
+
+// RegistryOption configures a Registry.
+type RegistryOption func(*Registry)
+
+// TimingFunc receives how long a handler took and the error it returned.
+type TimingFunc func(name string, elapsed time.Duration, err error)
+
+// WithTimingLog makes Dispatch write one "handler=<name> duration=<ms>
+// err=<error>" line to w for every handler it runs. It is a TimingFunc, so
+// it replaces any earlier WithTimingFunc and is replaced by a later one.
+func WithTimingLog(w io.Writer) RegistryOption {
+	return WithTimingFunc(func(name string, elapsed time.Duration, err error) {
+		errText := "none"
+		if err != nil {
+			errText = err.Error()
+		}
+		_, _ = fmt.Fprintf(w, "handler=%s duration=%d err=%s\n", name, elapsed.Milliseconds(), errText)
+	})
+}
+
+// WithTimingFunc makes Dispatch call fn after every handler it runs.
+func WithTimingFunc(fn TimingFunc) RegistryOption {
+	return func(r *Registry) {
+		r.timingFn = fn
+	}
+}
+
+// WithErrorLog makes Dispatch write every handler error to w, with the
+// stack trace of a recovered panic. Without it handler errors are dropped;
+// they never reach the response's stderr.
+func WithErrorLog(w io.Writer) RegistryOption {
+	return func(r *Registry) {
+		r.errLog = w
+	}
 }
 
 // NewRegistry creates an empty handler registry.
-func NewRegistry() *Registry {
-	return &Registry{handlers: make(map[string][]Handler)}
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{handlers: make(map[string][]Handler), errLog: nil, timingFn: nil}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // Register adds one or more handlers for the given event name.
@@ -24,6 +68,7 @@ func (r *Registry) Register(event string, handlers ...Handler) {
 
 // Dispatch runs all handlers for the event and merges their responses.
 // Unknown events return a zero-value Response (exit code 0, no output).
+// Handlers not yet started when ctx is done are skipped.
 func (r *Registry) Dispatch(ctx context.Context, input *hookcmd.HookInput) *Response {
 	handlers := r.handlers[input.HookEventName]
 	if len(handlers) == 0 {
@@ -32,9 +77,19 @@ func (r *Registry) Dispatch(ctx context.Context, input *hookcmd.HookInput) *Resp
 
 	merged := &Response{}
 	for _, h := range handlers {
+		// Once the hook is cancelled, for example by SIGTERM, the remaining
+		// handlers are skipped.
+		if err := ctx.Err(); err != nil {
+			merged.Stderr += fmt.Sprintf("[%s] skipped: %v\n", h.Name(), err)
+
+			continue
+		}
+
+		start := time.Now()
 		resp, err := r.dispatchOne(ctx, h, input)
+		r.logTiming(h.Name(), time.Since(start), err)
 		if err != nil {
-			merged.Stderr += fmt.Sprintf("[%s] error: %v\n", h.Name(), err)
+			r.logError(h.Name(), err)
 
 			continue
 		}
@@ -60,17 +115,36 @@ func (r *Registry) Dispatch(ctx context.Context, input *hookcmd.HookInput) *Resp
 }
 
 // dispatchOne calls a single handler with panic recovery. If the handler
-// panics, the panic value is captured and returned as an error.
+// panics, the panic is returned as a *hookcmd.PanicError.
 //
 //nolint:nonamedreturns // named returns required for defer/recover to assign err
 func (r *Registry) dispatchOne(
 	ctx context.Context, h Handler, input *hookcmd.HookInput,
 ) (resp *Response, err error) {
-	defer func() {
-		if p := recover(); p != nil {
-			err = fmt.Errorf("panic: %v", p)
-		}
-	}()
+	defer hookcmd.Recover(&err)
 
 	return h.Handle(ctx, input)
 }
+
+// logError records a handler error, with the stack trace of a recovered
+// panic, when an error log is set.
+func (r *Registry) logError(name string, err error) {
+	if r.errLog == nil {
+		return
+	}
+
+	var panicErr *hookcmd.PanicError
+	if errors.As(err, &panicErr) {
+		_, _ = fmt.Fprintf(r.errLog, "handler=%s panic=%v\n%s", name, panicErr.Value, panicErr.Stack)
+		return
+	}
+	_, _ = fmt.Fprintf(r.errLog, "handler=%s error=%v\n", name, err)
+}
+
+// logTiming passes how long a handler took to the timing func, if one is
+// set.
+func (r *Registry) logTiming(name string, elapsed time.Duration, err error) {
+	if r.timingFn != nil {
+		r.timingFn(name, elapsed, err)
+	}
+}
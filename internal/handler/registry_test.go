@@ -3,8 +3,13 @@
 package handler_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -38,6 +43,32 @@ func (p *panicHandler) Handle(_ context.Context, _ *hookcmd.HookInput) (*handler
 	panic(p.msg)
 }
 
+// slowHandler is a test handler that sleeps before returning err.
+type slowHandler struct {
+	name  string
+	delay time.Duration
+	err   error
+}
+
+func (s *slowHandler) Name() string { return s.name }
+
+func (s *slowHandler) Handle(_ context.Context, _ *hookcmd.HookInput) (*handler.Response, error) {
+	time.Sleep(s.delay)
+	return nil, s.err
+}
+
+// blockingHandler is a test handler that waits for its context to be done.
+type blockingHandler struct {
+	name string
+}
+
+func (b *blockingHandler) Name() string { return b.name }
+
+func (b *blockingHandler) Handle(ctx context.Context, _ *hookcmd.HookInput) (*handler.Response, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
 func TestRegistry_Dispatch_NoHandlers(t *testing.T) {
 	t.Parallel()
 	r := handler.NewRegistry()
@@ -134,9 +165,9 @@ func TestRegistry_Dispatch_HandlerError(t *testing.T) {
 	input := &hookcmd.HookInput{HookEventName: hookcmd.EventStop}
 	resp := r.Dispatch(context.Background(), input)
 
-	// Errors are logged to stderr, not fatal.
+	// Errors are not fatal and stay out of the hook's output.
 	assert.Equal(t, 0, resp.ExitCode)
-	assert.Contains(t, resp.Stderr, "[broken] error:")
+	assert.Empty(t, resp.Stderr)
 }
 
 func TestRegistry_Dispatch_NilResponse(t *testing.T) {
@@ -157,7 +188,8 @@ func TestRegistry_Dispatch_NilResponse(t *testing.T) {
 
 func TestRegistry_Dispatch_PanicRecovery(t *testing.T) {
 	t.Parallel()
-	r := handler.NewRegistry()
+	var log bytes.Buffer
+	r := handler.NewRegistry(handler.WithErrorLog(&log))
 	r.Register(hookcmd.EventPreToolUse,
 		&panicHandler{name: "crasher", msg: "unexpected nil pointer"},
 		&stubHandler{
@@ -176,11 +208,113 @@ func TestRegistry_Dispatch_PanicRecovery(t *testing.T) {
 	resp := r.Dispatch(context.Background(), input)
 
 	require.NotNil(t, resp)
-	// The panicking handler's error is captured in stderr.
-	assert.Contains(t, resp.Stderr, "[crasher] error:")
-	assert.Contains(t, resp.Stderr, "panic:")
-	assert.Contains(t, resp.Stderr, "unexpected nil pointer")
+	// The panic is captured in the error log, not stderr.
+	assert.Empty(t, resp.Stderr)
+	assert.Contains(t, log.String(), "handler=crasher panic=unexpected nil pointer")
 	// The normal handler's response is still included.
 	require.NotNil(t, resp.Stdout)
 	assert.Equal(t, "still here", resp.Stdout.SystemMessage)
 }
+
+func TestRegistry_Dispatch_TimingLog(t *testing.T) {
+	t.Parallel()
+	var log bytes.Buffer
+	r := handler.NewRegistry(handler.WithTimingLog(&log))
+	r.Register(hookcmd.EventStop,
+		&slowHandler{name: "slow", delay: 30 * time.Millisecond, err: nil},
+		&slowHandler{name: "failing", delay: 0, err: errors.New("boom")},
+		&panicHandler{name: "crasher", msg: "bad state"},
+	)
+
+	r.Dispatch(context.Background(), &hookcmd.HookInput{HookEventName: hookcmd.EventStop})
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	require.Len(t, lines, 3)
+
+	fields := strings.Fields(lines[0])
+	require.Len(t, fields, 3)
+	assert.Equal(t, "handler=slow", fields[0])
+	ms, err := strconv.Atoi(strings.TrimPrefix(fields[1], "duration="))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, ms, 30)
+	assert.Equal(t, "err=none", fields[2])
+
+	assert.Regexp(t, `^handler=failing duration=\d+ err=boom$`, lines[1])
+	assert.Regexp(t, `^handler=crasher duration=\d+ err=panic: bad state$`, lines[2])
+}
+
+func TestRegistry_Dispatch_TimingFunc(t *testing.T) {
+	t.Parallel()
+	var names []string
+	var errs []error
+	r := handler.NewRegistry(handler.WithTimingFunc(func(name string, elapsed time.Duration, err error) {
+		names = append(names, name)
+		errs = append(errs, err)
+		assert.Positive(t, elapsed)
+	}))
+	r.Register(hookcmd.EventStop,
+		&slowHandler{name: "slow", delay: time.Millisecond, err: nil},
+		&slowHandler{name: "failing", delay: time.Millisecond, err: errors.New("boom")},
+	)
+
+	r.Dispatch(context.Background(), &hookcmd.HookInput{HookEventName: hookcmd.EventStop})
+
+	assert.Equal(t, []string{"slow", "failing"}, names)
+	require.Len(t, errs, 2)
+	require.NoError(t, errs[0])
+	require.EqualError(t, errs[1], "boom")
+}
+
+func TestRegistry_Dispatch_LastTimingOptionWins(t *testing.T) {
+	t.Parallel()
+	var log bytes.Buffer
+	var names []string
+	r := handler.NewRegistry(
+		handler.WithTimingLog(&log),
+		handler.WithTimingFunc(func(name string, _ time.Duration, _ error) {
+			names = append(names, name)
+		}),
+	)
+	r.Register(hookcmd.EventStop, &slowHandler{name: "slow", delay: 0, err: nil})
+
+	r.Dispatch(context.Background(), &hookcmd.HookInput{HookEventName: hookcmd.EventStop})
+
+	assert.Equal(t, []string{"slow"}, names)
+	assert.Empty(t, log.String())
+}
+
+func TestRegistry_Dispatch_ErrorLog(t *testing.T) {
+	t.Parallel()
+	var log bytes.Buffer
+	r := handler.NewRegistry(handler.WithErrorLog(&log))
+	r.Register(hookcmd.EventStop,
+		&slowHandler{name: "failing", delay: 0, err: errors.New("boom")},
+		&panicHandler{name: "crasher", msg: "bad state"},
+	)
+
+	resp := r.Dispatch(context.Background(), &hookcmd.HookInput{HookEventName: hookcmd.EventStop})
+
+	assert.Equal(t, 0, resp.ExitCode)
+	assert.Empty(t, resp.Stderr)
+	want := "handler=failing error=boom\nhandler=crasher panic=bad state\ngoroutine "
+	assert.True(t, strings.HasPrefix(log.String(), want),
+		"each error is logged, the panic with its stack: %q", log.String())
+	assert.Contains(t, log.String(), "(*panicHandler).Handle")
+}
+
+func TestRegistry_Dispatch_Cancelled(t *testing.T) {
+	r := handler.NewRegistry()
+	next := &stubHandler{name: "next", resp: &handler.Response{ExitCode: 2}, err: nil}
+	r.Register(hookcmd.EventPostToolUse, &blockingHandler{name: "blocking"}, next)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	resp := r.Dispatch(ctx, &hookcmd.HookInput{HookEventName: hookcmd.EventPostToolUse})
+
+	assert.Less(t, time.Since(start), 5*time.Second, "Dispatch should return soon after cancellation")
+	assert.Equal(t, 0, resp.ExitCode, "handlers after the cancellation should not run")
+	assert.NotContains(t, resp.Stderr, "[blocking] error")
+	assert.Contains(t, resp.Stderr, "[next] skipped: context canceled")
+}
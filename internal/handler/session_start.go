@@ -8,9 +8,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/observe"
 	"github.com/riddopic/cc-tools/internal/pkgmanager"
 	"github.com/riddopic/cc-tools/internal/session"
 	"github.com/riddopic/cc-tools/internal/superpowers"
@@ -21,6 +23,7 @@ var (
 	_ Handler = (*SuperpowersHandler)(nil)
 	_ Handler = (*PkgManagerHandler)(nil)
 	_ Handler = (*SessionContextHandler)(nil)
+	_ Handler = (*ObserveRetentionHandler)(nil)
 )
 
 // ---------------------------------------------------------------------
@@ -81,20 +84,31 @@ func (h *PkgManagerHandler) Name() string { return "pkg-manager" }
 
 // Handle detects the project's package manager and persists it in the
 // .claude/.env file so it is available to Bash commands during the session.
+// A value already in the file is kept, so once one is recorded later
+// sessions neither detect nor write.
 func (h *PkgManagerHandler) Handle(_ context.Context, input *hookcmd.HookInput) (*Response, error) {
+	envDir := filepath.Join(input.Cwd, ".claude")
+	envFile := filepath.Join(envDir, ".env")
+
+	_, recorded, err := pkgmanager.ReadEnvFile(envFile)
+	if err != nil {
+		return nil, fmt.Errorf("read env file: %w", err)
+	}
+	if recorded {
+		return &Response{ExitCode: 0}, nil
+	}
+
 	var preferred string
 	if h.cfg != nil {
 		preferred = h.cfg.PackageManager.Preferred
 	}
 	manager := pkgmanager.DetectWithPreferred(input.Cwd, preferred)
 
-	envDir := filepath.Join(input.Cwd, ".claude")
-	if err := os.MkdirAll(envDir, 0o750); err != nil {
+	if err = os.MkdirAll(envDir, 0o750); err != nil {
 		return nil, fmt.Errorf("create .claude directory: %w", err)
 	}
 
-	envFile := filepath.Join(envDir, ".env")
-	if err := pkgmanager.WriteToEnvFile(envFile, manager); err != nil {
+	if err = pkgmanager.WriteToEnvFile(envFile, manager); err != nil {
 		return nil, fmt.Errorf("write env file: %w", err)
 	}
 
@@ -190,3 +204,69 @@ func (h *SessionContextHandler) Handle(_ context.Context, _ *hookcmd.HookInput)
 
 	return resp, nil
 }
+
+// ---------------------------------------------------------------------
+// ObserveRetentionHandler
+// ---------------------------------------------------------------------
+
+// ObserveRetentionOption configures an ObserveRetentionHandler.
+type ObserveRetentionOption func(*ObserveRetentionHandler)
+
+// WithObserveRetentionDir overrides the observation directory for testing.
+func WithObserveRetentionDir(dir string) ObserveRetentionOption {
+	return func(h *ObserveRetentionHandler) {
+		h.dir = dir
+	}
+}
+
+// ObserveRetentionHandler purges old observations on session start.
+type ObserveRetentionHandler struct {
+	cfg *config.Values
+	dir string
+}
+
+// NewObserveRetentionHandler creates a new ObserveRetentionHandler.
+func NewObserveRetentionHandler(cfg *config.Values, opts ...ObserveRetentionOption) *ObserveRetentionHandler {
+	h := &ObserveRetentionHandler{
+		cfg: cfg,
+		dir: "",
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Name returns the handler identifier.
+func (h *ObserveRetentionHandler) Name() string { return "observe-retention" }
+
+// Enabled reports whether observe.retention_days is positive.
+func (h *ObserveRetentionHandler) Enabled() bool {
+	return h.cfg != nil && h.cfg.Observe.RetentionDays > 0
+}
+
+// Handle removes observations older than observe.retention_days. A zero or
+// negative retention keeps everything.
+func (h *ObserveRetentionHandler) Handle(_ context.Context, _ *hookcmd.HookInput) (*Response, error) {
+	if !h.Enabled() {
+		return &Response{ExitCode: 0}, nil
+	}
+
+	dir := h.dir
+	if dir == "" {
+		defaultDir, err := observe.DefaultDir()
+		if err != nil {
+			return nil, fmt.Errorf("resolve observe directory: %w", err)
+		}
+
+		dir = defaultDir
+	}
+
+	cutoff := time.Now().Add(-time.Duration(h.cfg.Observe.RetentionDays) * observe.HoursPerDay * time.Hour)
+	if _, err := observe.Purge(dir, cutoff); err != nil {
+		return nil, fmt.Errorf("purge observations: %w", err)
+	}
+
+	return &Response{ExitCode: 0}, nil
+}
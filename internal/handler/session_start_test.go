@@ -135,6 +135,34 @@ func TestPkgManagerHandler_Handle_CreatesEnvFile(t *testing.T) {
 	assert.Contains(t, string(data), "PREFERRED_PACKAGE_MANAGER=")
 }
 
+func TestPkgManagerHandler_Handle_SkipsRecordedManager(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "yarn.lock"), []byte(""), 0o600))
+
+	envFile := filepath.Join(tmpDir, ".claude", ".env")
+	require.NoError(t, os.MkdirAll(filepath.Dir(envFile), 0o750))
+	content := "OTHER=1\nPREFERRED_PACKAGE_MANAGER=yarn\n"
+	require.NoError(t, os.WriteFile(envFile, []byte(content), 0o600))
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(envFile, past, past))
+
+	h := handler.NewPkgManagerHandler(nil)
+	resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
+		HookEventName: hookcmd.EventSessionStart,
+		Cwd:           tmpDir,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.ExitCode)
+
+	info, err := os.Stat(envFile)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(past), "env file should not be rewritten, mtime %v", info.ModTime())
+	data, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
+
 func TestPkgManagerHandler_Handle_DetectsYarn(t *testing.T) {
 	t.Parallel()
 	tmpDir := t.TempDir()
@@ -491,3 +519,50 @@ func TestSessionContextHandler_ImplementsHandler(t *testing.T) {
 	t.Parallel()
 	var _ handler.Handler = handler.NewSessionContextHandler()
 }
+
+// ---------------------------------------------------------------------
+// ObserveRetentionHandler
+// ---------------------------------------------------------------------
+
+func TestObserveRetentionHandler_Name(t *testing.T) {
+	t.Parallel()
+	h := handler.NewObserveRetentionHandler(nil)
+	assert.Equal(t, "observe-retention", h.Name())
+}
+
+func TestObserveRetentionHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name          string
+		retentionDays int
+		wantRemoved   bool
+	}{
+		{name: "purges old archives", retentionDays: 30, wantRemoved: true},
+		{name: "zero retention keeps everything", retentionDays: 0, wantRemoved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obsDir := t.TempDir()
+			archive := filepath.Join(obsDir, "observations-20240101-000000.jsonl")
+			require.NoError(t, os.WriteFile(archive, []byte("old\n"), 0o600))
+			old := time.Now().Add(-60 * 24 * time.Hour)
+			require.NoError(t, os.Chtimes(archive, old, old))
+
+			cfg := newTestConfig()
+			cfg.Observe.RetentionDays = tt.retentionDays
+			h := handler.NewObserveRetentionHandler(cfg, handler.WithObserveRetentionDir(obsDir))
+
+			resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
+				HookEventName: hookcmd.EventSessionStart,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 0, resp.ExitCode)
+
+			if tt.wantRemoved {
+				assert.NoFileExists(t, archive)
+			} else {
+				assert.FileExists(t, archive)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // Compile-time interface check.
@@ -47,20 +48,23 @@ func NewStopReminderHandler(cfg *config.Values, opts ...StopReminderOption) *Sto
 // Name returns the handler identifier.
 func (h *StopReminderHandler) Name() string { return "stop-reminder" }
 
+// Enabled reports whether stop_reminder.enabled is set.
+func (h *StopReminderHandler) Enabled() bool { return h.cfg != nil && h.cfg.StopReminder.Enabled }
+
 // Handle processes a Stop event, incrementing the response counter and emitting
 // a reminder when the configured interval or warning threshold is reached.
 func (h *StopReminderHandler) Handle(_ context.Context, input *hookcmd.HookInput) (*Response, error) {
-	if h.cfg == nil || !h.cfg.StopReminder.Enabled {
+	if !h.Enabled() {
 		return &Response{ExitCode: 0}, nil
 	}
 
 	stateDir := h.stateDir
 	if stateDir == "" {
-		homeDir, err := os.UserHomeDir()
+		dir, err := shared.CacheDir("stop")
 		if err != nil {
-			return nil, fmt.Errorf("get home directory: %w", err)
+			return nil, err
 		}
-		stateDir = filepath.Join(homeDir, ".cache", "cc-tools", "stop")
+		stateDir = dir
 	}
 
 	count := h.readCount(stateDir, input.SessionID)
@@ -5,14 +5,15 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/riddopic/cc-tools/internal/compact"
 	"github.com/riddopic/cc-tools/internal/config"
 	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
 	"github.com/riddopic/cc-tools/internal/observe"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // Compile-time interface checks.
@@ -25,6 +26,13 @@ var (
 // defaultPreCommitCommand is the fallback pre-commit command.
 const defaultPreCommitCommand = "task pre-commit"
 
+// observeDedupWindow is how soon an identical event must follow the last
+// one to be treated as a retry when observe.dedup is on.
+const observeDedupWindow = 5 * time.Second
+
+// compactHintTimeout bounds a compact.context_hint command.
+const compactHintTimeout = 5 * time.Second
+
 // ---------------------------------------------------------------------
 // SuggestCompactHandler
 // ---------------------------------------------------------------------
@@ -39,11 +47,20 @@ func WithCompactStateDir(dir string) SuggestCompactOption {
 	}
 }
 
+// WithCompactHintRunner overrides the runner for compact.context_hint
+// commands.
+func WithCompactHintRunner(runner hooks.CommandRunner) SuggestCompactOption {
+	return func(h *SuggestCompactHandler) {
+		h.runner = runner
+	}
+}
+
 // SuggestCompactHandler records tool calls and suggests compaction when a
 // threshold is exceeded.
 type SuggestCompactHandler struct {
 	cfg      *config.Values
 	stateDir string
+	runner   hooks.CommandRunner
 }
 
 // NewSuggestCompactHandler creates a new SuggestCompactHandler.
@@ -51,6 +68,7 @@ func NewSuggestCompactHandler(cfg *config.Values, opts ...SuggestCompactOption)
 	h := &SuggestCompactHandler{
 		cfg:      cfg,
 		stateDir: "",
+		runner:   hooks.NewDefaultDependencies().Runner,
 	}
 	for _, opt := range opts {
 		opt(h)
@@ -64,22 +82,24 @@ func (h *SuggestCompactHandler) Name() string { return "suggest-compact" }
 
 // Handle records a tool call and writes a /compact suggestion to stderr
 // when the session threshold is reached.
-func (h *SuggestCompactHandler) Handle(_ context.Context, input *hookcmd.HookInput) (*Response, error) {
+func (h *SuggestCompactHandler) Handle(ctx context.Context, input *hookcmd.HookInput) (*Response, error) {
 	if h.cfg == nil {
 		return &Response{ExitCode: 0}, nil
 	}
 
-	stateDir := h.stateDir
-	if stateDir == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("get home directory: %w", err)
-		}
+	stateDir, err := h.resolveStateDir()
+	if err != nil {
+		return nil, err
+	}
 
-		stateDir = filepath.Join(homeDir, ".cache", "cc-tools", "compact")
+	opts := []compact.SuggestorOption{compact.WithMessageTemplate(h.cfg.Compact.MessageTemplate)}
+	if source := h.cfg.Compact.ContextHint; source != "" {
+		opts = append(opts, compact.WithContextHint(func() (string, error) {
+			return h.readContextHint(ctx, source, input.Cwd)
+		}))
 	}
 
-	s := compact.NewSuggestor(stateDir, h.cfg.Compact.Threshold, h.cfg.Compact.ReminderInterval)
+	s := compact.NewSuggestor(stateDir, h.cfg.Compact.Threshold, h.cfg.Compact.ReminderInterval, opts...)
 
 	var buf bytes.Buffer
 	s.RecordCall(input.SessionID, &buf)
@@ -90,6 +110,55 @@ func (h *SuggestCompactHandler) Handle(_ context.Context, input *hookcmd.HookInp
 	}, nil
 }
 
+// readContextHint returns the compact.context_hint text. A source naming an
+// existing file is read; anything else is run as a command from dir.
+func (h *SuggestCompactHandler) readContextHint(ctx context.Context, source, dir string) (string, error) {
+	// A command may name variables that are unset here; it is still run.
+	path, err := shared.ExpandPath(source)
+	if err != nil {
+		path = source
+	}
+	if info, statErr := os.Stat(path); statErr == nil && info.Mode().IsRegular() {
+		// #nosec G304 -- compact.context_hint is sensitive, so the path comes
+		// from the user's own config and never from a project file.
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return "", fmt.Errorf("read context hint: %w", readErr)
+		}
+		return string(data), nil
+	}
+
+	words, err := hooks.SplitCommandLine(source)
+	if err != nil {
+		return "", fmt.Errorf("parse context hint command: %w", err)
+	}
+	if len(words) == 0 {
+		return "", nil
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, compactHintTimeout)
+	defer cancel()
+
+	out, err := h.runner.RunContext(runCtx, dir, words[0], words[1:]...)
+	if err != nil {
+		return "", fmt.Errorf("run context hint command: %w", err)
+	}
+	return string(out.Stdout), nil
+}
+
+// resolveStateDir picks the counter directory: the WithCompactStateDir
+// override, then compact.state_dir, then the compact cache directory.
+func (h *SuggestCompactHandler) resolveStateDir() (string, error) {
+	if h.stateDir != "" {
+		return h.stateDir, nil
+	}
+	if h.cfg.Compact.StateDir != "" {
+		return shared.ExpandPath(h.cfg.Compact.StateDir)
+	}
+
+	return shared.CacheDir("compact")
+}
+
 // ---------------------------------------------------------------------
 // ObserveHandler
 // ---------------------------------------------------------------------
@@ -129,32 +198,45 @@ func NewObserveHandler(cfg *config.Values, phase string, opts ...ObserveOption)
 // Name returns the handler identifier.
 func (h *ObserveHandler) Name() string { return "observe-" + h.phase }
 
+// Enabled reports whether observe.enabled is set.
+func (h *ObserveHandler) Enabled() bool { return h.cfg != nil && h.cfg.Observe.Enabled }
+
 // Handle records a tool usage event to the observations JSONL file.
 func (h *ObserveHandler) Handle(_ context.Context, input *hookcmd.HookInput) (*Response, error) {
-	if h.cfg == nil || !h.cfg.Observe.Enabled {
+	if !h.Enabled() {
 		return &Response{ExitCode: 0}, nil
 	}
 
 	dir := h.dir
 	if dir == "" {
-		homeDir, err := os.UserHomeDir()
+		defaultDir, err := observe.DefaultDir()
 		if err != nil {
-			return nil, fmt.Errorf("get home directory: %w", err)
+			return nil, fmt.Errorf("resolve observe directory: %w", err)
 		}
 
-		dir = filepath.Join(homeDir, ".cache", "cc-tools", "observations")
+		dir = defaultDir
 	}
 
-	obs := observe.NewObserver(dir, h.cfg.Observe.MaxFileSizeMB)
+	opts := []observe.ObserverOption{observe.WithMaxInputBytes(h.cfg.Observe.MaxInputBytes)}
+	if h.cfg.Observe.Anonymize {
+		opts = append(opts, observe.WithAnonymizer(newPathAnonymizer(input.Cwd)))
+	}
+	if h.cfg.Observe.Dedup {
+		opts = append(opts, observe.WithDedup(observeDedupWindow))
+	}
+
+	obs := observe.NewObserver(dir, h.cfg.Observe.MaxFileSizeMB, opts...)
 
 	if err := obs.Record(observe.Event{
-		Timestamp:  time.Now(),
-		Phase:      h.phase,
-		ToolName:   input.ToolName,
-		ToolInput:  input.ToolInput,
-		ToolOutput: input.ToolOutput,
-		Error:      input.Error,
-		SessionID:  string(input.SessionID),
+		Timestamp:     time.Now(),
+		Phase:         h.phase,
+		ToolName:      input.ToolName,
+		ToolInput:     input.ToolInput,
+		ToolOutput:    input.ToolOutput,
+		Error:         input.Error,
+		SessionID:     string(input.SessionID),
+		FilePath:      "",
+		CorrelationID: input.ToolUseID,
 	}); err != nil {
 		return nil, fmt.Errorf("record observation: %w", err)
 	}
@@ -162,6 +244,20 @@ func (h *ObserveHandler) Handle(_ context.Context, input *hookcmd.HookInput) (*R
 	return &Response{ExitCode: 0}, nil
 }
 
+// newPathAnonymizer builds an anonymizer for the user's home directory and
+// the project containing cwd. Directories that cannot be resolved are left
+// as-is.
+func newPathAnonymizer(cwd string) *observe.Anonymizer {
+	homeDir, _ := os.UserHomeDir()
+
+	projectRoot := ""
+	if cwd != "" {
+		projectRoot, _ = shared.FindProjectRoot(cwd, nil)
+	}
+
+	return observe.NewAnonymizer(homeDir, projectRoot)
+}
+
 // ---------------------------------------------------------------------
 // PreCommitReminderHandler
 // ---------------------------------------------------------------------
@@ -180,10 +276,13 @@ func NewPreCommitReminderHandler(cfg *config.Values) *PreCommitReminderHandler {
 // Name returns the handler identifier.
 func (h *PreCommitReminderHandler) Name() string { return "pre-commit-reminder" }
 
+// Enabled reports whether pre_commit_reminder.enabled is set.
+func (h *PreCommitReminderHandler) Enabled() bool { return h.cfg != nil && h.cfg.PreCommit.Enabled }
+
 // Handle checks if the tool input contains a git commit command and writes
 // a reminder to run the pre-commit command.
 func (h *PreCommitReminderHandler) Handle(_ context.Context, input *hookcmd.HookInput) (*Response, error) {
-	if h.cfg == nil || !h.cfg.PreCommit.Enabled {
+	if !h.Enabled() {
 		return &Response{ExitCode: 0}, nil
 	}
 
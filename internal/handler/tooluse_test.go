@@ -23,8 +23,15 @@ import (
 func newTestConfig() *config.Values {
 	return &config.Values{
 		Validate: config.ValidateValues{
-			Timeout:  0,
-			Cooldown: 0,
+			Timeout:             0,
+			Cooldown:            0,
+			ExtraCommands:       nil,
+			Env:                 nil,
+			CleanEnv:            false,
+			InjectCI:            false,
+			SkipDuringGitOps:    false,
+			WarnDirty:           false,
+			CaseInsensitiveSkip: false,
 		},
 		Notifications: config.NotificationsValues{
 			NtfyTopic: "",
@@ -32,6 +39,10 @@ func newTestConfig() *config.Values {
 		Compact: config.CompactValues{
 			Threshold:        0,
 			ReminderInterval: 0,
+			MessageTemplate:  "",
+			Snapshot:         false,
+			StateDir:         "",
+			ContextHint:      "",
 		},
 		Notify: config.NotifyValues{
 			QuietHours: config.QuietHoursValues{
@@ -42,6 +53,7 @@ func newTestConfig() *config.Values {
 			Audio: config.AudioValues{
 				Enabled:   false,
 				Directory: "",
+				Player:    "",
 			},
 			Desktop: config.DesktopValues{
 				Enabled: false,
@@ -50,6 +62,10 @@ func newTestConfig() *config.Values {
 		Observe: config.ObserveValues{
 			Enabled:       false,
 			MaxFileSizeMB: 0,
+			Anonymize:     false,
+			MaxInputBytes: 0,
+			RetentionDays: 0,
+			Dedup:         false,
 		},
 		Learning: config.LearningValues{
 			MinSessionLength:  0,
@@ -72,6 +88,9 @@ func newTestConfig() *config.Values {
 			Interval: 0,
 			WarnAt:   0,
 		},
+		Format: config.FormatValues{
+			OnEdit: false,
+		},
 	}
 }
 
@@ -125,6 +144,47 @@ func TestSuggestCompactHandler_RecordsCall(t *testing.T) {
 	assert.NoError(t, statErr, "counter file should be created")
 }
 
+func TestSuggestCompactHandler_ConfiguredStateDir(t *testing.T) {
+	stateDir := filepath.Join(t.TempDir(), "relocated")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	cfg := newTestConfig()
+	cfg.Compact.Threshold = 5
+	cfg.Compact.StateDir = stateDir
+
+	h := handler.NewSuggestCompactHandler(cfg)
+	input := &hookcmd.HookInput{
+		HookEventName: hookcmd.EventPreToolUse,
+		SessionID:     "test-session-dir",
+	}
+
+	_, err := h.Handle(context.Background(), input)
+	require.NoError(t, err)
+
+	_, statErr := os.Stat(filepath.Join(stateDir, "cc-tools-compact-test-session-dir.count"))
+	assert.NoError(t, statErr, "counter file should be written to compact.state_dir")
+}
+
+func TestSuggestCompactHandler_XDGCacheFallback(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+
+	cfg := newTestConfig()
+	cfg.Compact.Threshold = 5
+
+	h := handler.NewSuggestCompactHandler(cfg)
+	input := &hookcmd.HookInput{
+		HookEventName: hookcmd.EventPreToolUse,
+		SessionID:     "test-session-xdg",
+	}
+
+	_, err := h.Handle(context.Background(), input)
+	require.NoError(t, err)
+
+	_, statErr := os.Stat(filepath.Join(cacheHome, "cc-tools", "compact", "cc-tools-compact-test-session-xdg.count"))
+	assert.NoError(t, statErr, "counter file should be written under XDG_CACHE_HOME")
+}
+
 func TestSuggestCompactHandler_SuggestsAtThreshold(t *testing.T) {
 	t.Parallel()
 	tmpDir := t.TempDir()
@@ -338,6 +398,51 @@ func TestSuggestCompactHandler_SuggestionMessage(t *testing.T) {
 		"message should mention /compact")
 }
 
+func TestSuggestCompactHandler_ContextHint(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, hint string) string {
+		t.Helper()
+		cfg := newTestConfig()
+		cfg.Compact.Threshold = 1
+		cfg.Compact.ContextHint = hint
+
+		h := handler.NewSuggestCompactHandler(cfg, handler.WithCompactStateDir(t.TempDir()))
+		resp, err := h.Handle(context.Background(), &hookcmd.HookInput{
+			HookEventName: hookcmd.EventPreToolUse,
+			SessionID:     "hint-test",
+			Cwd:           t.TempDir(),
+		})
+		require.NoError(t, err)
+		return resp.Stderr
+	}
+
+	t.Run("file hint", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "hint.txt")
+		require.NoError(t, os.WriteFile(path, []byte("Read PLAN.md after compacting\n"), 0o600))
+
+		assert.Contains(t, run(t, path), "/compact to reduce context usage.\nRead PLAN.md after compacting\n")
+	})
+
+	t.Run("command hint", func(t *testing.T) {
+		t.Parallel()
+		assert.Contains(t, run(t, "echo 'on branch main'"), "\non branch main\n")
+	})
+
+	t.Run("failing command adds nothing", func(t *testing.T) {
+		t.Parallel()
+		stderr := run(t, "false")
+		assert.True(t, strings.HasSuffix(stderr, "usage.\n"), "got %q", stderr)
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		t.Parallel()
+		stderr := run(t, "")
+		assert.True(t, strings.HasSuffix(stderr, "usage.\n"), "got %q", stderr)
+	})
+}
+
 func TestSuggestCompactHandler_ImplementsHandler(t *testing.T) {
 	t.Parallel()
 	var _ handler.Handler = handler.NewSuggestCompactHandler(nil)
@@ -433,6 +538,33 @@ func TestObserveHandler_RecordsEvent(t *testing.T) {
 	assert.Contains(t, string(data), "observe-session")
 }
 
+func TestObserveHandler_Dedup(t *testing.T) {
+	t.Parallel()
+	obsDir := filepath.Join(t.TempDir(), "observations")
+
+	cfg := newTestConfig()
+	cfg.Observe.Enabled = true
+	cfg.Observe.MaxFileSizeMB = 10
+	cfg.Observe.Dedup = true
+
+	h := handler.NewObserveHandler(cfg, "pre", handler.WithObserveDir(obsDir))
+	input := &hookcmd.HookInput{
+		HookEventName: hookcmd.EventPreToolUse,
+		ToolName:      "Bash",
+		ToolInput:     json.RawMessage(`{"command":"go test ./..."}`),
+		SessionID:     "retry-session",
+	}
+
+	for range 2 {
+		_, err := h.Handle(context.Background(), input)
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(obsDir, "observations.jsonl"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"), "retried event should be recorded once")
+}
+
 func TestObserveHandler_PostPhase(t *testing.T) {
 	t.Parallel()
 	tmpDir := t.TempDir()
@@ -2,8 +2,10 @@ package hookcmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"runtime/debug"
 )
 
 // Handler processes a hook event.
@@ -12,19 +14,117 @@ type Handler interface {
 	Run(ctx context.Context, input *HookInput, out io.Writer, errOut io.Writer) error
 }
 
-// RunHandlers executes handlers sequentially. Errors are logged to errOut
-// but do not stop subsequent handlers. Panics are recovered.
-func RunHandlers(ctx context.Context, input *HookInput, handlers []Handler, out, errOut io.Writer) {
+// ExitCoder is implemented by handler errors that request a specific exit
+// code from Dispatch.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitError is a handler error that requests exit code Code from Dispatch,
+// for example 2 to block the tool call.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// ExitCode returns the requested exit code.
+func (e *ExitError) ExitCode() int { return e.Code }
+
+// HandlerError records which handler returned an error.
+type HandlerError struct {
+	Handler string
+	Err     error
+}
+
+func (e *HandlerError) Error() string { return fmt.Sprintf("%s: %v", e.Handler, e.Err) }
+
+func (e *HandlerError) Unwrap() error { return e.Err }
+
+// PanicError is the error a recovered handler panic becomes. Stack is the
+// stack trace of the panicking goroutine, for the debug log.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("panic: %v", e.Value) }
+
+// Recover turns a panic in progress into a *PanicError stored in *err. It
+// must itself be the deferred call: defer hookcmd.Recover(&err).
+func Recover(err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Value: r, Stack: debug.Stack()}
+	}
+}
+
+// RunHandlers executes handlers sequentially. Errors do not stop
+// subsequent handlers; each is written to debugLog, when it is not nil,
+// rather than to the hook's errOut. A panic is recovered as a *PanicError
+// and logged with its stack trace. The returned error joins a HandlerError
+// for every handler that failed, or is nil.
+func RunHandlers(
+	ctx context.Context, input *HookInput, handlers []Handler, out, errOut, debugLog io.Writer,
+) error {
+	var errs []error
 	for _, h := range handlers {
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					fmt.Fprintf(errOut, "[%s] panic recovered: %v\n", h.Name(), r)
-				}
-			}()
-			if err := h.Run(ctx, input, out, errOut); err != nil {
-				fmt.Fprintf(errOut, "[%s] error: %v\n", h.Name(), err)
-			}
-		}()
+		err := runHandler(ctx, h, input, out, errOut)
+		if err == nil {
+			continue
+		}
+
+		logHandlerError(debugLog, h.Name(), err)
+		errs = append(errs, &HandlerError{Handler: h.Name(), Err: err})
+	}
+
+	return errors.Join(errs...)
+}
+
+// logHandlerError writes err from the named handler to w, with the stack
+// trace of a recovered panic. A nil w discards it.
+func logHandlerError(w io.Writer, name string, err error) {
+	if w == nil {
+		return
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		_, _ = fmt.Fprintf(w, "[%s] panic recovered: %v\n%s", name, panicErr.Value, panicErr.Stack)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "[%s] error: %v\n", name, err)
+}
+
+// runHandler runs h, returning a panic as a *PanicError.
+//
+//nolint:nonamedreturns // named returns required for defer/recover to assign err
+func runHandler(ctx context.Context, h Handler, input *HookInput, out, errOut io.Writer) (err error) {
+	defer Recover(&err)
+	return h.Run(ctx, input, out, errOut)
+}
+
+// exitCodeOf returns the highest exit code requested by the handler errors
+// joined in err. Errors that do not implement ExitCoder count as 0.
+func exitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		var coder ExitCoder
+		if errors.As(err, &coder) {
+			return coder.ExitCode()
+		}
+		return 0
+	}
+
+	code := 0
+	for _, e := range joined.Unwrap() {
+		code = max(code, exitCodeOf(e))
 	}
+	return code
 }
@@ -27,10 +27,10 @@ func (h *testHandler) Run(_ context.Context, _ *hookcmd.HookInput, _ io.Writer,
 
 func TestRunHandlers(t *testing.T) {
 	tests := []struct {
-		name           string
-		handlers       []hookcmd.Handler
-		wantErrOut     string
-		expectNoErrOut bool
+		name        string
+		handlers    []hookcmd.Handler
+		wantLog     string
+		expectNoLog bool
 	}{
 		{
 			name: "sequential execution in order",
@@ -56,8 +56,8 @@ func TestRunHandlers(t *testing.T) {
 					},
 				}
 			}(),
-			wantErrOut:     "",
-			expectNoErrOut: true,
+			wantLog:     "",
+			expectNoLog: true,
 		},
 		{
 			name: "continues after error",
@@ -77,8 +77,8 @@ func TestRunHandlers(t *testing.T) {
 					},
 				}
 			}(),
-			wantErrOut:     "[failing] error: something broke",
-			expectNoErrOut: false,
+			wantLog:     "[failing] error: something broke",
+			expectNoLog: false,
 		},
 		{
 			name: "recovers from panic and continues",
@@ -98,30 +98,34 @@ func TestRunHandlers(t *testing.T) {
 					},
 				}
 			}(),
-			wantErrOut:     "[panicking] panic recovered: unexpected panic",
-			expectNoErrOut: false,
+			wantLog:     "[panicking] panic recovered: unexpected panic",
+			expectNoLog: false,
 		},
 		{
-			name:           "empty handler list does nothing",
-			handlers:       []hookcmd.Handler{},
-			wantErrOut:     "",
-			expectNoErrOut: true,
+			name:        "empty handler list does nothing",
+			handlers:    []hookcmd.Handler{},
+			wantLog:     "",
+			expectNoLog: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var out, errOut bytes.Buffer
+			var out, errOut, log bytes.Buffer
 			ctx := context.Background()
 			input := &hookcmd.HookInput{}
 
-			hookcmd.RunHandlers(ctx, input, tt.handlers, &out, &errOut)
+			err := hookcmd.RunHandlers(ctx, input, tt.handlers, &out, &errOut, &log)
 
-			if tt.expectNoErrOut {
-				assert.Empty(t, errOut.String())
+			if tt.expectNoLog {
+				require.NoError(t, err)
+				assert.Empty(t, log.String())
+			} else {
+				require.Error(t, err)
 			}
-			if tt.wantErrOut != "" {
-				assert.Contains(t, errOut.String(), tt.wantErrOut)
+			assert.Empty(t, errOut.String(), "handler errors go to the debug log")
+			if tt.wantLog != "" {
+				assert.Contains(t, log.String(), tt.wantLog)
 			}
 		})
 	}
@@ -157,7 +161,7 @@ func TestRunHandlersExecutionOrder(t *testing.T) {
 	ctx := context.Background()
 	input := &hookcmd.HookInput{}
 
-	hookcmd.RunHandlers(ctx, input, handlers, &out, &errOut)
+	require.NoError(t, hookcmd.RunHandlers(ctx, input, handlers, &out, &errOut, nil))
 
 	require.Len(t, order, 3)
 	assert.Equal(t, []string{"first", "second", "third"}, order)
@@ -5,16 +5,23 @@ import (
 	"io"
 )
 
-// Dispatch routes a hook event to registered handlers.
-// Returns the exit code (always 0 -- errors are logged, not fatal).
-func Dispatch(ctx context.Context, input *HookInput, out, errOut io.Writer, registry map[string][]Handler) int {
+// Dispatch routes a hook event to registered handlers. Every handler runs
+// even if an earlier one fails or panics, and each failure is written to
+// debugLog when it is not nil. The returned error joins all handler
+// errors, with a panic as a *PanicError carrying its stack trace, and the
+// exit code is the highest requested by any error implementing ExitCoder,
+// such as *ExitError (0 when none do, so a panic alone does not fail the
+// hook).
+func Dispatch(
+	ctx context.Context, input *HookInput, out, errOut, debugLog io.Writer, registry map[string][]Handler,
+) (int, error) {
 	handlers, ok := registry[input.HookEventName]
 	if !ok {
 		// Unknown event type -- accept gracefully.
-		return 0
+		return 0, nil
 	}
 
-	RunHandlers(ctx, input, handlers, out, errOut)
+	err := RunHandlers(ctx, input, handlers, out, errOut, debugLog)
 
-	return 0
+	return exitCodeOf(err), err
 }
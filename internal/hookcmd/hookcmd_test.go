@@ -3,9 +3,13 @@ package hookcmd_test
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/riddopic/cc-tools/internal/hookcmd"
 )
@@ -88,8 +92,9 @@ func TestDispatch(t *testing.T) {
 			var out, errOut bytes.Buffer
 			ctx := context.Background()
 
-			exitCode := hookcmd.Dispatch(ctx, tt.input, &out, &errOut, tt.registry)
+			exitCode, err := hookcmd.Dispatch(ctx, tt.input, &out, &errOut, nil, tt.registry)
 
+			require.NoError(t, err)
 			assert.Equal(t, tt.wantExit, exitCode)
 			if tt.wantErrOut != "" {
 				assert.Contains(t, errOut.String(), tt.wantErrOut)
@@ -119,8 +124,136 @@ func TestDispatchRoutesToCorrectHandler(t *testing.T) {
 	var out, errOut bytes.Buffer
 	ctx := context.Background()
 
-	exitCode := hookcmd.Dispatch(ctx, input, &out, &errOut, registry)
+	exitCode, err := hookcmd.Dispatch(ctx, input, &out, &errOut, nil, registry)
 
+	require.NoError(t, err)
 	assert.Equal(t, 0, exitCode)
 	assert.True(t, called, "expected handler to be called")
 }
+
+func TestDispatchAggregatesErrors(t *testing.T) {
+	errLint := errors.New("lint failed")
+	var ran []string
+	registry := map[string][]hookcmd.Handler{
+		"PostToolUse": {
+			&testHandler{
+				name: "lint",
+				runFn: func() error {
+					ran = append(ran, "lint")
+					return errLint
+				},
+			},
+			&testHandler{
+				name: "ok",
+				runFn: func() error {
+					ran = append(ran, "ok")
+					return nil
+				},
+			},
+			&testHandler{
+				name: "block",
+				runFn: func() error {
+					ran = append(ran, "block")
+					return &hookcmd.ExitError{Code: 2, Err: errors.New("blocked")}
+				},
+			},
+			&testHandler{
+				name: "warn",
+				runFn: func() error {
+					ran = append(ran, "warn")
+					return &hookcmd.ExitError{Code: 1, Err: errors.New("warned")}
+				},
+			},
+		},
+	}
+
+	var out, errOut, log bytes.Buffer
+	exitCode, err := hookcmd.Dispatch(
+		context.Background(), &hookcmd.HookInput{HookEventName: "PostToolUse"}, &out, &errOut, &log, registry,
+	)
+
+	require.Error(t, err)
+	assert.Equal(t, []string{"lint", "ok", "block", "warn"}, ran)
+	assert.Equal(t, 2, exitCode)
+	require.ErrorIs(t, err, errLint)
+
+	var handlerErr *hookcmd.HandlerError
+	require.ErrorAs(t, err, &handlerErr)
+	assert.Equal(t, "lint", handlerErr.Handler)
+
+	var exitErr *hookcmd.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, "blocked", exitErr.Error())
+
+	for _, name := range []string{"lint", "block", "warn"} {
+		assert.Contains(t, err.Error(), name+":")
+		assert.Contains(t, log.String(), "["+name+"] error:")
+	}
+	assert.Empty(t, errOut.String(), "handler errors go to the debug log, not the hook's stderr")
+}
+
+func TestExitError(t *testing.T) {
+	cause := errors.New("secret in diff")
+	var err error = &hookcmd.ExitError{Code: 2, Err: cause}
+
+	assert.Equal(t, "secret in diff", err.Error())
+	require.ErrorIs(t, err, cause)
+
+	var coder hookcmd.ExitCoder
+	require.ErrorAs(t, err, &coder)
+	assert.Equal(t, 2, coder.ExitCode())
+
+	wrapped := fmt.Errorf("scan: %w", err)
+	exitCode, _ := hookcmd.Dispatch(
+		context.Background(), &hookcmd.HookInput{HookEventName: "PreToolUse"}, io.Discard, io.Discard, nil,
+		map[string][]hookcmd.Handler{
+			"PreToolUse": {&testHandler{name: "scan", runFn: func() error { return wrapped }}},
+		},
+	)
+	assert.Equal(t, 2, exitCode, "a wrapped ExitError still sets the exit code")
+}
+
+func TestDispatchRecoversPanic(t *testing.T) {
+	var ran []string
+	registry := map[string][]hookcmd.Handler{
+		"Stop": {
+			&testHandler{
+				name: "before",
+				runFn: func() error {
+					ran = append(ran, "before")
+					return nil
+				},
+			},
+			&testHandler{
+				name: "crasher",
+				runFn: func() error {
+					var m map[string]int
+					m["boom"]++
+					return nil
+				},
+			},
+			&testHandler{
+				name: "after",
+				runFn: func() error {
+					ran = append(ran, "after")
+					return nil
+				},
+			},
+		},
+	}
+
+	var out, errOut, log bytes.Buffer
+	exitCode, err := hookcmd.Dispatch(
+		context.Background(), &hookcmd.HookInput{HookEventName: "Stop"}, &out, &errOut, &log, registry,
+	)
+
+	assert.Equal(t, []string{"before", "after"}, ran, "the handlers around the panic still run")
+	assert.Equal(t, 0, exitCode, "a panic alone does not fail the hook")
+	assert.Contains(t, log.String(), "[crasher] panic recovered: assignment to entry in nil map")
+	assert.Contains(t, log.String(), "hookcmd_test.TestDispatchRecoversPanic", "the log has the stack trace")
+	assert.Empty(t, errOut.String())
+
+	var panicErr *hookcmd.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Contains(t, string(panicErr.Stack), "hookcmd_test.TestDispatchRecoversPanic")
+}
@@ -76,6 +76,10 @@ type HookInput struct {
 	// PreCompact specific.
 	Trigger            string `json:"trigger,omitempty"`
 	CustomInstructions string `json:"custom_instructions,omitempty"`
+
+	// Raw is the event JSON as read by [ParseInput], including fields
+	// HookInput does not model. It is empty for inputs built in code.
+	Raw json.RawMessage `json:"-"`
 }
 
 // ParseInput reads JSON from the given reader and parses it into [HookInput].
@@ -93,6 +97,7 @@ func ParseInput(r io.Reader) (*HookInput, error) {
 	if unmarshalErr := json.Unmarshal(data, &input); unmarshalErr != nil {
 		return nil, fmt.Errorf("parsing hook input JSON: %w", unmarshalErr)
 	}
+	input.Raw = data
 
 	return &input, nil
 }
@@ -16,7 +16,7 @@ import (
 func TestExecutorEdgeCases(t *testing.T) {
 	t.Run("Execute with nil command", func(t *testing.T) {
 		testDeps := hooks.CreateTestDependencies()
-		executor := hooks.NewCommandExecutor(5, false, testDeps.Dependencies)
+		executor := hooks.NewCommandExecutor(5, false, hooks.DefaultEnvPolicy(), testDeps.Dependencies)
 
 		result := executor.Execute(context.Background(), nil)
 		if result.Success {
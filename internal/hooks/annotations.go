@@ -0,0 +1,153 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Annotation levels, named after the GitHub Actions workflow commands.
+const (
+	AnnotationError   = "error"
+	AnnotationWarning = "warning"
+)
+
+var (
+	// locationLine matches file:line[:col]: message, as printed by
+	// golangci-lint, go vet, ruff, and most compilers.
+	locationLine = regexp.MustCompile(`^([^\s:][^:]*):(\d+):(?:(\d+):)?\s*(.+)$`)
+	// eslintIssue matches an issue line under a file heading in eslint's
+	// default output: "  3:7  error  message  rule".
+	eslintIssue = regexp.MustCompile(`^\s+(\d+):(\d+)\s+(error|warning)\s+(.+?)\s*$`)
+)
+
+// Annotation is one linter or compiler finding tied to a file location.
+type Annotation struct {
+	Level   string
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// String renders the annotation as a GitHub Actions workflow command.
+func (a Annotation) String() string {
+	props := "file=" + escapeAnnotationProperty(a.File) + ",line=" + strconv.Itoa(a.Line)
+	if a.Column > 0 {
+		props += ",col=" + strconv.Itoa(a.Column)
+	}
+	return fmt.Sprintf("::%s %s::%s", a.Level, props, escapeAnnotationData(a.Message))
+}
+
+// ParseAnnotations extracts file locations from command output in the
+// file:line[:col]: message form and from eslint's default output. Lines
+// in neither form are ignored.
+func ParseAnnotations(output string) []Annotation {
+	var annotations []Annotation
+	eslintFile := ""
+
+	for line := range strings.Lines(output) {
+		line = strings.TrimRight(line, "\r\n")
+
+		if m := eslintIssue.FindStringSubmatch(line); m != nil && eslintFile != "" {
+			annotations = append(annotations, newAnnotation(m[3], eslintFile, m[1], m[2], m[4]))
+			continue
+		}
+		if m := locationLine.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			annotations = append(annotations, newAnnotation(AnnotationError, m[1], m[2], m[3], m[4]))
+			eslintFile = ""
+			continue
+		}
+
+		// eslint prints each file's path alone on a line before its issues
+		// and a blank line after them.
+		switch {
+		case strings.TrimSpace(line) == "":
+			eslintFile = ""
+		case !strings.ContainsAny(line, " \t"):
+			eslintFile = line
+		}
+	}
+
+	return annotations
+}
+
+func newAnnotation(level, file, line, col, message string) Annotation {
+	lineNum, _ := strconv.Atoi(line)
+	colNum, _ := strconv.Atoi(col)
+	return Annotation{Level: level, File: file, Line: lineNum, Column: colNum, Message: message}
+}
+
+// WriteGitHubAnnotations writes a workflow command for each finding in the
+// output of the failed commands in result. Paths are made relative to
+// projectRoot, which GitHub expects to be the repository root. A failed
+// command whose output has no recognizable locations is reported as a
+// single error annotation followed by its output as plain text.
+func WriteGitHubAnnotations(w io.Writer, projectRoot string, result *ValidateResult) error {
+	results := append([]*ValidationResult{result.LintResult, result.TestResult}, result.ExtraResults...)
+
+	var b strings.Builder
+	for _, r := range results {
+		if r == nil || r.Success {
+			continue
+		}
+
+		annotations := ParseAnnotations(r.Output)
+		for _, a := range annotations {
+			a.File = annotationPath(projectRoot, r.Command, a.File)
+			b.WriteString(a.String())
+			b.WriteByte('\n')
+		}
+		if len(annotations) > 0 {
+			continue
+		}
+
+		command := string(r.Type)
+		if r.Command != nil {
+			command = r.Command.String()
+		}
+		summary := fmt.Sprintf("%s failed (exit %d)", command, r.ExitCode)
+		_, _ = fmt.Fprintf(&b, "::error::%s\n", escapeAnnotationData(summary))
+		if output := strings.TrimRight(r.Output, "\n"); output != "" {
+			b.WriteString(output)
+			b.WriteByte('\n')
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write annotations: %w", err)
+	}
+	return nil
+}
+
+// annotationPath resolves file, which a command prints relative to its
+// working directory, to a slash-separated path relative to projectRoot.
+// Paths outside the project are left as they are.
+func annotationPath(projectRoot string, cmd *DiscoveredCommand, file string) string {
+	path := file
+	if !filepath.IsAbs(path) {
+		if cmd == nil || cmd.WorkingDir == "" {
+			return filepath.ToSlash(file)
+		}
+		path = filepath.Join(cmd.WorkingDir, path)
+	}
+
+	rel, err := filepath.Rel(projectRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// escapeAnnotationData escapes a workflow command message.
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeAnnotationProperty escapes a workflow command property value.
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
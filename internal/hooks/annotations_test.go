@@ -0,0 +1,119 @@
+package hooks_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []hooks.Annotation
+	}{
+		{
+			name: "golangci-lint",
+			output: "internal/app/main.go:12:2: declared and not used: x (unused)\n" +
+				"cmd/tool.go:40: line is 130 characters (lll)\n" +
+				"1 issues:\n* unused: 1\n",
+			want: []hooks.Annotation{
+				{
+					Level: hooks.AnnotationError, File: "internal/app/main.go", Line: 12, Column: 2,
+					Message: "declared and not used: x (unused)",
+				},
+				{
+					Level: hooks.AnnotationError, File: "cmd/tool.go", Line: 40, Column: 0,
+					Message: "line is 130 characters (lll)",
+				},
+			},
+		},
+		{
+			name:   "ruff",
+			output: "app/views.py:1:8: F401 [*] `os` imported but unused\nFound 1 error.\n",
+			want: []hooks.Annotation{{
+				Level: hooks.AnnotationError, File: "app/views.py", Line: 1, Column: 8,
+				Message: "F401 [*] `os` imported but unused",
+			}},
+		},
+		{
+			name: "eslint",
+			output: "\n/project/src/app.js\n" +
+				"  3:7   error    'x' is assigned a value but never used  no-unused-vars\n" +
+				"  9:1   warning  Unexpected console statement            no-console\n\n" +
+				"✖ 2 problems (1 error, 1 warning)\n",
+			want: []hooks.Annotation{
+				{
+					Level: hooks.AnnotationError, File: "/project/src/app.js", Line: 3, Column: 7,
+					Message: "'x' is assigned a value but never used  no-unused-vars",
+				},
+				{
+					Level: hooks.AnnotationWarning, File: "/project/src/app.js", Line: 9, Column: 1,
+					Message: "Unexpected console statement            no-console",
+				},
+			},
+		},
+		{
+			name:   "unrecognized output",
+			output: "make: *** [Makefile:3: lint] Error 1\n--- FAIL: TestX (0.00s)\n",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hooks.ParseAnnotations(tt.output))
+		})
+	}
+}
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	lint := newFailureResult(hooks.CommandTypeLint, false,
+		"main.go:3:5: unused variable: 50% done, really\n/elsewhere/x.go:1: outside\n")
+	lint.Command.WorkingDir = "/project/sub"
+	test := newFailureResult(hooks.CommandTypeTest, false, "panic: boom\n")
+	test.ExitCode = 2
+
+	var buf bytes.Buffer
+	require.NoError(t, hooks.WriteGitHubAnnotations(&buf, "/project", &hooks.ValidateResult{
+		LintResult:   lint,
+		TestResult:   test,
+		ExtraResults: []*hooks.ValidationResult{newFailureResult(hooks.CommandTypeExtra, true, "a.go:1: fine")},
+		BothPassed:   false,
+	}))
+
+	assert.Equal(t,
+		"::error file=sub/main.go,line=3,col=5::unused variable: 50%25 done, really\n"+
+			"::error file=/elsewhere/x.go,line=1::outside\n"+
+			"::error::make test failed (exit 2)\n"+
+			"panic: boom\n",
+		buf.String(), "paths are relative to the root, and unparsed output falls back to text")
+}
+
+func TestRunValidateHookWithSkip_GitHubFormat(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	setupGitMakefileProjectFS(testDeps)
+	testDeps.MockRunner.RunContextFunc = makeDiscoveryAndExecRunner(
+		failOutput("main.go:3: unused variable"),
+		successOutput("ok"),
+	)
+
+	input := &hookcmd.HookInput{
+		HookEventName: "PostToolUse",
+		ToolName:      "Edit",
+		ToolInput:     hooks.MustMarshalJSON(map[string]any{"file_path": "/project/main.go"}),
+	}
+
+	opts := hooks.NewValidateOptions(10, 0)
+	opts.Format = hooks.ValidateFormatGitHub
+	exitCode := hooks.RunValidateHookWithSkip(context.Background(), input, opts, testDeps.Dependencies)
+	assert.Equal(t, hooks.ExitCodeShowMessage, exitCode)
+	assert.Contains(t, testDeps.MockStderr.String(), "lint failures", "stderr keeps the human message")
+	assert.Equal(t, "::error file=main.go,line=3::unused variable\n", testDeps.MockStdout.String())
+}
@@ -0,0 +1,134 @@
+package hooks
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const cacheFileMode = 0o600 // Read/write for owner only
+
+// DefaultResultCacheTTL is how long a passing result is reused for the
+// same command and the same content of the edited file.
+const DefaultResultCacheTTL = 5 * time.Minute
+
+// cacheEntry is the last recorded result for one command type.
+type cacheEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	Command     string `json:"command"`
+	Success     bool   `json:"success"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+// ResultCache remembers the last validation result per command type for a
+// project so that an identical command that recently passed for the same
+// content of the edited file is not re-run, as when a file is saved again
+// unchanged.
+type ResultCache struct {
+	mu      sync.Mutex
+	file    string
+	content string
+	ttl     time.Duration
+	deps    *Dependencies
+}
+
+// NewResultCache creates a result cache for the given project and edited
+// file. Passing results are reused for ttl while filePath keeps the content
+// it has now. When filePath cannot be read, nothing is cached.
+func NewResultCache(projectRoot, filePath string, ttl time.Duration, deps *Dependencies) *ResultCache {
+	if deps == nil {
+		deps = NewDefaultDependencies()
+	}
+
+	hash := sha256.Sum256([]byte(projectRoot))
+	fileName := fmt.Sprintf("claude-hook-validate-cache-%x.json", hash[:8])
+
+	content := ""
+	if data, err := deps.FS.ReadFile(filePath); err == nil {
+		sum := sha256.Sum256(data)
+		content = filePath + "\x00" + fmt.Sprintf("%x", sum[:])
+	}
+
+	return &ResultCache{
+		mu:      sync.Mutex{},
+		file:    filepath.Join(deps.FS.TempDir(), fileName),
+		content: content,
+		ttl:     ttl,
+		deps:    deps,
+	}
+}
+
+// RecentlyPassed reports whether cmd is identical to the last recorded
+// command of its type, that command ran against the current content of the
+// edited file, and it passed within the TTL.
+func (c *ResultCache) RecentlyPassed(cmd *DiscoveredCommand) bool {
+	if cmd == nil || c.ttl <= 0 || c.content == "" {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.load()[string(cmd.Type)]
+	if !ok || !entry.Success || entry.Fingerprint != c.fingerprint(cmd) {
+		return false
+	}
+
+	return c.deps.Clock.Now().Sub(time.Unix(entry.Timestamp, 0)) < c.ttl
+}
+
+// Record stores the result of running cmd against the current content of
+// the edited file.
+func (c *ResultCache) Record(cmd *DiscoveredCommand, success bool) error {
+	if cmd == nil || c.content == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := c.load()
+	entries[string(cmd.Type)] = cacheEntry{
+		Fingerprint: c.fingerprint(cmd),
+		Command:     cmd.String(),
+		Success:     success,
+		Timestamp:   c.deps.Clock.Now().Unix(),
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("encoding result cache: %w", err)
+	}
+	if writeErr := c.deps.FS.WriteFile(c.file, data, cacheFileMode); writeErr != nil {
+		return fmt.Errorf("writing result cache: %w", writeErr)
+	}
+	return nil
+}
+
+// load reads the cache file, returning an empty map when it is missing or
+// unreadable.
+func (c *ResultCache) load() map[string]cacheEntry {
+	entries := make(map[string]cacheEntry)
+
+	data, err := c.deps.FS.ReadFile(c.file)
+	if err != nil {
+		return entries
+	}
+	if jsonErr := json.Unmarshal(data, &entries); jsonErr != nil {
+		return make(map[string]cacheEntry)
+	}
+	return entries
+}
+
+// fingerprint identifies a run of cmd by the command and the edited file
+// content it checked.
+func (c *ResultCache) fingerprint(cmd *DiscoveredCommand) string {
+	parts := []string{c.content, string(cmd.Type), cmd.WorkingDir, cmd.Command}
+	parts = append(parts, cmd.Args...)
+	hash := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return fmt.Sprintf("%x", hash[:])
+}
@@ -0,0 +1,242 @@
+package hooks_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+// memoryFS is an in-memory file system for the hooks dependencies.
+type memoryFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// Put stores data as the content of name.
+func (m *memoryFS) Put(name string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = data
+}
+
+// setupMemoryFS backs ReadFile, WriteFile, CreateExclusive, and Remove with
+// an in-memory map.
+func setupMemoryFS(deps *hooks.TestDependencies) *memoryFS {
+	mem := &memoryFS{mu: sync.Mutex{}, files: make(map[string][]byte)}
+
+	deps.MockFS.TempDirFunc = func() string { return "/tmp" }
+	deps.MockFS.ReadFileFunc = func(name string) ([]byte, error) {
+		mem.mu.Lock()
+		defer mem.mu.Unlock()
+		data, ok := mem.files[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return data, nil
+	}
+	deps.MockFS.WriteFileFunc = func(name string, data []byte, _ os.FileMode) error {
+		mem.mu.Lock()
+		defer mem.mu.Unlock()
+		mem.files[name] = data
+		return nil
+	}
+	deps.MockFS.CreateExclusiveFunc = func(name string, data []byte, _ os.FileMode) error {
+		mem.mu.Lock()
+		defer mem.mu.Unlock()
+		if _, ok := mem.files[name]; ok {
+			return os.ErrExist
+		}
+		mem.files[name] = data
+		return nil
+	}
+	deps.MockFS.RemoveFunc = func(name string) error {
+		mem.mu.Lock()
+		defer mem.mu.Unlock()
+		if _, ok := mem.files[name]; !ok {
+			return os.ErrNotExist
+		}
+		delete(mem.files, name)
+		return nil
+	}
+	return mem
+}
+
+func newCacheTestCommand(args ...string) *hooks.DiscoveredCommand {
+	return &hooks.DiscoveredCommand{
+		Type:       hooks.CommandTypeLint,
+		Command:    "make",
+		Args:       args,
+		WorkingDir: "/project",
+		Source:     "Makefile",
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		record  *hooks.DiscoveredCommand
+		success bool
+		elapsed time.Duration
+		edit    string
+		check   *hooks.DiscoveredCommand
+		want    bool
+	}{
+		{
+			name:    "same command within ttl",
+			record:  newCacheTestCommand("lint"),
+			success: true,
+			elapsed: 2 * time.Second,
+			check:   newCacheTestCommand("lint"),
+			want:    true,
+		},
+		{
+			name:    "ttl expired",
+			record:  newCacheTestCommand("lint"),
+			success: true,
+			elapsed: 10 * time.Minute,
+			check:   newCacheTestCommand("lint"),
+			want:    false,
+		},
+		{
+			name:    "previous run failed",
+			record:  newCacheTestCommand("lint"),
+			success: false,
+			elapsed: time.Second,
+			check:   newCacheTestCommand("lint"),
+			want:    false,
+		},
+		{
+			name:    "arguments changed",
+			record:  newCacheTestCommand("lint"),
+			success: true,
+			elapsed: time.Second,
+			check:   newCacheTestCommand("lint", "FIX=1"),
+			want:    false,
+		},
+		{
+			name:    "file content changed",
+			record:  newCacheTestCommand("lint"),
+			success: true,
+			elapsed: time.Second,
+			edit:    "package main // edited\n",
+			check:   newCacheTestCommand("lint"),
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDeps := hooks.CreateTestDependencies()
+			mem := setupMemoryFS(testDeps)
+			mem.Put("/project/main.go", []byte("package main\n"))
+
+			now := time.Unix(1700000000, 0)
+			testDeps.MockClock.NowFunc = func() time.Time { return now }
+
+			cache := hooks.NewResultCache("/project", "/project/main.go", hooks.DefaultResultCacheTTL, testDeps.Dependencies)
+			if err := cache.Record(tt.record, tt.success); err != nil {
+				t.Fatalf("Record() error = %v", err)
+			}
+
+			now = now.Add(tt.elapsed)
+			if tt.edit != "" {
+				mem.Put("/project/main.go", []byte(tt.edit))
+			}
+			// Each hook run builds its own cache from the file as it is then.
+			next := hooks.NewResultCache("/project", "/project/main.go", hooks.DefaultResultCacheTTL, testDeps.Dependencies)
+			if got := next.RecentlyPassed(tt.check); got != tt.want {
+				t.Errorf("RecentlyPassed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParallelValidateExecutor_ResultCacheSkipsRerun(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	setupMakefileFS(testDeps)
+	setupMemoryFS(testDeps).Put("/project/main.go", []byte("package main\n"))
+	testDeps.MockClock.NowFunc = func() time.Time { return time.Unix(1700000000, 0) }
+
+	var mu sync.Mutex
+	runs := 0
+	testDeps.MockRunner.RunContextFunc = func(_ context.Context, _, _ string, args ...string) (*hooks.CommandOutput, error) {
+		if len(args) >= 3 && args[len(args)-2] == "-n" {
+			return &hooks.CommandOutput{Stdout: []byte("echo cmd"), Stderr: nil}, nil
+		}
+		mu.Lock()
+		runs++
+		mu.Unlock()
+		return &hooks.CommandOutput{Stdout: []byte("OK"), Stderr: nil}, nil
+	}
+
+	cache := hooks.NewResultCache("/project", "/project/main.go", hooks.DefaultResultCacheTTL, testDeps.Dependencies)
+	for i := range 2 {
+		executor := hooks.NewParallelValidateExecutor("/project", 10, false, nil, testDeps.Dependencies)
+		executor.SetResultCache(cache)
+
+		result, err := executor.ExecuteValidations(context.Background(), "/project", "/project")
+		if err != nil {
+			t.Fatalf("run %d: ExecuteValidations() error = %v", i, err)
+		}
+		if !result.BothPassed {
+			t.Errorf("run %d: expected both validations to pass", i)
+		}
+	}
+
+	if runs != 2 {
+		t.Errorf("commands executed = %d, want 2 (second run should hit the cache)", runs)
+	}
+}
+
+func TestRunValidateHookWithSkip_ResultCacheAcrossCooldown(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	setupGitMakefileProjectFS(testDeps)
+	mem := setupMemoryFS(testDeps)
+	mem.Put("/project/main.go", []byte("package main\n"))
+
+	now := time.Unix(1700000000, 0)
+	testDeps.MockClock.NowFunc = func() time.Time { return now }
+
+	var mu sync.Mutex
+	runs := 0
+	exec := makeDiscoveryAndExecRunner(successOutput("OK"), successOutput("OK"))
+	testDeps.MockRunner.RunContextFunc = func(ctx context.Context, dir, name string, args ...string) (*hooks.CommandOutput, error) {
+		if len(args) < 3 || args[len(args)-2] != "-n" {
+			mu.Lock()
+			runs++
+			mu.Unlock()
+		}
+		return exec(ctx, dir, name, args...)
+	}
+
+	input := &hookcmd.HookInput{
+		HookEventName: "PostToolUse",
+		ToolName:      "Edit",
+		ToolInput:     hooks.MustMarshalJSON(map[string]any{"file_path": "/project/main.go"}),
+	}
+	run := func() {
+		t.Helper()
+		opts := hooks.NewValidateOptions(10, 2)
+		exitCode := hooks.RunValidateHookWithSkip(context.Background(), input, opts, testDeps.Dependencies)
+		assert.Equal(t, hooks.ExitCodeShowMessage, exitCode)
+		// Wait out the cooldown the lock records on release.
+		now = now.Add(3 * time.Second)
+	}
+
+	run()
+	assert.Equal(t, 2, runs, "the first run executes lint and test")
+
+	run()
+	assert.Equal(t, 2, runs, "saving the file unchanged reuses the passing results")
+
+	mem.Put("/project/main.go", []byte("package main // edited\n"))
+	run()
+	assert.Equal(t, 4, runs, "changed content runs lint and test again")
+}
@@ -1,18 +1,22 @@
 package hooks
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
-	"sync"
 	"syscall"
 	"time"
 
 	"github.com/riddopic/cc-tools/internal/shared"
 )
 
+// commandWaitDelay bounds how long a finished or cancelled command's output
+// is waited for after the command itself has exited.
+const commandWaitDelay = 2 * time.Second
+
 // CommandOutput contains the output from a command execution.
 type CommandOutput struct {
 	Stdout []byte
@@ -25,6 +29,18 @@ type CommandRunner interface {
 	LookPath(file string) (string, error)
 }
 
+// EnvCommandRunner is a CommandRunner that can run a command with an
+// explicit environment. A nil env inherits the parent environment.
+type EnvCommandRunner interface {
+	RunContextEnv(ctx context.Context, env []string, dir, name string, args ...string) (*CommandOutput, error)
+}
+
+// StdinCommandRunner is a CommandRunner that can feed data to a command's
+// standard input.
+type StdinCommandRunner interface {
+	RunContextStdin(ctx context.Context, stdin []byte, dir, name string, args ...string) (*CommandOutput, error)
+}
+
 // ProcessManager manages system processes.
 type ProcessManager interface {
 	GetPID() int
@@ -57,52 +73,60 @@ type Dependencies struct {
 type realCommandRunner struct{}
 
 func (r *realCommandRunner) RunContext(ctx context.Context, dir, name string, args ...string) (*CommandOutput, error) {
-	cmd := exec.CommandContext(ctx, name, args...)
-	cmd.Dir = dir
-
-	// Capture stdout and stderr separately
-	var stdout, stderr []byte
-	var err error
+	return r.RunContextEnv(ctx, nil, dir, name, args...)
+}
 
-	// Get stdout
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, fmt.Errorf("create stdout pipe: %w", err)
-	}
+func (r *realCommandRunner) RunContextEnv(
+	ctx context.Context,
+	env []string,
+	dir, name string,
+	args ...string,
+) (*CommandOutput, error) {
+	return r.run(ctx, env, nil, dir, name, args...)
+}
 
-	// Get stderr
-	stderrPipe, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, fmt.Errorf("create stderr pipe: %w", err)
-	}
+func (r *realCommandRunner) RunContextStdin(
+	ctx context.Context,
+	stdin []byte,
+	dir, name string,
+	args ...string,
+) (*CommandOutput, error) {
+	return r.run(ctx, nil, stdin, dir, name, args...)
+}
 
-	// Start the command
-	if startErr := cmd.Start(); startErr != nil {
-		return nil, fmt.Errorf("start command %s: %w", name, startErr)
+// run executes name with the given environment, feeding stdin to it when
+// stdin is non-nil.
+func (r *realCommandRunner) run(
+	ctx context.Context,
+	env []string,
+	stdin []byte,
+	dir, name string,
+	args ...string,
+) (*CommandOutput, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
 	}
 
-	// Read outputs concurrently to avoid pipe buffer deadlock
-	var wg sync.WaitGroup
-	wg.Add(2) //nolint:mnd // two goroutines for stdout and stderr
-
-	go func() {
-		defer wg.Done()
-		stdout, _ = io.ReadAll(stdoutPipe)
-	}()
-
-	go func() {
-		defer wg.Done()
-		stderr, _ = io.ReadAll(stderrPipe)
-	}()
+	// Capture stdout and stderr separately. exec drains both concurrently,
+	// so a chatty command cannot deadlock on a full pipe buffer.
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
 
-	wg.Wait()
+	// Cancelling kills the command's whole process group where supported.
+	// Stop waiting on output still held open by any surviving descendant
+	// shortly after, so a cancelled hook returns.
+	killProcessGroupOnCancel(cmd)
+	cmd.WaitDelay = commandWaitDelay
 
-	// Wait for completion
-	err = cmd.Wait()
+	err := cmd.Run()
 
 	output := &CommandOutput{
-		Stdout: stdout,
-		Stderr: stderr,
+		Stdout: stdout.Bytes(),
+		Stderr: stderr.Bytes(),
 	}
 
 	if err != nil {
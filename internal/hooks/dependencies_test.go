@@ -15,6 +15,23 @@ import (
 	"github.com/riddopic/cc-tools/internal/hooks"
 )
 
+func TestRealCommandRunner_RunContext_CancelReturnsPromptly(t *testing.T) {
+	t.Parallel()
+
+	runner := hooks.NewRealCommandRunner()
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	// The shell's sleep child shares the output pipes, so it has to be
+	// stopped along with the shell for the runner to return.
+	start := time.Now()
+	_, err := runner.RunContext(ctx, "", "sh", "-c", "sleep 30; true")
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.Less(t, elapsed, 10*time.Second, "runner should return soon after cancellation")
+}
+
 func TestRealCommandRunner_RunContext_ConcurrentOutput(t *testing.T) {
 	t.Parallel()
 
@@ -2,10 +2,12 @@ package hooks
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
-	"strings"
 	"time"
+
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // CommandType represents the type of command to discover.
@@ -16,6 +18,49 @@ const (
 	CommandTypeLint CommandType = "lint"
 	// CommandTypeTest represents test commands (used internally by validate).
 	CommandTypeTest CommandType = "test"
+	// CommandTypeFormat represents single-file formatter commands.
+	CommandTypeFormat CommandType = "format"
+	// CommandTypeExtra represents user-configured validation commands.
+	CommandTypeExtra CommandType = "extra"
+)
+
+// ErrUnknownCommandType is returned by ParseCommandType for a name that is
+// not a command type.
+var ErrUnknownCommandType = errors.New("unknown command type")
+
+// CommandTypes returns every command type.
+func CommandTypes() []CommandType {
+	return []CommandType{CommandTypeLint, CommandTypeTest, CommandTypeFormat, CommandTypeExtra}
+}
+
+// ParseCommandType returns the command type named by name: lint, test,
+// format, or extra.
+func ParseCommandType(name string) (CommandType, error) {
+	for _, t := range CommandTypes() {
+		if name == string(t) {
+			return t, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w %q (want lint, test, format, or extra)", ErrUnknownCommandType, name)
+}
+
+// String returns the name of t.
+func (t CommandType) String() string {
+	return string(t)
+}
+
+// RunFrom selects the working directory for discovered commands.
+type RunFrom string
+
+const (
+	// RunFromProjectRoot runs commands from the directory where they were
+	// discovered while walking up toward the project root.
+	RunFromProjectRoot RunFrom = "project_root"
+	// RunFromFileDir runs language tools, such as go vet or ruff, from the
+	// edited file's directory. Task runner targets and scripts still run
+	// from the directory that declares them.
+	RunFromFileDir RunFrom = "file_dir"
 )
 
 // DiscoveredCommand represents a discovered command.
@@ -32,6 +77,7 @@ type CommandDiscovery struct {
 	projectRoot string
 	timeout     int
 	debug       bool
+	runFrom     RunFrom
 	deps        *Dependencies
 }
 
@@ -44,6 +90,7 @@ func NewCommandDiscovery(projectRoot string, timeoutSecs int, deps *Dependencies
 		projectRoot: projectRoot,
 		timeout:     timeoutSecs,
 		debug:       false,
+		runFrom:     RunFromProjectRoot,
 		deps:        deps,
 	}
 }
@@ -53,6 +100,11 @@ func (cd *CommandDiscovery) SetDebug(debug bool) {
 	cd.debug = debug
 }
 
+// SetRunFrom selects the working directory mode for discovered commands.
+func (cd *CommandDiscovery) SetRunFrom(runFrom RunFrom) {
+	cd.runFrom = runFrom
+}
+
 // debugf writes a debug message to stderr when debug mode is enabled.
 func (cd *CommandDiscovery) debugf(format string, args ...any) {
 	if cd.debug {
@@ -71,52 +123,145 @@ func (cd *CommandDiscovery) DiscoverCommand(
 		currentDir = cd.projectRoot
 	}
 
-	// Walk up from current directory to project root
-	for {
-		// Check for Makefile
-		if cmd := cd.checkMakefile(ctx, currentDir, cmdType); cmd != nil {
-			return cmd, nil
-		}
-
-		// Check for Taskfile
-		if cmd := cd.checkTaskfile(ctx, currentDir, cmdType); cmd != nil {
-			return cmd, nil
+	cmd := cd.walkUp(ctx, cmdType, currentDir)
+	if cmd == nil {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("discover %s command: %w", cmdType, err)
 		}
+		return nil, fmt.Errorf("no command found for type %s", cmdType)
+	}
 
-		// Check for justfile
-		if cmd := cd.checkJustfile(ctx, currentDir, cmdType); cmd != nil {
-			return cmd, nil
-		}
+	cd.applyRunFrom(cmd, currentDir)
 
-		// Check for package.json (Node.js)
-		if cmd := cd.checkPackageJSON(ctx, currentDir, cmdType); cmd != nil {
-			return cmd, nil
-		}
+	return cmd, nil
+}
 
-		// Check for scripts directory
-		if cmd := cd.checkScriptsDir(ctx, currentDir, cmdType); cmd != nil {
-			return cmd, nil
+// walkUp checks each directory from currentDir up to the project root and
+// returns the first command found.
+func (cd *CommandDiscovery) walkUp(
+	ctx context.Context,
+	cmdType CommandType,
+	currentDir string,
+) *DiscoveredCommand {
+	for ctx.Err() == nil {
+		for _, check := range cd.sourceChecks() {
+			if cmd := check(ctx, currentDir, cmdType); cmd != nil {
+				return cmd
+			}
 		}
-
-		// Check for language-specific tools
 		if cmd := cd.checkLanguageSpecific(ctx, currentDir, cmdType); cmd != nil {
-			return cmd, nil
+			return cmd
 		}
 
-		// Stop at project root or filesystem root
-		if currentDir == cd.projectRoot || currentDir == "/" {
+		parent, ok := cd.parentWithin(currentDir)
+		if !ok {
 			break
 		}
+		currentDir = parent
+	}
 
-		// Move up one directory
-		parent := filepath.Dir(currentDir)
-		if parent == currentDir {
-			break
+	return nil
+}
+
+// applyRunFrom moves cmd to fileDir in file_dir mode when cmd can run
+// there. Language tools take paths relative to their working directory, but
+// Makefile, Taskfile, justfile, and package.json targets and scripts/ are
+// resolved relative to the directory that declares them and fail below it.
+func (cd *CommandDiscovery) applyRunFrom(cmd *DiscoveredCommand, fileDir string) {
+	if cd.runFrom != RunFromFileDir {
+		return
+	}
+	switch cmd.Source {
+	case "go.mod", "Cargo.toml", "Python project":
+		cmd.WorkingDir = fileDir
+	}
+}
+
+// Candidate is a command that discovery found for a command type, with
+// whether it is the one DiscoverCommand picks and why.
+type Candidate struct {
+	Command  *DiscoveredCommand
+	Selected bool
+	Reason   string
+}
+
+// ExplainCommand returns every command of cmdType found from startDir up to
+// the project root, in the order discovery checks them. The first is the
+// one DiscoverCommand returns; the Reason of each other candidate names
+// what takes precedence over it.
+func (cd *CommandDiscovery) ExplainCommand(
+	ctx context.Context,
+	cmdType CommandType,
+	startDir string,
+) ([]Candidate, error) {
+	currentDir := startDir
+	if currentDir == "" {
+		currentDir = cd.projectRoot
+	}
+
+	var found []*DiscoveredCommand
+	for dir, ok := currentDir, true; ok && ctx.Err() == nil; dir, ok = cd.parentWithin(dir) {
+		for _, check := range cd.sourceChecks() {
+			if cmd := check(ctx, dir, cmdType); cmd != nil {
+				found = append(found, cmd)
+			}
 		}
-		currentDir = parent
+		found = append(found, cd.languageCandidates(ctx, dir, cmdType)...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("explain %s command: %w", cmdType, err)
 	}
 
-	return nil, fmt.Errorf("no command found for type %s", cmdType)
+	candidates := make([]Candidate, 0, len(found))
+	for i, cmd := range found {
+		candidate := Candidate{Command: cmd, Selected: i == 0, Reason: "first match in priority order"}
+		if i > 0 {
+			candidate.Reason = precedenceReason(found[0], cmd)
+		}
+		candidates = append(candidates, candidate)
+	}
+	if len(candidates) > 0 {
+		cd.applyRunFrom(candidates[0].Command, currentDir)
+	}
+
+	return candidates, nil
+}
+
+// precedenceReason explains why winner is picked over cmd.
+func precedenceReason(winner, cmd *DiscoveredCommand) string {
+	if winner.WorkingDir != cmd.WorkingDir {
+		return fmt.Sprintf("%s in %s is nearer the file", winner.Source, winner.WorkingDir)
+	}
+	if winner.Source != cmd.Source {
+		return fmt.Sprintf("%s is checked before %s", winner.Source, cmd.Source)
+	}
+	return fmt.Sprintf("%s is preferred over %s", winner, cmd)
+}
+
+// sourceChecks returns the checks for task runners and scripts, in the
+// order discovery tries them in each directory. Language-specific tools are
+// tried after all of them.
+func (cd *CommandDiscovery) sourceChecks() []func(context.Context, string, CommandType) *DiscoveredCommand {
+	return []func(context.Context, string, CommandType) *DiscoveredCommand{
+		cd.checkMakefile,
+		cd.checkTaskfile,
+		cd.checkJustfile,
+		cd.checkPackageJSON,
+		cd.checkScriptsDir,
+	}
+}
+
+// parentWithin returns the directory above dir, or false once dir is the
+// project root or the filesystem root.
+func (cd *CommandDiscovery) parentWithin(dir string) (string, bool) {
+	if dir == cd.projectRoot || dir == "/" {
+		return "", false
+	}
+	parent := filepath.Dir(dir)
+	if parent == dir {
+		return "", false
+	}
+	return parent, true
 }
 
 // checkMakefile checks for Makefile targets.
@@ -138,6 +283,12 @@ func (cd *CommandDiscovery) checkMakefile(
 		timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(cd.timeout)*time.Second)
 		_, err := cd.deps.Runner.RunContext(timeoutCtx, dir, "make", "-f", path, "-n", target)
 		cancel()
+		if err != nil && cd.makefileDeclares(path, target) {
+			// The dry run can fail for reasons unrelated to the target, such
+			// as a missing include, so trust a declared rule or .PHONY entry.
+			cd.debugf("make: dry run of %q failed (%v), but %s declares it", target, err, path)
+			err = nil
+		}
 		if err == nil {
 			return &DiscoveredCommand{
 				Type:       cmdType,
@@ -153,6 +304,15 @@ func (cd *CommandDiscovery) checkMakefile(
 	return nil
 }
 
+// makefileDeclares reports whether the Makefile at path declares target.
+func (cd *CommandDiscovery) makefileDeclares(path, target string) bool {
+	data, err := cd.deps.FS.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return makefileDeclaresTarget(data, target)
+}
+
 // checkTaskfile checks for Taskfile tasks.
 func (cd *CommandDiscovery) checkTaskfile(
 	ctx context.Context,
@@ -289,79 +449,77 @@ func (cd *CommandDiscovery) checkLanguageSpecific(
 	dir string,
 	cmdType CommandType,
 ) *DiscoveredCommand {
-	// Check for various project markers
-	projectTypes := cd.detectProjectTypes(dir)
+	if cmds := cd.languageCandidates(ctx, dir, cmdType); len(cmds) > 0 {
+		return cmds[0]
+	}
+	return nil
+}
+
+// languageCandidates returns the language-specific tools usable in dir,
+// most preferred first.
+func (cd *CommandDiscovery) languageCandidates(
+	ctx context.Context,
+	dir string,
+	cmdType CommandType,
+) []*DiscoveredCommand {
+	var cmds []*DiscoveredCommand
 
-	for _, projectType := range projectTypes {
+	for _, projectType := range cd.detectProjectTypes(dir) {
 		switch projectType {
 		case "go":
-			if cmd := cd.checkGoCommands(ctx, dir, cmdType); cmd != nil {
-				return cmd
-			}
+			cmds = append(cmds, cd.goCommands(ctx, dir, cmdType)...)
 		case "rust":
-			if cmd := cd.checkRustCommands(ctx, dir, cmdType); cmd != nil {
-				return cmd
-			}
+			cmds = append(cmds, cd.rustCommands(ctx, dir, cmdType)...)
 		case "python":
-			if cmd := cd.checkPythonCommands(ctx, dir, cmdType); cmd != nil {
-				return cmd
-			}
+			cmds = append(cmds, cd.pythonCommands(ctx, dir, cmdType)...)
 		}
 	}
 
-	return nil
+	return cmds
 }
 
-// checkGoCommands checks for Go-specific commands.
-func (cd *CommandDiscovery) checkGoCommands(
+// goCommands returns the Go commands usable in dir, most preferred first.
+func (cd *CommandDiscovery) goCommands(
 	_ context.Context,
 	dir string,
 	cmdType CommandType,
-) *DiscoveredCommand {
+) []*DiscoveredCommand {
 	// Only check if go.mod exists in this directory
 	if _, err := cd.deps.FS.Stat(filepath.Join(dir, "go.mod")); err != nil {
 		return nil
 	}
 
-	switch cmdType {
-	case CommandTypeLint:
-		// Try golangci-lint first
-		if _, err := cd.deps.Runner.LookPath("golangci-lint"); err == nil {
-			return &DiscoveredCommand{
-				Type:       cmdType,
-				Command:    "golangci-lint",
-				Args:       []string{"run"},
-				WorkingDir: dir,
-				Source:     "go.mod",
-			}
-		}
-		// Fall back to go vet
+	newCmd := func(command string, args ...string) *DiscoveredCommand {
 		return &DiscoveredCommand{
 			Type:       cmdType,
-			Command:    "go",
-			Args:       []string{"vet", "./..."},
+			Command:    command,
+			Args:       args,
 			WorkingDir: dir,
 			Source:     "go.mod",
 		}
-	case CommandTypeTest:
-		return &DiscoveredCommand{
-			Type:       cmdType,
-			Command:    "go",
-			Args:       []string{"test", "./..."},
-			WorkingDir: dir,
-			Source:     "go.mod",
+	}
+
+	switch cmdType {
+	case CommandTypeLint:
+		var cmds []*DiscoveredCommand
+		// Prefer golangci-lint, falling back to go vet
+		if _, err := cd.deps.Runner.LookPath("golangci-lint"); err == nil {
+			cmds = append(cmds, newCmd("golangci-lint", "run"))
 		}
+		return append(cmds, newCmd("go", "vet", "./..."))
+	case CommandTypeTest:
+		return []*DiscoveredCommand{newCmd("go", "test", "./...")}
 	}
 
 	return nil
 }
 
-// checkRustCommands checks for Rust-specific commands.
-func (cd *CommandDiscovery) checkRustCommands(
+// rustCommands returns the Rust commands usable in dir.
+func (cd *CommandDiscovery) rustCommands(
 	_ context.Context,
 	dir string,
 	cmdType CommandType,
-) *DiscoveredCommand {
+) []*DiscoveredCommand {
 	// Only check if Cargo.toml exists in this directory
 	if _, err := cd.deps.FS.Stat(filepath.Join(dir, "Cargo.toml")); err != nil {
 		return nil
@@ -369,32 +527,33 @@ func (cd *CommandDiscovery) checkRustCommands(
 
 	switch cmdType {
 	case CommandTypeLint:
-		return &DiscoveredCommand{
+		return []*DiscoveredCommand{{
 			Type:       cmdType,
 			Command:    "cargo",
 			Args:       []string{"clippy", "--", "-D", "warnings"},
 			WorkingDir: dir,
 			Source:     "Cargo.toml",
-		}
+		}}
 	case CommandTypeTest:
-		return &DiscoveredCommand{
+		return []*DiscoveredCommand{{
 			Type:       cmdType,
 			Command:    "cargo",
 			Args:       []string{"test"},
 			WorkingDir: dir,
 			Source:     "Cargo.toml",
-		}
+		}}
 	}
 
 	return nil
 }
 
-// checkPythonCommands checks for Python-specific commands.
-func (cd *CommandDiscovery) checkPythonCommands(
+// pythonCommands returns the Python commands usable in dir, most preferred
+// first.
+func (cd *CommandDiscovery) pythonCommands(
 	_ context.Context,
 	dir string,
 	cmdType CommandType,
-) *DiscoveredCommand {
+) []*DiscoveredCommand {
 	// Check if this is a Python project directory
 	pythonMarkers := []string{"pyproject.toml", "setup.py", "requirements.txt"}
 	hasPython := false
@@ -409,9 +568,10 @@ func (cd *CommandDiscovery) checkPythonCommands(
 		return nil
 	}
 
+	var cmds []*DiscoveredCommand
 	switch cmdType {
 	case CommandTypeLint:
-		// Try linters in order of preference
+		// Linters in order of preference
 		linters := []struct {
 			name string
 			args []string
@@ -422,39 +582,39 @@ func (cd *CommandDiscovery) checkPythonCommands(
 		}
 
 		for _, linter := range linters {
-			if _, err := cd.deps.Runner.LookPath(linter.name); err == nil {
-				return &DiscoveredCommand{
-					Type:       cmdType,
-					Command:    linter.name,
-					Args:       linter.args,
-					WorkingDir: dir,
-					Source:     "Python project",
-				}
+			if _, err := cd.deps.Runner.LookPath(linter.name); err != nil {
+				cd.debugf("python: linter %q not found in PATH", linter.name)
+				continue
 			}
-			cd.debugf("python: linter %q not found in PATH", linter.name)
+			cmds = append(cmds, &DiscoveredCommand{
+				Type:       cmdType,
+				Command:    linter.name,
+				Args:       linter.args,
+				WorkingDir: dir,
+				Source:     "Python project",
+			})
 		}
 	case CommandTypeTest:
-		// Try test runners in order of preference
+		// Prefer pytest, falling back to unittest
 		if _, err := cd.deps.Runner.LookPath("pytest"); err == nil {
-			return &DiscoveredCommand{
+			cmds = append(cmds, &DiscoveredCommand{
 				Type:       cmdType,
 				Command:    "pytest",
 				Args:       []string{},
 				WorkingDir: dir,
 				Source:     "Python project",
-			}
+			})
 		}
-		// Fall back to unittest
-		return &DiscoveredCommand{
+		cmds = append(cmds, &DiscoveredCommand{
 			Type:       cmdType,
 			Command:    "python",
 			Args:       []string{"-m", "unittest"},
 			WorkingDir: dir,
 			Source:     "Python project",
-		}
+		})
 	}
 
-	return nil
+	return cmds
 }
 
 // detectPackageManager detects which package manager to use based on lock files.
@@ -502,14 +662,12 @@ func (cd *CommandDiscovery) detectProjectTypes(dir string) []string {
 	return types
 }
 
-// String returns a string representation of the discovered command.
+// String returns the command line for display. Words containing spaces or
+// shell metacharacters are single-quoted so the result can be pasted into a
+// shell; the command itself still runs with the unquoted Args.
 func (dc *DiscoveredCommand) String() string {
 	if dc == nil {
 		return ""
 	}
-	args := strings.Join(dc.Args, " ")
-	if args != "" {
-		return fmt.Sprintf("%s %s", dc.Command, args)
-	}
-	return dc.Command
+	return shared.ShellJoin(append([]string{dc.Command}, dc.Args...))
 }
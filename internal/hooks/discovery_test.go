@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/riddopic/cc-tools/internal/hooks"
@@ -52,6 +54,34 @@ func testDiscoversMakefileLintTarget(t *testing.T) {
 	}
 }
 
+func testFallsBackToParsingMakefile(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+
+	testDeps.MockFS.StatFunc = func(path string) (os.FileInfo, error) {
+		if strings.HasSuffix(path, "Makefile") {
+			return hooks.NewMockFileInfo("Makefile", 0, 0, time.Time{}, false), nil
+		}
+		return nil, os.ErrNotExist
+	}
+	testDeps.MockFS.ReadFileFunc = func(path string) ([]byte, error) {
+		if strings.HasSuffix(path, "Makefile") {
+			return []byte("include missing.mk\n.PHONY: lint test\nlint:\n\tgolangci-lint run\n"), nil
+		}
+		return nil, os.ErrNotExist
+	}
+	testDeps.MockRunner.RunContextFunc = func(_ context.Context, _, _ string, _ ...string) (*hooks.CommandOutput, error) {
+		return nil, errors.New("missing.mk: No such file or directory")
+	}
+
+	discovery := hooks.NewCommandDiscovery("/project", 20, testDeps.Dependencies)
+	cmd, err := discovery.DiscoverCommand(context.Background(), hooks.CommandTypeLint, "/project")
+	require.NoError(t, err)
+	require.NotNil(t, cmd, "Expected the parsed Makefile target")
+	assert.Equal(t, "make", cmd.Command)
+	assert.Equal(t, []string{"lint"}, cmd.Args)
+	assert.Equal(t, "Makefile", cmd.Source)
+}
+
 func testDiscoversJustfileRecipe(t *testing.T) {
 	testDeps := hooks.CreateTestDependencies()
 
@@ -704,6 +734,7 @@ func testDetectsMultipleProjectTypes(t *testing.T) {
 
 func TestCommandDiscovery(t *testing.T) {
 	t.Run("discovers Makefile lint target", testDiscoversMakefileLintTarget)
+	t.Run("falls back to parsing Makefile", testFallsBackToParsingMakefile)
 	t.Run("discovers justfile recipe", testDiscoversJustfileRecipe)
 	t.Run("discovers Taskfile task", testDiscoversTaskfileTask)
 	t.Run("discovers package.json scripts with npm", testDiscoversNpmScripts)
@@ -726,6 +757,142 @@ func TestCommandDiscovery(t *testing.T) {
 	t.Run("detects multiple project types", testDetectsMultipleProjectTypes)
 }
 
+func TestCommandDiscoveryRunFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		marker  string
+		runFrom hooks.RunFrom
+		wantDir string
+	}{
+		{name: "project root", marker: "/project/Makefile", runFrom: hooks.RunFromProjectRoot, wantDir: "/project"},
+		{
+			name:    "file dir keeps Makefile target in its directory",
+			marker:  "/project/Makefile",
+			runFrom: hooks.RunFromFileDir,
+			wantDir: "/project",
+		},
+		{
+			name:    "file dir moves language tool to file directory",
+			marker:  "/project/go.mod",
+			runFrom: hooks.RunFromFileDir,
+			wantDir: "/project/web/src/components",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDeps := hooks.CreateTestDependencies()
+
+			testDeps.MockFS.StatFunc = func(path string) (os.FileInfo, error) {
+				if path == tt.marker {
+					return hooks.NewMockFileInfo(path, 0, 0, time.Time{}, false), nil
+				}
+				return nil, os.ErrNotExist
+			}
+
+			testDeps.MockRunner.RunContextFunc = func(
+				_ context.Context, _, name string, args ...string,
+			) (*hooks.CommandOutput, error) {
+				if name == "make" && args[len(args)-1] == "lint" {
+					return &hooks.CommandOutput{Stdout: []byte("lint"), Stderr: nil}, nil
+				}
+				return nil, errors.New("command failed")
+			}
+			testDeps.MockRunner.LookPathFunc = func(_ string) (string, error) {
+				return "", errors.New("not found")
+			}
+
+			discovery := hooks.NewCommandDiscovery("/project", 20, testDeps.Dependencies)
+			discovery.SetRunFrom(tt.runFrom)
+
+			cmd, err := discovery.DiscoverCommand(
+				context.Background(),
+				hooks.CommandTypeLint,
+				"/project/web/src/components",
+			)
+			require.NoError(t, err)
+			require.NotNil(t, cmd)
+			assert.Equal(t, tt.wantDir, cmd.WorkingDir)
+		})
+	}
+}
+
+func TestExplainCommand(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	testDeps.MockFS.StatFunc = func(path string) (os.FileInfo, error) {
+		switch path {
+		case "/project/Makefile", "/project/go.mod", "/project/api/go.mod":
+			return hooks.NewMockFileInfo(path, 0, 0, time.Time{}, false), nil
+		}
+		return nil, os.ErrNotExist
+	}
+	testDeps.MockRunner.RunContextFunc = func(_ context.Context, _, name string, _ ...string) (*hooks.CommandOutput, error) {
+		if name == "make" {
+			return &hooks.CommandOutput{Stdout: []byte("golangci-lint run"), Stderr: nil}, nil
+		}
+		return nil, errors.New("command failed")
+	}
+	testDeps.MockRunner.LookPathFunc = func(file string) (string, error) {
+		return "/usr/bin/" + file, nil
+	}
+
+	discovery := hooks.NewCommandDiscovery("/project", 20, testDeps.Dependencies)
+	candidates, err := discovery.ExplainCommand(context.Background(), hooks.CommandTypeLint, "/project/api")
+	require.NoError(t, err)
+
+	got := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		got = append(got, c.Command.String()+" @ "+c.Command.WorkingDir+": "+c.Reason)
+	}
+	assert.Equal(t, []string{
+		"golangci-lint run @ /project/api: first match in priority order",
+		"go vet ./... @ /project/api: golangci-lint run is preferred over go vet ./...",
+		"make lint @ /project: go.mod in /project/api is nearer the file",
+		"golangci-lint run @ /project: go.mod in /project/api is nearer the file",
+		"go vet ./... @ /project: go.mod in /project/api is nearer the file",
+	}, got)
+	assert.True(t, candidates[0].Selected)
+	assert.False(t, candidates[2].Selected)
+
+	picked, err := discovery.DiscoverCommand(context.Background(), hooks.CommandTypeLint, "/project/api")
+	require.NoError(t, err)
+	assert.Equal(t, candidates[0].Command, picked, "the first candidate is the one discovery picks")
+}
+
+func TestDiscoverCommand_Cancelled(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	testDeps.MockFS.StatFunc = func(_ string) (os.FileInfo, error) {
+		return hooks.NewMockFileInfo("Makefile", 0, 0, time.Time{}, false), nil
+	}
+
+	discovery := hooks.NewCommandDiscovery("/project", 20, testDeps.Dependencies)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cmd, err := discovery.DiscoverCommand(ctx, hooks.CommandTypeLint, "/project/pkg")
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, cmd)
+}
+
+func TestParseCommandType(t *testing.T) {
+	for _, want := range hooks.CommandTypes() {
+		got, err := hooks.ParseCommandType(want.String())
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	got, err := hooks.ParseCommandType("lint")
+	require.NoError(t, err)
+	assert.Equal(t, hooks.CommandTypeLint, got)
+	assert.Equal(t, "lint", got.String())
+
+	for _, name := range []string{"", "Lint", "build", " test"} {
+		_, err := hooks.ParseCommandType(name)
+		require.ErrorIs(t, err, hooks.ErrUnknownCommandType, "name %q", name)
+		assert.Contains(t, err.Error(), "want lint, test, format, or extra")
+	}
+}
+
 func TestDiscoveredCommandString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -781,6 +948,50 @@ func TestDiscoveredCommandString(t *testing.T) {
 			},
 			expected: "./scripts/test",
 		},
+		{
+			name: "arg with spaces",
+			cmd: &hooks.DiscoveredCommand{
+				Type:       "",
+				Command:    "git",
+				Args:       []string{"commit", "--msg", "a b"},
+				WorkingDir: "",
+				Source:     "",
+			},
+			expected: "git commit --msg 'a b'",
+		},
+		{
+			name: "arg with quotes",
+			cmd: &hooks.DiscoveredCommand{
+				Type:       "",
+				Command:    "echo",
+				Args:       []string{"it's", `say "hi"`},
+				WorkingDir: "",
+				Source:     "",
+			},
+			expected: `echo 'it'\''s' 'say "hi"'`,
+		},
+		{
+			name: "arg with dollar",
+			cmd: &hooks.DiscoveredCommand{
+				Type:       "",
+				Command:    "echo",
+				Args:       []string{"$HOME", "--flag=${X}"},
+				WorkingDir: "",
+				Source:     "",
+			},
+			expected: "echo '$HOME' '--flag=${X}'",
+		},
+		{
+			name: "empty arg",
+			cmd: &hooks.DiscoveredCommand{
+				Type:       "",
+				Command:    "grep",
+				Args:       []string{"-e", "", "file.go"},
+				WorkingDir: "",
+				Source:     "",
+			},
+			expected: "grep -e '' file.go",
+		},
 	}
 
 	for _, tt := range tests {
@@ -789,6 +1000,20 @@ func TestDiscoveredCommandString(t *testing.T) {
 			if result != tt.expected {
 				t.Errorf("String() = %v, want %v", result, tt.expected)
 			}
+
+			if tt.cmd == nil {
+				return
+			}
+
+			// The quoted form must split back into the executed argv.
+			words, err := hooks.SplitCommandLine(result)
+			if err != nil {
+				t.Fatalf("SplitCommandLine(%q) error = %v", result, err)
+			}
+			want := append([]string{tt.cmd.Command}, tt.cmd.Args...)
+			if !slices.Equal(words, want) {
+				t.Errorf("SplitCommandLine(%q) = %q, want %q", result, words, want)
+			}
 		})
 	}
 }
@@ -0,0 +1,75 @@
+package hooks
+
+import (
+	"os"
+	"slices"
+	"strings"
+)
+
+// ciEnv is injected into every validation command unless disabled so that
+// linters and test runners behave as they would in CI.
+const ciEnv = "CI=1"
+
+// cleanEnvKeys lists the parent variables kept when an EnvPolicy is clean.
+// They cover tool lookup, home and temp directories, locale, and the Go
+// toolchain caches that lint and test commands commonly rely on.
+var cleanEnvKeys = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM",
+	"TMPDIR", "TMP", "TEMP",
+	"LANG", "LC_ALL", "LC_CTYPE",
+	"XDG_CACHE_HOME", "XDG_CONFIG_HOME",
+	"GOPATH", "GOROOT", "GOCACHE", "GOMODCACHE", "GOFLAGS", "GOPROXY", "GOPRIVATE",
+	"SYSTEMROOT", "USERPROFILE", "APPDATA", "LOCALAPPDATA",
+}
+
+// EnvPolicy controls the environment that validation commands run with.
+type EnvPolicy struct {
+	// Clean drops every parent variable not in the allowlist instead of
+	// passing the full parent environment through.
+	Clean bool
+	// Extra holds KEY=VALUE entries applied last, overriding the base
+	// environment and the injected CI variable.
+	Extra []string
+	// NoCI disables injecting CI=1.
+	NoCI bool
+}
+
+// DefaultEnvPolicy passes the parent environment through and injects CI=1.
+func DefaultEnvPolicy() EnvPolicy {
+	return EnvPolicy{
+		Clean: false,
+		Extra: nil,
+		NoCI:  false,
+	}
+}
+
+// Environ builds the child environment from parent, typically
+// [os.Environ]. Later entries win when a key repeats, matching how
+// [os/exec.Cmd] resolves duplicates.
+func (p EnvPolicy) Environ(parent []string) []string {
+	env := make([]string, 0, len(parent)+len(p.Extra)+1)
+
+	for _, kv := range parent {
+		if p.Clean && !isCleanEnvKey(kv) {
+			continue
+		}
+		env = append(env, kv)
+	}
+
+	if !p.NoCI {
+		env = append(env, ciEnv)
+	}
+
+	return append(env, p.Extra...)
+}
+
+// environ builds the child environment from the current process.
+func (p EnvPolicy) environ() []string {
+	return p.Environ(os.Environ())
+}
+
+// isCleanEnvKey reports whether a KEY=VALUE entry is kept by a clean policy.
+func isCleanEnvKey(kv string) bool {
+	key, _, _ := strings.Cut(kv, "=")
+	return slices.Contains(cleanEnvKeys, key)
+}
@@ -0,0 +1,71 @@
+package hooks_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+func TestEnvPolicyEnviron(t *testing.T) {
+	parent := []string{"PATH=/usr/bin", "HOME=/home/dev", "CLAUDE_HOOKS_DEBUG=1"}
+
+	tests := []struct {
+		name   string
+		policy hooks.EnvPolicy
+		want   []string
+	}{
+		{
+			name:   "default passes parent through and injects CI",
+			policy: hooks.DefaultEnvPolicy(),
+			want:   []string{"PATH=/usr/bin", "HOME=/home/dev", "CLAUDE_HOOKS_DEBUG=1", "CI=1"},
+		},
+		{
+			name:   "clean keeps only allowlisted keys",
+			policy: hooks.EnvPolicy{Clean: true, Extra: nil, NoCI: false},
+			want:   []string{"PATH=/usr/bin", "HOME=/home/dev", "CI=1"},
+		},
+		{
+			name:   "extra entries come last",
+			policy: hooks.EnvPolicy{Clean: true, Extra: []string{"GOFLAGS=-mod=mod", "CI=0"}, NoCI: false},
+			want:   []string{"PATH=/usr/bin", "HOME=/home/dev", "CI=1", "GOFLAGS=-mod=mod", "CI=0"},
+		},
+		{
+			name:   "CI injection can be disabled",
+			policy: hooks.EnvPolicy{Clean: false, Extra: nil, NoCI: true},
+			want:   parent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Environ(parent)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Environ() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandExecutorEnvPolicy(t *testing.T) {
+	t.Setenv("CC_TOOLS_LEAKED_VAR", "leaked")
+
+	policy := hooks.EnvPolicy{Clean: true, Extra: []string{"CC_TOOLS_EXTRA=configured"}, NoCI: false}
+	executor := hooks.NewCommandExecutor(5, false, policy, nil)
+	cmd := newTestDiscoveredCommand(hooks.CommandTypeLint, "env", nil, t.TempDir())
+
+	result := executor.Execute(context.Background(), cmd)
+	assertExecutorSuccess(t, result)
+
+	lines := strings.Split(result.Stdout, "\n")
+	for _, want := range []string{"CC_TOOLS_EXTRA=configured", "CI=1"} {
+		if !slices.Contains(lines, want) {
+			t.Errorf("child environment missing %q:\n%s", want, result.Stdout)
+		}
+	}
+	if strings.Contains(result.Stdout, "CC_TOOLS_LEAKED_VAR") {
+		t.Errorf("child environment should not contain CC_TOOLS_LEAKED_VAR:\n%s", result.Stdout)
+	}
+}
@@ -24,23 +24,28 @@ type ExecutorResult struct {
 	Stderr   string
 	Error    error
 	TimedOut bool
+	// Duration is how long the command ran.
+	Duration time.Duration
 }
 
 // CommandExecutor handles executing discovered commands.
 type CommandExecutor struct {
 	timeout time.Duration
 	debug   bool
+	env     EnvPolicy
 	deps    *Dependencies
 }
 
-// NewCommandExecutor creates a new command executor.
-func NewCommandExecutor(timeoutSecs int, debug bool, deps *Dependencies) *CommandExecutor {
+// NewCommandExecutor creates a new command executor. Commands run with the
+// environment described by env when the runner supports it.
+func NewCommandExecutor(timeoutSecs int, debug bool, env EnvPolicy, deps *Dependencies) *CommandExecutor {
 	if deps == nil {
 		deps = NewDefaultDependencies()
 	}
 	return &CommandExecutor{
 		timeout: time.Duration(timeoutSecs) * time.Second,
 		debug:   debug,
+		env:     env,
 		deps:    deps,
 	}
 }
@@ -55,6 +60,7 @@ func (ce *CommandExecutor) Execute(ctx context.Context, cmd *DiscoveredCommand)
 			Stderr:   "",
 			Error:    errors.New("no command to execute"),
 			TimedOut: false,
+			Duration: 0,
 		}
 	}
 
@@ -63,7 +69,27 @@ func (ce *CommandExecutor) Execute(ctx context.Context, cmd *DiscoveredCommand)
 	defer cancel()
 
 	// Run the command through dependencies
-	output, err := ce.deps.Runner.RunContext(ctx, cmd.WorkingDir, cmd.Command, cmd.Args...)
+	start := ce.deps.Clock.Now()
+	output, err := ce.run(ctx, cmd)
+	elapsed := ce.deps.Clock.Now().Sub(start)
+
+	// Check if the caller cancelled, for example on SIGTERM
+	if errors.Is(ctx.Err(), context.Canceled) {
+		var stdout, stderr string
+		if output != nil {
+			stdout = string(output.Stdout)
+			stderr = string(output.Stderr)
+		}
+		return &ExecutorResult{
+			Success:  false,
+			ExitCode: -1,
+			Stdout:   stdout,
+			Stderr:   stderr,
+			Error:    fmt.Errorf("command cancelled: %w", ctx.Err()),
+			TimedOut: false,
+			Duration: elapsed,
+		}
+	}
 
 	// Check if context timed out
 	if ctx.Err() == context.DeadlineExceeded {
@@ -79,6 +105,7 @@ func (ce *CommandExecutor) Execute(ctx context.Context, cmd *DiscoveredCommand)
 			Stderr:   stderr,
 			Error:    fmt.Errorf("command timed out after %v", ce.timeout),
 			TimedOut: true,
+			Duration: elapsed,
 		}
 	}
 
@@ -106,7 +133,17 @@ func (ce *CommandExecutor) Execute(ctx context.Context, cmd *DiscoveredCommand)
 		Stderr:   stderr,
 		Error:    err,
 		TimedOut: false,
+		Duration: elapsed,
+	}
+}
+
+// run executes cmd through the runner, applying the env policy when the
+// runner can take an explicit environment.
+func (ce *CommandExecutor) run(ctx context.Context, cmd *DiscoveredCommand) (*CommandOutput, error) {
+	if runner, ok := ce.deps.Runner.(EnvCommandRunner); ok {
+		return runner.RunContextEnv(ctx, ce.env.environ(), cmd.WorkingDir, cmd.Command, cmd.Args...)
 	}
+	return ce.deps.Runner.RunContext(ctx, cmd.WorkingDir, cmd.Command, cmd.Args...)
 }
 
 // handleInputError handles errors from reading hook input.
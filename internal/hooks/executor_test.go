@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/riddopic/cc-tools/internal/hookcmd"
 	"github.com/riddopic/cc-tools/internal/hooks"
@@ -77,7 +78,7 @@ func TestCommandExecutor(t *testing.T) {
 			return nil, errors.New("unexpected command")
 		}
 
-		executor := hooks.NewCommandExecutor(5, false, testDeps.Dependencies)
+		executor := hooks.NewCommandExecutor(5, false, hooks.DefaultEnvPolicy(), testDeps.Dependencies)
 		cmd := newTestDiscoveredCommand(hooks.CommandTypeLint, "echo", []string{"hello"}, ".")
 
 		result := executor.Execute(context.Background(), cmd)
@@ -95,12 +96,38 @@ func TestCommandExecutor(t *testing.T) {
 			return nil, errors.New("unexpected command")
 		}
 
-		executor := hooks.NewCommandExecutor(5, false, testDeps.Dependencies)
+		executor := hooks.NewCommandExecutor(5, false, hooks.DefaultEnvPolicy(), testDeps.Dependencies)
 		cmd := newTestDiscoveredCommand(hooks.CommandTypeLint, "false", []string{}, ".")
 
 		result := executor.Execute(context.Background(), cmd)
 		assertExecutorFailure(t, result)
 	})
+
+	t.Run("cancellation stops a blocked command", func(t *testing.T) {
+		testDeps := hooks.CreateTestDependencies()
+		testDeps.MockRunner.RunContextFunc = func(ctx context.Context, _, _ string, _ ...string) (*hooks.CommandOutput, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		executor := hooks.NewCommandExecutor(60, false, hooks.DefaultEnvPolicy(), testDeps.Dependencies)
+		cmd := newTestDiscoveredCommand(hooks.CommandTypeTest, "make", []string{"test"}, ".")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		start := time.Now()
+		result := executor.Execute(ctx, cmd)
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Fatalf("Execute() returned after %v, want prompt return on cancel", elapsed)
+		}
+		if !errors.Is(result.Error, context.Canceled) {
+			t.Errorf("Execute() error = %v, want context.Canceled", result.Error)
+		}
+		if result.Success || result.TimedOut {
+			t.Errorf("Execute() = %+v, want a failed, non-timed-out result", result)
+		}
+	})
 }
 
 // --- TestValidateHookEvent ---
@@ -274,7 +274,7 @@ func CheckSkipsFromInputForTest(
 	debug bool,
 	stderr io.Writer,
 ) (bool, bool) {
-	return checkSkipsFromInput(ctx, input, debug, stderr)
+	return checkSkipsFromInput(ctx, input, "", debug, stderr)
 }
 
 // SetCleanupOnExit sets the cleanupOnExit field on a LockManager for testing.
@@ -291,3 +291,8 @@ func (l *LockManager) LockFileForTest() string {
 func NewRealCommandRunner() CommandRunner {
 	return &realCommandRunner{}
 }
+
+// MakefileDeclaresTargetForTest exposes makefileDeclaresTarget for testing.
+func MakefileDeclaresTargetForTest(data []byte, target string) bool {
+	return makefileDeclaresTarget(data, target)
+}
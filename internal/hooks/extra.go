@@ -0,0 +1,94 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errUnterminatedQuote is returned when a command line ends inside quotes.
+var errUnterminatedQuote = errors.New("unterminated quote")
+
+// BuildExtraCommands parses user-configured command lines into commands that
+// run from projectRoot. Each entry is split into words using shell quoting
+// rules; blank entries are ignored.
+func BuildExtraCommands(entries []string, projectRoot string) ([]*DiscoveredCommand, error) {
+	cmds := make([]*DiscoveredCommand, 0, len(entries))
+	for _, entry := range entries {
+		words, err := SplitCommandLine(entry)
+		if err != nil {
+			return nil, fmt.Errorf("parse extra command %q: %w", entry, err)
+		}
+		if len(words) == 0 {
+			continue
+		}
+
+		cmds = append(cmds, &DiscoveredCommand{
+			Type:       CommandTypeExtra,
+			Command:    words[0],
+			Args:       words[1:],
+			WorkingDir: projectRoot,
+			Source:     "validate.extra_commands",
+		})
+	}
+	return cmds, nil
+}
+
+// SplitCommandLine splits s into words the way a POSIX shell would, honoring
+// single quotes, double quotes, and backslash escapes. It does not expand
+// variables or globs.
+func SplitCommandLine(s string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errUnterminatedQuote
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,153 @@
+package hooks_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+func TestBuildExtraCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    string
+		wantCmd  string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{name: "simple", entry: "make vet-custom", wantCmd: "make", wantArgs: []string{"vet-custom"}, wantErr: false},
+		{
+			name:     "quoted argument",
+			entry:    `go test -run 'TestA|TestB' "./pkg/a b"`,
+			wantCmd:  "go",
+			wantArgs: []string{"test", "-run", "TestA|TestB", "./pkg/a b"},
+			wantErr:  false,
+		},
+		{
+			name:     "escapes",
+			entry:    `echo a\ b "say \"hi\""`,
+			wantCmd:  "echo",
+			wantArgs: []string{"a b", `say "hi"`},
+			wantErr:  false,
+		},
+		{name: "empty quotes", entry: `printf ''`, wantCmd: "printf", wantArgs: []string{""}, wantErr: false},
+		{name: "unterminated quote", entry: `echo "oops`, wantCmd: "", wantArgs: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmds, err := hooks.BuildExtraCommands([]string{tt.entry}, "/project")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("BuildExtraCommands() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildExtraCommands() error = %v", err)
+			}
+			if len(cmds) != 1 {
+				t.Fatalf("got %d commands, want 1", len(cmds))
+			}
+			cmd := cmds[0]
+			if cmd.Command != tt.wantCmd || !slices.Equal(cmd.Args, tt.wantArgs) {
+				t.Errorf("got %q %q, want %q %q", cmd.Command, cmd.Args, tt.wantCmd, tt.wantArgs)
+			}
+			if cmd.WorkingDir != "/project" || cmd.Type != hooks.CommandTypeExtra {
+				t.Errorf("got dir %q type %q, want /project extra", cmd.WorkingDir, cmd.Type)
+			}
+		})
+	}
+}
+
+func TestBuildExtraCommands_SkipsBlank(t *testing.T) {
+	cmds, err := hooks.BuildExtraCommands([]string{"  ", "make vet"}, "/project")
+	if err != nil {
+		t.Fatalf("BuildExtraCommands() error = %v", err)
+	}
+	if len(cmds) != 1 {
+		t.Errorf("got %d commands, want 1", len(cmds))
+	}
+}
+
+// setupNoDiscoveryFS makes lint and test discovery find nothing.
+func setupNoDiscoveryFS(deps *hooks.TestDependencies) {
+	deps.MockFS.StatFunc = func(_ string) (os.FileInfo, error) {
+		return nil, os.ErrNotExist
+	}
+}
+
+func TestParallelValidateExecutor_ExtraCommands(t *testing.T) {
+	tests := []struct {
+		name       string
+		extra      []string
+		timeout    int
+		runFunc    func(ctx context.Context, dir, name string, args ...string) (*hooks.CommandOutput, error)
+		wantPassed bool
+		wantInMsg  string
+	}{
+		{
+			name:    "passing extra command",
+			extra:   []string{"make vet-custom"},
+			timeout: 10,
+			runFunc: func(_ context.Context, dir, _ string, _ ...string) (*hooks.CommandOutput, error) {
+				if dir != "/project" {
+					return nil, errors.New("wrong working dir: " + dir)
+				}
+				return &hooks.CommandOutput{Stdout: []byte("ok"), Stderr: nil}, nil
+			},
+			wantPassed: true,
+			wantInMsg:  "Validations pass",
+		},
+		{
+			name:    "failing extra command blocks",
+			extra:   []string{"make vet-custom"},
+			timeout: 10,
+			runFunc: func(_ context.Context, _, _ string, _ ...string) (*hooks.CommandOutput, error) {
+				return &hooks.CommandOutput{Stdout: nil, Stderr: []byte("vet failed")}, errors.New("exit status 2")
+			},
+			wantPassed: false,
+			wantInMsg:  "Extra command failed (exit status 2). Run 'cd /project && make vet-custom'",
+		},
+		{
+			name:    "extra command respects timeout",
+			extra:   []string{"sleep 60"},
+			timeout: 1,
+			runFunc: func(ctx context.Context, _, _ string, _ ...string) (*hooks.CommandOutput, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			wantPassed: false,
+			wantInMsg:  "timed out",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDeps := hooks.CreateTestDependencies()
+			setupNoDiscoveryFS(testDeps)
+			testDeps.MockRunner.RunContextFunc = tt.runFunc
+
+			executor := hooks.NewParallelValidateExecutor("/project", tt.timeout, false, nil, testDeps.Dependencies)
+			executor.SetExtraCommands(tt.extra)
+
+			result, err := executor.ExecuteValidations(context.Background(), "/project", "/project/pkg")
+			if err != nil {
+				t.Fatalf("ExecuteValidations() error = %v", err)
+			}
+			if result.BothPassed != tt.wantPassed {
+				t.Errorf("BothPassed = %v, want %v", result.BothPassed, tt.wantPassed)
+			}
+			if len(result.ExtraResults) != len(tt.extra) {
+				t.Errorf("got %d extra results, want %d", len(result.ExtraResults), len(tt.extra))
+			}
+			if msg := stripANSI(result.FormatMessage()); !strings.Contains(msg, tt.wantInMsg) {
+				t.Errorf("FormatMessage() = %q, want it to contain %q", msg, tt.wantInMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package hooks
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// failureSummaryLimit caps the length of a recorded failure summary.
+const failureSummaryLimit = 200
+
+// Failure is an outstanding lint or test failure seen after a file was
+// edited.
+type Failure struct {
+	File      string      `json:"file"`
+	Type      CommandType `json:"type"`
+	Command   string      `json:"command"`
+	Timestamp time.Time   `json:"timestamp"`
+	Summary   string      `json:"summary"`
+}
+
+// FailureLog keeps the current lint, test, and extra command failures for a
+// project in the system temp directory. A file's entry for a command is
+// replaced on each failure. A pass run from the project root covers every
+// file, so it removes all entries for that command; a pass run from a
+// subdirectory removes only the edited file's entry.
+type FailureLog struct {
+	mu   sync.Mutex
+	root string
+	file string
+	deps *Dependencies
+}
+
+// NewFailureLog creates the failure log for the given project.
+func NewFailureLog(projectRoot string, deps *Dependencies) *FailureLog {
+	if deps == nil {
+		deps = NewDefaultDependencies()
+	}
+
+	hash := sha256.Sum256([]byte(projectRoot))
+	fileName := fmt.Sprintf("claude-hook-validate-failures-%x.json", hash[:8])
+
+	return &FailureLog{
+		mu:   sync.Mutex{},
+		root: projectRoot,
+		file: filepath.Join(deps.FS.TempDir(), fileName),
+		deps: deps,
+	}
+}
+
+// Record updates the log with the lint, test, and extra command results of
+// a validation run triggered by an edit to file. Commands that did not run
+// leave their entries untouched.
+func (l *FailureLog) Record(file string, result *ValidateResult) error {
+	if result == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	failures := l.load()
+	results := append([]*ValidationResult{result.LintResult, result.TestResult}, result.ExtraResults...)
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+
+		var command string
+		if r.Command != nil {
+			command = r.Command.String()
+		}
+		wholeProject := r.Success && r.Command != nil && r.Command.WorkingDir == l.root
+		failures = slices.DeleteFunc(failures, func(f Failure) bool {
+			// Extra commands share a type, so they are told apart by command.
+			sameCheck := f.Type == r.Type && (r.Type != CommandTypeExtra || f.Command == command)
+			return sameCheck && (wholeProject || f.File == file)
+		})
+		if r.Success {
+			continue
+		}
+
+		failures = append(failures, Failure{
+			File:      file,
+			Type:      r.Type,
+			Command:   command,
+			Timestamp: l.deps.Clock.Now(),
+			Summary:   failureSummary(r),
+		})
+	}
+
+	data, err := json.Marshal(failures)
+	if err != nil {
+		return fmt.Errorf("encoding failure log: %w", err)
+	}
+	if writeErr := l.deps.FS.WriteFile(l.file, data, cacheFileMode); writeErr != nil {
+		return fmt.Errorf("writing failure log: %w", writeErr)
+	}
+	return nil
+}
+
+// List returns the outstanding failures, sorted by file, command type, and
+// command.
+func (l *FailureLog) List() []Failure {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	failures := l.load()
+	slices.SortFunc(failures, func(a, b Failure) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Type, b.Type), cmp.Compare(a.Command, b.Command))
+	})
+	return failures
+}
+
+// load reads the log file, returning nothing when it is missing or
+// unreadable.
+func (l *FailureLog) load() []Failure {
+	data, err := l.deps.FS.ReadFile(l.file)
+	if err != nil {
+		return nil
+	}
+
+	var failures []Failure
+	if jsonErr := json.Unmarshal(data, &failures); jsonErr != nil {
+		return nil
+	}
+	return failures
+}
+
+// failureSummary returns the first non-blank line of the command output, or
+// the error when there was no output.
+func failureSummary(r *ValidationResult) string {
+	summary := ""
+	for line := range strings.Lines(r.Output) {
+		if line = strings.TrimSpace(line); line != "" {
+			summary = line
+			break
+		}
+	}
+	if summary == "" && r.Error != nil {
+		summary = r.Error.Error()
+	}
+
+	if runes := []rune(summary); len(runes) > failureSummaryLimit {
+		summary = string(runes[:failureSummaryLimit]) + "…"
+	}
+	return summary
+}
@@ -0,0 +1,173 @@
+package hooks_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+func newFailureResult(cmdType hooks.CommandType, success bool, output string) *hooks.ValidationResult {
+	return &hooks.ValidationResult{
+		Type:     cmdType,
+		Success:  success,
+		ExitCode: 0,
+		Message:  "",
+		Output:   output,
+		Command: &hooks.DiscoveredCommand{
+			Type:       cmdType,
+			Command:    "make",
+			Args:       []string{string(cmdType)},
+			WorkingDir: "/project",
+			Source:     "Makefile",
+		},
+		Error: nil,
+	}
+}
+
+func TestFailureLog(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	setupMemoryFS(testDeps)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	testDeps.MockClock.NowFunc = func() time.Time { return now }
+
+	log := hooks.NewFailureLog("/project", testDeps.Dependencies)
+	assert.Empty(t, log.List())
+
+	require.NoError(t, log.Record("/project/main.go", &hooks.ValidateResult{
+		LintResult:   newFailureResult(hooks.CommandTypeLint, false, "\nmain.go:3: unused variable x\nmore\n"),
+		TestResult:   newFailureResult(hooks.CommandTypeTest, true, "ok"),
+		ExtraResults: nil,
+		BothPassed:   false,
+	}))
+
+	assert.Equal(t, []hooks.Failure{{
+		File:      "/project/main.go",
+		Type:      hooks.CommandTypeLint,
+		Command:   "make lint",
+		Timestamp: now,
+		Summary:   "main.go:3: unused variable x",
+	}}, log.List())
+
+	// A test failure for another file is kept alongside.
+	require.NoError(t, log.Record("/project/util.go", &hooks.ValidateResult{
+		LintResult:   nil,
+		TestResult:   newFailureResult(hooks.CommandTypeTest, false, ""),
+		ExtraResults: nil,
+		BothPassed:   false,
+	}))
+	require.Len(t, log.List(), 2)
+
+	// The next passing lint run for main.go clears its entry; the test
+	// failure stays because test did not run.
+	require.NoError(t, log.Record("/project/main.go", &hooks.ValidateResult{
+		LintResult:   newFailureResult(hooks.CommandTypeLint, true, ""),
+		TestResult:   nil,
+		ExtraResults: nil,
+		BothPassed:   true,
+	}))
+
+	failures := hooks.NewFailureLog("/project", testDeps.Dependencies).List()
+	require.Len(t, failures, 1)
+	assert.Equal(t, "/project/util.go", failures[0].File)
+	assert.Equal(t, hooks.CommandTypeTest, failures[0].Type)
+
+	// A test pass from the project root, after any edit, covers util.go too.
+	require.NoError(t, log.Record("/project/main.go", &hooks.ValidateResult{
+		LintResult:   nil,
+		TestResult:   newFailureResult(hooks.CommandTypeTest, true, ""),
+		ExtraResults: nil,
+		BothPassed:   true,
+	}))
+	assert.Empty(t, log.List())
+}
+
+func TestFailureLog_SubdirectoryPass(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	setupMemoryFS(testDeps)
+	log := hooks.NewFailureLog("/project", testDeps.Dependencies)
+
+	for _, file := range []string{"/project/a/a.go", "/project/b/b.go"} {
+		require.NoError(t, log.Record(file, &hooks.ValidateResult{
+			LintResult:   newFailureResult(hooks.CommandTypeLint, false, "bad"),
+			TestResult:   nil,
+			ExtraResults: nil,
+			BothPassed:   false,
+		}))
+	}
+
+	// A pass run from a file's directory says nothing about other files.
+	pass := newFailureResult(hooks.CommandTypeLint, true, "")
+	pass.Command.WorkingDir = "/project/a"
+	require.NoError(t, log.Record("/project/a/a.go", &hooks.ValidateResult{
+		LintResult:   pass,
+		TestResult:   nil,
+		ExtraResults: nil,
+		BothPassed:   true,
+	}))
+
+	failures := log.List()
+	require.Len(t, failures, 1)
+	assert.Equal(t, "/project/b/b.go", failures[0].File)
+}
+
+func TestFailureLog_ExtraCommands(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	setupMemoryFS(testDeps)
+	log := hooks.NewFailureLog("/project", testDeps.Dependencies)
+
+	extra := func(name string, success bool) *hooks.ValidationResult {
+		r := newFailureResult(hooks.CommandTypeExtra, success, name+" failed")
+		r.Command.Args = []string{name}
+		return r
+	}
+
+	require.NoError(t, log.Record("/project/main.go", &hooks.ValidateResult{
+		LintResult:   nil,
+		TestResult:   nil,
+		ExtraResults: []*hooks.ValidationResult{extra("vet", false), extra("vuln", false)},
+		BothPassed:   false,
+	}))
+	failures := log.List()
+	require.Len(t, failures, 2)
+	assert.Equal(t, "make vet", failures[0].Command)
+	assert.Equal(t, "vet failed", failures[0].Summary)
+	assert.Equal(t, "make vuln", failures[1].Command)
+
+	// Passing one extra command clears only its own entry.
+	require.NoError(t, log.Record("/project/main.go", &hooks.ValidateResult{
+		LintResult:   nil,
+		TestResult:   nil,
+		ExtraResults: []*hooks.ValidationResult{extra("vet", true)},
+		BothPassed:   false,
+	}))
+	failures = log.List()
+	require.Len(t, failures, 1)
+	assert.Equal(t, "make vuln", failures[0].Command)
+}
+
+func TestFailureLog_Summary(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	setupMemoryFS(testDeps)
+	log := hooks.NewFailureLog("/project", testDeps.Dependencies)
+
+	noOutput := newFailureResult(hooks.CommandTypeLint, false, "")
+	noOutput.Error = errors.New("exit status 2")
+	long := newFailureResult(hooks.CommandTypeTest, false, strings.Repeat("x", 300))
+	require.NoError(t, log.Record("/project/main.go", &hooks.ValidateResult{
+		LintResult:   noOutput,
+		TestResult:   long,
+		ExtraResults: nil,
+		BothPassed:   false,
+	}))
+
+	failures := log.List()
+	require.Len(t, failures, 2)
+	assert.Equal(t, "exit status 2", failures[0].Summary, "the error stands in for missing output")
+	assert.Equal(t, strings.Repeat("x", 200)+"…", failures[1].Summary)
+}
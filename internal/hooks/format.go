@@ -0,0 +1,46 @@
+package hooks
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DiscoverFormatter returns the formatter command for a single file based on
+// its extension. It returns nil when no formatter handles the file type or
+// the formatter binary is not on PATH.
+func DiscoverFormatter(filePath string, runner CommandRunner) *DiscoveredCommand {
+	ext := strings.ToLower(filepath.Ext(filePath))
+
+	name, args := formatterForExt(ext)
+	if name == "" {
+		return nil
+	}
+
+	if _, err := runner.LookPath(name); err != nil {
+		return nil
+	}
+
+	return &DiscoveredCommand{
+		Type:       CommandTypeFormat,
+		Command:    name,
+		Args:       append(args, filePath),
+		WorkingDir: filepath.Dir(filePath),
+		Source:     ext,
+	}
+}
+
+// formatterForExt maps a file extension to a formatter binary and the
+// arguments that precede the file path.
+func formatterForExt(ext string) (string, []string) {
+	switch ext {
+	case ".go":
+		return "gofmt", []string{"-w"}
+	case ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx",
+		".json", ".css", ".scss", ".html", ".vue", ".md", ".yaml", ".yml":
+		return "prettier", []string{"--write"}
+	case ".py", ".pyi":
+		return "ruff", []string{"format"}
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,75 @@
+package hooks_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+func TestDiscoverFormatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		wantCmd  string
+		wantArgs []string
+	}{
+		{
+			name:     "go file uses gofmt",
+			filePath: "/project/main.go",
+			wantCmd:  "gofmt",
+			wantArgs: []string{"-w", "/project/main.go"},
+		},
+		{
+			name:     "typescript file uses prettier",
+			filePath: "/project/src/app.tsx",
+			wantCmd:  "prettier",
+			wantArgs: []string{"--write", "/project/src/app.tsx"},
+		},
+		{
+			name:     "python file uses ruff format",
+			filePath: "/project/pkg/mod.py",
+			wantCmd:  "ruff",
+			wantArgs: []string{"format", "/project/pkg/mod.py"},
+		},
+		{
+			name:     "unknown extension has no formatter",
+			filePath: "/project/notes.txt",
+			wantCmd:  "",
+			wantArgs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDeps := hooks.CreateTestDependencies()
+			testDeps.MockRunner.LookPathFunc = func(file string) (string, error) {
+				return "/usr/bin/" + file, nil
+			}
+
+			cmd := hooks.DiscoverFormatter(tt.filePath, testDeps.MockRunner)
+
+			if tt.wantCmd == "" {
+				assert.Nil(t, cmd)
+				return
+			}
+
+			require.NotNil(t, cmd)
+			assert.Equal(t, hooks.CommandTypeFormat, cmd.Type)
+			assert.Equal(t, tt.wantCmd, cmd.Command)
+			assert.Equal(t, tt.wantArgs, cmd.Args)
+		})
+	}
+}
+
+func TestDiscoverFormatter_NotInstalled(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	testDeps.MockRunner.LookPathFunc = func(_ string) (string, error) {
+		return "", errors.New("not found")
+	}
+
+	assert.Nil(t, hooks.DiscoverFormatter("/project/main.go", testDeps.MockRunner))
+}
@@ -0,0 +1,50 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/riddopic/cc-tools/internal/output"
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// gitOpMarkers are the paths, relative to a project root, that git creates
+// while a rebase or merge is waiting on the user.
+var gitOpMarkers = []string{
+	filepath.Join(".git", "rebase-merge"),
+	filepath.Join(".git", "rebase-apply"),
+	filepath.Join(".git", "MERGE_HEAD"),
+}
+
+// gitOperationInProgress returns the first marker found under projectRoot,
+// or an empty string when no rebase or merge is underway.
+func gitOperationInProgress(fs shared.HooksFS, projectRoot string) string {
+	for _, marker := range gitOpMarkers {
+		if _, err := fs.Stat(filepath.Join(projectRoot, marker)); err == nil {
+			return marker
+		}
+	}
+	return ""
+}
+
+// dirtyTreeNote runs git status in projectRoot and returns an informational
+// note when the working tree has uncommitted changes. It returns an empty
+// string when the tree is clean or git cannot be run.
+func dirtyTreeNote(ctx context.Context, runner CommandRunner, projectRoot string) string {
+	out, err := runner.RunContext(ctx, projectRoot, "git", "status", "--porcelain")
+	if err != nil || out == nil {
+		return ""
+	}
+
+	status := strings.TrimSpace(string(out.Stdout))
+	if status == "" {
+		return ""
+	}
+
+	changes := strings.Count(status, "\n") + 1
+	return output.NewHookFormatter().FormatWarning(fmt.Sprintf(
+		"ℹ️ Note: %d uncommitted change(s) in the working tree; files outside this validation were not checked. "+
+			"Run 'git status' in %s to review them.", changes, projectRoot))
+}
@@ -0,0 +1,161 @@
+package hooks_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+func TestRunValidateHookWithSkip_GitOperations(t *testing.T) {
+	tests := []struct {
+		name       string
+		marker     string
+		skipGitOps bool
+		wantExit   int
+		wantRun    bool
+	}{
+		{name: "interactive rebase", marker: ".git/rebase-merge", skipGitOps: true, wantExit: 0, wantRun: false},
+		{name: "am-style rebase", marker: ".git/rebase-apply", skipGitOps: true, wantExit: 0, wantRun: false},
+		{name: "merge", marker: ".git/MERGE_HEAD", skipGitOps: true, wantExit: 0, wantRun: false},
+		{
+			name:       "disabled still validates",
+			marker:     ".git/rebase-merge",
+			skipGitOps: false,
+			wantExit:   hooks.ExitCodeShowMessage,
+			wantRun:    true,
+		},
+		{name: "no git operation", marker: "", skipGitOps: true, wantExit: hooks.ExitCodeShowMessage, wantRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDeps := hooks.CreateTestDependencies()
+			testDeps.MockFS.StatFunc = func(path string) (os.FileInfo, error) {
+				isMarker := tt.marker != "" && path == filepath.Join("/project", tt.marker)
+				if isMarker || strings.HasSuffix(path, ".git") || strings.HasSuffix(path, "Makefile") {
+					return hooks.NewMockFileInfo(filepath.Base(path), 0, 0, time.Time{}, false), nil
+				}
+				return nil, os.ErrNotExist
+			}
+
+			ran := false
+			runner := makeDiscoveryAndExecRunner(failOutput("lint failed"), successOutput("ok"))
+			testDeps.MockRunner.RunContextFunc = func(
+				ctx context.Context, dir, name string, args ...string,
+			) (*hooks.CommandOutput, error) {
+				ran = true
+				return runner(ctx, dir, name, args...)
+			}
+
+			input := &hookcmd.HookInput{
+				HookEventName: "PostToolUse",
+				ToolName:      "Edit",
+				ToolInput:     hooks.MustMarshalJSON(map[string]any{"file_path": "/project/main.go"}),
+			}
+			skip := &hooks.SkipConfig{SkipLint: false, SkipTest: false, SkipDuringGitOps: tt.skipGitOps}
+
+			opts := hooks.NewValidateOptions(10, 0)
+			opts.Debug = true
+			opts.SkipConfig = *skip
+			exitCode := hooks.RunValidateHookWithSkip(context.Background(), input, opts, testDeps.Dependencies)
+			if exitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", exitCode, tt.wantExit)
+			}
+			if ran != tt.wantRun {
+				t.Errorf("commands ran = %v, want %v", ran, tt.wantRun)
+			}
+			if !tt.wantRun && !strings.Contains(testDeps.MockStderr.String(), "rebase or merge in progress") {
+				t.Errorf("stderr should explain the skip, got %q", testDeps.MockStderr.String())
+			}
+		})
+	}
+}
+
+func TestRunValidateHookWithSkip_WarnDirty(t *testing.T) {
+	tests := []struct {
+		name      string
+		warnDirty bool
+		lint      func() (*hooks.CommandOutput, error)
+		status    string
+		wantNote  bool
+		wantGit   bool
+	}{
+		{
+			name:      "dirty tree after pass",
+			warnDirty: true,
+			lint:      successOutput("ok"),
+			status:    " M main.go\n?? notes.txt\n",
+			wantNote:  true,
+			wantGit:   true,
+		},
+		{name: "clean tree", warnDirty: true, lint: successOutput("ok"), status: "", wantNote: false, wantGit: true},
+		{
+			name:      "disabled",
+			warnDirty: false,
+			lint:      successOutput("ok"),
+			status:    " M main.go\n",
+			wantNote:  false,
+			wantGit:   false,
+		},
+		{
+			name:      "failed validation",
+			warnDirty: true,
+			lint:      failOutput("lint failed"),
+			status:    " M main.go\n",
+			wantNote:  false,
+			wantGit:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDeps := hooks.CreateTestDependencies()
+			setupGitMakefileProjectFS(testDeps)
+
+			gitRan := false
+			runner := makeDiscoveryAndExecRunner(tt.lint, successOutput("ok"))
+			testDeps.MockRunner.RunContextFunc = func(
+				ctx context.Context, dir, name string, args ...string,
+			) (*hooks.CommandOutput, error) {
+				if name == "git" {
+					gitRan = true
+					if dir != "/project" || strings.Join(args, " ") != "status --porcelain" {
+						t.Errorf("git ran as %q in %s", args, dir)
+					}
+					return &hooks.CommandOutput{Stdout: []byte(tt.status), Stderr: nil}, nil
+				}
+				return runner(ctx, dir, name, args...)
+			}
+
+			input := &hookcmd.HookInput{
+				HookEventName: "PostToolUse",
+				ToolName:      "Edit",
+				ToolInput:     hooks.MustMarshalJSON(map[string]any{"file_path": "/project/main.go"}),
+			}
+			skip := &hooks.SkipConfig{SkipLint: false, SkipTest: false, SkipDuringGitOps: false, WarnDirty: tt.warnDirty}
+
+			opts := hooks.NewValidateOptions(10, 0)
+			opts.SkipConfig = *skip
+			exitCode := hooks.RunValidateHookWithSkip(context.Background(), input, opts, testDeps.Dependencies)
+			if exitCode != hooks.ExitCodeShowMessage {
+				t.Errorf("exit code = %d, want %d", exitCode, hooks.ExitCodeShowMessage)
+			}
+			if gitRan != tt.wantGit {
+				t.Errorf("git ran = %v, want %v", gitRan, tt.wantGit)
+			}
+			stderr := testDeps.MockStderr.String()
+			if got := strings.Contains(stderr, "uncommitted change(s)"); got != tt.wantNote {
+				t.Errorf("note present = %v, want %v; stderr = %q", got, tt.wantNote, stderr)
+			}
+			if tt.wantNote && !strings.Contains(stderr, "2 uncommitted change(s)") {
+				t.Errorf("note should count both changes, got %q", stderr)
+			}
+		})
+	}
+}
@@ -312,7 +312,7 @@ func TestDiscoveredCommandStringOld(t *testing.T) {
 // TestCommandExecutorBasic tests basic command execution.
 func TestCommandExecutorBasic(t *testing.T) {
 	t.Run("execute simple command", func(t *testing.T) {
-		executor := hooks.NewCommandExecutor(5, false, nil)
+		executor := hooks.NewCommandExecutor(5, false, hooks.DefaultEnvPolicy(), nil)
 
 		cmd := &hooks.DiscoveredCommand{
 			Type:       hooks.CommandTypeTest,
@@ -329,7 +329,7 @@ func TestCommandExecutorBasic(t *testing.T) {
 	})
 
 	t.Run("handle command timeout", func(t *testing.T) {
-		executor := hooks.NewCommandExecutor(1, false, nil) // 1 second timeout
+		executor := hooks.NewCommandExecutor(1, false, hooks.DefaultEnvPolicy(), nil) // 1 second timeout
 
 		cmd := &hooks.DiscoveredCommand{
 			Type:       hooks.CommandTypeTest,
@@ -349,7 +349,7 @@ func TestCommandExecutorBasic(t *testing.T) {
 	})
 
 	t.Run("handle non-existent command", func(t *testing.T) {
-		executor := hooks.NewCommandExecutor(5, false, nil)
+		executor := hooks.NewCommandExecutor(5, false, hooks.DefaultEnvPolicy(), nil)
 
 		cmd := &hooks.DiscoveredCommand{
 			Type:       hooks.CommandTypeTest,
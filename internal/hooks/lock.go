@@ -2,18 +2,37 @@ package hooks
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"strconv"
+	"time"
 )
 
 const lockFileMode = 0o600 // Read/write for owner only
 
+// DefaultLockGrace is how long past the cooldown a held lock is trusted. A
+// hook that is killed never releases its lock, and its PID may be reused by
+// an unrelated process, so a lock older than this is reclaimed regardless.
+const DefaultLockGrace = 10 * time.Minute
+
+// The lock file holds the PID of the holder, the time the last run
+// completed, and the time the lock was acquired, one per line. A held lock
+// has a PID and an acquisition time; a released one has only a completion
+// time, which starts the cooldown.
+const (
+	lockLinePID = iota
+	lockLineCompleted
+	lockLineAcquired
+)
+
 // LockManager handles process locking to prevent concurrent hook execution.
 type LockManager struct {
 	lockFile      string
 	pid           int
 	cooldownSecs  int
+	grace         time.Duration
 	cleanupOnExit bool
 	deps          *Dependencies
 }
@@ -33,18 +52,25 @@ func NewLockManager(workspaceDir, hookName string, cooldownSecs int, deps *Depen
 		lockFile:      lockFile,
 		pid:           deps.Process.GetPID(),
 		cooldownSecs:  cooldownSecs,
+		grace:         DefaultLockGrace,
 		cleanupOnExit: true,
 		deps:          deps,
 	}
 }
 
+// SetGrace sets how long past the cooldown a held lock is trusted before it
+// is treated as abandoned. It should exceed the longest run the lock guards.
+func (l *LockManager) SetGrace(grace time.Duration) {
+	l.grace = grace
+}
+
 // isAnotherProcessRunning checks if another process holds the lock.
 func (l *LockManager) isAnotherProcessRunning(lines []string) bool {
-	if len(lines) < 1 || lines[0] == "" {
+	if len(lines) <= lockLinePID || lines[lockLinePID] == "" {
 		return false
 	}
 
-	pid, err := strconv.Atoi(lines[0])
+	pid, err := strconv.Atoi(lines[lockLinePID])
 	if err != nil {
 		return false
 	}
@@ -52,13 +78,30 @@ func (l *LockManager) isAnotherProcessRunning(lines []string) bool {
 	return l.deps.Process.ProcessExists(pid)
 }
 
+// isExpired reports whether a held lock was acquired more than the cooldown
+// plus the grace period ago. A lock written before acquisition times were
+// recorded never expires this way.
+func (l *LockManager) isExpired(lines []string) bool {
+	if len(lines) <= lockLineAcquired || lines[lockLineAcquired] == "" {
+		return false
+	}
+
+	acquiredAt, err := strconv.ParseInt(lines[lockLineAcquired], 10, 64)
+	if err != nil {
+		return true
+	}
+
+	held := l.deps.Clock.Now().Sub(time.Unix(acquiredAt, 0))
+	return held >= time.Duration(l.cooldownSecs)*time.Second+l.grace
+}
+
 // isInCooldownPeriod checks if the lock is in cooldown period.
 func (l *LockManager) isInCooldownPeriod(lines []string) bool {
-	if len(lines) < 2 || lines[1] == "" {
+	if len(lines) <= lockLineCompleted || lines[lockLineCompleted] == "" {
 		return false
 	}
 
-	completionTime, err := strconv.ParseInt(lines[1], 10, 64)
+	completionTime, err := strconv.ParseInt(lines[lockLineCompleted], 10, 64)
 	if err != nil {
 		return false
 	}
@@ -72,7 +115,7 @@ func (l *LockManager) isInCooldownPeriod(lines []string) bool {
 func (l *LockManager) TryAcquire() (bool, error) {
 	// First, try to atomically create the lock file
 	// CreateExclusive uses O_EXCL to ensure this fails if the file already exists
-	content := fmt.Sprintf("%d\n", l.pid)
+	content := fmt.Sprintf("%d\n\n%d\n", l.pid, l.deps.Clock.Now().Unix())
 	err := l.deps.FS.CreateExclusive(l.lockFile, []byte(content), lockFileMode)
 	if err == nil {
 		// We created the file atomically!
@@ -89,8 +132,9 @@ func (l *LockManager) TryAcquire() (bool, error) {
 
 	lines := splitLines(string(data))
 
-	// Check if another process is running
-	if l.isAnotherProcessRunning(lines) {
+	// Check if another process is running. A lock held past its expiry is
+	// stale even when its PID now belongs to a live process.
+	if !l.isExpired(lines) && l.isAnotherProcessRunning(lines) {
 		return false, nil
 	}
 
@@ -116,12 +160,20 @@ func (l *LockManager) TryAcquire() (bool, error) {
 	return true, nil
 }
 
-// Release releases the lock and starts the cooldown period.
+// Release releases the lock and starts the cooldown period. With no
+// cooldown there is nothing to record, so the lock file is removed.
 func (l *LockManager) Release() error {
 	if !l.cleanupOnExit {
 		return nil
 	}
 
+	if l.cooldownSecs <= 0 {
+		if err := l.deps.FS.Remove(l.lockFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing lock file: %w", err)
+		}
+		return nil
+	}
+
 	// Write empty PID and completion timestamp
 	content := fmt.Sprintf("\n%d\n", l.deps.Clock.Now().Unix())
 	if err := l.deps.FS.WriteFile(l.lockFile, []byte(content), lockFileMode); err != nil {
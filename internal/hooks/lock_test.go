@@ -188,6 +188,103 @@ func TestLockManagerWithMocks(t *testing.T) {
 		}
 	})
 
+	t.Run("acquire records pid and time", func(t *testing.T) {
+		testDeps := hooks.CreateTestDependencies()
+		setBasicFSMocks(testDeps)
+
+		var writtenData []byte
+
+		testDeps.MockFS.CreateExclusiveFunc = func(_ string, data []byte, _ os.FileMode) error {
+			writtenData = data
+			return nil
+		}
+
+		lm := hooks.NewLockManager("/project", "test", 5, testDeps.Dependencies)
+		requireAcquireSuccess(t, lm)
+
+		expected := "99999\n\n1700000000\n"
+		if string(writtenData) != expected {
+			t.Errorf("Expected written data %q, got %q", expected, string(writtenData))
+		}
+	})
+
+	t.Run("reclaims lock held past cooldown plus grace", func(t *testing.T) {
+		testDeps := hooks.CreateTestDependencies()
+		setBasicFSMocks(testDeps)
+
+		var createExclusiveCallCount int
+
+		testDeps.MockFS.CreateExclusiveFunc = func(_ string, _ []byte, _ os.FileMode) error {
+			createExclusiveCallCount++
+			if createExclusiveCallCount == 1 {
+				return errors.New("file exists")
+			}
+			return nil
+		}
+		// Held since 1700000000 by a PID that is alive, as after the holder
+		// was killed and its PID reused.
+		testDeps.MockFS.ReadFileFunc = func(_ string) ([]byte, error) {
+			return []byte("12345\n\n1700000000\n"), nil
+		}
+		testDeps.MockFS.RemoveFunc = func(_ string) error {
+			return nil
+		}
+		testDeps.MockProcess.ProcessExistsFunc = func(_ int) bool {
+			return true
+		}
+		testDeps.MockClock.NowFunc = func() time.Time {
+			return time.Unix(1700000000, 0).Add(5*time.Second + time.Minute)
+		}
+
+		lm := hooks.NewLockManager("/project", "test", 5, testDeps.Dependencies)
+		lm.SetGrace(time.Minute)
+		requireAcquireSuccess(t, lm)
+		assertTwoCreateExclusiveCalls(t, createExclusiveCallCount)
+	})
+
+	t.Run("respects live holder within cooldown plus grace", func(t *testing.T) {
+		testDeps := hooks.CreateTestDependencies()
+		setBasicFSMocks(testDeps)
+		testDeps.MockFS.CreateExclusiveFunc = func(_ string, _ []byte, _ os.FileMode) error {
+			return errors.New("file exists")
+		}
+		testDeps.MockFS.ReadFileFunc = func(_ string) ([]byte, error) {
+			return []byte("12345\n\n1700000000\n"), nil
+		}
+		testDeps.MockProcess.ProcessExistsFunc = func(pid int) bool {
+			return pid == 12345
+		}
+		testDeps.MockClock.NowFunc = func() time.Time {
+			return time.Unix(1700000000, 0).Add(hooks.DefaultLockGrace)
+		}
+
+		lm := hooks.NewLockManager("/project", "test", 5, testDeps.Dependencies)
+		requireAcquireBlocked(t, lm)
+	})
+
+	t.Run("release without cooldown removes the lock", func(t *testing.T) {
+		testDeps := hooks.CreateTestDependencies()
+		setBasicFSMocks(testDeps)
+
+		var removed string
+
+		testDeps.MockFS.RemoveFunc = func(name string) error {
+			removed = name
+			return nil
+		}
+		testDeps.MockFS.WriteFileFunc = func(_ string, _ []byte, _ os.FileMode) error {
+			t.Error("Release should not write a completion time without a cooldown")
+			return nil
+		}
+
+		lm := hooks.NewLockManager("/project", "test", 0, testDeps.Dependencies)
+		requireRelease(t, lm)
+
+		if removed != lm.LockFileForTest() {
+			t.Errorf("Expected %q to be removed, got %q", lm.LockFileForTest(), removed)
+		}
+	})
+
 	t.Run("handles write error on acquire", func(t *testing.T) {
 		testDeps := hooks.CreateTestDependencies()
 		setBasicFSMocks(testDeps)
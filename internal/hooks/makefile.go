@@ -0,0 +1,94 @@
+package hooks
+
+import (
+	"strings"
+)
+
+// phonyTarget is the special Makefile target that lists phony targets.
+const phonyTarget = ".PHONY"
+
+// makefileDeclaresTarget reports whether a Makefile declares target, either
+// as a rule or in a .PHONY list. It is a lightweight fallback for when
+// `make -n` fails for reasons unrelated to the target, such as a missing
+// include or a recipe that shells out during parsing. Conditionals,
+// includes, and variable expansion are not evaluated.
+func makefileDeclaresTarget(data []byte, target string) bool {
+	for line := range makefileLines(string(data)) {
+		targets, ok := ruleTargets(line)
+		if !ok {
+			continue
+		}
+
+		names := strings.Fields(targets)
+		if len(names) == 1 && names[0] == phonyTarget {
+			_, prereqs, _ := strings.Cut(line, ":")
+			if containsField(prereqs, target) {
+				return true
+			}
+			continue
+		}
+
+		if containsField(targets, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// makefileLines yields the logical lines of a Makefile with backslash
+// continuations joined. Recipe lines, which start with a tab, are skipped.
+func makefileLines(content string) func(yield func(string) bool) {
+	return func(yield func(string) bool) {
+		var logical strings.Builder
+		for raw := range strings.SplitSeq(content, "\n") {
+			raw = strings.TrimSuffix(raw, "\r")
+			if logical.Len() == 0 && strings.HasPrefix(raw, "\t") {
+				continue
+			}
+
+			if cont, ok := strings.CutSuffix(raw, "\\"); ok {
+				logical.WriteString(cont)
+				logical.WriteByte(' ')
+				continue
+			}
+
+			logical.WriteString(raw)
+			line := logical.String()
+			logical.Reset()
+			if !yield(line) {
+				return
+			}
+		}
+		if logical.Len() > 0 {
+			yield(logical.String())
+		}
+	}
+}
+
+// ruleTargets returns the target list of a rule line, the text before the
+// first colon. Comments, variable assignments, and lines without a colon
+// are not rules.
+func ruleTargets(line string) (string, bool) {
+	line, _, _ = strings.Cut(line, "#")
+	targets, rest, ok := strings.Cut(line, ":")
+	if !ok || strings.TrimSpace(targets) == "" {
+		return "", false
+	}
+	// Reject ":=" and "::=" assignments and "VAR = a:b" style values.
+	if strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, ":=") || strings.Contains(targets, "=") {
+		return "", false
+	}
+	return targets, true
+}
+
+// containsField reports whether the whitespace-separated list s contains
+// name.
+func containsField(s, name string) bool {
+	for field := range strings.FieldsSeq(s) {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
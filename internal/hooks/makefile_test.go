@@ -0,0 +1,39 @@
+package hooks_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+func TestMakefileDeclaresTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		makefile string
+		target   string
+		want     bool
+	}{
+		{name: "simple rule", makefile: "lint:\n\tgolangci-lint run\n", target: "lint", want: true},
+		{name: "rule with prerequisites", makefile: "test: build\n\tgo test ./...\n", target: "test", want: true},
+		{name: "one of several targets", makefile: "fmt lint vet:\n\t@echo $@\n", target: "lint", want: true},
+		{name: "double-colon rule", makefile: "lint::\n\t@true\n", target: "lint", want: true},
+		{name: "phony only", makefile: ".PHONY: build lint\n", target: "lint", want: true},
+		{name: "phony continuation", makefile: ".PHONY: build \\\n\tlint\n", target: "lint", want: true},
+		{name: "prerequisite is not a target", makefile: "all: lint\n", target: "lint", want: false},
+		{name: "simple assignment", makefile: "lint := golangci-lint\n", target: "lint", want: false},
+		{name: "recursive assignment with colon", makefile: "lint = a:b\n", target: "lint", want: false},
+		{name: "recipe line", makefile: "all:\n\tlint: nope\n", target: "lint", want: false},
+		{name: "commented out", makefile: "# lint:\n", target: "lint", want: false},
+		{name: "prefix match", makefile: "lint-fix:\n", target: "lint", want: false},
+		{name: "crlf line endings", makefile: "test:\r\n\tgo test\r\n", target: "test", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hooks.MakefileDeclaresTargetForTest([]byte(tt.makefile), tt.target)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package hooks
+
+import "os/exec"
+
+// killProcessGroupOnCancel leaves cancellation to kill only cmd itself on
+// platforms without process groups.
+func killProcessGroupOnCancel(_ *exec.Cmd) {}
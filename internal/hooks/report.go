@@ -0,0 +1,108 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ValidateFormat selects how validate reports results.
+type ValidateFormat string
+
+const (
+	// ValidateFormatText reports failures as a message on stderr only.
+	ValidateFormatText ValidateFormat = "text"
+	// ValidateFormatJSON additionally writes a JSON array of
+	// CommandReport values to stdout.
+	ValidateFormatJSON ValidateFormat = "json"
+	// ValidateFormatGitHub additionally writes GitHub Actions annotations
+	// for failures to stdout; see WriteGitHubAnnotations.
+	ValidateFormatGitHub ValidateFormat = "github"
+)
+
+// Report statuses.
+const (
+	ReportStatusPass = "pass"
+	ReportStatusFail = "fail"
+	ReportStatusSkip = "skip"
+)
+
+// CommandReport is the machine-readable result of one validation command.
+type CommandReport struct {
+	Type     CommandType `json:"type"`
+	Status   string      `json:"status"`
+	Command  string      `json:"command"`
+	ExitCode int         `json:"exit_code"`
+	Output   string      `json:"output"`
+}
+
+// Reports converts the result into one CommandReport per command that ran,
+// in lint, test, extra order. Lint and test are reported as skipped when
+// skipConfig excludes them; commands that were not discovered are omitted.
+func (vr *ValidateResult) Reports(skipConfig *SkipConfig) []CommandReport {
+	reports := make([]CommandReport, 0, len(vr.ExtraResults)+2) //nolint:mnd // lint and test
+
+	skipLint := skipConfig != nil && skipConfig.SkipLint
+	skipTest := skipConfig != nil && skipConfig.SkipTest
+
+	if skipLint {
+		reports = append(reports, skippedReport(CommandTypeLint))
+	} else if vr.LintResult != nil {
+		reports = append(reports, vr.LintResult.report())
+	}
+
+	if skipTest {
+		reports = append(reports, skippedReport(CommandTypeTest))
+	} else if vr.TestResult != nil {
+		reports = append(reports, vr.TestResult.report())
+	}
+
+	for _, extra := range vr.ExtraResults {
+		reports = append(reports, extra.report())
+	}
+
+	return reports
+}
+
+// report converts a single validation result.
+func (r *ValidationResult) report() CommandReport {
+	status := ReportStatusPass
+	if !r.Success {
+		status = ReportStatusFail
+	}
+
+	var command string
+	if r.Command != nil {
+		command = r.Command.String()
+	}
+
+	return CommandReport{
+		Type:     r.Type,
+		Status:   status,
+		Command:  command,
+		ExitCode: r.ExitCode,
+		Output:   r.Output,
+	}
+}
+
+func skippedReport(cmdType CommandType) CommandReport {
+	return CommandReport{
+		Type:     cmdType,
+		Status:   ReportStatusSkip,
+		Command:  "",
+		ExitCode: 0,
+		Output:   "",
+	}
+}
+
+// WriteReports writes reports to w as a JSON array followed by a newline.
+func WriteReports(w io.Writer, reports []CommandReport) error {
+	data, err := json.Marshal(reports)
+	if err != nil {
+		return fmt.Errorf("marshal validate results: %w", err)
+	}
+	if _, err = fmt.Fprintln(w, string(data)); err != nil {
+		return fmt.Errorf("write validate results: %w", err)
+	}
+	return nil
+}
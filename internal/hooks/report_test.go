@@ -0,0 +1,79 @@
+package hooks_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/riddopic/cc-tools/internal/hookcmd"
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+func TestRunValidateHookWithSkip_JSONFormat(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	setupGitMakefileProjectFS(testDeps)
+	testDeps.MockRunner.RunContextFunc = makeDiscoveryAndExecRunner(
+		failOutput("main.go:3: unused variable"),
+		successOutput("ok"),
+	)
+
+	input := &hookcmd.HookInput{
+		HookEventName: "PostToolUse",
+		ToolName:      "Edit",
+		ToolInput:     hooks.MustMarshalJSON(map[string]any{"file_path": "/project/main.go"}),
+	}
+
+	opts := hooks.NewValidateOptions(10, 0)
+	opts.Format = hooks.ValidateFormatJSON
+	exitCode := hooks.RunValidateHookWithSkip(context.Background(), input, opts, testDeps.Dependencies)
+	if exitCode != hooks.ExitCodeShowMessage {
+		t.Errorf("exit code = %d, want %d", exitCode, hooks.ExitCodeShowMessage)
+	}
+	if !strings.Contains(testDeps.MockStderr.String(), "lint failures") {
+		t.Errorf("stderr should keep the human message, got %q", testDeps.MockStderr.String())
+	}
+
+	var reports []map[string]any
+	if err := json.Unmarshal(testDeps.MockStdout.WrittenData, &reports); err != nil {
+		t.Fatalf("stdout is not a JSON array: %v\n%s", err, testDeps.MockStdout.String())
+	}
+	if len(reports) != 2 {
+		t.Fatalf("got %d reports, want 2: %v", len(reports), reports)
+	}
+
+	lint := reports[0]
+	want := map[string]any{
+		"type":    "lint",
+		"status":  "fail",
+		"command": "make lint",
+		"output":  "main.go:3: unused variable",
+	}
+	for key, value := range want {
+		if lint[key] != value {
+			t.Errorf("lint[%q] = %v, want %v", key, lint[key], value)
+		}
+	}
+	if code, ok := lint["exit_code"].(float64); !ok || code == 0 {
+		t.Errorf("lint exit_code = %v, want a non-zero number", lint["exit_code"])
+	}
+	if reports[1]["type"] != "test" || reports[1]["status"] != "pass" {
+		t.Errorf("test report = %v, want a passing test", reports[1])
+	}
+}
+
+func TestValidateResultReports_Skipped(t *testing.T) {
+	result := &hooks.ValidateResult{LintResult: nil, TestResult: nil, ExtraResults: nil, BothPassed: true}
+	skip := &hooks.SkipConfig{SkipLint: true, SkipTest: false, SkipDuringGitOps: false}
+
+	var buf bytes.Buffer
+	if err := hooks.WriteReports(&buf, result.Reports(skip)); err != nil {
+		t.Fatalf("WriteReports() error = %v", err)
+	}
+
+	want := `[{"type":"lint","status":"skip","command":"","exit_code":0,"output":""}]` + "\n"
+	if buf.String() != want {
+		t.Errorf("WriteReports() = %s, want %s", buf.String(), want)
+	}
+}
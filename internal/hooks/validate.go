@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/riddopic/cc-tools/internal/hookcmd"
 	"github.com/riddopic/cc-tools/internal/output"
@@ -16,6 +18,94 @@ import (
 type SkipConfig struct {
 	SkipLint bool
 	SkipTest bool
+	// SkipDuringGitOps skips validation entirely while a rebase or merge
+	// is in progress at the project root.
+	SkipDuringGitOps bool
+	// WarnDirty appends a note to a passing result when the working tree
+	// at the project root has uncommitted changes.
+	WarnDirty bool
+	// CaseInsensitiveSkip matches the built-in skip patterns regardless of
+	// case; see shared.ShouldSkipFileFold.
+	CaseInsensitiveSkip bool
+	// Only, when set to CommandTypeLint or CommandTypeTest, runs just that
+	// phase. The other phase and extra commands are neither discovered nor
+	// run.
+	Only CommandType
+	// NoCooldown runs validation on every invocation: the cooldown lock is
+	// not taken and recently passed commands are not reused.
+	NoCooldown bool
+	// Quiet leaves the timing summary off a passing result.
+	Quiet bool
+	// ProjectRoot, when set, is used as the project root instead of the
+	// nearest directory with a project marker.
+	ProjectRoot string
+}
+
+// ValidateOptions configures one validation run: the skip settings plus
+// the timeout, cooldown, commands, environment, and output format, usually
+// read from a single config load.
+type ValidateOptions struct {
+	SkipConfig
+
+	// Debug writes diagnostics to stderr.
+	Debug bool
+	// TimeoutSecs bounds each lint, test, and extra command.
+	TimeoutSecs int
+	// CooldownSecs is the minimum time between the end of one run and the
+	// start of the next for the same project.
+	CooldownSecs int
+	// RunFrom selects the working directory of discovered commands.
+	RunFrom RunFrom
+	// ExtraCommands are run from the project root after lint and test.
+	ExtraCommands []string
+	// Env is the environment lint, test, and extra commands run with.
+	Env EnvPolicy
+	// Format selects what is written to stdout besides the message.
+	Format ValidateFormat
+}
+
+// NewValidateOptions returns options with the given timeout and cooldown,
+// nothing skipped, commands run from the project root in the default
+// environment, and text output.
+func NewValidateOptions(timeoutSecs, cooldownSecs int) *ValidateOptions {
+	return &ValidateOptions{
+		SkipConfig: SkipConfig{
+			SkipLint:            false,
+			SkipTest:            false,
+			SkipDuringGitOps:    false,
+			WarnDirty:           false,
+			CaseInsensitiveSkip: false,
+			Only:                "",
+			NoCooldown:          false,
+			Quiet:               false,
+			ProjectRoot:         "",
+		},
+		Debug:         false,
+		TimeoutSecs:   timeoutSecs,
+		CooldownSecs:  cooldownSecs,
+		RunFrom:       RunFromProjectRoot,
+		ExtraCommands: nil,
+		Env:           DefaultEnvPolicy(),
+		Format:        ValidateFormatText,
+	}
+}
+
+// cooldownEnabled reports whether the cooldown lock and result cache apply.
+func (sc *SkipConfig) cooldownEnabled() bool {
+	return sc == nil || !sc.NoCooldown
+}
+
+// projectRoot returns the project root override, or "" for none.
+func (sc *SkipConfig) projectRoot() string {
+	if sc == nil {
+		return ""
+	}
+	return sc.ProjectRoot
+}
+
+// excludes reports whether Only names a phase other than cmdType.
+func (sc *SkipConfig) excludes(cmdType CommandType) bool {
+	return sc != nil && sc.Only != "" && sc.Only != cmdType
 }
 
 // ValidationResult represents the result of a single validation (lint or test).
@@ -24,8 +114,14 @@ type ValidationResult struct {
 	Success  bool
 	ExitCode int
 	Message  string
+	Output   string
 	Command  *DiscoveredCommand
 	Error    error
+	// Duration is how long the command ran; zero when Cached.
+	Duration time.Duration
+	// Cached is set when the command was not run because it passed
+	// recently.
+	Cached bool
 }
 
 // ValidateExecutor executes parallel validation commands.
@@ -33,11 +129,13 @@ type ValidateExecutor interface {
 	ExecuteValidations(ctx context.Context, projectRoot, fileDir string) (*ValidateResult, error)
 }
 
-// ValidateResult contains the combined results of lint and test validation.
+// ValidateResult contains the combined results of lint, test, and extra
+// command validation. BothPassed is true only when every command passed.
 type ValidateResult struct {
-	LintResult *ValidationResult
-	TestResult *ValidationResult
-	BothPassed bool
+	LintResult   *ValidationResult
+	TestResult   *ValidationResult
+	ExtraResults []*ValidationResult
+	BothPassed   bool
 }
 
 // FormatMessage returns the appropriate user message based on validation results.
@@ -49,6 +147,54 @@ func (vr *ValidateResult) FormatMessage() string {
 		return formatter.FormatValidationPass()
 	}
 
+	var messages []string
+	if msg := vr.formatLintTestMessage(formatter); msg != "" {
+		messages = append(messages, msg)
+	}
+	for _, extra := range vr.ExtraResults {
+		if extra.Success {
+			continue
+		}
+		messages = append(messages, formatter.FormatBlockingError(
+			"⛔ BLOCKING: Extra command failed (%v). Run 'cd %s && %s'%s to fix",
+			extra.Error, extra.Command.WorkingDir, extra.Command.String(), viaSource(extra.Command)))
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Summary returns a line naming each command that ran, where it was
+// discovered, and its result and duration, such as
+// "✓ lint (make lint) (via Makefile) 1.2s · ✓ test (make test) (via Makefile) 4.8s".
+// Commands reused from the result cache show "cached" for the duration. It
+// returns "" when no command ran.
+func (vr *ValidateResult) Summary() string {
+	results := append([]*ValidationResult{vr.LintResult, vr.TestResult}, vr.ExtraResults...)
+
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		if r == nil || r.Command == nil {
+			continue
+		}
+
+		mark := "✓"
+		if !r.Success {
+			mark = "✗"
+		}
+		timing := fmt.Sprintf("%.1fs", r.Duration.Seconds())
+		if r.Cached {
+			timing = "cached"
+		}
+		parts = append(parts,
+			fmt.Sprintf("%s %s (%s)%s %s", mark, r.Type, r.Command.String(), viaSource(r.Command), timing))
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+// formatLintTestMessage returns the blocking message for lint and test
+// failures, or "" if neither failed.
+func (vr *ValidateResult) formatLintTestMessage(formatter *output.HookFormatter) string {
 	// Determine what failed
 	lintFailed := vr.LintResult != nil && !vr.LintResult.Success
 	testFailed := vr.TestResult != nil && !vr.TestResult.Success
@@ -58,30 +204,40 @@ func (vr *ValidateResult) FormatMessage() string {
 		lintCmd := vr.LintResult.Command.String()
 		testCmd := vr.TestResult.Command.String()
 		return formatter.FormatBlockingError(
-			"⛔ BLOCKING: Lint and test failures. Run 'cd %s && %s' and '%s'",
-			vr.LintResult.Command.WorkingDir, lintCmd, testCmd)
+			"⛔ BLOCKING: Lint and test failures. Run 'cd %s && %s'%s and '%s'%s",
+			vr.LintResult.Command.WorkingDir, lintCmd, viaSource(vr.LintResult.Command),
+			testCmd, viaSource(vr.TestResult.Command))
 	}
 
 	// Only lint failed
 	if lintFailed {
 		cmdStr := vr.LintResult.Command.String()
 		return formatter.FormatBlockingError(
-			"⛔ BLOCKING: Run 'cd %s && %s' to fix lint failures",
-			vr.LintResult.Command.WorkingDir, cmdStr)
+			"⛔ BLOCKING: Run 'cd %s && %s'%s to fix lint failures",
+			vr.LintResult.Command.WorkingDir, cmdStr, viaSource(vr.LintResult.Command))
 	}
 
 	// Only test failed
 	if testFailed {
 		cmdStr := vr.TestResult.Command.String()
 		return formatter.FormatBlockingError(
-			"⛔ BLOCKING: Run 'cd %s && %s' to fix test failures",
-			vr.TestResult.Command.WorkingDir, cmdStr)
+			"⛔ BLOCKING: Run 'cd %s && %s'%s to fix test failures",
+			vr.TestResult.Command.WorkingDir, cmdStr, viaSource(vr.TestResult.Command))
 	}
 
-	// Neither command was found (both nil results)
+	// Neither lint nor test failed
 	return ""
 }
 
+// viaSource returns " (via <source>)" naming where cmd was discovered, such
+// as a Makefile or package.json, or "" when the source is not known.
+func viaSource(cmd *DiscoveredCommand) string {
+	if cmd == nil || cmd.Source == "" {
+		return ""
+	}
+	return " (via " + cmd.Source + ")"
+}
+
 // ParallelValidateExecutor implements ValidateExecutor with parallel execution.
 type ParallelValidateExecutor struct {
 	discovery  *CommandDiscovery
@@ -89,6 +245,8 @@ type ParallelValidateExecutor struct {
 	timeout    int
 	debug      bool
 	skipConfig *SkipConfig
+	cache      *ResultCache
+	extra      []string
 	stderr     io.Writer
 }
 
@@ -107,47 +265,115 @@ func NewParallelValidateExecutor(
 	discovery.SetDebug(debug)
 	return &ParallelValidateExecutor{
 		discovery:  discovery,
-		executor:   NewCommandExecutor(timeout, debug, deps),
+		executor:   NewCommandExecutor(timeout, debug, DefaultEnvPolicy(), deps),
 		timeout:    timeout,
 		debug:      debug,
 		skipConfig: skipConfig,
+		cache:      nil,
+		extra:      nil,
 		stderr:     deps.Stderr,
 	}
 }
 
-// ExecuteValidations discovers and runs lint and test commands in parallel.
+// SetRunFrom selects the working directory mode for discovered commands.
+func (pve *ParallelValidateExecutor) SetRunFrom(runFrom RunFrom) {
+	pve.discovery.SetRunFrom(runFrom)
+}
+
+// SetResultCache enables skipping commands that recently passed unchanged.
+func (pve *ParallelValidateExecutor) SetResultCache(cache *ResultCache) {
+	pve.cache = cache
+}
+
+// SetEnvPolicy sets the environment that lint, test, and extra commands
+// run with.
+func (pve *ParallelValidateExecutor) SetEnvPolicy(env EnvPolicy) {
+	pve.executor.env = env
+}
+
+// SetExtraCommands sets command lines to run from the project root after
+// lint and test. Each entry is split into words using shell quoting rules.
+func (pve *ParallelValidateExecutor) SetExtraCommands(entries []string) {
+	pve.extra = entries
+}
+
+// ExecuteValidations discovers and runs lint and test commands in parallel,
+// then runs any extra commands sequentially from projectRoot.
 func (pve *ParallelValidateExecutor) ExecuteValidations(
 	ctx context.Context,
-	_, fileDir string,
+	projectRoot, fileDir string,
 ) (*ValidateResult, error) {
+	var extraCmds []*DiscoveredCommand
+	if pve.skipConfig == nil || pve.skipConfig.Only == "" {
+		var err error
+		extraCmds, err = BuildExtraCommands(pve.extra, projectRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Discover commands
 	lintCmd, testCmd := pve.discoverCommands(ctx, fileDir)
 
-	// If neither command found, return empty result
-	if lintCmd == nil && testCmd == nil {
+	// If nothing to run, return empty result
+	if lintCmd == nil && testCmd == nil && len(extraCmds) == 0 {
 		return &ValidateResult{
-			LintResult: nil,
-			TestResult: nil,
-			BothPassed: true,
+			LintResult:   nil,
+			TestResult:   nil,
+			ExtraResults: nil,
+			BothPassed:   true,
 		}, nil
 	}
 
 	// Execute commands in parallel
 	result := pve.executeParallel(ctx, lintCmd, testCmd)
 
+	// Extra commands run after lint and test, one at a time
+	result.ExtraResults = pve.executeExtra(ctx, extraCmds)
+
 	// Determine overall success
 	result.BothPassed = pve.checkSuccess(result)
 
 	return result, nil
 }
 
+// executeExtra runs each extra command in order and collects the results.
+func (pve *ParallelValidateExecutor) executeExtra(
+	ctx context.Context,
+	cmds []*DiscoveredCommand,
+) []*ValidationResult {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	results := make([]*ValidationResult, 0, len(cmds))
+	for _, cmd := range cmds {
+		if pve.debug {
+			_, _ = fmt.Fprintf(pve.stderr, "Running extra command: %s\n", cmd.String())
+		}
+		execResult := pve.executor.Execute(ctx, cmd)
+		results = append(results, &ValidationResult{
+			Type:     CommandTypeExtra,
+			Success:  execResult.Success,
+			ExitCode: execResult.ExitCode,
+			Message:  "",
+			Output:   execResult.Stdout + execResult.Stderr,
+			Command:  cmd,
+			Error:    execResult.Error,
+			Duration: execResult.Duration,
+			Cached:   false,
+		})
+	}
+	return results
+}
+
 // discoverCommands discovers lint and test commands based on skip configuration.
 func (pve *ParallelValidateExecutor) discoverCommands(
 	ctx context.Context,
 	fileDir string,
 ) (*DiscoveredCommand, *DiscoveredCommand) {
-	skipLint := pve.skipConfig != nil && pve.skipConfig.SkipLint
-	skipTest := pve.skipConfig != nil && pve.skipConfig.SkipTest
+	skipLint := pve.skipConfig != nil && pve.skipConfig.SkipLint || pve.skipConfig.excludes(CommandTypeLint)
+	skipTest := pve.skipConfig != nil && pve.skipConfig.SkipTest || pve.skipConfig.excludes(CommandTypeTest)
 
 	var lintCmd, testCmd *DiscoveredCommand
 	if !skipLint {
@@ -175,9 +401,10 @@ func (pve *ParallelValidateExecutor) executeParallel(
 ) *ValidateResult {
 	var wg sync.WaitGroup
 	result := &ValidateResult{
-		LintResult: nil,
-		TestResult: nil,
-		BothPassed: false,
+		LintResult:   nil,
+		TestResult:   nil,
+		ExtraResults: nil,
+		BothPassed:   false,
 	}
 
 	skipLint := pve.skipConfig != nil && pve.skipConfig.SkipLint
@@ -201,7 +428,7 @@ func (pve *ParallelValidateExecutor) executeParallel(
 	return result
 }
 
-// checkSuccess determines if both lint and test passed.
+// checkSuccess determines if lint, test, and every extra command passed.
 func (pve *ParallelValidateExecutor) checkSuccess(result *ValidateResult) bool {
 	skipLint := pve.skipConfig != nil && pve.skipConfig.SkipLint
 	skipTest := pve.skipConfig != nil && pve.skipConfig.SkipTest
@@ -209,6 +436,12 @@ func (pve *ParallelValidateExecutor) checkSuccess(result *ValidateResult) bool {
 	lintPassed := result.LintResult == nil || result.LintResult.Success || skipLint
 	testPassed := result.TestResult == nil || result.TestResult.Success || skipTest
 
+	for _, extra := range result.ExtraResults {
+		if !extra.Success {
+			return false
+		}
+	}
+
 	return lintPassed && testPassed
 }
 
@@ -218,29 +451,53 @@ func (pve *ParallelValidateExecutor) executeCommand(
 	cmd *DiscoveredCommand,
 	cmdType CommandType,
 ) *ValidationResult {
+	if pve.cache != nil && pve.cache.RecentlyPassed(cmd) {
+		if pve.debug {
+			_, _ = fmt.Fprintf(pve.stderr, "Skipping %s: %s passed recently\n", cmdType, cmd.String())
+		}
+		return &ValidationResult{
+			Type:     cmdType,
+			Success:  true,
+			ExitCode: 0,
+			Message:  "",
+			Output:   "",
+			Command:  cmd,
+			Error:    nil,
+			Duration: 0,
+			Cached:   true,
+		}
+	}
+
 	execResult := pve.executor.Execute(ctx, cmd)
 
+	if pve.cache != nil {
+		if err := pve.cache.Record(cmd, execResult.Success); err != nil && pve.debug {
+			_, _ = fmt.Fprintf(pve.stderr, "Result cache error: %v\n", err)
+		}
+	}
+
 	return &ValidationResult{
 		Type:     cmdType,
 		Success:  execResult.Success,
 		ExitCode: execResult.ExitCode,
 		Message:  "",
+		Output:   execResult.Stdout + execResult.Stderr,
 		Command:  cmd,
 		Error:    execResult.Error,
+		Duration: execResult.Duration,
+		Cached:   false,
 	}
 }
 
-// RunValidateHookWithSkip is the main entry point for the validate hook with skip configuration.
+// RunValidateHookWithSkip is the main entry point for the validate hook
+// with the given options, which must not be nil.
 func RunValidateHookWithSkip(
 	ctx context.Context,
 	input *hookcmd.HookInput,
-	debug bool,
-	timeoutSecs int,
-	cooldownSecs int,
-	skipConfig *SkipConfig,
+	opts *ValidateOptions,
 	deps *Dependencies,
 ) int {
-	return runValidateHookInternal(ctx, input, debug, timeoutSecs, cooldownSecs, skipConfig, deps)
+	return runValidateHookInternal(ctx, input, opts, deps)
 }
 
 // RunValidateHook is the main entry point for the validate hook.
@@ -252,22 +509,23 @@ func RunValidateHook(
 	cooldownSecs int,
 	deps *Dependencies,
 ) int {
-	return runValidateHookInternal(ctx, input, debug, timeoutSecs, cooldownSecs, nil, deps)
+	opts := NewValidateOptions(timeoutSecs, cooldownSecs)
+	opts.Debug = debug
+	return runValidateHookInternal(ctx, input, opts, deps)
 }
 
 // runValidateHookInternal contains the shared logic for running validation.
 func runValidateHookInternal(
 	ctx context.Context,
 	input *hookcmd.HookInput,
-	debug bool,
-	timeoutSecs int,
-	cooldownSecs int,
-	skipConfig *SkipConfig,
+	opts *ValidateOptions,
 	deps *Dependencies,
 ) int {
 	if deps == nil {
 		deps = NewDefaultDependencies()
 	}
+	debug := opts.Debug
+	skipConfig := &opts.SkipConfig
 
 	// Validate event and get file path
 	filePath, shouldProcess := validateHookEvent(input, debug, deps.Stderr)
@@ -276,13 +534,13 @@ func runValidateHookInternal(
 	}
 
 	// Check if file should be skipped
-	if shared.ShouldSkipFile(filePath) {
+	if shared.ShouldSkipFileFold(filePath, skipConfig.CaseInsensitiveSkip) {
 		return 0
 	}
 
 	// Find project root
 	fileDir := filepath.Dir(filePath)
-	projectRoot, err := shared.FindProjectRoot(fileDir, nil)
+	projectRoot, err := shared.ResolveProjectRoot(skipConfig.projectRoot(), fileDir, nil)
 	if err != nil {
 		if debug {
 			_, _ = fmt.Fprintf(deps.Stderr, "Error finding project root: %v\n", err)
@@ -290,17 +548,38 @@ func runValidateHookInternal(
 		return 0
 	}
 
+	if skipConfig.SkipDuringGitOps {
+		if marker := gitOperationInProgress(deps.FS, projectRoot); marker != "" {
+			if debug {
+				_, _ = fmt.Fprintf(deps.Stderr, "Skipping validation: %s exists, rebase or merge in progress\n", marker)
+			}
+			return 0
+		}
+	}
+
 	// Acquire lock for validate
-	lockMgr := NewLockManager(projectRoot, "validate", cooldownSecs, deps)
-	if !acquireLock(lockMgr, debug, deps.Stderr, nil) {
-		return 0
+	if skipConfig.cooldownEnabled() {
+		lockMgr := NewLockManager(projectRoot, "validate", opts.CooldownSecs, deps)
+		// Discovery, the parallel lint and test run, and each extra command
+		// can each take up to the timeout, so trust the lock at least that long.
+		runLimit := time.Duration(opts.TimeoutSecs*(len(opts.ExtraCommands)+2)) * time.Second
+		lockMgr.SetGrace(max(DefaultLockGrace, runLimit))
+		if !acquireLock(lockMgr, debug, deps.Stderr, nil) {
+			return 0
+		}
+		defer func() {
+			_ = lockMgr.Release()
+		}()
 	}
-	defer func() {
-		_ = lockMgr.Release()
-	}()
 
 	// Execute validations in parallel with optional skip configuration
-	validateExecutor := NewParallelValidateExecutor(projectRoot, timeoutSecs, debug, skipConfig, deps)
+	validateExecutor := NewParallelValidateExecutor(projectRoot, opts.TimeoutSecs, debug, skipConfig, deps)
+	validateExecutor.SetRunFrom(opts.RunFrom)
+	if skipConfig.cooldownEnabled() {
+		validateExecutor.SetResultCache(NewResultCache(projectRoot, filePath, DefaultResultCacheTTL, deps))
+	}
+	validateExecutor.SetExtraCommands(opts.ExtraCommands)
+	validateExecutor.SetEnvPolicy(opts.Env)
 	result, err := validateExecutor.ExecuteValidations(ctx, projectRoot, fileDir)
 	if err != nil {
 		if debug {
@@ -309,8 +588,56 @@ func runValidateHookInternal(
 		return 0
 	}
 
+	// Results from commands killed by cancellation are not real failures,
+	// and nothing is listening for them.
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if debug {
+			_, _ = fmt.Fprintf(deps.Stderr, "Validation cancelled: %v\n", ctxErr)
+		}
+		return 0
+	}
+
+	if recordErr := NewFailureLog(projectRoot, deps).Record(filePath, result); recordErr != nil && debug {
+		_, _ = fmt.Fprintf(deps.Stderr, "Failure log error: %v\n", recordErr)
+	}
+
+	return reportValidateResult(ctx, opts, projectRoot, result, deps)
+}
+
+// reportValidateResult writes result in the format opts selects and the
+// message for Claude to stderr, and returns the hook exit code.
+func reportValidateResult(
+	ctx context.Context,
+	opts *ValidateOptions,
+	projectRoot string,
+	result *ValidateResult,
+	deps *Dependencies,
+) int {
+	switch opts.Format {
+	case ValidateFormatJSON:
+		if writeErr := WriteReports(deps.Stdout, result.Reports(&opts.SkipConfig)); writeErr != nil && opts.Debug {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error writing results: %v\n", writeErr)
+		}
+	case ValidateFormatGitHub:
+		if writeErr := WriteGitHubAnnotations(deps.Stdout, projectRoot, result); writeErr != nil && opts.Debug {
+			_, _ = fmt.Fprintf(deps.Stderr, "Error writing annotations: %v\n", writeErr)
+		}
+	case ValidateFormatText:
+		// The message below is the whole report.
+	}
+
 	// Format and display message
 	message := result.FormatMessage()
+	if result.BothPassed && !opts.Quiet {
+		if summary := result.Summary(); summary != "" {
+			message += "\n" + summary
+		}
+	}
+	if result.BothPassed && opts.WarnDirty {
+		if note := dirtyTreeNote(ctx, deps.Runner, projectRoot); note != "" {
+			message += "\n" + note
+		}
+	}
 	if message != "" {
 		_, _ = fmt.Fprintln(deps.Stderr, message)
 		return ExitCodeShowMessage
@@ -13,41 +13,40 @@ import (
 )
 
 // ValidateWithSkipCheck parses stdinData into a hookcmd.HookInput, checks the
-// skip registry, and runs validation. This is the main entry point for both
+// skip registry, and runs validation with a copy of opts whose SkipLint and
+// SkipTest are set from the registry. This is the main entry point for both
 // cc-tools validate and cc-tools-validate binaries.
 func ValidateWithSkipCheck(
 	ctx context.Context,
 	stdinData []byte,
 	stdout io.Writer,
 	stderr io.Writer,
-	debug bool,
-	timeoutSecs int,
-	cooldownSecs int,
+	base *ValidateOptions,
 ) int {
+	opts := *base
+
 	// Parse stdin into HookInput
 	input, err := hookcmd.ParseInput(bytes.NewReader(stdinData))
 	if err != nil {
-		handleInputError(err, debug, stderr)
+		handleInputError(err, opts.Debug, stderr)
 		return 0
 	}
 
-	// Check if directory should be skipped
-	skipLint, skipTest := checkSkipsFromInput(ctx, input, debug, stderr)
+	// Check if directory should be skipped, and pass that on to the hook
+	opts.SkipLint, opts.SkipTest = checkSkipsFromInput(ctx, input, opts.ProjectRoot, opts.Debug, stderr)
 
 	// If both are skipped, exit silently
-	if skipLint && skipTest {
-		if debug {
+	if opts.SkipLint && opts.SkipTest {
+		if opts.Debug {
 			_, _ = fmt.Fprintf(stderr, "Both lint and test skipped, exiting silently\n")
 		}
+		if opts.Format == ValidateFormatJSON {
+			empty := &ValidateResult{LintResult: nil, TestResult: nil, ExtraResults: nil, BothPassed: true}
+			_ = WriteReports(stdout, empty.Reports(&opts.SkipConfig))
+		}
 		return 0
 	}
 
-	// Pass skip information to the validate hook
-	skipConfig := &SkipConfig{
-		SkipLint: skipLint,
-		SkipTest: skipTest,
-	}
-
 	// Create dependencies
 	defaults := NewDefaultDependencies()
 	deps := &Dependencies{
@@ -59,11 +58,15 @@ func ValidateWithSkipCheck(
 		Clock:   defaults.Clock,
 	}
 
-	return RunValidateHookWithSkip(ctx, input, debug, timeoutSecs, cooldownSecs, skipConfig, deps)
+	return RunValidateHookWithSkip(ctx, input, &opts, deps)
 }
 
-// checkSkipsFromInput checks the skip registry using the parsed HookInput.
-func checkSkipsFromInput(ctx context.Context, input *hookcmd.HookInput, debug bool, stderr io.Writer) (bool, bool) {
+// checkSkipsFromInput checks the skip registry using the parsed HookInput,
+// for projectRoot when it is set and otherwise for the root found from the
+// edited file.
+func checkSkipsFromInput(
+	ctx context.Context, input *hookcmd.HookInput, projectRoot string, debug bool, stderr io.Writer,
+) (bool, bool) {
 	if input == nil {
 		return false, false
 	}
@@ -83,7 +86,7 @@ func checkSkipsFromInput(ctx context.Context, input *hookcmd.HookInput, debug bo
 	fileDir := filepath.Dir(filePath)
 
 	// Find the project root - same as we do for discovering lint/test commands
-	projectRoot, err := shared.FindProjectRoot(fileDir, nil)
+	projectRoot, err := shared.ResolveProjectRoot(projectRoot, fileDir, nil)
 	if err != nil {
 		if debug {
 			_, _ = fmt.Fprintf(stderr, "Failed to find project root: %v\n", err)
@@ -76,11 +76,9 @@ func TestValidateWithSkipCheck_RealIntegration(t *testing.T) {
 			inputJSON, _ := json.Marshal(tt.input)
 			var stdout, stderr bytes.Buffer
 
-			exitCode := hooks.ValidateWithSkipCheck(
-				context.Background(),
-				inputJSON, &stdout, &stderr,
-				tt.debug, 5, 0,
-			)
+			opts := hooks.NewValidateOptions(5, 0)
+			opts.Debug = tt.debug
+			exitCode := hooks.ValidateWithSkipCheck(context.Background(), inputJSON, &stdout, &stderr, opts)
 
 			assertExitCode(t, exitCode, tt.wantExitCode)
 			assertStderrStringsIntegration(t, stderr.String(), tt.wantInStderr)
@@ -207,9 +205,7 @@ func TestValidateWithSkipCheck_ErrorHandling(t *testing.T) {
 			var stdout, stderr bytes.Buffer
 
 			exitCode := hooks.ValidateWithSkipCheck(
-				context.Background(),
-				tt.stdinData, &stdout, &stderr,
-				false, 1, 0,
+				context.Background(), tt.stdinData, &stdout, &stderr, hooks.NewValidateOptions(1, 0),
 			)
 
 			assertExitCode(t, exitCode, tt.wantExitCode)
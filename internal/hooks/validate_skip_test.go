@@ -110,8 +110,9 @@ func TestExecuteValidationsWithSkip(t *testing.T) {
 		{
 			name: "skip lint only runs test",
 			skipConfig: &hooks.SkipConfig{
-				SkipLint: true,
-				SkipTest: false,
+				SkipLint:         true,
+				SkipTest:         false,
+				SkipDuringGitOps: false,
 			},
 			setupMocks: func(td *hooks.TestDependencies) {
 				setupSkipTestProjectFS(td, projectPaths...)
@@ -122,8 +123,9 @@ func TestExecuteValidationsWithSkip(t *testing.T) {
 		{
 			name: "skip test only runs lint",
 			skipConfig: &hooks.SkipConfig{
-				SkipLint: false,
-				SkipTest: true,
+				SkipLint:         false,
+				SkipTest:         true,
+				SkipDuringGitOps: false,
 			},
 			setupMocks: func(td *hooks.TestDependencies) {
 				setupSkipTestProjectFS(td, projectPaths...)
@@ -134,8 +136,9 @@ func TestExecuteValidationsWithSkip(t *testing.T) {
 		{
 			name: "skip both returns success without running",
 			skipConfig: &hooks.SkipConfig{
-				SkipLint: true,
-				SkipTest: true,
+				SkipLint:         true,
+				SkipTest:         true,
+				SkipDuringGitOps: false,
 			},
 			setupMocks: func(td *hooks.TestDependencies) {
 				setupSkipTestProjectFS(td, projectPathsNoMakefile...)
@@ -148,8 +151,9 @@ func TestExecuteValidationsWithSkip(t *testing.T) {
 		{
 			name: "lint fails when not skipped",
 			skipConfig: &hooks.SkipConfig{
-				SkipLint: false,
-				SkipTest: true,
+				SkipLint:         false,
+				SkipTest:         true,
+				SkipDuringGitOps: false,
 			},
 			setupMocks: func(td *hooks.TestDependencies) {
 				setupSkipTestProjectFS(td, projectPaths...)
@@ -180,6 +184,49 @@ func TestExecuteValidationsWithSkip(t *testing.T) {
 	}
 }
 
+func TestExecuteValidationsOnly(t *testing.T) {
+	for _, only := range []hooks.CommandType{hooks.CommandTypeLint, hooks.CommandTypeTest} {
+		t.Run(string(only), func(t *testing.T) {
+			testDeps := hooks.CreateTestDependencies()
+			setupSkipTestProjectFS(testDeps, "/project/.git", "/project/go.mod", "/project/Makefile")
+			setupSkipTestRunner(testDeps, successOutput("Lint Success"), successOutput("Test Success"))
+
+			var targets []string
+			run := testDeps.MockRunner.RunContextFunc
+			testDeps.MockRunner.RunContextFunc = func(
+				ctx context.Context, dir, name string, args ...string,
+			) (*hooks.CommandOutput, error) {
+				targets = append(targets, name+" "+args[len(args)-1])
+				return run(ctx, dir, name, args...)
+			}
+
+			skipConfig := &hooks.SkipConfig{Only: only}
+			executor := hooks.NewParallelValidateExecutor("/project", 10, false, skipConfig, testDeps.Dependencies)
+			executor.SetExtraCommands([]string{"extra-check"})
+
+			result, err := executor.ExecuteValidations(context.Background(), "/project", "/project/src")
+			if err != nil {
+				t.Fatalf("ExecuteValidations() error = %v", err)
+			}
+
+			if (result.LintResult != nil) != (only == hooks.CommandTypeLint) {
+				t.Errorf("LintResult = %+v with --only %s", result.LintResult, only)
+			}
+			if (result.TestResult != nil) != (only == hooks.CommandTypeTest) {
+				t.Errorf("TestResult = %+v with --only %s", result.TestResult, only)
+			}
+			if len(result.ExtraResults) != 0 {
+				t.Errorf("extra commands ran with --only %s: %+v", only, result.ExtraResults)
+			}
+			for _, target := range targets {
+				if target != "make "+string(only) {
+					t.Errorf("ran %q with --only %s", target, only)
+				}
+			}
+		})
+	}
+}
+
 func TestValidateCommandWithSkipRegistry(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -16,6 +16,7 @@ import (
 
 	"github.com/riddopic/cc-tools/internal/hookcmd"
 	"github.com/riddopic/cc-tools/internal/hooks"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 func TestValidateResult_FormatMessage(t *testing.T) {
@@ -28,9 +29,10 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 		{
 			name: "both passed",
 			result: &hooks.ValidateResult{
-				LintResult: nil,
-				TestResult: nil,
-				BothPassed: true,
+				LintResult:   nil,
+				TestResult:   nil,
+				ExtraResults: nil,
+				BothPassed:   true,
 			},
 			wantEmpty:    false,
 			wantContains: []string{"Validations pass"},
@@ -43,6 +45,7 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					Success:  false,
 					ExitCode: 1,
 					Message:  "",
+					Output:   "",
 					Command: &hooks.DiscoveredCommand{
 						Type:       hooks.CommandTypeLint,
 						Command:    "make",
@@ -57,6 +60,7 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					Success:  true,
 					ExitCode: 0,
 					Message:  "",
+					Output:   "",
 					Command: &hooks.DiscoveredCommand{
 						Type:       hooks.CommandTypeTest,
 						Command:    "make",
@@ -66,7 +70,8 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					},
 					Error: nil,
 				},
-				BothPassed: false,
+				ExtraResults: nil,
+				BothPassed:   false,
 			},
 			wantEmpty:    false,
 			wantContains: []string{"BLOCKING", "lint failures", "make lint"},
@@ -79,6 +84,7 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					Success:  true,
 					ExitCode: 0,
 					Message:  "",
+					Output:   "",
 					Command: &hooks.DiscoveredCommand{
 						Type:       hooks.CommandTypeLint,
 						Command:    "make",
@@ -93,6 +99,7 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					Success:  false,
 					ExitCode: 1,
 					Message:  "",
+					Output:   "",
 					Command: &hooks.DiscoveredCommand{
 						Type:       hooks.CommandTypeTest,
 						Command:    "make",
@@ -102,7 +109,8 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					},
 					Error: nil,
 				},
-				BothPassed: false,
+				ExtraResults: nil,
+				BothPassed:   false,
 			},
 			wantEmpty:    false,
 			wantContains: []string{"BLOCKING", "test failures", "make test"},
@@ -115,6 +123,7 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					Success:  false,
 					ExitCode: 1,
 					Message:  "",
+					Output:   "",
 					Command: &hooks.DiscoveredCommand{
 						Type:       hooks.CommandTypeLint,
 						Command:    "make",
@@ -129,6 +138,7 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					Success:  false,
 					ExitCode: 1,
 					Message:  "",
+					Output:   "",
 					Command: &hooks.DiscoveredCommand{
 						Type:       hooks.CommandTypeTest,
 						Command:    "make",
@@ -138,17 +148,46 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					},
 					Error: nil,
 				},
-				BothPassed: false,
+				ExtraResults: nil,
+				BothPassed:   false,
 			},
 			wantEmpty:    false,
 			wantContains: []string{"BLOCKING", "Lint and test failures", "make lint", "make test"},
 		},
 		{
-			name: "no commands found",
+			name: "extra command failed",
 			result: &hooks.ValidateResult{
 				LintResult: nil,
 				TestResult: nil,
-				BothPassed: true,
+				ExtraResults: []*hooks.ValidationResult{
+					{
+						Type:     hooks.CommandTypeExtra,
+						Success:  false,
+						ExitCode: 2,
+						Message:  "",
+						Output:   "",
+						Command: &hooks.DiscoveredCommand{
+							Type:       hooks.CommandTypeExtra,
+							Command:    "make",
+							Args:       []string{"vet-custom"},
+							WorkingDir: "/project",
+							Source:     "validate.extra_commands",
+						},
+						Error: errors.New("exit status 2"),
+					},
+				},
+				BothPassed: false,
+			},
+			wantEmpty:    false,
+			wantContains: []string{"BLOCKING", "Extra command failed", "exit status 2", "make vet-custom"},
+		},
+		{
+			name: "no commands found",
+			result: &hooks.ValidateResult{
+				LintResult:   nil,
+				TestResult:   nil,
+				ExtraResults: nil,
+				BothPassed:   true,
 			},
 			wantEmpty:    false,
 			wantContains: []string{"Validations pass"},
@@ -161,6 +200,7 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					Success:  true,
 					ExitCode: 0,
 					Message:  "",
+					Output:   "",
 					Command: &hooks.DiscoveredCommand{
 						Type:       hooks.CommandTypeLint,
 						Command:    "make",
@@ -170,8 +210,9 @@ func TestValidateResult_FormatMessage(t *testing.T) {
 					},
 					Error: nil,
 				},
-				TestResult: nil,
-				BothPassed: true,
+				TestResult:   nil,
+				ExtraResults: nil,
+				BothPassed:   true,
 			},
 			wantEmpty:    false,
 			wantContains: []string{"Validations pass"},
@@ -318,6 +359,97 @@ func assertValidateResults(t *testing.T, result *hooks.ValidateResult, wantBothP
 	}
 }
 
+func TestValidateResult_Summary(t *testing.T) {
+	command := func(cmdType hooks.CommandType, name string, args ...string) *hooks.DiscoveredCommand {
+		return &hooks.DiscoveredCommand{
+			Type:       cmdType,
+			Command:    name,
+			Args:       args,
+			WorkingDir: "/project",
+			Source:     "",
+		}
+	}
+	result := func(cmd *hooks.DiscoveredCommand, d time.Duration, cached bool) *hooks.ValidationResult {
+		return &hooks.ValidationResult{
+			Type:     cmd.Type,
+			Success:  true,
+			ExitCode: 0,
+			Message:  "",
+			Output:   "",
+			Command:  cmd,
+			Error:    nil,
+			Duration: d,
+			Cached:   cached,
+		}
+	}
+
+	vr := &hooks.ValidateResult{
+		LintResult:   result(command(hooks.CommandTypeLint, "make", "lint"), 1200*time.Millisecond, false),
+		TestResult:   result(command(hooks.CommandTypeTest, "go", "test", "./..."), 4800*time.Millisecond, false),
+		ExtraResults: nil,
+		BothPassed:   true,
+	}
+	assert.Equal(t, "✓ lint (make lint) 1.2s · ✓ test (go test ./...) 4.8s", vr.Summary())
+
+	vr.LintResult = result(command(hooks.CommandTypeLint, "make", "lint"), 0, true)
+	vr.TestResult = nil
+	vr.ExtraResults = []*hooks.ValidationResult{
+		result(command(hooks.CommandTypeExtra, "./scripts/check"), 300*time.Millisecond, false),
+	}
+	assert.Equal(t, "✓ lint (make lint) cached · ✓ extra (./scripts/check) 0.3s", vr.Summary())
+
+	empty := &hooks.ValidateResult{LintResult: nil, TestResult: nil, ExtraResults: nil, BothPassed: true}
+	assert.Empty(t, empty.Summary())
+
+	lint := command(hooks.CommandTypeLint, "make", "lint")
+	lint.Source = "Makefile"
+	vr.LintResult = result(lint, 1200*time.Millisecond, false)
+	vr.ExtraResults = nil
+	assert.Equal(t, "✓ lint (make lint) (via Makefile) 1.2s", vr.Summary())
+}
+
+func TestRunValidateHookWithSkip_ShowsCommandSource(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	setupGitMakefileProjectFS(testDeps)
+	testDeps.MockRunner.RunContextFunc = makeDiscoveryAndExecRunner(
+		failOutput("main.go:3: unused variable"),
+		successOutput("ok"),
+	)
+
+	input := &hookcmd.HookInput{
+		HookEventName: "PostToolUse",
+		ToolName:      "Edit",
+		ToolInput:     hooks.MustMarshalJSON(map[string]any{"file_path": "/project/main.go"}),
+	}
+
+	opts := hooks.NewValidateOptions(10, 0)
+	exitCode := hooks.RunValidateHookWithSkip(context.Background(), input, opts, testDeps.Dependencies)
+	assert.Equal(t, hooks.ExitCodeShowMessage, exitCode)
+	assert.Contains(t, testDeps.MockStderr.String(),
+		"BLOCKING: Run 'cd /project && make lint' (via Makefile) to fix lint failures")
+}
+
+func TestCommandExecutor_Duration(t *testing.T) {
+	testDeps := hooks.CreateTestDependencies()
+	now := time.Unix(1700000000, 0)
+	testDeps.MockClock.NowFunc = func() time.Time { return now }
+	testDeps.MockRunner.RunContextFunc = func(_ context.Context, _, _ string, _ ...string) (*hooks.CommandOutput, error) {
+		now = now.Add(1500 * time.Millisecond)
+		return &hooks.CommandOutput{Stdout: nil, Stderr: nil}, nil
+	}
+
+	executor := hooks.NewCommandExecutor(10, false, hooks.DefaultEnvPolicy(), testDeps.Dependencies)
+	res := executor.Execute(context.Background(), &hooks.DiscoveredCommand{
+		Type:       hooks.CommandTypeLint,
+		Command:    "make",
+		Args:       []string{"lint"},
+		WorkingDir: "/project",
+		Source:     "",
+	})
+	require.True(t, res.Success)
+	assert.Equal(t, 1500*time.Millisecond, res.Duration)
+}
+
 func TestParallelValidateExecutor_ExecuteValidations(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -626,3 +758,45 @@ func TestParallelValidateExecutor_DiscoveryErrorsLoggedInDebugMode(t *testing.T)
 		})
 	}
 }
+
+func TestRunValidateHookWithSkip_ProjectRootOverride(t *testing.T) {
+	// The edited file sits under a go.mod, which would make svc the project
+	// root, but the override points discovery at the Makefile above it.
+	outer := shared.ResolvePath(t.TempDir())
+	svc := filepath.Join(outer, "svc")
+	require.NoError(t, os.MkdirAll(svc, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(svc, "go.mod"), []byte("module svc\n"), 0o600))
+
+	testDeps := hooks.CreateTestDependencies()
+	testDeps.MockFS.StatFunc = func(path string) (os.FileInfo, error) {
+		if path == filepath.Join(outer, "Makefile") {
+			return hooks.NewMockFileInfo("Makefile", 0, 0, time.Time{}, false), nil
+		}
+		return nil, os.ErrNotExist
+	}
+	var makeDirs []string
+	runMake := makeDiscoveryAndExecRunner(successOutput("lint ok"), successOutput("test ok"))
+	testDeps.MockRunner.RunContextFunc = func(
+		ctx context.Context, dir, name string, args ...string,
+	) (*hooks.CommandOutput, error) {
+		makeDirs = append(makeDirs, dir)
+		return runMake(ctx, dir, name, args...)
+	}
+
+	input := &hookcmd.HookInput{
+		HookEventName: "PostToolUse",
+		ToolName:      "Edit",
+		ToolInput:     hooks.MustMarshalJSON(map[string]any{"file_path": filepath.Join(svc, "main.go")}),
+	}
+	skip := &hooks.SkipConfig{NoCooldown: true, ProjectRoot: outer}
+
+	opts := hooks.NewValidateOptions(10, 0)
+	opts.SkipConfig = *skip
+	exitCode := hooks.RunValidateHookWithSkip(context.Background(), input, opts, testDeps.Dependencies)
+	assert.Equal(t, hooks.ExitCodeShowMessage, exitCode)
+	assert.Contains(t, testDeps.MockStderr.String(), "make lint")
+	assert.NotEmpty(t, makeDirs)
+	for _, dir := range makeDirs {
+		assert.Equal(t, outer, dir, "commands are found and run in the overridden root")
+	}
+}
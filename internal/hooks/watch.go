@@ -0,0 +1,138 @@
+package hooks
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// DefaultWatchInterval is how often a Poller rescans its tree.
+const DefaultWatchInterval = time.Second
+
+// Poller detects file changes under a directory by comparing modification
+// times between scans. Directories that ShouldSkipFile matches, such as
+// .git and node_modules, are not descended into.
+type Poller struct {
+	root   string
+	fold   bool
+	mtimes map[string]time.Time
+}
+
+// NewPoller creates a Poller for root. fold matches skip patterns without
+// regard to case, as validate.case_insensitive_skip does.
+func NewPoller(root string, fold bool) *Poller {
+	return &Poller{root: root, fold: fold, mtimes: nil}
+}
+
+// Scan walks the tree and returns the files created or modified since the
+// previous scan, sorted. The first scan records the current state and
+// reports nothing.
+func (p *Poller) Scan() ([]string, error) {
+	seen := make(map[string]time.Time, len(p.mtimes))
+	err := filepath.WalkDir(p.root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			// Files can vanish mid-walk; skip what cannot be read.
+			if d != nil && d.IsDir() && path != p.root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != p.root && shared.ShouldSkipFileFold(path+string(os.PathSeparator), p.fold) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		// A file removed between listing and stat is simply not recorded.
+		if info, infoErr := d.Info(); infoErr == nil {
+			seen[path] = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	first := p.mtimes == nil
+	var changed []string
+	for path, mtime := range seen {
+		if prev, ok := p.mtimes[path]; !first && (!ok || !prev.Equal(mtime)) {
+			changed = append(changed, path)
+		}
+	}
+	p.mtimes = seen
+
+	slices.Sort(changed)
+	return changed, nil
+}
+
+// Run scans the tree every interval until ctx is done and sends each
+// non-empty batch of changed files. Unless Scan has already been called,
+// the starting state is recorded first so only later edits are reported.
+// The channel is closed when Run stops.
+func (p *Poller) Run(ctx context.Context, interval time.Duration) <-chan []string {
+	events := make(chan []string)
+	if p.mtimes == nil {
+		_, _ = p.Scan()
+	}
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			changed, err := p.Scan()
+			if err != nil || len(changed) == 0 {
+				continue
+			}
+
+			select {
+			case events <- changed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// Watch calls run once for each batch of changed files received from events,
+// passing the first file that ShouldSkipFile does not match. Batches made up
+// only of skipped files are ignored. Watch returns when ctx is done or events
+// is closed.
+func Watch(ctx context.Context, events <-chan []string, fold bool, run func(ctx context.Context, path string)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case batch, ok := <-events:
+			if !ok {
+				return
+			}
+			for _, path := range batch {
+				if !shared.ShouldSkipFileFold(path, fold) {
+					run(ctx, path)
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package hooks_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/hooks"
+)
+
+func TestWatch(t *testing.T) {
+	events := make(chan []string, 3)
+	events <- []string{"/project/vendor/lib.go", "/project/main.go", "/project/util.go"}
+	events <- []string{"/project/main_test.go", "/project/node_modules/x.js"}
+	events <- []string{"/project/util.go"}
+	close(events)
+
+	var runs []string
+	hooks.Watch(context.Background(), events, false, func(_ context.Context, path string) {
+		runs = append(runs, path)
+	})
+
+	assert.Equal(t, []string{"/project/main.go", "/project/util.go"}, runs,
+		"one run per batch, for the first file not skipped; all-skipped batches do not run")
+}
+
+func TestWatch_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ran := false
+	hooks.Watch(ctx, make(chan []string), false, func(context.Context, string) { ran = true })
+	assert.False(t, ran)
+}
+
+func TestPoller_Scan(t *testing.T) {
+	root := t.TempDir()
+	mainGo := filepath.Join(root, "main.go")
+	require.NoError(t, os.WriteFile(mainGo, []byte("package main\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "node_modules", "pkg"), 0o750))
+
+	p := hooks.NewPoller(root, false)
+	changed, err := p.Scan()
+	require.NoError(t, err)
+	assert.Empty(t, changed, "the first scan records the starting state")
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(mainGo, later, later))
+	newGo := filepath.Join(root, "new.go")
+	require.NoError(t, os.WriteFile(newGo, []byte("package main\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "node_modules", "pkg", "index.js"), nil, 0o600))
+
+	changed, err = p.Scan()
+	require.NoError(t, err)
+	assert.Equal(t, []string{mainGo, newGo}, changed, "skipped directories are not watched")
+
+	changed, err = p.Scan()
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestPoller_Run(t *testing.T) {
+	root := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := hooks.NewPoller(root, false)
+	events := p.Run(ctx, 10*time.Millisecond)
+
+	path := filepath.Join(root, "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n"), 0o600))
+
+	select {
+	case batch := <-events:
+		assert.Equal(t, []string{path}, batch)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change reported")
+	}
+
+	cancel()
+	for range events {
+	}
+}
@@ -117,3 +117,91 @@ func avgConfidence(instincts []Instinct) float64 {
 
 	return sum / float64(len(instincts))
 }
+
+// GroupSimilar groups instincts whose normalized triggers are within
+// maxDistance edits of each other, linking transitively. Only groups with
+// at least two members are returned, in the order their first member
+// appears in instincts.
+func GroupSimilar(instincts []Instinct, maxDistance int) [][]Instinct {
+	parent := make([]int, len(instincts))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	keys := make([]string, len(instincts))
+	for i, inst := range instincts {
+		keys[i] = strings.Join(NormalizeTrigger(inst.Trigger), " ")
+	}
+
+	for i := range instincts {
+		for j := i + 1; j < len(instincts); j++ {
+			if editDistance(keys[i], keys[j]) <= maxDistance {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	members := make(map[int][]Instinct)
+	var roots []int
+	for i, inst := range instincts {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], inst)
+	}
+
+	var groups [][]Instinct
+	for _, root := range roots {
+		if len(members[root]) > 1 {
+			groups = append(groups, members[root])
+		}
+	}
+
+	return groups
+}
+
+// CombineConfidence merges the confidences of near-duplicate instincts,
+// treating each as independent evidence: 1 - (1-c1)(1-c2)... The result
+// is clamped to [MinConfidence, MaxConfidence].
+func CombineConfidence(instincts []Instinct) float64 {
+	doubt := 1.0
+	for _, inst := range instincts {
+		doubt *= 1 - inst.Confidence
+	}
+
+	return ClampConfidence(1 - doubt)
+}
+
+// editDistance returns the Levenshtein distance between a and b in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
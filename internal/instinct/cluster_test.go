@@ -107,3 +107,44 @@ func TestNormalizeTrigger(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupSimilar(t *testing.T) {
+	instincts := []instinct.Instinct{
+		newClusterInstinct("a", "when writing go tests", "testing", 0.6),
+		newClusterInstinct("b", "Writing Go test", "testing", 0.5),
+		newClusterInstinct("c", "when debugging flaky CI", "debugging", 0.7),
+		newClusterInstinct("d", "writing go tests quickly", "testing", 0.4),
+		newClusterInstinct("e", "debugging flaky ci!", "debugging", 0.5),
+		newClusterInstinct("f", "formatting markdown tables", "docs", 0.8),
+	}
+
+	groups := instinct.GroupSimilar(instincts, 3)
+	assert.Len(t, groups, 2)
+
+	ids := func(group []instinct.Instinct) []string {
+		var out []string
+		for _, inst := range group {
+			out = append(out, inst.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"a", "b"}, ids(groups[0]))
+	assert.Equal(t, []string{"c", "e"}, ids(groups[1]))
+
+	assert.Empty(t, instinct.GroupSimilar(instincts, 0), "distance 0 only groups identical triggers")
+	assert.Len(t, instinct.GroupSimilar(instincts, 10)[0], 3, "a wider distance pulls in d")
+}
+
+func TestCombineConfidence(t *testing.T) {
+	pair := []instinct.Instinct{
+		newClusterInstinct("a", "x", "d", 0.5),
+		newClusterInstinct("b", "x", "d", 0.4),
+	}
+	assert.InDelta(t, 0.7, instinct.CombineConfidence(pair), 0.001)
+
+	strong := []instinct.Instinct{
+		newClusterInstinct("a", "x", "d", 0.8),
+		newClusterInstinct("b", "x", "d", 0.8),
+	}
+	assert.InDelta(t, instinct.MaxConfidence, instinct.CombineConfidence(strong), 0.001)
+}
@@ -3,6 +3,7 @@ package instinct
 import (
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -10,8 +11,20 @@ import (
 	"time"
 )
 
-// ErrNotFound indicates the requested instinct was not found.
-var ErrNotFound = errors.New("instinct not found")
+var (
+	// ErrNotFound indicates the requested instinct was not found.
+	ErrNotFound = errors.New("instinct not found")
+	// ErrExists indicates a personal instinct with the same ID already exists.
+	ErrExists = errors.New("instinct already exists")
+	// ErrStoreFull indicates the personal directory holds the maximum number
+	// of instincts.
+	ErrStoreFull = errors.New("personal instinct limit reached")
+	// ErrInvalidConfidence indicates a confidence outside [0, 1].
+	ErrInvalidConfidence = errors.New("confidence must be between 0 and 1")
+)
+
+// SourceManual marks instincts added by hand rather than learned.
+const SourceManual = "manual"
 
 // FileStore stores instincts as YAML frontmatter files on disk.
 type FileStore struct {
@@ -49,6 +62,37 @@ func (s *FileStore) Save(inst Instinct) error {
 	return nil
 }
 
+// Add saves a new personal instinct. It fails with ErrExists when a personal
+// instinct already uses the ID, and with ErrStoreFull when personalDir
+// already holds maxInstincts instincts. A maxInstincts of zero or less
+// disables the limit.
+func (s *FileStore) Add(inst Instinct, maxInstincts int) error {
+	if err := validateID(inst.ID); err != nil {
+		return fmt.Errorf("add instinct: %w", err)
+	}
+
+	if math.IsNaN(inst.Confidence) || inst.Confidence < 0 || inst.Confidence > 1 {
+		return fmt.Errorf("add instinct %s: %w, got %g", inst.ID, ErrInvalidConfidence, inst.Confidence)
+	}
+
+	if _, err := os.Stat(filepath.Join(s.personalDir, inst.ID+".yaml")); err == nil {
+		return fmt.Errorf("add instinct: %w: %s", ErrExists, inst.ID)
+	}
+
+	if maxInstincts > 0 {
+		personal, err := s.globDir(s.personalDir)
+		if err != nil {
+			return fmt.Errorf("list personal instincts: %w", err)
+		}
+
+		if len(personal) >= maxInstincts {
+			return fmt.Errorf("add instinct %s: %w (%d)", inst.ID, ErrStoreFull, maxInstincts)
+		}
+	}
+
+	return s.Save(inst)
+}
+
 // Get retrieves an instinct by ID, searching personalDir first then
 // inheritedDir. Returns ErrNotFound if the instinct does not exist in
 // either directory.
@@ -1,6 +1,7 @@
 package instinct_test
 
 import (
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
@@ -311,3 +312,51 @@ func testGetValidIDAfterSave(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "valid-id", got.ID)
 }
+
+func TestFileStore_Add(t *testing.T) {
+	t.Run("saves new instinct", func(t *testing.T) {
+		store := instinct.NewFileStore(t.TempDir(), t.TempDir())
+
+		require.NoError(t, store.Add(newTestInstinct("added", "go", 0.6), 10))
+
+		got, err := store.Get("added")
+		require.NoError(t, err)
+		assert.InDelta(t, 0.6, got.Confidence, 0.001)
+	})
+
+	t.Run("rejects duplicate name", func(t *testing.T) {
+		store := instinct.NewFileStore(t.TempDir(), t.TempDir())
+		require.NoError(t, store.Add(newTestInstinct("dup", "go", 0.6), 10))
+
+		err := store.Add(newTestInstinct("dup", "python", 0.4), 10)
+		require.ErrorIs(t, err, instinct.ErrExists)
+
+		got, getErr := store.Get("dup")
+		require.NoError(t, getErr)
+		assert.Equal(t, "go", got.Domain, "existing instinct must not be overwritten")
+	})
+
+	t.Run("enforces capacity", func(t *testing.T) {
+		store := instinct.NewFileStore(t.TempDir(), t.TempDir())
+		require.NoError(t, store.Add(newTestInstinct("one", "go", 0.5), 2))
+		require.NoError(t, store.Add(newTestInstinct("two", "go", 0.5), 2))
+
+		err := store.Add(newTestInstinct("three", "go", 0.5), 2)
+		require.ErrorIs(t, err, instinct.ErrStoreFull)
+
+		_, getErr := store.Get("three")
+		require.ErrorIs(t, getErr, instinct.ErrNotFound)
+
+		require.NoError(t, store.Add(newTestInstinct("three", "go", 0.5), 0), "zero disables the limit")
+	})
+
+	t.Run("rejects confidence outside unit range", func(t *testing.T) {
+		store := instinct.NewFileStore(t.TempDir(), t.TempDir())
+
+		for _, c := range []float64{-0.1, 1.5, math.NaN()} {
+			err := store.Add(newTestInstinct("bad", "go", c), 10)
+			require.ErrorIs(t, err, instinct.ErrInvalidConfidence, "confidence %g", c)
+		}
+		require.NoError(t, store.Add(newTestInstinct("edge", "go", 1), 10))
+	})
+}
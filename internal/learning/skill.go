@@ -0,0 +1,265 @@
+// Package learning turns recorded sessions into learned skill files.
+package learning
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/riddopic/cc-tools/internal/session"
+)
+
+// Sentinel errors returned by Extract.
+var (
+	// ErrSessionTooShort indicates the session has fewer messages than the
+	// minimum length.
+	ErrSessionTooShort = errors.New("session is shorter than the minimum length")
+	// ErrNothingToLearn indicates the session recorded no tools to derive
+	// steps from.
+	ErrNothingToLearn = errors.New("session has no reusable steps")
+)
+
+// skillFileExt is the extension of a learned skill file.
+const skillFileExt = ".md"
+
+// titleFileLimit caps how many file names a derived title lists.
+const titleFileLimit = 3
+
+// Skill is a reusable procedure extracted from a session.
+type Skill struct {
+	// Name is the file stem: the session date and ID.
+	Name        string
+	Title       string
+	Description string
+	SessionID   string
+	Steps       []string
+	Files       []string
+}
+
+// stepPhase groups tools that serve the same purpose into one step.
+type stepPhase struct {
+	tools []string
+	step  string
+}
+
+// stepPhases lists the phases of a session in the order their steps are
+// written. A phase contributes its step when the session used any of its
+// tools; %s is replaced with the tools used.
+var stepPhases = []stepPhase{
+	{tools: []string{"Glob", "Grep", "LS", "Read"}, step: "Survey the existing code with %s before changing it."},
+	{tools: []string{"WebFetch", "WebSearch"}, step: "Check external documentation with %s."},
+	{tools: []string{"Task", "TodoWrite"}, step: "Break the work into tracked steps with %s."},
+	{tools: []string{"Edit", "MultiEdit", "NotebookEdit", "Write"}, step: "Make the changes with %s."},
+	{tools: []string{"Bash"}, step: "Build and run the tests with %s to verify the change."},
+}
+
+// Extract derives a skill from sess. Sessions with fewer than minLength
+// messages return ErrSessionTooShort. The steps come from the tools the
+// session used, grouped into survey, research, planning, editing, and
+// verification phases; tools outside those phases are listed as a step of
+// their own before verification. A session with no recorded tools returns
+// ErrNothingToLearn.
+func Extract(sess *session.Session, minLength int) (*Skill, error) {
+	if sess.MessageCount < minLength {
+		return nil, fmt.Errorf("%w: %d of %d messages", ErrSessionTooShort, sess.MessageCount, minLength)
+	}
+
+	steps := extractSteps(sess.ToolsUsed)
+	if len(steps) == 0 {
+		return nil, ErrNothingToLearn
+	}
+
+	return &Skill{
+		Name:        sess.Date + "-" + sess.ID,
+		Title:       skillTitle(sess),
+		Description: session.Summarize(sess),
+		SessionID:   sess.ID,
+		Steps:       steps,
+		Files:       slices.Sorted(slices.Values(sess.FilesModified)),
+	}, nil
+}
+
+// extractSteps maps the tools a session used to ordered steps.
+func extractSteps(tools []string) []string {
+	phased := make(map[string]bool)
+	for _, phase := range stepPhases {
+		for _, tool := range phase.tools {
+			phased[tool] = true
+		}
+	}
+
+	var steps []string
+	work, verify := stepPhases[:len(stepPhases)-1], stepPhases[len(stepPhases)-1]
+	for _, phase := range work {
+		if step, ok := phase.stepFor(tools); ok {
+			steps = append(steps, step)
+		}
+	}
+
+	var other []string
+	for _, tool := range tools {
+		if !phased[tool] {
+			other = append(other, tool)
+		}
+	}
+	if len(other) > 0 {
+		slices.Sort(other)
+		steps = append(steps, fmt.Sprintf("Use %s as needed.", strings.Join(other, ", ")))
+	}
+
+	if step, ok := verify.stepFor(tools); ok {
+		steps = append(steps, step)
+	}
+
+	return steps
+}
+
+// stepFor returns the phase's step naming which of its tools appear in
+// tools, or false when none do.
+func (p stepPhase) stepFor(tools []string) (string, bool) {
+	var used []string
+	for _, tool := range p.tools {
+		if slices.Contains(tools, tool) {
+			used = append(used, tool)
+		}
+	}
+	if len(used) == 0 {
+		return "", false
+	}
+
+	return fmt.Sprintf(p.step, strings.Join(used, ", ")), true
+}
+
+// skillTitle returns the session title, or one naming the files it
+// modified when the title is the session-end placeholder.
+func skillTitle(sess *session.Session) string {
+	if title := strings.TrimSpace(sess.Title); title != "" && !session.IsDefaultTitle(title) {
+		return title
+	}
+
+	if len(sess.FilesModified) == 0 {
+		return "Workflow from session " + sess.ID
+	}
+
+	names := make([]string, 0, titleFileLimit)
+	for _, f := range sess.FilesModified {
+		if len(names) == titleFileLimit {
+			break
+		}
+		names = append(names, filepath.Base(f))
+	}
+	title := "Changes to " + strings.Join(names, ", ")
+	if rest := len(sess.FilesModified) - len(names); rest > 0 {
+		title += fmt.Sprintf(" and %d more", rest)
+	}
+
+	return title
+}
+
+// Markdown renders the skill file: YAML front matter with the name,
+// description, and source session, then a heading with the title, the
+// description, a numbered list of steps, and the files the session touched.
+func (s *Skill) Markdown() []byte {
+	var b strings.Builder
+
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "name: %s\n", s.Name)
+	fmt.Fprintf(&b, "description: %q\n", s.Description)
+	fmt.Fprintf(&b, "source_session: %s\n", s.SessionID)
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s\n\n", s.Title)
+	if s.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", s.Description)
+	}
+
+	b.WriteString("## Steps\n\n")
+	for i, step := range s.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+
+	if len(s.Files) > 0 {
+		b.WriteString("\n## Files\n\n")
+		for _, f := range s.Files {
+			fmt.Fprintf(&b, "- `%s`\n", f)
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// Write saves the skill as dir/<name>.md, replacing an earlier extraction
+// of the same session, and returns the path written.
+func Write(dir string, skill *Skill) (string, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("create skills directory: %w", err)
+	}
+
+	path := filepath.Join(dir, skill.Name+skillFileExt)
+	if err := os.WriteFile(path, skill.Markdown(), 0o600); err != nil {
+		return "", fmt.Errorf("write skill file: %w", err)
+	}
+
+	return path, nil
+}
+
+// SkillFile describes a skill file found by List.
+type SkillFile struct {
+	File  string `json:"file"`
+	Title string `json:"title"`
+	Size  int64  `json:"size"`
+}
+
+// List returns the skill files in dir, sorted by name. Title is the text of
+// the first level-one heading, or empty when the file has none. A missing
+// directory holds no skills.
+func List(dir string) ([]SkillFile, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+skillFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("glob skill files: %w", err)
+	}
+
+	skills := make([]SkillFile, 0, len(matches))
+	for _, path := range matches {
+		info, statErr := os.Stat(path)
+		if statErr != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		// #nosec G304 -- path comes from a glob of the skills directory.
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return nil, fmt.Errorf("read skill file: %w", readErr)
+		}
+
+		skills = append(skills, SkillFile{
+			File:  filepath.Base(path),
+			Title: firstHeading(string(data)),
+			Size:  info.Size(),
+		})
+	}
+
+	return skills, nil
+}
+
+// firstHeading returns the text of the first "# " line in markdown,
+// skipping YAML front matter, whose comments also start with #.
+func firstHeading(markdown string) string {
+	inFrontMatter := false
+	first := true
+	for line := range strings.Lines(markdown) {
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "---" && (first || inFrontMatter):
+			inFrontMatter = !inFrontMatter
+		case !inFrontMatter && strings.HasPrefix(line, "# "):
+			return strings.TrimSpace(line[len("# "):])
+		}
+		first = false
+	}
+
+	return ""
+}
@@ -0,0 +1,164 @@
+package learning_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/learning"
+	"github.com/riddopic/cc-tools/internal/session"
+)
+
+func learnedSession(messages int) *session.Session {
+	return &session.Session{
+		Version:       "1",
+		ID:            "abc123",
+		Date:          "2026-02-20",
+		Started:       time.Date(2026, 2, 20, 10, 0, 0, 0, time.UTC),
+		Ended:         time.Time{},
+		Title:         "Session 10:00",
+		Summary:       "",
+		ToolsUsed:     []string{"Bash", "Edit", "Grep", "Read", "mcp__jira__get_issue"},
+		FilesModified: []string{"/repo/internal/handler/notify.go", "/repo/cmd/main.go"},
+		MessageCount:  messages,
+	}
+}
+
+func TestExtract_MinLength(t *testing.T) {
+	tests := []struct {
+		name      string
+		messages  int
+		minLength int
+		wantErr   error
+	}{
+		{name: "below minimum", messages: 9, minLength: 10, wantErr: learning.ErrSessionTooShort},
+		{name: "at minimum", messages: 10, minLength: 10, wantErr: nil},
+		{name: "above minimum", messages: 25, minLength: 10, wantErr: nil},
+		{name: "no minimum", messages: 0, minLength: 0, wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skill, err := learning.Extract(learnedSession(tt.messages), tt.minLength)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, skill)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, skill)
+		})
+	}
+}
+
+func TestExtract_Steps(t *testing.T) {
+	skill, err := learning.Extract(learnedSession(12), 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-02-20-abc123", skill.Name)
+	assert.Equal(t, "Changes to notify.go, main.go", skill.Title)
+	assert.Equal(t, []string{
+		"Survey the existing code with Grep, Read before changing it.",
+		"Make the changes with Edit.",
+		"Use mcp__jira__get_issue as needed.",
+		"Build and run the tests with Bash to verify the change.",
+	}, skill.Steps)
+	assert.Equal(t, []string{"/repo/cmd/main.go", "/repo/internal/handler/notify.go"}, skill.Files)
+}
+
+func TestExtract_KeepsSessionTitle(t *testing.T) {
+	sess := learnedSession(12)
+	sess.Title = "Add quiet hours to ntfy"
+
+	skill, err := learning.Extract(sess, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "Add quiet hours to ntfy", skill.Title)
+}
+
+func TestExtract_NothingToLearn(t *testing.T) {
+	sess := learnedSession(12)
+	sess.ToolsUsed = nil
+
+	_, err := learning.Extract(sess, 10)
+	require.ErrorIs(t, err, learning.ErrNothingToLearn)
+}
+
+func TestWrite(t *testing.T) {
+	skill, err := learning.Extract(learnedSession(12), 10)
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "skills", "learned")
+	path, err := learning.Write(dir, skill)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "2026-02-20-abc123.md"), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `---
+name: 2026-02-20-abc123
+description: "Modified notify.go, main.go using Bash, Edit, Grep and 2 more tools over 12 messages."
+source_session: abc123
+---
+
+# Changes to notify.go, main.go
+
+Modified notify.go, main.go using Bash, Edit, Grep and 2 more tools over 12 messages.
+
+## Steps
+
+1. Survey the existing code with Grep, Read before changing it.
+2. Make the changes with Edit.
+3. Use mcp__jira__get_issue as needed.
+4. Build and run the tests with Bash to verify the change.
+
+## Files
+
+- `+"`/repo/cmd/main.go`"+`
+- `+"`/repo/internal/handler/notify.go`"+`
+`, string(data))
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"2026-02-20-abc123.md": "---\nname: x\n# not a heading\n---\n\n# Add quiet hours\n\n## Steps\n",
+		"deploy.md":            "Intro text\n# Deploy the service\r\n",
+		"notes.md":             "no heading here\n",
+		"ignored.txt":          "# Not a skill\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested.md"), 0o750))
+
+	skills, err := learning.List(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []learning.SkillFile{
+		{File: "2026-02-20-abc123.md", Title: "Add quiet hours", Size: int64(len(files["2026-02-20-abc123.md"]))},
+		{File: "deploy.md", Title: "Deploy the service", Size: int64(len(files["deploy.md"]))},
+		{File: "notes.md", Title: "", Size: int64(len(files["notes.md"]))},
+	}, skills)
+}
+
+func TestList_MissingDir(t *testing.T) {
+	skills, err := learning.List(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, skills)
+}
+
+func TestList_ReadsWrittenSkill(t *testing.T) {
+	skill, err := learning.Extract(learnedSession(12), 10)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	_, err = learning.Write(dir, skill)
+	require.NoError(t, err)
+
+	skills, err := learning.List(dir)
+	require.NoError(t, err)
+	require.Len(t, skills, 1)
+	assert.Equal(t, skill.Title, skills[0].Title)
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Server types accepted by Add.
+const (
+	ServerTypeLocal  = "local"
+	ServerTypeRemote = "remote"
+)
+
+// mcpServersKey is the settings file key that holds server definitions.
+const mcpServersKey = "mcpServers"
+
+// ErrInvalidServer is returned when a server definition is missing required
+// fields or sets fields that do not apply to its type.
+var ErrInvalidServer = errors.New("invalid MCP server definition")
+
+// ValidateServer checks that server has the fields its type requires: a
+// command for local servers and a URL for remote ones.
+func ValidateServer(server Server) error {
+	switch server.Type {
+	case ServerTypeLocal:
+		if server.Command == "" {
+			return fmt.Errorf("%w: local servers require a command", ErrInvalidServer)
+		}
+		if server.URL != "" {
+			return fmt.Errorf("%w: local servers do not take a URL", ErrInvalidServer)
+		}
+	case ServerTypeRemote:
+		if server.URL == "" {
+			return fmt.Errorf("%w: remote servers require a URL", ErrInvalidServer)
+		}
+		if server.Command != "" || len(server.Args) > 0 {
+			return fmt.Errorf("%w: remote servers do not take a command or args", ErrInvalidServer)
+		}
+	default:
+		return fmt.Errorf("%w: type must be %q or %q, got %q",
+			ErrInvalidServer, ServerTypeLocal, ServerTypeRemote, server.Type)
+	}
+	return nil
+}
+
+// Add writes a server definition into the settings file, replacing any
+// existing definition with the same name. With local set, the
+// settings.local.json overlay is edited instead. Other settings in the file
+// are preserved.
+func (m *Manager) Add(name string, server Server, local bool) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidServer)
+	}
+	if err := ValidateServer(server); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(server)
+	if err != nil {
+		return fmt.Errorf("marshal server %s: %w", name, err)
+	}
+
+	path := m.writePath(local)
+	return editServers(path, func(servers map[string]json.RawMessage) error {
+		servers[name] = raw
+		return nil
+	})
+}
+
+// Remove deletes a server definition from the settings file, or from the
+// settings.local.json overlay when local is set. The name must match
+// exactly.
+func (m *Manager) Remove(name string, local bool) error {
+	path := m.writePath(local)
+	return editServers(path, func(servers map[string]json.RawMessage) error {
+		if _, ok := servers[name]; !ok {
+			return fmt.Errorf("%w: '%s' is not in %s", ErrServerNotFound, name, path)
+		}
+		delete(servers, name)
+		return nil
+	})
+}
+
+// writePath returns the settings file edited by Add and Remove.
+func (m *Manager) writePath(local bool) string {
+	if local {
+		return m.LocalSettingsPath()
+	}
+	return m.settingsPath
+}
+
+// editServers loads the settings file at path, applies edit to its server
+// map, and writes the result back atomically. A missing file is treated as
+// empty.
+func editServers(path string, edit func(servers map[string]json.RawMessage) error) error {
+	doc := map[string]json.RawMessage{}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// Start from an empty document.
+	case err != nil:
+		return fmt.Errorf("reading settings: %w", err)
+	default:
+		if unmarshalErr := json.Unmarshal(data, &doc); unmarshalErr != nil {
+			return fmt.Errorf("parsing settings %s: %w", path, unmarshalErr)
+		}
+	}
+
+	servers := map[string]json.RawMessage{}
+	if existing, ok := doc[mcpServersKey]; ok {
+		if unmarshalErr := json.Unmarshal(existing, &servers); unmarshalErr != nil {
+			return fmt.Errorf("parsing %s in %s: %w", mcpServersKey, path, unmarshalErr)
+		}
+	}
+
+	if editErr := edit(servers); editErr != nil {
+		return editErr
+	}
+
+	encoded, err := json.Marshal(servers)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", mcpServersKey, err)
+	}
+	doc[mcpServersKey] = encoded
+
+	return writeSettingsFile(path, doc)
+}
+
+// writeSettingsFile writes doc to path through a temporary file and rename
+// so that a failed write never leaves a truncated settings file behind.
+func writeSettingsFile(path string, doc map[string]json.RawMessage) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal settings: %w", err)
+	}
+	data = append(data, '\n')
+
+	if mkdirErr := os.MkdirAll(filepath.Dir(path), 0o750); mkdirErr != nil {
+		return fmt.Errorf("create settings dir: %w", mkdirErr)
+	}
+
+	tempFile := path + ".tmp"
+	if writeErr := os.WriteFile(tempFile, data, 0o600); writeErr != nil {
+		return fmt.Errorf("write temp file: %w", writeErr)
+	}
+
+	if renameErr := os.Rename(tempFile, path); renameErr != nil {
+		_ = os.Remove(tempFile)
+		return fmt.Errorf("rename settings file: %w", renameErr)
+	}
+
+	return nil
+}
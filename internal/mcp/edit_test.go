@@ -0,0 +1,196 @@
+package mcp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/riddopic/cc-tools/internal/mcp"
+)
+
+// readSettingsDoc parses a settings file into its top-level keys.
+func readSettingsDoc(t *testing.T, path string) map[string]json.RawMessage {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	var doc map[string]json.RawMessage
+	if err = json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("parse %s: %v", path, err)
+	}
+	return doc
+}
+
+func TestAddRemove_RoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsPath := filepath.Join(tmpDir, "settings.json")
+	os.WriteFile(settingsPath, []byte(`{"hooks":{"Stop":[]},"mcpServers":{"team":{"command":"team-cmd"}}}`), 0o600)
+
+	m := mcp.NewTestManager(settingsPath, nil, nil)
+
+	local := mcp.Server{
+		Type:    mcp.ServerTypeLocal,
+		Command: "jira-mcp",
+		Args:    []string{"--port", "9000"},
+		Env:     map[string]any{"JIRA_TOKEN": "secret"},
+		URL:     "",
+	}
+	remote := mcp.Server{Type: mcp.ServerTypeRemote, Command: "", Args: nil, Env: nil, URL: "https://mcp.example.com"}
+
+	if err := m.Add("jira", local, false); err != nil {
+		t.Fatalf("Add(jira) error = %v", err)
+	}
+	if err := m.Add("docs", remote, false); err != nil {
+		t.Fatalf("Add(docs) error = %v", err)
+	}
+
+	settings, err := mcp.ManagerLoadSettings(m)
+	if err != nil {
+		t.Fatalf("loadSettings() error = %v", err)
+	}
+	got := settings.MCPServers["jira"]
+	if got.Command != "jira-mcp" || !slices.Equal(got.Args, local.Args) || got.Env["JIRA_TOKEN"] != "secret" {
+		t.Errorf("jira = %+v, want %+v", got, local)
+	}
+	if settings.MCPServers["docs"].URL != remote.URL {
+		t.Errorf("docs URL = %q, want %q", settings.MCPServers["docs"].URL, remote.URL)
+	}
+	if _, ok := readSettingsDoc(t, settingsPath)["hooks"]; !ok {
+		t.Error("Add() dropped unrelated settings keys")
+	}
+
+	if err = m.Remove("jira", false); err != nil {
+		t.Fatalf("Remove(jira) error = %v", err)
+	}
+	settings, err = mcp.ManagerLoadSettings(m)
+	if err != nil {
+		t.Fatalf("loadSettings() error = %v", err)
+	}
+	if _, ok := settings.MCPServers["jira"]; ok {
+		t.Error("jira still present after Remove()")
+	}
+	if _, ok := settings.MCPServers["team"]; !ok {
+		t.Error("Remove() dropped an unrelated server")
+	}
+
+	if _, statErr := os.Stat(settingsPath + ".tmp"); !os.IsNotExist(statErr) {
+		t.Errorf("temporary file left behind: %v", statErr)
+	}
+}
+
+func TestAdd_Local(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsPath := filepath.Join(tmpDir, "settings.json")
+	base := `{"mcpServers":{"team":{"command":"team-cmd"}}}`
+	os.WriteFile(settingsPath, []byte(base), 0o600)
+
+	m := mcp.NewTestManager(settingsPath, nil, nil)
+	server := mcp.Server{Type: mcp.ServerTypeLocal, Command: "mine-cmd", Args: nil, Env: nil, URL: ""}
+
+	if err := m.Add("mine", server, true); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(settingsPath)
+	if string(data) != base {
+		t.Errorf("base settings changed: %s", data)
+	}
+	if _, ok := readSettingsDoc(t, m.LocalSettingsPath())["mcpServers"]; !ok {
+		t.Errorf("%s has no mcpServers", m.LocalSettingsPath())
+	}
+
+	if err := m.Remove("mine", false); !errors.Is(err, mcp.ErrServerNotFound) {
+		t.Errorf("Remove() from base error = %v, want %v", err, mcp.ErrServerNotFound)
+	}
+	if err := m.Remove("mine", true); err != nil {
+		t.Errorf("Remove() from local error = %v", err)
+	}
+}
+
+func TestAdd_CreatesSettingsFile(t *testing.T) {
+	settingsPath := filepath.Join(t.TempDir(), ".claude", "settings.json")
+	m := mcp.NewTestManager(settingsPath, nil, nil)
+
+	server := mcp.Server{Type: mcp.ServerTypeLocal, Command: "cmd", Args: nil, Env: nil, URL: ""}
+	if err := m.Add("new", server, false); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	settings, err := mcp.ManagerLoadSettings(m)
+	if err != nil {
+		t.Fatalf("loadSettings() error = %v", err)
+	}
+	if _, ok := settings.MCPServers["new"]; !ok {
+		t.Error("server not written")
+	}
+}
+
+func TestValidateServer(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  mcp.Server
+		wantErr bool
+	}{
+		{
+			name:    "local with command",
+			server:  mcp.Server{Type: mcp.ServerTypeLocal, Command: "cmd", Args: nil, Env: nil, URL: ""},
+			wantErr: false,
+		},
+		{
+			name:    "local without command",
+			server:  mcp.Server{Type: mcp.ServerTypeLocal, Command: "", Args: nil, Env: nil, URL: ""},
+			wantErr: true,
+		},
+		{
+			name:    "local with url",
+			server:  mcp.Server{Type: mcp.ServerTypeLocal, Command: "cmd", Args: nil, Env: nil, URL: "https://x"},
+			wantErr: true,
+		},
+		{
+			name:    "remote with url",
+			server:  mcp.Server{Type: mcp.ServerTypeRemote, Command: "", Args: nil, Env: nil, URL: "https://x"},
+			wantErr: false,
+		},
+		{
+			name:    "remote without url",
+			server:  mcp.Server{Type: mcp.ServerTypeRemote, Command: "", Args: nil, Env: nil, URL: ""},
+			wantErr: true,
+		},
+		{
+			name:    "remote with command",
+			server:  mcp.Server{Type: mcp.ServerTypeRemote, Command: "cmd", Args: nil, Env: nil, URL: "https://x"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			server:  mcp.Server{Type: "stdio", Command: "cmd", Args: nil, Env: nil, URL: ""},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mcp.ValidateServer(tt.server)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, mcp.ErrInvalidServer) {
+				t.Errorf("ValidateServer() error = %v, want %v", err, mcp.ErrInvalidServer)
+			}
+		})
+	}
+}
+
+func TestRemove_MissingServer(t *testing.T) {
+	settingsPath := filepath.Join(t.TempDir(), "settings.json")
+	os.WriteFile(settingsPath, []byte(`{"mcpServers":{}}`), 0o600)
+
+	m := mcp.NewTestManager(settingsPath, nil, nil)
+	if err := m.Remove("ghost", false); !errors.Is(err, mcp.ErrServerNotFound) {
+		t.Errorf("Remove() error = %v, want %v", err, mcp.ErrServerNotFound)
+	}
+}
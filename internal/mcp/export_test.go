@@ -49,3 +49,8 @@ func ManagerFindMCPByName(m *Manager, settings *Settings, name string) (string,
 func ManagerRemoveMCP(ctx context.Context, m *Manager, name string) error {
 	return m.removeMCP(ctx, name)
 }
+
+// ExpandCommand exports expandCommand for testing.
+func ExpandCommand(command string) (string, error) {
+	return expandCommand(command)
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// logPollInterval is how often a followed log is checked for new data.
+const logPollInterval = 500 * time.Millisecond
+
+// ErrLogNotFound is returned when no log file can be found for a server.
+var ErrLogNotFound = errors.New("MCP server log not found")
+
+// LogPathResolver returns the log file for the named server.
+type LogPathResolver func(name string) (string, error)
+
+// ResolveName returns the settings key for name, using the same flexible
+// matching as Enable.
+func (m *Manager) ResolveName(name string) (string, error) {
+	settings, err := m.loadSettings()
+	if err != nil {
+		return "", err
+	}
+
+	actualName, _, err := m.findMCPByName(settings, name)
+	if err != nil {
+		return "", err
+	}
+	return actualName, nil
+}
+
+// DefaultLogPath looks for the newest log Claude has written for the
+// named server. Claude Code keeps one file per run under
+// <cache>/claude-cli-nodejs/<project>/mcp-logs-<name>/, and Claude Desktop
+// on macOS writes ~/Library/Logs/Claude/mcp-server-<name>.log.
+func DefaultLogPath(name string) (string, error) {
+	var patterns []string
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		patterns = append(patterns, filepath.Join(cacheDir, "claude-cli-nodejs", "*", "mcp-logs-"+name, "*"))
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		patterns = append(patterns, filepath.Join(homeDir, "Library", "Logs", "Claude", "mcp-server-"+name+".log"))
+	}
+
+	return newestMatch(name, patterns)
+}
+
+// newestMatch returns the most recently modified regular file matching any
+// of patterns.
+func newestMatch(name string, patterns []string) (string, error) {
+	var (
+		newest    string
+		newestMod time.Time
+	)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return "", fmt.Errorf("search logs: %w", err)
+		}
+		for _, match := range matches {
+			info, statErr := os.Stat(match)
+			if statErr != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if newest == "" || info.ModTime().After(newestMod) {
+				newest, newestMod = match, info.ModTime()
+			}
+		}
+	}
+
+	if newest == "" {
+		return "", fmt.Errorf("%w: '%s' (searched %s)", ErrLogNotFound, name, strings.Join(patterns, ", "))
+	}
+	return newest, nil
+}
+
+// TailLog writes the last n lines of the file at path to w. With follow
+// set, it keeps writing data appended to the file until ctx is done.
+func TailLog(ctx context.Context, w io.Writer, path string, n int, follow bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open log: %w", err)
+	}
+	defer f.Close()
+
+	lines, err := lastLines(f, n)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err = fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("write log: %w", err)
+		}
+	}
+
+	if !follow {
+		return nil
+	}
+
+	ticker := time.NewTicker(logPollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err = io.Copy(w, f); err != nil {
+			return fmt.Errorf("follow log: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// lastLines reads r to the end and returns its final n lines.
+func lastLines(r io.Reader, n int) ([]string, error) {
+	if n <= 0 {
+		_, err := io.Copy(io.Discard, r)
+		return nil, err
+	}
+
+	ring := make([]string, 0, n)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1<<20)
+	for scanner.Scan() {
+		if len(ring) == n {
+			ring = ring[1:]
+		}
+		ring = append(ring, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read log: %w", err)
+	}
+	return ring, nil
+}
@@ -0,0 +1,114 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/riddopic/cc-tools/internal/mcp"
+)
+
+func TestTailLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	os.WriteFile(path, []byte("one\ntwo\nthree\nfour\n"), 0o600)
+
+	tests := []struct {
+		name  string
+		lines int
+		want  string
+	}{
+		{name: "last lines", lines: 2, want: "three\nfour\n"},
+		{name: "more lines than file", lines: 10, want: "one\ntwo\nthree\nfour\n"},
+		{name: "zero lines", lines: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := mcp.TailLog(context.Background(), &out, path, tt.lines, false); err != nil {
+				t.Fatalf("TailLog() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("TailLog() output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTailLog_FollowStopsOnCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	os.WriteFile(path, []byte("start\n"), 0o600)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	if err := mcp.TailLog(ctx, &out, path, 10, true); err != nil {
+		t.Fatalf("TailLog() error = %v", err)
+	}
+	if out.String() != "start\n" {
+		t.Errorf("TailLog() output = %q, want %q", out.String(), "start\n")
+	}
+}
+
+func TestTailLog_MissingFile(t *testing.T) {
+	err := mcp.TailLog(context.Background(), &bytes.Buffer{}, filepath.Join(t.TempDir(), "missing.log"), 10, false)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("TailLog() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestDefaultLogPath(t *testing.T) {
+	homeDir := t.TempDir()
+	cacheDir := filepath.Join(homeDir, ".cache")
+	t.Setenv("HOME", homeDir)
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+
+	_, err := mcp.DefaultLogPath("jira")
+	if !errors.Is(err, mcp.ErrLogNotFound) {
+		t.Fatalf("DefaultLogPath() error = %v, want %v", err, mcp.ErrLogNotFound)
+	}
+	if !strings.Contains(err.Error(), "mcp-logs-jira") {
+		t.Errorf("DefaultLogPath() error %q should list the searched locations", err)
+	}
+
+	cacheRoot, err := os.UserCacheDir()
+	if err != nil {
+		t.Skipf("no user cache dir: %v", err)
+	}
+	logDir := filepath.Join(cacheRoot, "claude-cli-nodejs", "-home-dev-project", "mcp-logs-jira")
+	os.MkdirAll(logDir, 0o750)
+	want := filepath.Join(logDir, "2026-10-16T09-00-00.txt")
+	os.WriteFile(want, []byte("log\n"), 0o600)
+
+	got, err := mcp.DefaultLogPath("jira")
+	if err != nil {
+		t.Fatalf("DefaultLogPath() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("DefaultLogPath() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveName(t *testing.T) {
+	settingsPath := filepath.Join(t.TempDir(), "settings.json")
+	os.WriteFile(settingsPath, []byte(`{"mcpServers":{"targetprocess":{"command":"tp"}}}`), 0o600)
+
+	m := mcp.NewTestManager(settingsPath, nil, nil)
+
+	got, err := m.ResolveName("target")
+	if err != nil {
+		t.Fatalf("ResolveName() error = %v", err)
+	}
+	if got != "targetprocess" {
+		t.Errorf("ResolveName() = %q, want %q", got, "targetprocess")
+	}
+
+	if _, err = m.ResolveName("ghost"); !errors.Is(err, mcp.ErrServerNotFound) {
+		t.Errorf("ResolveName() error = %v, want %v", err, mcp.ErrServerNotFound)
+	}
+}
@@ -6,38 +6,145 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/riddopic/cc-tools/internal/output"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
-// Server represents an MCP server configuration.
+var (
+	// ErrServerNotFound is returned when no MCP server matches a name.
+	ErrServerNotFound = errors.New("MCP server not found")
+	// ErrAmbiguousName is returned when a name partially matches more than
+	// one MCP server.
+	ErrAmbiguousName = errors.New("ambiguous MCP server name")
+	// ErrClaudeNotFound is returned when the claude CLI cannot be found.
+	ErrClaudeNotFound = errors.New(
+		"claude CLI not found on PATH; install it from https://docs.anthropic.com/en/docs/claude-code",
+	)
+	// ErrCommandRecorded is returned by DryRunExecutor for a command it
+	// recorded instead of building. The Manager treats it as a command that
+	// succeeded with no output.
+	ErrCommandRecorded = errors.New("command recorded, not run")
+)
+
+// localSettingsFile is the per-user overlay merged on top of settings.json.
+const localSettingsFile = "settings.local.json"
+
+// Server represents an MCP server configuration. Local servers run Command
+// with Args; remote servers are reached at URL.
 type Server struct {
-	Type    string         `json:"type"`
-	Command string         `json:"command"`
-	Args    []string       `json:"args"`
-	Env     map[string]any `json:"env"`
+	Type    string         `json:"type"              yaml:"type"`
+	Command string         `json:"command,omitempty" yaml:"command,omitempty"`
+	Args    []string       `json:"args,omitempty"    yaml:"args,omitempty"`
+	Env     map[string]any `json:"env,omitempty"     yaml:"env,omitempty"`
+	URL     string         `json:"url,omitempty"     yaml:"url,omitempty"`
+}
+
+// ServerStatus is the reported state of an enabled MCP server.
+type ServerStatus struct {
+	Name    string
+	Status  string
+	Healthy bool
 }
 
 // Settings represents the structure of ~/.claude/settings.json.
 type Settings struct {
-	MCPServers map[string]Server `json:"mcpServers"`
+	MCPServers map[string]Server `json:"mcpServers" yaml:"mcpServers"`
 }
 
-// CommandExecutor executes external commands.
+// CommandExecutor executes external commands. CommandContext returns a
+// command ready to run, or an error, such as [ErrCommandRecorded], when it
+// has none.
 type CommandExecutor interface {
-	CommandContext(ctx context.Context, name string, arg ...string) *exec.Cmd
+	CommandContext(ctx context.Context, name string, arg ...string) (*exec.Cmd, error)
+	LookPath(file string) (string, error)
 }
 
 // RealCommandExecutor uses os/exec to run commands.
 type RealCommandExecutor struct{}
 
 // CommandContext creates a new command using [exec.CommandContext].
-func (r *RealCommandExecutor) CommandContext(ctx context.Context, name string, arg ...string) *exec.Cmd {
-	return exec.CommandContext(ctx, name, arg...)
+func (r *RealCommandExecutor) CommandContext(ctx context.Context, name string, arg ...string) (*exec.Cmd, error) {
+	return exec.CommandContext(ctx, name, arg...), nil
+}
+
+// LookPath searches for an executable using [exec.LookPath].
+func (r *RealCommandExecutor) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
+
+// DryRunExecutor prints the commands it is asked to run instead of running
+// them. Read-only "mcp list" calls are delegated to the wrapped executor so
+// that DisableAll can still discover which servers it would remove.
+type DryRunExecutor struct {
+	mu       sync.Mutex
+	out      io.Writer
+	next     CommandExecutor
+	commands [][]string
+}
+
+// NewDryRunExecutor creates a DryRunExecutor that writes would-run commands
+// to out. A nil next executor disables list delegation.
+func NewDryRunExecutor(out io.Writer, next CommandExecutor) *DryRunExecutor {
+	return &DryRunExecutor{
+		mu:       sync.Mutex{},
+		out:      out,
+		next:     next,
+		commands: nil,
+	}
+}
+
+// CommandContext records and prints the argv and returns
+// [ErrCommandRecorded], so that no process is started.
+func (d *DryRunExecutor) CommandContext(ctx context.Context, name string, arg ...string) (*exec.Cmd, error) {
+	if d.next != nil && isListCommand(name, arg) {
+		return d.next.CommandContext(ctx, name, arg...) //nolint:wrapcheck // passed through unchanged
+	}
+
+	argv := append([]string{name}, arg...)
+
+	d.mu.Lock()
+	d.commands = append(d.commands, argv)
+	d.mu.Unlock()
+
+	_, _ = fmt.Fprintf(d.out, "would run: %s\n", shared.ShellJoin(argv))
+
+	return nil, ErrCommandRecorded
+}
+
+// LookPath delegates to the wrapped executor so that list delegation fails
+// cleanly when the CLI is missing. Without a wrapped executor every lookup
+// succeeds, since nothing is ever run.
+func (d *DryRunExecutor) LookPath(file string) (string, error) {
+	if d.next != nil {
+		return d.next.LookPath(file)
+	}
+	return file, nil
+}
+
+// Commands returns the argv of every command recorded so far.
+func (d *DryRunExecutor) Commands() [][]string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result := make([][]string, len(d.commands))
+	copy(result, d.commands)
+	return result
+}
+
+// isListCommand reports whether the argv is the read-only "claude mcp list".
+func isListCommand(name string, arg []string) bool {
+	return name == "claude" && len(arg) == 2 && arg[0] == "mcp" && arg[1] == "list"
 }
 
 // Manager handles MCP server operations.
@@ -67,16 +174,67 @@ func NewManagerWithExecutor(out *output.Terminal, executor CommandExecutor) *Man
 	}
 }
 
-// loadSettings reads the settings.json file.
+// SettingsPath returns the path to the Claude settings file that defines MCP
+// servers.
+func (m *Manager) SettingsPath() string {
+	return m.settingsPath
+}
+
+// claudeCommand builds a claude CLI command, failing with [ErrClaudeNotFound]
+// when the binary is not on PATH. Callers treat [ErrCommandRecorded] as
+// success with no output.
+func (m *Manager) claudeCommand(ctx context.Context, arg ...string) (*exec.Cmd, error) {
+	if _, err := m.executor.LookPath("claude"); err != nil {
+		return nil, ErrClaudeNotFound
+	}
+	return m.executor.CommandContext(ctx, "claude", arg...) //nolint:wrapcheck // callers check for ErrCommandRecorded
+}
+
+// LocalSettingsPath returns the path to the per-user settings.local.json
+// overlay that sits next to the settings file.
+func (m *Manager) LocalSettingsPath() string {
+	return filepath.Join(filepath.Dir(m.settingsPath), localSettingsFile)
+}
+
+// loadSettings reads the settings.json file and merges the
+// settings.local.json overlay on top of it. A server defined in both files
+// takes its definition from the overlay. Either file may be missing, but not
+// both.
 func (m *Manager) loadSettings() (*Settings, error) {
-	data, err := os.ReadFile(m.settingsPath)
+	settings, err := readSettings(m.settingsPath)
+	local, localErr := readSettings(m.LocalSettingsPath())
+
+	switch {
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	case localErr != nil && !errors.Is(localErr, fs.ErrNotExist):
+		return nil, localErr
+	case err != nil && localErr != nil:
+		return nil, err
+	case err != nil:
+		return local, nil
+	case localErr != nil:
+		return settings, nil
+	}
+
+	if settings.MCPServers == nil && len(local.MCPServers) > 0 {
+		settings.MCPServers = make(map[string]Server, len(local.MCPServers))
+	}
+	maps.Copy(settings.MCPServers, local.MCPServers)
+
+	return settings, nil
+}
+
+// readSettings reads and parses a single settings file.
+func readSettings(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading settings: %w", err)
 	}
 
 	var settings Settings
 	if unmarshalErr := json.Unmarshal(data, &settings); unmarshalErr != nil {
-		return nil, fmt.Errorf("parsing settings: %w", unmarshalErr)
+		return nil, fmt.Errorf("parsing settings %s: %w", path, unmarshalErr)
 	}
 
 	return &settings, nil
@@ -93,34 +251,49 @@ func (m *Manager) findMCPByName(settings *Settings, name string) (string, *Serve
 		}
 	}
 
-	// Try partial matches
-	for key, server := range settings.MCPServers {
-		lowerKey := strings.ToLower(key)
-
-		// Handle targetprocess variations
-		if name == "target" && lowerKey == "targetprocess" {
-			return key, &server, nil
-		}
-		if name == "target-process" && lowerKey == "targetprocess" {
-			return key, &server, nil
+	// Handle targetprocess variations
+	if name == "target" || name == "target-process" {
+		for key, server := range settings.MCPServers {
+			if strings.ToLower(key) == "targetprocess" {
+				return key, &server, nil
+			}
 		}
+	}
 
-		// Handle partial matches
+	// Try partial matches
+	var matches []string
+	for key := range settings.MCPServers {
+		lowerKey := strings.ToLower(key)
 		if strings.Contains(lowerKey, name) || strings.Contains(name, lowerKey) {
-			return key, &server, nil
+			matches = append(matches, key)
 		}
 	}
 
-	return "", nil, fmt.Errorf("MCP server '%s' not found in settings", name)
+	switch len(matches) {
+	case 0:
+		return "", nil, fmt.Errorf("%w: '%s' is not in settings", ErrServerNotFound, name)
+	case 1:
+		server := settings.MCPServers[matches[0]]
+		return matches[0], &server, nil
+	default:
+		sort.Strings(matches)
+		return "", nil, fmt.Errorf("%w: '%s' matches %s", ErrAmbiguousName, name, strings.Join(matches, ", "))
+	}
 }
 
 // List shows all available MCP servers and their status.
 func (m *Manager) List(ctx context.Context) error {
 	// Just run claude mcp list and let it output directly
-	cmd := m.executor.CommandContext(ctx, "claude", "mcp", "list")
+	cmd, err := m.claudeCommand(ctx, "mcp", "list")
+	if errors.Is(err, ErrCommandRecorded) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err = cmd.Run(); err != nil {
 		return fmt.Errorf("listing MCPs: %w", err)
 	}
 	return nil
@@ -138,41 +311,101 @@ func (m *Manager) Enable(ctx context.Context, name string) error {
 		return err
 	}
 
-	// Build the claude mcp add command
-	// baseEnableArgs accounts for: "mcp", "add", actualName, command
-	const baseEnableArgs = 4
-	args := make([]string, 0, baseEnableArgs+len(server.Args))
-	args = append(args, "mcp", "add")
-
-	// Add the name
-	args = append(args, actualName)
+	_ = m.output.Info("Enabling MCP server '%s'...", actualName)
 
-	// Add the command (expand ~ to home directory)
-	command := server.Command
-	if strings.HasPrefix(command, "~/") {
-		homeDir, _ := os.UserHomeDir()
-		command = filepath.Join(homeDir, command[2:])
+	alreadyEnabled, err := m.addMCP(ctx, actualName, server)
+	if err != nil {
+		return err
+	}
+	if alreadyEnabled {
+		_ = m.output.Warning("MCP server '%s' is already enabled", actualName)
+		return nil
 	}
-	args = append(args, command)
 
-	// Add any additional args
-	args = append(args, server.Args...)
+	_ = m.output.Success("✓ Enabled MCP server '%s'", actualName)
+	return nil
+}
 
-	_ = m.output.Info("Enabling MCP server '%s'...", actualName)
+// addMCP runs the claude mcp add command for server. It reports
+// alreadyEnabled instead of an error when claude says the server exists.
+func (m *Manager) addMCP(ctx context.Context, name string, server *Server) (bool, error) {
+	args, err := addArgs(name, server)
+	if err != nil {
+		return false, err
+	}
 
-	cmd := m.executor.CommandContext(ctx, "claude", args...)
+	cmd, err := m.claudeCommand(ctx, args...)
+	if errors.Is(err, ErrCommandRecorded) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Check if it's already enabled
 		if strings.Contains(string(output), "already exists") {
-			_ = m.output.Warning("MCP server '%s' is already enabled", actualName)
-			return nil
+			return true, nil
 		}
-		return fmt.Errorf("enabling MCP: %w\nOutput: %s", err, output)
+		return false, fmt.Errorf("enabling MCP: %w\nOutput: %s", err, output)
 	}
 
-	_ = m.output.Success("✓ Enabled MCP server '%s'", actualName)
-	return nil
+	return false, nil
+}
+
+// addArgs returns the claude arguments that add server under name. Each Env
+// entry is passed with -e. A remote server is added by URL with its
+// transport; a local one runs its command, after "--" so that its own flags
+// are not read by claude.
+func addArgs(name string, server *Server) ([]string, error) {
+	args := []string{"mcp", "add"}
+	if server.URL != "" {
+		args = append(args, "--transport", transport(server))
+	}
+
+	keys := slices.Sorted(maps.Keys(server.Env))
+	for _, key := range keys {
+		args = append(args, "-e", fmt.Sprintf("%s=%v", key, server.Env[key]))
+	}
+
+	if server.URL != "" {
+		return append(args, name, server.URL), nil
+	}
+
+	// Expand ~ and environment variables in the command.
+	command, err := expandCommand(server.Command)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, name, "--", command)
+	return append(args, server.Args...), nil
+}
+
+// transport returns the claude transport for a server reached by URL:
+// the server's own type when it names one claude knows, otherwise http.
+func transport(server *Server) string {
+	switch server.Type {
+	case "http", "sse":
+		return server.Type
+	default:
+		return "http"
+	}
+}
+
+// expandCommand expands a server command that starts with ~/ or names an
+// environment variable. Other commands, including bare names looked up on
+// PATH and relative paths, are passed to claude as written.
+func expandCommand(command string) (string, error) {
+	if !strings.HasPrefix(command, "~/") && !strings.Contains(command, "$") {
+		return command, nil
+	}
+
+	expanded, err := shared.ExpandPath(command)
+	if err != nil {
+		return "", fmt.Errorf("expand command %q: %w", command, err)
+	}
+
+	return expanded, nil
 }
 
 // Disable removes an MCP server.
@@ -185,10 +418,13 @@ func (m *Manager) Disable(ctx context.Context, name string) error {
 
 	// Try to find the actual name from settings
 	actualName, _, err := m.findMCPByName(settings, name)
-	if err != nil {
+	if errors.Is(err, ErrServerNotFound) {
 		// If not found in settings, try with the provided name anyway
 		return m.removeMCP(ctx, name)
 	}
+	if err != nil {
+		return err
+	}
 
 	return m.removeMCP(ctx, actualName)
 }
@@ -197,7 +433,14 @@ func (m *Manager) Disable(ctx context.Context, name string) error {
 func (m *Manager) removeMCP(ctx context.Context, name string) error {
 	_ = m.output.Info("Disabling MCP server '%s'...", name)
 
-	cmd := m.executor.CommandContext(ctx, "claude", "mcp", "remove", name)
+	cmd, err := m.claudeCommand(ctx, "mcp", "remove", name)
+	if errors.Is(err, ErrCommandRecorded) {
+		_ = m.output.Success("✓ Disabled MCP server '%s'", name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Check if it doesn't exist
@@ -212,55 +455,69 @@ func (m *Manager) removeMCP(ctx context.Context, name string) error {
 	return nil
 }
 
-// EnableAll enables all MCP servers from settings.
-func (m *Manager) EnableAll(ctx context.Context) error {
+// EnableAllSummary reports the outcome of EnableAll for each server, with
+// names in sorted order.
+type EnableAllSummary struct {
+	// Enabled lists servers that were added.
+	Enabled []string
+	// Skipped lists servers claude reported as already enabled.
+	Skipped []string
+	// Failed lists servers that could not be added.
+	Failed []string
+}
+
+// EnableAll enables every MCP server from settings. It keeps going after a
+// failure and skips servers that are already enabled, so it is safe to run
+// again after fixing whatever failed. The error is non-nil only when a
+// server failed or the servers could not be listed.
+func (m *Manager) EnableAll(ctx context.Context) (*EnableAllSummary, error) {
 	settings, err := m.loadSettings()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if _, lookErr := m.executor.LookPath("claude"); lookErr != nil {
+		return nil, ErrClaudeNotFound
 	}
 
 	_ = m.output.Info("Enabling all %d MCP servers...", len(settings.MCPServers))
 
-	hasError := false
-	for name := range settings.MCPServers {
-		if enableErr := m.Enable(ctx, name); enableErr != nil {
-			_ = m.output.Error("Error enabling %s: %v", name, enableErr)
-			hasError = true
+	summary := &EnableAllSummary{Enabled: nil, Skipped: nil, Failed: nil}
+	for _, name := range slices.Sorted(maps.Keys(settings.MCPServers)) {
+		server := settings.MCPServers[name]
+		alreadyEnabled, addErr := m.addMCP(ctx, name, &server)
+		switch {
+		case addErr != nil:
+			_ = m.output.Error("Error enabling %s: %v", name, addErr)
+			summary.Failed = append(summary.Failed, name)
+		case alreadyEnabled:
+			summary.Skipped = append(summary.Skipped, name)
+		default:
+			summary.Enabled = append(summary.Enabled, name)
 		}
 	}
 
-	if hasError {
-		return errors.New("some MCP servers failed to enable")
+	if len(summary.Failed) > 0 {
+		return summary, fmt.Errorf("%d of %d MCP servers failed to enable: %s",
+			len(summary.Failed), len(settings.MCPServers), strings.Join(summary.Failed, ", "))
 	}
 
-	_ = m.output.Success("✓ All MCP servers enabled")
-	return nil
+	return summary, nil
 }
 
 // DisableAll disables all MCP servers.
 func (m *Manager) DisableAll(ctx context.Context) error {
 	// Get current list of enabled MCPs
-	cmd := m.executor.CommandContext(ctx, "claude", "mcp", "list")
-	output, err := cmd.Output()
+	output, err := m.listOutput(ctx)
 	if err != nil {
-		return fmt.Errorf("listing MCPs: %w", err)
+		return err
 	}
 
 	// Parse the output to find enabled MCPs
-	lines := strings.Split(string(output), "\n")
-	mcpNames := []string{}
-
-	for _, line := range lines {
-		// Look for lines with MCP names (they start with a name followed by a colon)
-		if strings.Contains(line, ":") && !strings.Contains(line, "Checking") {
-			parts := strings.Split(line, ":")
-			if len(parts) > 0 {
-				name := strings.TrimSpace(parts[0])
-				if name != "" {
-					mcpNames = append(mcpNames, name)
-				}
-			}
-		}
+	statuses := parseMCPList(string(output))
+	mcpNames := make([]string, 0, len(statuses))
+	for _, st := range statuses {
+		mcpNames = append(mcpNames, st.Name)
 	}
 
 	if len(mcpNames) == 0 {
@@ -285,3 +542,68 @@ func (m *Manager) DisableAll(ctx context.Context) error {
 	_ = m.output.Success("✓ All MCP servers disabled")
 	return nil
 }
+
+// CheckHealth runs "claude mcp list" and returns the status of every
+// enabled MCP server.
+func (m *Manager) CheckHealth(ctx context.Context) ([]ServerStatus, error) {
+	output, err := m.listOutput(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMCPList(string(output)), nil
+}
+
+// listOutput runs "claude mcp list" and returns its stdout, or no output
+// when the executor only recorded the command.
+func (m *Manager) listOutput(ctx context.Context) ([]byte, error) {
+	cmd, err := m.claudeCommand(ctx, "mcp", "list")
+	if errors.Is(err, ErrCommandRecorded) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing MCPs: %w", err)
+	}
+	return output, nil
+}
+
+// parseMCPList extracts server names and statuses from "claude mcp list"
+// output. Server lines start with a name followed by a colon; the status is
+// the text after the last " - " separator, or the whole remainder when there
+// is none.
+func parseMCPList(output string) []ServerStatus {
+	statuses := []ServerStatus{}
+	for line := range strings.SplitSeq(output, "\n") {
+		if !strings.Contains(line, ":") || strings.Contains(line, "Checking") {
+			continue
+		}
+
+		name, rest, _ := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		status := strings.TrimSpace(rest)
+		if idx := strings.LastIndex(status, " - "); idx >= 0 {
+			status = strings.TrimSpace(status[idx+len(" - "):])
+		}
+
+		statuses = append(statuses, ServerStatus{
+			Name:    name,
+			Status:  status,
+			Healthy: isHealthyStatus(status),
+		})
+	}
+	return statuses
+}
+
+// isHealthyStatus reports whether a server status means the server is up.
+func isHealthyStatus(status string) bool {
+	status = strings.TrimSpace(strings.TrimPrefix(status, "✓"))
+	return strings.EqualFold(status, "Running") || strings.EqualFold(status, "Connected")
+}
@@ -4,15 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"slices"
 	"strings"
 	"testing"
 
 	"github.com/riddopic/cc-tools/internal/mcp"
 	"github.com/riddopic/cc-tools/internal/output"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // mockCommandExecutor is a mock implementation of [mcp.CommandExecutor] for testing.
@@ -26,24 +30,29 @@ type mockCommandExecutor struct {
 }
 
 // CommandContext captures the command and returns a mock [exec.Cmd].
-func (m *mockCommandExecutor) CommandContext(_ context.Context, name string, args ...string) *exec.Cmd {
+func (m *mockCommandExecutor) CommandContext(_ context.Context, name string, args ...string) (*exec.Cmd, error) {
 	m.capturedCmd = name
 	m.capturedArgs = args
 
 	if m.commandHandler != nil {
-		return m.commandHandler(name, args)
+		return m.commandHandler(name, args), nil
 	}
 
 	if m.shouldFail {
 		if m.mockOutput != "" {
-			return exec.Command("sh", "-c", "echo '"+m.mockOutput+"' && false")
+			return exec.Command("sh", "-c", "echo '"+m.mockOutput+"' && false"), nil
 		}
-		return exec.Command("false")
+		return exec.Command("false"), nil
 	}
 	if m.mockOutput != "" {
-		return exec.Command("echo", m.mockOutput)
+		return exec.Command("echo", m.mockOutput), nil
 	}
-	return exec.Command("echo", "success")
+	return exec.Command("echo", "success"), nil
+}
+
+// LookPath reports every executable as found.
+func (m *mockCommandExecutor) LookPath(file string) (string, error) {
+	return file, nil
 }
 
 func TestNewManager(t *testing.T) {
@@ -110,12 +119,14 @@ func TestLoadSettings(t *testing.T) {
 							Env: map[string]any{
 								"API_KEY": "test-key",
 							},
+							URL: "",
 						},
 						"jira": {
 							Type:    "local",
 							Command: "python",
 							Args:    []string{"jira_mcp.py"},
 							Env:     nil,
+							URL:     "",
 						},
 					},
 				}
@@ -235,8 +246,8 @@ func assertServerNotFound(t *testing.T, err error) {
 		t.Error("findMCPByName() should return error for not found")
 		return
 	}
-	if !strings.Contains(err.Error(), "not found") {
-		t.Errorf("error should mention 'not found', got %v", err)
+	if !errors.Is(err, mcp.ErrServerNotFound) {
+		t.Errorf("error should wrap ErrServerNotFound, got %v", err)
 	}
 }
 
@@ -248,18 +259,21 @@ func TestFindMCPByName(t *testing.T) {
 				Command: "node",
 				Args:    nil,
 				Env:     nil,
+				URL:     "",
 			},
 			"jira-mcp": {
 				Type:    "local",
 				Command: "python",
 				Args:    nil,
 				Env:     nil,
+				URL:     "",
 			},
 			"GitHub": {
 				Type:    "local",
 				Command: "gh",
 				Args:    nil,
 				Env:     nil,
+				URL:     "",
 			},
 		},
 	}
@@ -348,6 +362,7 @@ func TestEnable(t *testing.T) {
 						Command: "node",
 						Args:    []string{"server.js"},
 						Env:     nil,
+						URL:     "",
 					},
 				},
 			},
@@ -356,7 +371,7 @@ func TestEnable(t *testing.T) {
 			wantErr:    false,
 			checkCommand: func(t *testing.T, _ string, args []string) {
 				t.Helper()
-				expectedArgs := []string{"mcp", "add", "targetprocess", "node", "server.js"}
+				expectedArgs := []string{"mcp", "add", "targetprocess", "--", "node", "server.js"}
 				if !slicesEqual(args, expectedArgs) {
 					t.Errorf("args = %v, want %v", args, expectedArgs)
 				}
@@ -372,6 +387,7 @@ func TestEnable(t *testing.T) {
 						Command: "~/bin/mcp",
 						Args:    []string{"--port", "3000"},
 						Env:     nil,
+						URL:     "",
 					},
 				},
 			},
@@ -380,10 +396,10 @@ func TestEnable(t *testing.T) {
 			wantErr:    false,
 			checkCommand: func(t *testing.T, _ string, args []string) {
 				t.Helper()
-				if len(args) < 4 {
+				if len(args) < 5 {
 					t.Fatalf("Not enough args: %v", args)
 				}
-				commandPath := args[3]
+				commandPath := args[4]
 				if strings.Contains(commandPath, "~") {
 					t.Error("Command path should have ~ expanded")
 				}
@@ -392,6 +408,58 @@ func TestEnable(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:    "adds remote server by url",
+			mcpName: "docs",
+			settings: &mcp.Settings{
+				MCPServers: map[string]mcp.Server{
+					"docs": {
+						Type:    mcp.ServerTypeRemote,
+						Command: "",
+						Args:    nil,
+						Env:     nil,
+						URL:     "https://mcp.example.com",
+					},
+				},
+			},
+			mockOutput: "",
+			shouldFail: false,
+			wantErr:    false,
+			checkCommand: func(t *testing.T, _ string, args []string) {
+				t.Helper()
+				expectedArgs := []string{"mcp", "add", "--transport", "http", "docs", "https://mcp.example.com"}
+				if !slicesEqual(args, expectedArgs) {
+					t.Errorf("args = %v, want %v", args, expectedArgs)
+				}
+			},
+		},
+		{
+			name:    "passes env entries",
+			mcpName: "github",
+			settings: &mcp.Settings{
+				MCPServers: map[string]mcp.Server{
+					"github": {
+						Type:    "local",
+						Command: "gh-mcp",
+						Args:    nil,
+						Env:     map[string]any{"GITHUB_TOKEN": "abc", "DEBUG": "1"},
+						URL:     "",
+					},
+				},
+			},
+			mockOutput: "",
+			shouldFail: false,
+			wantErr:    false,
+			checkCommand: func(t *testing.T, _ string, args []string) {
+				t.Helper()
+				expectedArgs := []string{
+					"mcp", "add", "-e", "DEBUG=1", "-e", "GITHUB_TOKEN=abc", "github", "--", "gh-mcp",
+				}
+				if !slicesEqual(args, expectedArgs) {
+					t.Errorf("args = %v, want %v", args, expectedArgs)
+				}
+			},
+		},
 		{
 			name:    "handles already enabled",
 			mcpName: "jira",
@@ -402,6 +470,7 @@ func TestEnable(t *testing.T) {
 						Command: "jira-mcp",
 						Args:    nil,
 						Env:     nil,
+						URL:     "",
 					},
 				},
 			},
@@ -454,6 +523,29 @@ func TestEnable(t *testing.T) {
 	}
 }
 
+func TestFindMCPByName_Ambiguous(t *testing.T) {
+	settings := &mcp.Settings{
+		MCPServers: map[string]mcp.Server{
+			"jira-cloud":  {Type: "local", Command: "node", Args: nil, Env: nil, URL: ""},
+			"jira-server": {Type: "local", Command: "node", Args: nil, Env: nil, URL: ""},
+		},
+	}
+
+	m := mcp.NewTestManager("", nil, nil)
+	_, _, err := mcp.ManagerFindMCPByName(m, settings, "jira")
+	if !errors.Is(err, mcp.ErrAmbiguousName) {
+		t.Fatalf("findMCPByName() error = %v, want ErrAmbiguousName", err)
+	}
+	if !strings.Contains(err.Error(), "jira-cloud, jira-server") {
+		t.Errorf("error should list the candidates, got %v", err)
+	}
+
+	key, _, err := mcp.ManagerFindMCPByName(m, settings, "jira-server")
+	if err != nil || key != "jira-server" {
+		t.Errorf("exact match should win over ambiguity, got key=%q err=%v", key, err)
+	}
+}
+
 func TestDisable(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -474,6 +566,7 @@ func TestDisable(t *testing.T) {
 						Command: "",
 						Args:    nil,
 						Env:     nil,
+						URL:     "",
 					},
 				},
 			},
@@ -565,32 +658,83 @@ func TestEnableAll(t *testing.T) {
 		settings       *mcp.Settings
 		enabledServers []string
 		failServers    []string
+		existsServers  []string
+		wantSummary    *mcp.EnableAllSummary
 		wantErr        bool
 	}{
 		{
 			name: "enables all servers",
 			settings: &mcp.Settings{
 				MCPServers: map[string]mcp.Server{
-					"server1": {Type: "", Command: "cmd1", Args: nil, Env: nil},
-					"server2": {Type: "", Command: "cmd2", Args: nil, Env: nil},
-					"server3": {Type: "", Command: "cmd3", Args: nil, Env: nil},
+					"server1": {Type: "", Command: "cmd1", Args: nil, Env: nil, URL: ""},
+					"server2": {Type: "", Command: "cmd2", Args: nil, Env: nil, URL: ""},
+					"server3": {Type: "", Command: "cmd3", Args: nil, Env: nil, URL: ""},
 				},
 			},
 			enabledServers: []string{"server1", "server2", "server3"},
 			failServers:    nil,
-			wantErr:        false,
+			existsServers:  nil,
+			wantSummary: &mcp.EnableAllSummary{
+				Enabled: []string{"server1", "server2", "server3"},
+				Skipped: nil,
+				Failed:  nil,
+			},
+			wantErr: false,
 		},
 		{
 			name: "handles partial failures",
 			settings: &mcp.Settings{
 				MCPServers: map[string]mcp.Server{
-					"server1": {Type: "", Command: "cmd1", Args: nil, Env: nil},
-					"server2": {Type: "", Command: "cmd2", Args: nil, Env: nil},
+					"server1": {Type: "", Command: "cmd1", Args: nil, Env: nil, URL: ""},
+					"server2": {Type: "", Command: "cmd2", Args: nil, Env: nil, URL: ""},
 				},
 			},
-			enabledServers: []string{"server1"},
+			enabledServers: []string{"server1", "server2"},
 			failServers:    []string{"server2"},
-			wantErr:        true,
+			existsServers:  nil,
+			wantSummary: &mcp.EnableAllSummary{
+				Enabled: []string{"server1"},
+				Skipped: nil,
+				Failed:  []string{"server2"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "reports mixed outcomes",
+			settings: &mcp.Settings{
+				MCPServers: map[string]mcp.Server{
+					"alpha": {Type: "", Command: "cmd1", Args: nil, Env: nil, URL: ""},
+					"bravo": {Type: "", Command: "cmd2", Args: nil, Env: nil, URL: ""},
+					"delta": {Type: "", Command: "cmd3", Args: nil, Env: nil, URL: ""},
+					"echo":  {Type: "", Command: "cmd4", Args: nil, Env: nil, URL: ""},
+				},
+			},
+			enabledServers: []string{"alpha", "bravo", "delta", "echo"},
+			failServers:    []string{"delta"},
+			existsServers:  []string{"bravo", "echo"},
+			wantSummary: &mcp.EnableAllSummary{
+				Enabled: []string{"alpha"},
+				Skipped: []string{"bravo", "echo"},
+				Failed:  []string{"delta"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "already enabled servers are not an error",
+			settings: &mcp.Settings{
+				MCPServers: map[string]mcp.Server{
+					"server1": {Type: "", Command: "cmd1", Args: nil, Env: nil, URL: ""},
+				},
+			},
+			enabledServers: []string{"server1"},
+			failServers:    nil,
+			existsServers:  []string{"server1"},
+			wantSummary: &mcp.EnableAllSummary{
+				Enabled: nil,
+				Skipped: []string{"server1"},
+				Failed:  nil,
+			},
+			wantErr: false,
 		},
 		{
 			name: "handles empty servers",
@@ -599,6 +743,8 @@ func TestEnableAll(t *testing.T) {
 			},
 			enabledServers: []string{},
 			failServers:    nil,
+			existsServers:  nil,
+			wantSummary:    &mcp.EnableAllSummary{Enabled: nil, Skipped: nil, Failed: nil},
 			wantErr:        false,
 		},
 	}
@@ -613,6 +759,7 @@ func TestEnableAll(t *testing.T) {
 
 			enabledServers := make(map[string]bool)
 			failServers := tt.failServers
+			existsServers := tt.existsServers
 
 			mockExec := &mockCommandExecutor{
 				capturedCmd:  "",
@@ -626,6 +773,9 @@ func TestEnableAll(t *testing.T) {
 						if slices.Contains(failServers, serverName) {
 							return exec.Command("false")
 						}
+						if slices.Contains(existsServers, serverName) {
+							return exec.Command("sh", "-c", "echo \"MCP server $0 already exists\"; exit 1", serverName)
+						}
 					}
 					return exec.Command("echo", "success")
 				},
@@ -634,16 +784,133 @@ func TestEnableAll(t *testing.T) {
 			out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
 			m := mcp.NewTestManager(settingsPath, out, mockExec)
 
-			err := m.EnableAll(context.Background())
+			summary, err := m.EnableAll(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("EnableAll() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if !reflect.DeepEqual(summary, tt.wantSummary) {
+				t.Errorf("EnableAll() summary = %+v, want %+v", summary, tt.wantSummary)
+			}
 
 			assertServersEnabled(t, enabledServers, tt.enabledServers)
 		})
 	}
 }
 
+func TestLoadSettings_LocalOverlay(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		local   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "local wins per server",
+			base:    `{"mcpServers":{"shared":{"command":"base-shared"},"team":{"command":"base-team"}}}`,
+			local:   `{"mcpServers":{"shared":{"command":"local-shared"},"mine":{"command":"local-mine"}}}`,
+			want:    map[string]string{"shared": "local-shared", "team": "base-team", "mine": "local-mine"},
+			wantErr: false,
+		},
+		{
+			name:    "base only",
+			base:    `{"mcpServers":{"team":{"command":"base-team"}}}`,
+			local:   "",
+			want:    map[string]string{"team": "base-team"},
+			wantErr: false,
+		},
+		{
+			name:    "local only",
+			base:    "",
+			local:   `{"mcpServers":{"mine":{"command":"local-mine"}}}`,
+			want:    map[string]string{"mine": "local-mine"},
+			wantErr: false,
+		},
+		{
+			name:    "base without servers",
+			base:    `{}`,
+			local:   `{"mcpServers":{"mine":{"command":"local-mine"}}}`,
+			want:    map[string]string{"mine": "local-mine"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid local",
+			base:    `{"mcpServers":{}}`,
+			local:   `{invalid`,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "neither file",
+			base:    "",
+			local:   "",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			settingsPath := filepath.Join(tmpDir, "settings.json")
+			if tt.base != "" {
+				os.WriteFile(settingsPath, []byte(tt.base), 0o600)
+			}
+			if tt.local != "" {
+				os.WriteFile(filepath.Join(tmpDir, "settings.local.json"), []byte(tt.local), 0o600)
+			}
+
+			m := mcp.NewTestManager(settingsPath, nil, nil)
+			settings, err := mcp.ManagerLoadSettings(m)
+			if !assertSettingsLoaded(t, settings, err, tt.wantErr) {
+				return
+			}
+
+			got := make(map[string]string, len(settings.MCPServers))
+			for name, server := range settings.MCPServers {
+				got[name] = server.Command
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("servers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnableAll_IncludesLocalServers(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsPath := filepath.Join(tmpDir, "settings.json")
+	os.WriteFile(settingsPath, []byte(`{"mcpServers":{"team":{"command":"team-cmd"}}}`), 0o600)
+	os.WriteFile(filepath.Join(tmpDir, "settings.local.json"),
+		[]byte(`{"mcpServers":{"mine":{"command":"mine-cmd"}}}`), 0o600)
+
+	added := make(map[string]string)
+	mockExec := &mockCommandExecutor{
+		capturedCmd:  "",
+		capturedArgs: nil,
+		mockOutput:   "",
+		shouldFail:   false,
+		commandHandler: func(_ string, args []string) *exec.Cmd {
+			if len(args) >= 5 && args[0] == "mcp" && args[1] == "add" {
+				added[args[2]] = args[4]
+			}
+			return exec.Command("echo", "success")
+		},
+	}
+
+	out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+	m := mcp.NewTestManager(settingsPath, out, mockExec)
+
+	if _, err := m.EnableAll(context.Background()); err != nil {
+		t.Fatalf("EnableAll() error = %v", err)
+	}
+
+	want := map[string]string{"team": "team-cmd", "mine": "mine-cmd"}
+	if !maps.Equal(added, want) {
+		t.Errorf("enabled servers = %v, want %v", added, want)
+	}
+}
+
 // assertServersRemoved checks that expected servers were removed and no unexpected ones.
 func assertServersRemoved(t *testing.T, removedServers map[string]bool, expected []string) {
 	t.Helper()
@@ -836,7 +1103,9 @@ func TestRemoveMCP(t *testing.T) {
 }
 
 func TestHomeDirectoryExpansion(t *testing.T) {
-	homeDir, _ := os.UserHomeDir()
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("MCP_TEST_ROOT", "/opt/mcp")
 
 	tests := []struct {
 		name     string
@@ -848,6 +1117,11 @@ func TestHomeDirectoryExpansion(t *testing.T) {
 			command:  "~/bin/mcp",
 			expected: filepath.Join(homeDir, "bin/mcp"),
 		},
+		{
+			name:     "expands environment variable",
+			command:  "${MCP_TEST_ROOT}/bin/mcp",
+			expected: "/opt/mcp/bin/mcp",
+		},
 		{
 			name:     "leaves absolute path unchanged",
 			command:  "/usr/local/bin/mcp",
@@ -858,13 +1132,18 @@ func TestHomeDirectoryExpansion(t *testing.T) {
 			command:  "./bin/mcp",
 			expected: "./bin/mcp",
 		},
+		{
+			name:     "leaves bare command unchanged",
+			command:  "npx",
+			expected: "npx",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			command := tt.command
-			if strings.HasPrefix(command, "~/") {
-				command = filepath.Join(homeDir, command[2:])
+			command, err := mcp.ExpandCommand(tt.command)
+			if err != nil {
+				t.Fatalf("ExpandCommand() error = %v", err)
 			}
 
 			if command != tt.expected {
@@ -872,6 +1151,213 @@ func TestHomeDirectoryExpansion(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("undefined variable", func(t *testing.T) {
+		if _, err := mcp.ExpandCommand("$MCP_TEST_UNDEFINED/bin/mcp"); !errors.Is(err, shared.ErrUndefinedVar) {
+			t.Errorf("ExpandCommand() error = %v, want %v", err, shared.ErrUndefinedVar)
+		}
+	})
+}
+
+func TestDryRunExecutor(t *testing.T) {
+	t.Run("enable prints argv without running claude", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		settingsPath := filepath.Join(tmpDir, "settings.json")
+		settings := &mcp.Settings{
+			MCPServers: map[string]mcp.Server{
+				"jira": {
+					Type:    "stdio",
+					Command: "jira-mcp",
+					Args:    []string{"--project", "My Project"},
+					Env:     nil,
+					URL:     "",
+				},
+			},
+		}
+		data, _ := json.MarshalIndent(settings, "", "  ")
+		os.WriteFile(settingsPath, data, 0o600)
+
+		inner := &mockCommandExecutor{
+			capturedCmd:    "",
+			capturedArgs:   nil,
+			mockOutput:     "",
+			shouldFail:     false,
+			commandHandler: nil,
+		}
+		var printed bytes.Buffer
+		dryRun := mcp.NewDryRunExecutor(&printed, inner)
+
+		out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+		m := mcp.NewTestManager(settingsPath, out, dryRun)
+
+		if err := m.Enable(context.Background(), "jira"); err != nil {
+			t.Fatalf("Enable() error = %v", err)
+		}
+
+		if inner.capturedCmd != "" {
+			t.Errorf("wrapped executor should not run, got %s %v", inner.capturedCmd, inner.capturedArgs)
+		}
+
+		commands := dryRun.Commands()
+		if len(commands) != 1 {
+			t.Fatalf("recorded %d commands, want 1", len(commands))
+		}
+		want := []string{"claude", "mcp", "add", "jira", "--", "jira-mcp", "--project", "My Project"}
+		if !slicesEqual(commands[0], want) {
+			t.Errorf("argv = %v, want %v", commands[0], want)
+		}
+
+		wantLine := "would run: claude mcp add jira -- jira-mcp --project 'My Project'\n"
+		if printed.String() != wantLine {
+			t.Errorf("printed = %q, want %q", printed.String(), wantLine)
+		}
+	})
+
+	t.Run("disable-all delegates list and records removals", func(t *testing.T) {
+		inner := &mockCommandExecutor{
+			capturedCmd:    "",
+			capturedArgs:   nil,
+			mockOutput:     "",
+			shouldFail:     false,
+			commandHandler: nil,
+		}
+		inner.commandHandler = func(_ string, args []string) *exec.Cmd {
+			if slicesEqual(args, []string{"mcp", "list"}) {
+				return exec.Command("echo", "jira: Running\ngithub: Running")
+			}
+			t.Errorf("wrapped executor ran non-list command: %v", args)
+			return exec.Command("false")
+		}
+		var printed bytes.Buffer
+		dryRun := mcp.NewDryRunExecutor(&printed, inner)
+
+		out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+		m := mcp.NewTestManager("", out, dryRun)
+
+		if err := m.DisableAll(context.Background()); err != nil {
+			t.Fatalf("DisableAll() error = %v", err)
+		}
+
+		commands := dryRun.Commands()
+		if len(commands) != 2 {
+			t.Fatalf("recorded %d commands, want 2", len(commands))
+		}
+		if !slicesEqual(commands[0], []string{"claude", "mcp", "remove", "jira"}) {
+			t.Errorf("first argv = %v", commands[0])
+		}
+		if !slicesEqual(commands[1], []string{"claude", "mcp", "remove", "github"}) {
+			t.Errorf("second argv = %v", commands[1])
+		}
+		if !strings.Contains(printed.String(), "would run: claude mcp remove github") {
+			t.Errorf("printed output missing remove command: %q", printed.String())
+		}
+	})
+
+	t.Run("recorded commands start no process", func(t *testing.T) {
+		var printed bytes.Buffer
+		dryRun := mcp.NewDryRunExecutor(&printed, nil)
+
+		cmd, err := dryRun.CommandContext(context.Background(), "claude", "mcp", "remove", "jira")
+		if cmd != nil || !errors.Is(err, mcp.ErrCommandRecorded) {
+			t.Errorf("CommandContext() = %v, %v, want nil, ErrCommandRecorded", cmd, err)
+		}
+
+		out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+		m := mcp.NewTestManager("", out, dryRun)
+		if err := m.List(context.Background()); err != nil {
+			t.Errorf("List() error = %v", err)
+		}
+		if err := m.DisableAll(context.Background()); err != nil {
+			t.Errorf("DisableAll() error = %v", err)
+		}
+		if got := len(dryRun.Commands()); got != 3 {
+			t.Errorf("recorded %d commands, want 3", got)
+		}
+	})
+}
+
+func TestCheckHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		listOutput string
+		shouldFail bool
+		want       []mcp.ServerStatus
+		wantErr    bool
+	}{
+		{
+			name: "mixed running and stopped",
+			listOutput: "Checking MCP servers...\n" +
+				"targetprocess: Running\n" +
+				"github: Stopped",
+			want: []mcp.ServerStatus{
+				{Name: "targetprocess", Status: "Running", Healthy: true},
+				{Name: "github", Status: "Stopped", Healthy: false},
+			},
+			shouldFail: false,
+			wantErr:    false,
+		},
+		{
+			name: "claude cli connection format",
+			listOutput: "Checking MCP server health...\n\n" +
+				"context7: npx -y @upstash/context7-mcp - ✓ Connected\n" +
+				"jira: https://jira.example.com/mcp (HTTP) - ✗ Failed to connect",
+			want: []mcp.ServerStatus{
+				{Name: "context7", Status: "✓ Connected", Healthy: true},
+				{Name: "jira", Status: "✗ Failed to connect", Healthy: false},
+			},
+			shouldFail: false,
+			wantErr:    false,
+		},
+		{
+			name:       "no servers",
+			listOutput: "No MCP servers configured.",
+			shouldFail: false,
+			want:       []mcp.ServerStatus{},
+			wantErr:    false,
+		},
+		{
+			name:       "list command fails",
+			listOutput: "",
+			shouldFail: true,
+			want:       nil,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExec := &mockCommandExecutor{
+				capturedCmd:    "",
+				capturedArgs:   nil,
+				mockOutput:     tt.listOutput,
+				shouldFail:     tt.shouldFail,
+				commandHandler: nil,
+			}
+
+			out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+			m := mcp.NewTestManager("", out, mockExec)
+
+			got, err := m.CheckHealth(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckHealth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !slicesEqual(mockExec.capturedArgs, []string{"mcp", "list"}) {
+				t.Errorf("args = %v, want [mcp list]", mockExec.capturedArgs)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d statuses, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("status[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
 }
 
 // slicesEqual compares two string slices for equality.
@@ -886,3 +1372,72 @@ func slicesEqual(a, b []string) bool {
 	}
 	return true
 }
+
+// missingClaudeExecutor is a mock executor whose PATH lookup always fails.
+type missingClaudeExecutor struct {
+	mockCommandExecutor
+}
+
+// LookPath reports every executable as missing.
+func (m *missingClaudeExecutor) LookPath(file string) (string, error) {
+	return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+}
+
+func TestClaudeNotFound(t *testing.T) {
+	settingsPath := filepath.Join(t.TempDir(), "settings.json")
+	data := `{"mcpServers":{"jira":{"type":"stdio","command":"jira-mcp","args":[],"env":{}}}}`
+	if err := os.WriteFile(settingsPath, []byte(data), 0o600); err != nil {
+		t.Fatalf("writing settings: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		run  func(m *mcp.Manager) error
+	}{
+		{name: "list", run: func(m *mcp.Manager) error { return m.List(context.Background()) }},
+		{name: "enable", run: func(m *mcp.Manager) error { return m.Enable(context.Background(), "jira") }},
+		{name: "disable", run: func(m *mcp.Manager) error { return m.Disable(context.Background(), "jira") }},
+		{
+			name: "enable all",
+			run: func(m *mcp.Manager) error {
+				_, err := m.EnableAll(context.Background())
+				return err
+			},
+		},
+		{name: "disable all", run: func(m *mcp.Manager) error { return m.DisableAll(context.Background()) }},
+		{
+			name: "check health",
+			run: func(m *mcp.Manager) error {
+				_, err := m.CheckHealth(context.Background())
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExec := &missingClaudeExecutor{
+				mockCommandExecutor: mockCommandExecutor{
+					capturedCmd:    "",
+					capturedArgs:   nil,
+					mockOutput:     "",
+					shouldFail:     false,
+					commandHandler: nil,
+				},
+			}
+			out := output.NewTerminal(&bytes.Buffer{}, &bytes.Buffer{})
+			m := mcp.NewTestManager(settingsPath, out, mockExec)
+
+			err := tt.run(m)
+			if !errors.Is(err, mcp.ErrClaudeNotFound) {
+				t.Fatalf("error = %v, want ErrClaudeNotFound", err)
+			}
+			if !strings.Contains(err.Error(), "claude CLI not found on PATH; install it from") {
+				t.Errorf("error = %q, want friendly install hint", err.Error())
+			}
+			if mockExec.capturedCmd != "" {
+				t.Errorf("command %q was run despite missing CLI", mockExec.capturedCmd)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File formats accepted by Import and Export.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// FormatForPath returns the file format implied by the extension of path:
+// YAML for .yaml and .yml files, JSON for everything else.
+func FormatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
+
+// DecodeSettings parses server definitions in the given format. The
+// document has the same shape as settings.json: a top-level mcpServers map.
+func DecodeSettings(data []byte, format string) (*Settings, error) {
+	var settings Settings
+
+	switch format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("parsing YAML: %w", err)
+		}
+	case FormatJSON:
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("parsing JSON: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	return &settings, nil
+}
+
+// EncodeSettings renders server definitions in the given format, indented
+// by two spaces and ending in a newline.
+func EncodeSettings(settings *Settings, format string) ([]byte, error) {
+	doc := Settings{MCPServers: settings.MCPServers}
+	if doc.MCPServers == nil {
+		doc.MCPServers = map[string]Server{}
+	}
+
+	switch format {
+	case FormatYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return nil, fmt.Errorf("encoding YAML: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("encoding YAML: %w", err)
+		}
+		return buf.Bytes(), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding JSON: %w", err)
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// Import reads server definitions from path, in the format given by its
+// extension, and writes them into the settings file, or the
+// settings.local.json overlay when local is set. Servers already defined
+// under the same name are replaced; other settings are preserved. It
+// returns the imported names in sorted order.
+func (m *Manager) Import(path string, local bool) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	settings, err := DecodeSettings(data, FormatForPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	names := slices.Sorted(maps.Keys(settings.MCPServers))
+
+	raw := make(map[string]json.RawMessage, len(names))
+	for _, name := range names {
+		server := settings.MCPServers[name]
+		if server.Command == "" && server.URL == "" {
+			return nil, fmt.Errorf("%w: '%s' in %s has neither a command nor a URL", ErrInvalidServer, name, path)
+		}
+		if raw[name], err = json.Marshal(server); err != nil {
+			return nil, fmt.Errorf("marshal server %s: %w", name, err)
+		}
+	}
+
+	err = editServers(m.writePath(local), func(servers map[string]json.RawMessage) error {
+		maps.Copy(servers, raw)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// Export writes every server definition, with the settings.local.json
+// overlay merged in, to path in the format given by its extension. It
+// returns the exported names in sorted order.
+func (m *Manager) Export(path string) ([]string, error) {
+	settings, err := m.loadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := EncodeSettings(settings, FormatForPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	if writeErr := os.WriteFile(path, data, 0o600); writeErr != nil {
+		return nil, fmt.Errorf("writing %s: %w", path, writeErr)
+	}
+
+	return slices.Sorted(maps.Keys(settings.MCPServers)), nil
+}
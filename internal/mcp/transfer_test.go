@@ -0,0 +1,107 @@
+package mcp_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/riddopic/cc-tools/internal/mcp"
+)
+
+const teamServersYAML = `mcpServers:
+  jira:
+    type: stdio
+    command: jira-mcp
+    args: [--stdio]
+    env:
+      JIRA_TOKEN: secret
+  docs:
+    type: remote
+    url: https://mcp.example.com
+`
+
+func TestFormatForPath(t *testing.T) {
+	tests := map[string]string{
+		"servers.yaml": mcp.FormatYAML,
+		"servers.YML":  mcp.FormatYAML,
+		"servers.json": mcp.FormatJSON,
+		"servers":      mcp.FormatJSON,
+	}
+	for path, want := range tests {
+		if got := mcp.FormatForPath(path); got != want {
+			t.Errorf("FormatForPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestImportExport_YAMLRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsPath := filepath.Join(tmpDir, "settings.json")
+	os.WriteFile(settingsPath, []byte(`{"hooks":{"Stop":[]},"mcpServers":{"team":{"command":"team-cmd"}}}`), 0o600)
+	src := filepath.Join(tmpDir, "team.yaml")
+	os.WriteFile(src, []byte(teamServersYAML), 0o600)
+
+	m := mcp.NewTestManager(settingsPath, nil, nil)
+
+	names, err := m.Import(src, false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if !slices.Equal(names, []string{"docs", "jira"}) {
+		t.Errorf("Import() names = %v, want [docs jira]", names)
+	}
+	if _, ok := readSettingsDoc(t, settingsPath)["hooks"]; !ok {
+		t.Error("Import() dropped the hooks key")
+	}
+
+	dst := filepath.Join(tmpDir, "export.yml")
+	names, err = m.Export(dst)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !slices.Equal(names, []string{"docs", "jira", "team"}) {
+		t.Errorf("Export() names = %v, want [docs jira team]", names)
+	}
+
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	exported, err := mcp.DecodeSettings(data, mcp.FormatYAML)
+	if err != nil {
+		t.Fatalf("export is not YAML: %v\n%s", err, data)
+	}
+	original, err := mcp.DecodeSettings([]byte(teamServersYAML), mcp.FormatYAML)
+	if err != nil {
+		t.Fatalf("DecodeSettings() error = %v", err)
+	}
+	for name, want := range original.MCPServers {
+		if got := exported.MCPServers[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("exported %s = %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsPath := filepath.Join(tmpDir, "settings.json")
+	m := mcp.NewTestManager(settingsPath, nil, nil)
+
+	bad := filepath.Join(tmpDir, "bad.yaml")
+	os.WriteFile(bad, []byte("mcpServers: [not, a, map]\n"), 0o600)
+	if _, err := m.Import(bad, false); err == nil {
+		t.Error("Import() of malformed YAML succeeded")
+	}
+
+	empty := filepath.Join(tmpDir, "empty.yml")
+	os.WriteFile(empty, []byte("mcpServers:\n  ghost:\n    type: stdio\n"), 0o600)
+	if _, err := m.Import(empty, false); !errors.Is(err, mcp.ErrInvalidServer) {
+		t.Errorf("Import() error = %v, want ErrInvalidServer", err)
+	}
+	if _, err := os.Stat(settingsPath); err == nil {
+		t.Error("a failed import wrote the settings file")
+	}
+}
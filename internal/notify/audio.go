@@ -7,7 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
+
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // AudioPlayer abstracts audio file playback for testing.
@@ -18,35 +19,40 @@ type AudioPlayer interface {
 // Audio manages audio notification playback.
 type Audio struct {
 	player     AudioPlayer
-	dir        string
+	dirs       []string
 	quietHours QuietHours
-	nowFunc    func() time.Time
+	clock      shared.Clock
 }
 
-// NewAudio creates a new Audio notifier.
-func NewAudio(player AudioPlayer, dir string, qh QuietHours, nowFunc func() time.Time) *Audio {
-	if nowFunc == nil {
-		nowFunc = time.Now
+// NewAudio creates a new Audio notifier that plays files from dirs. A nil
+// clock uses the system clock.
+func NewAudio(player AudioPlayer, dirs []string, qh QuietHours, clock shared.Clock) *Audio {
+	if clock == nil {
+		clock = shared.RealClock{}
 	}
 
 	return &Audio{
 		player:     player,
-		dir:        dir,
+		dirs:       dirs,
 		quietHours: qh,
-		nowFunc:    nowFunc,
+		clock:      clock,
 	}
 }
 
-// PlayRandom plays a random MP3 file from the audio directory.
-// Returns nil if quiet hours are active or no MP3 files are found.
+// PlayRandom plays an MP3 file picked uniformly from all the files in the
+// audio directories, so a directory with more files is picked from more
+// often. A file name found in several directories counts once, from the
+// first. Directories that cannot be read are skipped; an error is returned
+// only when none can. Returns nil if quiet hours are active or no MP3 files
+// are found.
 func (a *Audio) PlayRandom() error {
-	if a.quietHours.IsActive(a.nowFunc()) {
+	if a.quietHours.IsActive(a.clock.Now()) {
 		return nil
 	}
 
-	files, err := listMP3Files(a.dir)
+	files, err := listAllMP3Files(a.dirs)
 	if err != nil {
-		return fmt.Errorf("list mp3 files in %s: %w", a.dir, err)
+		return err
 	}
 
 	if len(files) == 0 {
@@ -68,6 +74,40 @@ func (a *Audio) PlayRandom() error {
 	return nil
 }
 
+// listAllMP3Files returns the MP3 files in dirs, skipping names already
+// found in an earlier directory.
+func listAllMP3Files(dirs []string) ([]string, error) {
+	var files []string
+	var firstErr error
+	readable := 0
+	seen := make(map[string]bool)
+
+	for _, dir := range dirs {
+		dirFiles, err := listMP3Files(dir)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("list mp3 files in %s: %w", dir, err)
+			}
+			continue
+		}
+		readable++
+
+		for _, file := range dirFiles {
+			name := filepath.Base(file)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			files = append(files, file)
+		}
+	}
+
+	if readable == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return files, nil
+}
+
 func listMP3Files(dir string) ([]string, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
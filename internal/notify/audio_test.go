@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/riddopic/cc-tools/internal/notify"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 type mockPlayer struct {
@@ -26,14 +27,14 @@ func (m *mockPlayer) Play(fp string) error {
 }
 
 func TestAudioPlayRandom(t *testing.T) {
-	noon := func() time.Time { return time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local) }
-	lateNight := func() time.Time { return time.Date(2026, 1, 1, 23, 0, 0, 0, time.Local) }
+	noon := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	lateNight := time.Date(2026, 1, 1, 23, 0, 0, 0, time.Local)
 
 	tests := []struct {
 		name       string
 		setupDir   func(t *testing.T) string
 		quietHours notify.QuietHours
-		nowFunc    func() time.Time
+		now        time.Time
 		playerErr  error
 		wantCalled bool
 		wantErr    bool
@@ -47,7 +48,7 @@ func TestAudioPlayRandom(t *testing.T) {
 				return dir
 			},
 			quietHours: notify.QuietHours{Enabled: false, Start: "21:00", End: "07:30"},
-			nowFunc:    noon,
+			now:        noon,
 			playerErr:  nil,
 			wantCalled: true,
 			wantErr:    false,
@@ -59,7 +60,7 @@ func TestAudioPlayRandom(t *testing.T) {
 				return t.TempDir()
 			},
 			quietHours: notify.QuietHours{Enabled: false, Start: "21:00", End: "07:30"},
-			nowFunc:    noon,
+			now:        noon,
 			playerErr:  nil,
 			wantCalled: false,
 			wantErr:    false,
@@ -73,7 +74,7 @@ func TestAudioPlayRandom(t *testing.T) {
 				return dir
 			},
 			quietHours: notify.QuietHours{Enabled: true, Start: "21:00", End: "07:30"},
-			nowFunc:    lateNight,
+			now:        lateNight,
 			playerErr:  nil,
 			wantCalled: false,
 			wantErr:    false,
@@ -87,7 +88,7 @@ func TestAudioPlayRandom(t *testing.T) {
 				return dir
 			},
 			quietHours: notify.QuietHours{Enabled: false, Start: "21:00", End: "07:30"},
-			nowFunc:    noon,
+			now:        noon,
 			playerErr:  errors.New("speaker busy"),
 			wantCalled: true,
 			wantErr:    true,
@@ -98,7 +99,7 @@ func TestAudioPlayRandom(t *testing.T) {
 				return "/tmp/nonexistent-audio-dir-xyz"
 			},
 			quietHours: notify.QuietHours{Enabled: false, Start: "21:00", End: "07:30"},
-			nowFunc:    noon,
+			now:        noon,
 			playerErr:  nil,
 			wantCalled: false,
 			wantErr:    true,
@@ -114,7 +115,7 @@ func TestAudioPlayRandom(t *testing.T) {
 				path:   "",
 			}
 
-			a := notify.NewAudio(player, dir, tt.quietHours, tt.nowFunc)
+			a := notify.NewAudio(player, []string{dir}, tt.quietHours, shared.NewFakeClock(tt.now))
 			err := a.PlayRandom()
 
 			if tt.wantErr {
@@ -131,3 +132,39 @@ func TestAudioPlayRandom(t *testing.T) {
 		})
 	}
 }
+
+func TestAudioPlayRandom_MultipleDirectories(t *testing.T) {
+	packA := t.TempDir()
+	packB := t.TempDir()
+	for _, name := range []string{"chime.mp3", "bell.mp3"} {
+		require.NoError(t, os.WriteFile(filepath.Join(packA, name), []byte("fake"), 0o600))
+	}
+	for _, name := range []string{"bell.mp3", "gong.MP3"} {
+		require.NoError(t, os.WriteFile(filepath.Join(packB, name), []byte("fake"), 0o600))
+	}
+	empty := t.TempDir()
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	played := make(map[string]bool)
+	player := &mockPlayer{
+		playFn: func(fp string) error {
+			played[fp] = true
+			return nil
+		},
+		called: false,
+		path:   "",
+	}
+	noQuietHours := notify.QuietHours{Enabled: false, Start: "", End: ""}
+	a := notify.NewAudio(player, []string{missing, packA, empty, packB}, noQuietHours, nil)
+
+	// Three eligible files; 200 draws miss one with probability under 1e-35.
+	for range 200 {
+		require.NoError(t, a.PlayRandom())
+	}
+
+	assert.Equal(t, map[string]bool{
+		filepath.Join(packA, "chime.mp3"): true,
+		filepath.Join(packA, "bell.mp3"):  true,
+		filepath.Join(packB, "gong.MP3"):  true,
+	}, played, "files from both packs play; a duplicate name plays from the first pack")
+}
@@ -3,7 +3,8 @@ package notify
 import (
 	"context"
 	"errors"
-	"time"
+
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // Sender sends a notification with a title and message body.
@@ -15,20 +16,27 @@ type Sender interface {
 type MultiNotifier struct {
 	senders    []Sender
 	quietHours *QuietHours
+	clock      shared.Clock
 }
 
-// NewMultiNotifier creates a notifier that fans out to all senders.
-func NewMultiNotifier(senders []Sender, qh *QuietHours) *MultiNotifier {
+// NewMultiNotifier creates a notifier that fans out to all senders. Quiet
+// hours are checked against clock; a nil clock uses the system clock.
+func NewMultiNotifier(senders []Sender, qh *QuietHours, clock shared.Clock) *MultiNotifier {
+	if clock == nil {
+		clock = shared.RealClock{}
+	}
+
 	return &MultiNotifier{
 		senders:    senders,
 		quietHours: qh,
+		clock:      clock,
 	}
 }
 
 // Send dispatches the notification to all backends. Errors are collected
 // and returned as a joined error. Quiet hours suppress all notifications.
 func (m *MultiNotifier) Send(ctx context.Context, title, message string) error {
-	if m.quietHours != nil && m.quietHours.IsActive(time.Now()) {
+	if m.quietHours != nil && m.quietHours.IsActive(m.clock.Now()) {
 		return nil
 	}
 
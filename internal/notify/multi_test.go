@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/riddopic/cc-tools/internal/notify"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 type mockSender struct {
@@ -27,7 +29,7 @@ func TestMultiNotifier_Send_AllBackends(t *testing.T) {
 	s1 := &mockSender{called: false, err: nil}
 	s2 := &mockSender{called: false, err: nil}
 
-	multi := notify.NewMultiNotifier([]notify.Sender{s1, s2}, nil)
+	multi := notify.NewMultiNotifier([]notify.Sender{s1, s2}, nil, nil)
 	err := multi.Send(context.Background(), "Title", "Body")
 	require.NoError(t, err)
 
@@ -41,7 +43,7 @@ func TestMultiNotifier_Send_CollectsErrors(t *testing.T) {
 	s1 := &mockSender{called: false, err: errors.New("fail1")}
 	s2 := &mockSender{called: false, err: errors.New("fail2")}
 
-	multi := notify.NewMultiNotifier([]notify.Sender{s1, s2}, nil)
+	multi := notify.NewMultiNotifier([]notify.Sender{s1, s2}, nil, nil)
 	err := multi.Send(context.Background(), "Title", "Body")
 
 	require.Error(t, err)
@@ -54,8 +56,9 @@ func TestMultiNotifier_Send_QuietHours(t *testing.T) {
 
 	s1 := &mockSender{called: false, err: nil}
 	qh := &notify.QuietHours{Enabled: true, Start: "00:00", End: "23:59"}
+	clock := shared.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local))
 
-	multi := notify.NewMultiNotifier([]notify.Sender{s1}, qh)
+	multi := notify.NewMultiNotifier([]notify.Sender{s1}, qh, clock)
 	err := multi.Send(context.Background(), "Title", "Body")
 
 	require.NoError(t, err)
@@ -66,7 +69,34 @@ func TestMultiNotifier_Send_QuietHours(t *testing.T) {
 func TestMultiNotifier_Send_NoSenders(t *testing.T) {
 	t.Parallel()
 
-	multi := notify.NewMultiNotifier(nil, nil)
+	multi := notify.NewMultiNotifier(nil, nil, nil)
 	err := multi.Send(context.Background(), "Title", "Body")
 	assert.NoError(t, err)
 }
+
+func TestMultiNotifier_Send_QuietHoursTransitions(t *testing.T) {
+	t.Parallel()
+
+	qh := &notify.QuietHours{Enabled: true, Start: "21:00", End: "07:30"}
+	clock := shared.NewFakeClock(time.Date(2026, 1, 1, 20, 59, 0, 0, time.Local))
+	s1 := &mockSender{called: false, err: nil}
+	multi := notify.NewMultiNotifier([]notify.Sender{s1}, qh, clock)
+
+	steps := []struct {
+		advance  time.Duration
+		wantSent bool
+	}{
+		{advance: 0, wantSent: true},                              // 20:59, before start
+		{advance: time.Minute, wantSent: false},                   // 21:00, quiet hours begin
+		{advance: 10*time.Hour + 29*time.Minute, wantSent: false}, // 07:29 next day
+		{advance: time.Minute, wantSent: true},                    // 07:30, quiet hours end
+	}
+
+	for _, step := range steps {
+		clock.Advance(step.advance)
+		s1.called = false
+
+		require.NoError(t, multi.Send(context.Background(), "Title", "Body"))
+		assert.Equal(t, step.wantSent, s1.called, "at %s", clock.Now().Format("15:04"))
+	}
+}
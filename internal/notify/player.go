@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// PlayerAuto selects the platform's default audio player.
+const PlayerAuto = "auto"
+
+// playerTimeout is the maximum time to wait for an audio player to finish.
+const playerTimeout = 30 * time.Second
+
+// linuxPlayers lists the Linux players tried by auto selection, in order.
+var linuxPlayers = []string{"paplay", "aplay"}
+
+// CommandPlayer plays audio files by running an external player command.
+type CommandPlayer struct {
+	name   string
+	args   []string
+	runner CmdRunner
+}
+
+// NewCommandPlayer creates a player for the given command line, such as
+// "afplay" or "mpv --really-quiet". The file to play is appended as the
+// last argument, except for powershell, which is given a playback script.
+// A nil runner runs the command with a 30 second timeout.
+func NewCommandPlayer(command string, runner CmdRunner) *CommandPlayer {
+	if runner == nil {
+		runner = &timeoutRunner{timeout: playerTimeout}
+	}
+
+	fields := strings.Fields(command)
+	p := &CommandPlayer{name: "", args: nil, runner: runner}
+	if len(fields) > 0 {
+		p.name = fields[0]
+		p.args = fields[1:]
+	}
+
+	return p
+}
+
+// Command returns the player binary name.
+func (p *CommandPlayer) Command() string {
+	return p.name
+}
+
+// Play plays the audio file at the given path.
+func (p *CommandPlayer) Play(path string) error {
+	if p.name == "" {
+		return errors.New("no audio player configured")
+	}
+
+	args := append([]string{}, p.args...)
+	if p.name == "powershell" {
+		args = append(args, "-NoProfile", "-NonInteractive", "-Command", powershellPlayScript(path))
+	} else {
+		args = append(args, path)
+	}
+
+	return p.runner.Run(p.name, args...)
+}
+
+// ResolvePlayer maps a notify.audio.player setting to a player command.
+// An empty or "auto" setting picks afplay on macOS, the first of paplay or
+// aplay found by lookPath on Linux, and powershell on Windows. It returns
+// "" when auto selection finds no player. Any other setting is returned
+// unchanged.
+func ResolvePlayer(setting, goos string, lookPath func(string) (string, error)) string {
+	if setting != "" && setting != PlayerAuto {
+		return setting
+	}
+
+	switch goos {
+	case "darwin":
+		return "afplay"
+	case "windows":
+		return "powershell"
+	case "linux":
+		for _, name := range linuxPlayers {
+			if _, err := lookPath(name); err == nil {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+// CheckPlayer reports an error when the binary for a notify.audio.player
+// setting cannot be found. Auto selection is always accepted.
+func CheckPlayer(setting string, lookPath func(string) (string, error)) error {
+	if setting == "" || setting == PlayerAuto {
+		return nil
+	}
+
+	fields := strings.Fields(setting)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if _, err := lookPath(fields[0]); err != nil {
+		return fmt.Errorf("audio player %q not found on PATH: %w", fields[0], err)
+	}
+
+	return nil
+}
+
+// powershellPlayScript returns a script that plays path synchronously
+// through the Windows media player.
+func powershellPlayScript(path string) string {
+	quoted := "'" + strings.ReplaceAll(path, "'", "''") + "'"
+
+	return "Add-Type -AssemblyName presentationCore; " +
+		"$p = New-Object System.Windows.Media.MediaPlayer; " +
+		"$p.Open(" + quoted + "); $p.Play(); " +
+		"Start-Sleep -Milliseconds 500; " +
+		"while ($p.Position -lt $p.NaturalDuration.TimeSpan) { Start-Sleep -Milliseconds 100 }"
+}
+
+// timeoutRunner runs commands with a fixed timeout.
+type timeoutRunner struct {
+	timeout time.Duration
+}
+
+// Run executes the named program, killing it after the timeout.
+func (r *timeoutRunner) Run(name string, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	return exec.CommandContext(ctx, name, args...).Run()
+}
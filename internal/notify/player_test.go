@@ -0,0 +1,125 @@
+package notify_test
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/notify"
+)
+
+// lookPathFor returns a lookPath func that finds only the given binaries.
+func lookPathFor(found ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		for _, f := range found {
+			if f == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+}
+
+func TestResolvePlayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		goos    string
+		found   []string
+		want    string
+	}{
+		{name: "auto on macOS", setting: "auto", goos: "darwin", found: nil, want: "afplay"},
+		{name: "empty means auto", setting: "", goos: "darwin", found: nil, want: "afplay"},
+		{name: "auto on windows", setting: "auto", goos: "windows", found: nil, want: "powershell"},
+		{name: "auto on linux prefers paplay", setting: "auto", goos: "linux", found: []string{"aplay", "paplay"}, want: "paplay"},
+		{name: "auto on linux falls back to aplay", setting: "auto", goos: "linux", found: []string{"aplay"}, want: "aplay"},
+		{name: "auto on linux without players", setting: "auto", goos: "linux", found: nil, want: ""},
+		{name: "auto on unknown OS", setting: "auto", goos: "plan9", found: nil, want: ""},
+		{name: "explicit setting wins", setting: "mpv --really-quiet", goos: "darwin", found: nil, want: "mpv --really-quiet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, notify.ResolvePlayer(tt.setting, tt.goos, lookPathFor(tt.found...)))
+		})
+	}
+}
+
+func TestCommandPlayer_Play(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		wantName string
+		wantArgs []string
+	}{
+		{name: "afplay", command: "afplay", wantName: "afplay", wantArgs: []string{"/sounds/a.mp3"}},
+		{name: "paplay", command: "paplay", wantName: "paplay", wantArgs: []string{"/sounds/a.mp3"}},
+		{
+			name:     "custom command with arguments",
+			command:  "mpv --really-quiet",
+			wantName: "mpv",
+			wantArgs: []string{"--really-quiet", "/sounds/a.mp3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			var gotArgs []string
+			runner := &mockRunner{runFn: func(name string, args ...string) error {
+				gotName, gotArgs = name, args
+				return nil
+			}}
+
+			p := notify.NewCommandPlayer(tt.command, runner)
+			require.NoError(t, p.Play("/sounds/a.mp3"))
+			assert.Equal(t, tt.wantName, gotName)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestCommandPlayer_PlayPowershell(t *testing.T) {
+	var gotName string
+	var gotArgs []string
+	runner := &mockRunner{runFn: func(name string, args ...string) error {
+		gotName, gotArgs = name, args
+		return nil
+	}}
+
+	p := notify.NewCommandPlayer("powershell", runner)
+	require.NoError(t, p.Play(`C:\sounds\it's.mp3`))
+
+	assert.Equal(t, "powershell", gotName)
+	require.Len(t, gotArgs, 4)
+	assert.Equal(t, []string{"-NoProfile", "-NonInteractive", "-Command"}, gotArgs[:3])
+	assert.Contains(t, gotArgs[3], `'C:\sounds\it''s.mp3'`)
+}
+
+func TestCommandPlayer_Errors(t *testing.T) {
+	t.Run("empty command", func(t *testing.T) {
+		p := notify.NewCommandPlayer("  ", &mockRunner{runFn: nil})
+		require.Error(t, p.Play("/a.mp3"))
+	})
+
+	t.Run("runner failure", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		p := notify.NewCommandPlayer("afplay", &mockRunner{runFn: func(string, ...string) error { return errBoom }})
+		require.ErrorIs(t, p.Play("/a.mp3"), errBoom)
+	})
+}
+
+func TestCheckPlayer(t *testing.T) {
+	lookPath := lookPathFor("mpv")
+
+	require.NoError(t, notify.CheckPlayer("auto", lookPath))
+	require.NoError(t, notify.CheckPlayer("", lookPath))
+	require.NoError(t, notify.CheckPlayer("mpv --really-quiet", lookPath))
+
+	err := notify.CheckPlayer("nosuchplayer", lookPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"nosuchplayer" not found on PATH`)
+}
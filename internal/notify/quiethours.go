@@ -46,6 +46,41 @@ func (qh QuietHours) IsActive(now time.Time) bool {
 	return nowMinutes >= startMinutes || nowMinutes < endMinutes
 }
 
+// NextTransition returns the next time after now at which quiet hours
+// start or end, in now's location. It reports false when quiet hours are
+// disabled, misconfigured, or have an empty window that never changes.
+func (qh QuietHours) NextTransition(now time.Time) (time.Time, bool) {
+	if !qh.Enabled {
+		return time.Time{}, false
+	}
+
+	startH, startM, err := parseTime(qh.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	endH, endM, err := parseTime(qh.End)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	if startH == endH && startM == endM {
+		return time.Time{}, false
+	}
+
+	h, m := startH, startM
+	if qh.IsActive(now) {
+		h, m = endH, endM
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), h, m, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next, true
+}
+
 func parseTime(s string) (int, int, error) {
 	var h, m int
 	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil {
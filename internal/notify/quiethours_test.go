@@ -145,3 +145,74 @@ func TestQuietHours_IsActive(t *testing.T) {
 		})
 	}
 }
+
+func TestQuietHours_NextTransition(t *testing.T) {
+	overnight := notify.QuietHours{Enabled: true, Start: "21:00", End: "07:30"}
+	daytime := notify.QuietHours{Enabled: true, Start: "08:00", End: "17:00"}
+
+	tests := []struct {
+		name   string
+		qh     notify.QuietHours
+		now    time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "active overnight ends next morning",
+			qh:     overnight,
+			now:    time.Date(2025, 1, 15, 22, 0, 0, 0, time.UTC),
+			want:   time.Date(2025, 1, 16, 7, 30, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "active after midnight ends same morning",
+			qh:     overnight,
+			now:    time.Date(2025, 1, 15, 3, 0, 0, 0, time.UTC),
+			want:   time.Date(2025, 1, 15, 7, 30, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "inactive starts this evening",
+			qh:     overnight,
+			now:    time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC),
+			want:   time.Date(2025, 1, 15, 21, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "inactive after daytime window starts tomorrow",
+			qh:     daytime,
+			now:    time.Date(2025, 1, 15, 17, 0, 0, 0, time.UTC),
+			want:   time.Date(2025, 1, 16, 8, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "disabled has no transition",
+			qh:     notify.QuietHours{Enabled: false, Start: "21:00", End: "07:30"},
+			now:    time.Date(2025, 1, 15, 22, 0, 0, 0, time.UTC),
+			want:   time.Time{},
+			wantOK: false,
+		},
+		{
+			name:   "empty window has no transition",
+			qh:     notify.QuietHours{Enabled: true, Start: "09:00", End: "09:00"},
+			now:    time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC),
+			want:   time.Time{},
+			wantOK: false,
+		},
+		{
+			name:   "invalid start has no transition",
+			qh:     notify.QuietHours{Enabled: true, Start: "bad", End: "07:30"},
+			now:    time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC),
+			want:   time.Time{},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.qh.NextTransition(tt.now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.True(t, tt.want.Equal(got), "NextTransition() = %v, want %v", got, tt.want)
+		})
+	}
+}
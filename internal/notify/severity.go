@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidSeverity is returned when a severity name is not recognized.
+var ErrInvalidSeverity = errors.New("invalid severity")
+
+// Severity ranks how urgently a notification needs the user. Higher values
+// are more urgent.
+type Severity int
+
+// Notification severities, lowest first.
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityAttention
+)
+
+// severityNames maps each severity to its config name.
+var severityNames = map[Severity]string{
+	SeverityInfo:      "info",
+	SeverityWarning:   "warning",
+	SeverityAttention: "attention",
+}
+
+// ParseSeverity returns the severity named by name: info, warning, or
+// attention.
+func ParseSeverity(name string) (Severity, error) {
+	for severity, severityName := range severityNames {
+		if name == severityName {
+			return severity, nil
+		}
+	}
+
+	return SeverityInfo, fmt.Errorf("%w: %q", ErrInvalidSeverity, name)
+}
+
+// String returns the config name of s.
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
@@ -0,0 +1,38 @@
+package notify_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/notify"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		name string
+		want notify.Severity
+	}{
+		{name: "info", want: notify.SeverityInfo},
+		{name: "warning", want: notify.SeverityWarning},
+		{name: "attention", want: notify.SeverityAttention},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := notify.ParseSeverity(tt.name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.name, got.String())
+		})
+	}
+
+	_, err := notify.ParseSeverity("critical")
+	require.ErrorIs(t, err, notify.ErrInvalidSeverity)
+}
+
+func TestSeverity_Order(t *testing.T) {
+	assert.Less(t, notify.SeverityInfo, notify.SeverityWarning)
+	assert.Less(t, notify.SeverityWarning, notify.SeverityAttention)
+}
@@ -0,0 +1,105 @@
+package observe
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Placeholders substituted for anonymized path prefixes.
+const (
+	homePlaceholder    = "~"
+	projectPlaceholder = "$PROJECT"
+)
+
+// Anonymizer rewrites absolute paths in tool events so that observation logs
+// do not leak usernames or machine-specific directory layouts.
+type Anonymizer struct {
+	rules []anonymizeRule
+}
+
+// anonymizeRule replaces one path prefix with a placeholder.
+type anonymizeRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// NewAnonymizer creates an Anonymizer that replaces projectRoot with
+// $PROJECT and homeDir with ~. Empty or root directories are ignored.
+func NewAnonymizer(homeDir, projectRoot string) *Anonymizer {
+	a := &Anonymizer{rules: nil}
+	// The project root usually lives under the home directory, so it is
+	// replaced first.
+	a.addRule(projectRoot, projectPlaceholder)
+	a.addRule(homeDir, homePlaceholder)
+
+	return a
+}
+
+func (a *Anonymizer) addRule(dir, placeholder string) {
+	dir = filepath.Clean(dir)
+	if dir == "." || dir == string(filepath.Separator) {
+		return
+	}
+
+	// Match the directory only when it is followed by a separator or a
+	// non-path character, so /home/al does not match /home/alice.
+	pattern := regexp.MustCompile(regexp.QuoteMeta(dir) + `(/|$|[^\w.\-])`)
+	a.rules = append(a.rules, anonymizeRule{
+		pattern: pattern,
+		// Escape "$" so the placeholder is not read as a group reference.
+		replacement: strings.ReplaceAll(placeholder, "$", "$$") + "${1}",
+	})
+}
+
+// Anonymize returns a copy of raw with every string value rewritten. Input
+// that is not valid JSON is returned unchanged.
+func (a *Anonymizer) Anonymize(raw json.RawMessage) json.RawMessage {
+	if a == nil || len(a.rules) == 0 || len(raw) == 0 {
+		return raw
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return raw
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(a.walk(value)); err != nil {
+		return raw
+	}
+
+	return bytes.TrimRight(buf.Bytes(), "\n")
+}
+
+// walk rewrites strings in a decoded JSON value.
+func (a *Anonymizer) walk(value any) any {
+	switch v := value.(type) {
+	case string:
+		return a.rewrite(v)
+	case []any:
+		for i := range v {
+			v[i] = a.walk(v[i])
+		}
+		return v
+	case map[string]any:
+		for k := range v {
+			v[k] = a.walk(v[k])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// rewrite replaces known path prefixes in s with their placeholders.
+func (a *Anonymizer) rewrite(s string) string {
+	for _, rule := range a.rules {
+		s = rule.pattern.ReplaceAllString(s, rule.replacement)
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package observe_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/observe"
+)
+
+func TestAnonymizer(t *testing.T) {
+	a := observe.NewAnonymizer("/home/alice", "/home/alice/repo")
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "project file",
+			input: `{"file_path":"/home/alice/repo/main.go"}`,
+			want:  `{"file_path":"$PROJECT/main.go"}`,
+		},
+		{
+			name:  "home file outside project",
+			input: `{"file_path":"/home/alice/.zshrc"}`,
+			want:  `{"file_path":"~/.zshrc"}`,
+		},
+		{
+			name:  "paths inside a command",
+			input: `{"command":"cd /home/alice/repo && cat /home/alice/notes.txt"}`,
+			want:  `{"command":"cd $PROJECT && cat ~/notes.txt"}`,
+		},
+		{
+			name:  "similar prefix is untouched",
+			input: `{"file_path":"/home/alice/repository/x.go"}`,
+			want:  `{"file_path":"~/repository/x.go"}`,
+		},
+		{
+			name:  "nested values",
+			input: `{"edits":[{"path":"/home/alice/repo/a.go"}],"limit":5}`,
+			want:  `{"edits":[{"path":"$PROJECT/a.go"}],"limit":5}`,
+		},
+		{
+			name:  "invalid json unchanged",
+			input: `/home/alice/repo`,
+			want:  `/home/alice/repo`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := a.Anonymize(json.RawMessage(tt.input))
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestRecord_Anonymize(t *testing.T) {
+	dir := t.TempDir()
+	obs := observe.NewObserver(dir, 10,
+		observe.WithAnonymizer(observe.NewAnonymizer("/home/alice", "/home/alice/repo")))
+
+	require.NoError(t, obs.Record(observe.Event{
+		Timestamp:  time.Now(),
+		Phase:      "pre",
+		ToolName:   "Edit",
+		ToolInput:  json.RawMessage(`{"file_path":"/home/alice/repo/main.go"}`),
+		ToolOutput: json.RawMessage(`{"stdout":"/home/alice/repo/main.go:3: undefined: x"}`),
+		Error:      "open /home/alice/.cache/go-build/ab: permission denied",
+		SessionID:  "s1",
+	}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "observations.jsonl"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "/home/alice", "no home path may appear anywhere in the line")
+
+	var got observe.Event
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.JSONEq(t, `{"file_path":"$PROJECT/main.go"}`, string(got.ToolInput))
+	assert.JSONEq(t, `{"stdout":"$PROJECT/main.go:3: undefined: x"}`, string(got.ToolOutput))
+	assert.Equal(t, "open ~/.cache/go-build/ab: permission denied", got.Error)
+	assert.Equal(t, "$PROJECT/main.go", got.FilePath)
+}
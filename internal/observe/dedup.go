@@ -0,0 +1,89 @@
+package observe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// dedupFile holds the last recorded event hash per session. Each hook runs
+// in its own process, so the state has to live on disk.
+const dedupFile = ".last-events.json"
+
+// lastEvent identifies the most recent event recorded for a session.
+type lastEvent struct {
+	Hash string    `json:"hash"`
+	At   time.Time `json:"at"`
+}
+
+// WithDedup skips events identical in phase, tool, and input to the last
+// event recorded for the same session less than window earlier. Zero
+// disables deduplication.
+func WithDedup(window time.Duration) ObserverOption {
+	return func(o *Observer) {
+		o.dedupWindow = window
+	}
+}
+
+// eventHash fingerprints the parts of an event that make a retry identical.
+func eventHash(event Event) string {
+	h := sha256.New()
+	h.Write([]byte(event.Phase))
+	h.Write([]byte{0})
+	h.Write([]byte(event.ToolName))
+	h.Write([]byte{0})
+	h.Write(event.ToolInput)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isDuplicate reports whether event repeats the last event recorded for its
+// session within the dedup window.
+func (o *Observer) isDuplicate(event Event, hash string) bool {
+	last, ok := o.loadLastEvents()[event.SessionID]
+	if !ok || last.Hash != hash {
+		return false
+	}
+
+	age := event.Timestamp.Sub(last.At)
+	return age >= 0 && age < o.dedupWindow
+}
+
+// rememberEvent stores hash as the session's last recorded event and drops
+// entries too old to match anything.
+func (o *Observer) rememberEvent(event Event, hash string) error {
+	events := o.loadLastEvents()
+	for id, last := range events {
+		if event.Timestamp.Sub(last.At) >= o.dedupWindow {
+			delete(events, id)
+		}
+	}
+	events[event.SessionID] = lastEvent{Hash: hash, At: event.Timestamp}
+
+	data, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("marshal dedup state: %w", err)
+	}
+	if writeErr := os.WriteFile(filepath.Join(o.dir, dedupFile), data, 0o600); writeErr != nil {
+		return fmt.Errorf("write dedup state: %w", writeErr)
+	}
+	return nil
+}
+
+// loadLastEvents reads the dedup state. A missing or unreadable file is
+// treated as empty so that a bad state file never blocks recording.
+func (o *Observer) loadLastEvents() map[string]lastEvent {
+	events := map[string]lastEvent{}
+
+	data, err := os.ReadFile(filepath.Join(o.dir, dedupFile))
+	if err != nil {
+		return events
+	}
+	if unmarshalErr := json.Unmarshal(data, &events); unmarshalErr != nil {
+		return map[string]lastEvent{}
+	}
+	return events
+}
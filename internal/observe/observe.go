@@ -2,16 +2,25 @@
 package observe
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
+	"unicode/utf8"
+
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // observationsFile is the name of the JSONL file that stores observations.
 const observationsFile = "observations.jsonl"
 
+// truncatedMarker is appended to tool input cut short by maxInputBytes.
+const truncatedMarker = "...truncated"
+
 // disabledFile is the name of the marker file that disables observation recording.
 const disabledFile = ".disabled"
 
@@ -24,40 +33,128 @@ type Event struct {
 	ToolOutput json.RawMessage `json:"tool_output,omitempty"`
 	Error      string          `json:"error,omitempty"`
 	SessionID  string          `json:"session_id"`
+	// FilePath is the file a file tool acted on, copied from its input so
+	// events can be queried by file. Record fills it in when it is empty.
+	FilePath string `json:"file_path,omitempty"`
+	// CorrelationID links the pre, post, and failure events of one tool
+	// call. Callers pass the hook's tool_use_id; when it is empty Record
+	// derives one from the session, tool, and input.
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// filePathKeys maps the tools whose input names a single file to the input
+// field that holds it.
+var filePathKeys = map[string]string{
+	"Edit":         "file_path",
+	"MultiEdit":    "file_path",
+	"NotebookEdit": "notebook_path",
+	"Read":         "file_path",
+	"Write":        "file_path",
 }
 
 // Observer records tool events to a JSONL file.
 type Observer struct {
 	dir           string
 	maxFileSizeMB int
+	anonymizer    *Anonymizer
+	maxInputBytes int
+	dedupWindow   time.Duration
+	warnOut       io.Writer
+}
+
+// ObserverOption configures an Observer.
+type ObserverOption func(*Observer)
+
+// WithAnonymizer rewrites paths in each event's tool input before it is
+// written.
+func WithAnonymizer(a *Anonymizer) ObserverOption {
+	return func(o *Observer) {
+		o.anonymizer = a
+	}
+}
+
+// WithMaxInputBytes caps the size of each event's tool input. Longer input
+// is replaced by a JSON string holding its first n bytes followed by
+// "...truncated". Zero means unlimited.
+func WithMaxInputBytes(n int) ObserverOption {
+	return func(o *Observer) {
+		o.maxInputBytes = n
+	}
+}
+
+// WithWarnOutput sets where the switch to a fallback directory is
+// reported. The default is stderr.
+func WithWarnOutput(w io.Writer) ObserverOption {
+	return func(o *Observer) {
+		o.warnOut = w
+	}
 }
 
 // NewObserver creates a new Observer.
-func NewObserver(dir string, maxFileSizeMB int) *Observer {
-	return &Observer{
+func NewObserver(dir string, maxFileSizeMB int, opts ...ObserverOption) *Observer {
+	o := &Observer{
 		dir:           dir,
 		maxFileSizeMB: maxFileSizeMB,
+		anonymizer:    nil,
+		maxInputBytes: 0,
+		dedupWindow:   0,
+		warnOut:       os.Stderr,
 	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
 }
 
 // Record appends an event as a JSON line to observations.jsonl.
 // It checks file size before writing and rotates if over maxFileSizeMB.
-// Returns nil if observation recording is disabled.
+// Returns nil if observation recording is disabled or, with WithDedup, if
+// the event repeats the session's last recorded event. When dir cannot be
+// written, events go to its temp-directory fallback; see shared.WritableDir.
 func (o *Observer) Record(event Event) error {
 	if o.isDisabled() {
 		return nil
 	}
 
-	if err := os.MkdirAll(o.dir, 0o750); err != nil {
+	dir, err := shared.WritableDir(o.dir, o.warnOut)
+	if err != nil {
 		return fmt.Errorf("create observe directory: %w", err)
 	}
+	o.dir = dir
+
+	var hash string
+	if o.dedupWindow > 0 {
+		hash = eventHash(event)
+		if o.isDuplicate(event, hash) {
+			return nil
+		}
+	}
 
 	filePath := filepath.Join(o.dir, observationsFile)
 
-	if err := RotateIfNeeded(filePath, o.maxFileSizeMB); err != nil {
-		return fmt.Errorf("rotate observations file: %w", err)
+	if rotateErr := RotateIfNeeded(filePath, o.maxFileSizeMB); rotateErr != nil {
+		return fmt.Errorf("rotate observations file: %w", rotateErr)
+	}
+
+	// The path is read before truncation can cut it out of the input.
+	if event.FilePath == "" {
+		event.FilePath = inputFilePath(event.ToolName, event.ToolInput)
+	}
+	if event.CorrelationID == "" {
+		event.CorrelationID = correlationID(event)
+	}
+	if o.anonymizer != nil {
+		event.FilePath = o.anonymizer.rewrite(event.FilePath)
+		event.Error = o.anonymizer.rewrite(event.Error)
 	}
 
+	// Tool output and errors carry compiler messages and stack traces full
+	// of absolute paths, so they are anonymized along with the input.
+	event.ToolInput = o.anonymizer.Anonymize(event.ToolInput)
+	event.ToolOutput = o.anonymizer.Anonymize(event.ToolOutput)
+	event.ToolInput = truncateInput(event.ToolInput, o.maxInputBytes)
+
 	data, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("marshal event: %w", err)
@@ -76,6 +173,10 @@ func (o *Observer) Record(event Event) error {
 		return fmt.Errorf("write event: %w", writeErr)
 	}
 
+	if o.dedupWindow > 0 {
+		return o.rememberEvent(event, hash)
+	}
+
 	return nil
 }
 
@@ -85,3 +186,56 @@ func (o *Observer) isDisabled() bool {
 
 	return err == nil
 }
+
+// inputFilePath returns the file named in the input of a file tool, or an
+// empty string for other tools and input that does not decode.
+func inputFilePath(toolName string, input json.RawMessage) string {
+	key, ok := filePathKeys[toolName]
+	if !ok || len(input) == 0 {
+		return ""
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(input, &fields) != nil {
+		return ""
+	}
+
+	var path string
+	if json.Unmarshal(fields[key], &path) != nil {
+		return ""
+	}
+
+	return path
+}
+
+// correlationID fingerprints the session, tool, and input of event, which a
+// call's pre and post events share.
+func correlationID(event Event) string {
+	h := sha256.New()
+	h.Write([]byte(event.SessionID))
+	h.Write([]byte{0})
+	h.Write([]byte(event.ToolName))
+	h.Write([]byte{0})
+	h.Write(event.ToolInput)
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// truncateInput returns raw unchanged if it fits in maxBytes. Otherwise it
+// returns a JSON string of the first maxBytes bytes plus truncatedMarker,
+// cut back to a UTF-8 boundary so the result stays valid.
+func truncateInput(raw json.RawMessage, maxBytes int) json.RawMessage {
+	if maxBytes <= 0 || len(raw) <= maxBytes {
+		return raw
+	}
+
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(raw[cut]) {
+		cut--
+	}
+
+	data, err := json.Marshal(string(raw[:cut]) + truncatedMarker)
+	if err != nil {
+		return nil
+	}
+	return data
+}
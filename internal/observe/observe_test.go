@@ -1,6 +1,7 @@
 package observe_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/riddopic/cc-tools/internal/observe"
+	"github.com/riddopic/cc-tools/internal/shared"
 )
 
 // verifyJSONLLines reads the observations file, checks the line count, and
@@ -226,3 +228,298 @@ func TestRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestRecord_MaxInputBytes(t *testing.T) {
+	const maxBytes = 4096
+
+	dir := t.TempDir()
+	obs := observe.NewObserver(dir, 10, observe.WithMaxInputBytes(maxBytes))
+
+	content := strings.Repeat("a", 1<<20)
+	input, err := json.Marshal(map[string]string{"file_path": "big.txt", "content": content})
+	require.NoError(t, err)
+
+	require.NoError(t, obs.Record(observe.Event{
+		Timestamp:  time.Now(),
+		Phase:      "pre",
+		ToolName:   "Write",
+		ToolInput:  input,
+		ToolOutput: nil,
+		Error:      "",
+		SessionID:  "s1",
+	}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "observations.jsonl"))
+	require.NoError(t, err)
+	assert.Less(t, len(data), 2*maxBytes, "stored line should be capped")
+
+	var got observe.Event
+	require.NoError(t, json.Unmarshal(data, &got))
+
+	var stored string
+	require.NoError(t, json.Unmarshal(got.ToolInput, &stored))
+	assert.True(t, strings.HasSuffix(stored, "...truncated"), "truncated input should be marked")
+	assert.Len(t, stored, maxBytes+len("...truncated"))
+}
+
+func TestRecord_MaxInputBytesUnlimited(t *testing.T) {
+	dir := t.TempDir()
+	obs := observe.NewObserver(dir, 10, observe.WithMaxInputBytes(0))
+
+	input := json.RawMessage(`{"content":"` + strings.Repeat("b", 10000) + `"}`)
+	require.NoError(t, obs.Record(observe.Event{
+		Timestamp:  time.Now(),
+		Phase:      "pre",
+		ToolName:   "Write",
+		ToolInput:  input,
+		ToolOutput: nil,
+		Error:      "",
+		SessionID:  "s1",
+	}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "observations.jsonl"))
+	require.NoError(t, err)
+
+	var got observe.Event
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.JSONEq(t, string(input), string(got.ToolInput))
+}
+
+func TestRecord_FilePath(t *testing.T) {
+	tests := []struct {
+		name  string
+		tool  string
+		input string
+		want  string
+	}{
+		{name: "edit", tool: "Edit", input: `{"file_path":"/repo/main.go","old_string":"a"}`, want: "/repo/main.go"},
+		{name: "write", tool: "Write", input: `{"file_path":"/repo/new.go","content":"package x"}`, want: "/repo/new.go"},
+		{name: "read", tool: "Read", input: `{"file_path":"/repo/README.md"}`, want: "/repo/README.md"},
+		{name: "notebook", tool: "NotebookEdit", input: `{"notebook_path":"/repo/a.ipynb"}`, want: "/repo/a.ipynb"},
+		{name: "bash has none", tool: "Bash", input: `{"command":"cat /repo/main.go"}`, want: ""},
+		{name: "malformed input", tool: "Edit", input: `{"file_path":42}`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			obs := observe.NewObserver(dir, 10)
+			require.NoError(t, obs.Record(observe.Event{
+				Timestamp:     time.Now(),
+				Phase:         "pre",
+				ToolName:      tt.tool,
+				ToolInput:     json.RawMessage(tt.input),
+				ToolOutput:    nil,
+				Error:         "",
+				SessionID:     "s1",
+				FilePath:      "",
+				CorrelationID: "",
+			}))
+
+			data, err := os.ReadFile(filepath.Join(dir, "observations.jsonl"))
+			require.NoError(t, err)
+
+			var got map[string]any
+			require.NoError(t, json.Unmarshal(data, &got))
+			if tt.want == "" {
+				assert.NotContains(t, got, "file_path")
+			} else {
+				assert.Equal(t, tt.want, got["file_path"])
+			}
+			assert.JSONEq(t, tt.input, string(mustMarshal(t, got["tool_input"])), "raw input should be kept")
+		})
+	}
+}
+
+func TestRecord_FilePathSurvivesTruncationAndAnonymization(t *testing.T) {
+	dir := t.TempDir()
+	obs := observe.NewObserver(dir, 10,
+		observe.WithMaxInputBytes(64),
+		observe.WithAnonymizer(observe.NewAnonymizer("/home/alice", "/home/alice/repo")),
+	)
+
+	input, err := json.Marshal(map[string]string{
+		"content":   strings.Repeat("x", 1000),
+		"file_path": "/home/alice/repo/internal/big.go",
+	})
+	require.NoError(t, err)
+	require.NoError(t, obs.Record(observe.Event{
+		Timestamp:     time.Now(),
+		Phase:         "pre",
+		ToolName:      "Write",
+		ToolInput:     input,
+		ToolOutput:    nil,
+		Error:         "",
+		SessionID:     "s1",
+		FilePath:      "",
+		CorrelationID: "",
+	}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "observations.jsonl"))
+	require.NoError(t, err)
+
+	var got observe.Event
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "$PROJECT/internal/big.go", got.FilePath)
+
+	var stored string
+	require.NoError(t, json.Unmarshal(got.ToolInput, &stored))
+	assert.True(t, strings.HasSuffix(stored, "...truncated"))
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+func TestRecord_Dedup(t *testing.T) {
+	start := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+	event := func(at time.Duration, session, input string) observe.Event {
+		return observe.Event{
+			Timestamp:  start.Add(at),
+			Phase:      "pre",
+			ToolName:   "Bash",
+			ToolInput:  json.RawMessage(input),
+			ToolOutput: nil,
+			Error:      "",
+			SessionID:  session,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		window    time.Duration
+		events    []observe.Event
+		wantLines int
+	}{
+		{
+			name:   "identical back-to-back events are recorded once",
+			window: 10 * time.Second,
+			events: []observe.Event{
+				event(0, "s1", `{"command":"ls"}`),
+				event(time.Second, "s1", `{"command":"ls"}`),
+			},
+			wantLines: 1,
+		},
+		{
+			name:   "different input is recorded",
+			window: 10 * time.Second,
+			events: []observe.Event{
+				event(0, "s1", `{"command":"ls"}`),
+				event(time.Second, "s1", `{"command":"pwd"}`),
+			},
+			wantLines: 2,
+		},
+		{
+			name:   "other sessions are independent",
+			window: 10 * time.Second,
+			events: []observe.Event{
+				event(0, "s1", `{"command":"ls"}`),
+				event(time.Second, "s2", `{"command":"ls"}`),
+			},
+			wantLines: 2,
+		},
+		{
+			name:   "repeat outside the window is recorded",
+			window: 10 * time.Second,
+			events: []observe.Event{
+				event(0, "s1", `{"command":"ls"}`),
+				event(time.Minute, "s1", `{"command":"ls"}`),
+			},
+			wantLines: 2,
+		},
+		{
+			name:   "disabled by default",
+			window: 0,
+			events: []observe.Event{
+				event(0, "s1", `{"command":"ls"}`),
+				event(time.Second, "s1", `{"command":"ls"}`),
+			},
+			wantLines: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			obs := observe.NewObserver(dir, 10, observe.WithDedup(tt.window))
+
+			for _, e := range tt.events {
+				require.NoError(t, obs.Record(e))
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "observations.jsonl"))
+			require.NoError(t, err)
+			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+			assert.Len(t, lines, tt.wantLines)
+		})
+	}
+}
+
+func TestRecord_CorrelationID(t *testing.T) {
+	dir := t.TempDir()
+	obs := observe.NewObserver(dir, 10)
+
+	event := func(phase, session, input, id string) observe.Event {
+		return observe.Event{
+			Timestamp:     time.Now(),
+			Phase:         phase,
+			ToolName:      "Bash",
+			ToolInput:     json.RawMessage(input),
+			ToolOutput:    nil,
+			Error:         "",
+			SessionID:     session,
+			FilePath:      "",
+			CorrelationID: id,
+		}
+	}
+
+	require.NoError(t, obs.Record(event("pre", "s1", `{"command":"ls"}`, "")))
+	require.NoError(t, obs.Record(event("post", "s1", `{"command":"ls"}`, "")))
+	require.NoError(t, obs.Record(event("pre", "s1", `{"command":"pwd"}`, "")))
+	require.NoError(t, obs.Record(event("pre", "s2", `{"command":"ls"}`, "")))
+	require.NoError(t, obs.Record(event("post", "s1", `{"command":"ls"}`, "toolu_01")))
+
+	data, err := os.ReadFile(filepath.Join(dir, "observations.jsonl"))
+	require.NoError(t, err)
+
+	var ids []string
+	for line := range strings.Lines(string(data)) {
+		var got observe.Event
+		require.NoError(t, json.Unmarshal([]byte(line), &got))
+		ids = append(ids, got.CorrelationID)
+	}
+	require.Len(t, ids, 5)
+
+	assert.NotEmpty(t, ids[0])
+	assert.Equal(t, ids[0], ids[1], "pre and post of the same call share an ID")
+	assert.NotEqual(t, ids[0], ids[2], "a different input is a different call")
+	assert.NotEqual(t, ids[0], ids[3], "a different session is a different call")
+	assert.Equal(t, "toolu_01", ids[4], "a tool_use_id from the hook is kept")
+}
+
+func TestRecord_UnwritableDirFallsBack(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".cache"), nil, 0o600))
+	dir := filepath.Join(home, ".cache", "cc-tools", "observe")
+
+	var warn bytes.Buffer
+	obs := observe.NewObserver(dir, 10, observe.WithWarnOutput(&warn))
+	require.NoError(t, obs.Record(observe.Event{
+		Timestamp:     time.Now(),
+		Phase:         "pre",
+		ToolName:      "Bash",
+		ToolInput:     json.RawMessage(`{"command":"ls"}`),
+		ToolOutput:    nil,
+		Error:         "",
+		SessionID:     "s1",
+		FilePath:      "",
+		CorrelationID: "",
+	}))
+
+	assert.Contains(t, warn.String(), "cannot write to "+dir)
+	assert.FileExists(t, filepath.Join(shared.FallbackDir(dir), "observations.jsonl"))
+}
@@ -0,0 +1,122 @@
+package observe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// maxLineBytes bounds a single JSONL line read while purging.
+const maxLineBytes = 16 * bytesPerMegabyte
+
+// HoursPerDay converts retention windows given in days to durations.
+const HoursPerDay = 24
+
+// archivePattern matches rotated files produced by RotateIfNeeded.
+const archivePattern = "observations-*.jsonl"
+
+// PurgeResult summarizes what Purge removed.
+type PurgeResult struct {
+	FilesRemoved  int
+	EventsRemoved int
+	BytesFreed    int64
+}
+
+// DefaultDir returns the directory observations are written to when no
+// override is configured.
+func DefaultDir() (string, error) {
+	return shared.CacheDir("observations")
+}
+
+// Purge deletes rotated observation files in dir last modified before cutoff
+// and drops events older than cutoff from the active observations file.
+// Lines in the active file that cannot be parsed are kept. A missing
+// directory is not an error.
+func Purge(dir string, cutoff time.Time) (PurgeResult, error) {
+	var result PurgeResult
+
+	archives, err := filepath.Glob(filepath.Join(dir, archivePattern))
+	if err != nil {
+		return result, fmt.Errorf("list rotated observations: %w", err)
+	}
+
+	for _, path := range archives {
+		info, statErr := os.Stat(path)
+		if statErr != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+
+		if removeErr := os.Remove(path); removeErr != nil {
+			return result, fmt.Errorf("remove %s: %w", path, removeErr)
+		}
+		result.FilesRemoved++
+		result.BytesFreed += info.Size()
+	}
+
+	removed, freed, err := trimActiveFile(filepath.Join(dir, observationsFile), cutoff)
+	if err != nil {
+		return result, err
+	}
+	result.EventsRemoved = removed
+	result.BytesFreed += freed
+
+	return result, nil
+}
+
+// trimActiveFile rewrites the active observations file without events
+// older than cutoff. It returns the number of events removed and the bytes
+// freed.
+func trimActiveFile(filePath string, cutoff time.Time) (int, int64, error) {
+	// #nosec G304 -- filePath is built from a controlled directory.
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, fmt.Errorf("read observations file: %w", err)
+	}
+
+	var kept bytes.Buffer
+	removed := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineBytes)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		var event struct {
+			Timestamp time.Time `json:"timestamp"`
+		}
+		if json.Unmarshal(line, &event) == nil && !event.Timestamp.IsZero() && event.Timestamp.Before(cutoff) {
+			removed++
+			continue
+		}
+
+		kept.Write(line)
+		kept.WriteByte('\n')
+	}
+	if scanErr := scanner.Err(); scanErr != nil {
+		return 0, 0, fmt.Errorf("scan observations file: %w", scanErr)
+	}
+
+	if removed == 0 {
+		return 0, 0, nil
+	}
+
+	tmpPath := filePath + ".tmp"
+	if writeErr := os.WriteFile(tmpPath, kept.Bytes(), 0o600); writeErr != nil {
+		return 0, 0, fmt.Errorf("write observations file: %w", writeErr)
+	}
+	if renameErr := os.Rename(tmpPath, filePath); renameErr != nil {
+		_ = os.Remove(tmpPath)
+		return 0, 0, fmt.Errorf("replace observations file: %w", renameErr)
+	}
+
+	return removed, int64(len(data) - kept.Len()), nil
+}
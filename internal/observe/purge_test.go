@@ -0,0 +1,93 @@
+package observe_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/observe"
+)
+
+// writeAged writes content to path and backdates its mtime by age.
+func writeAged(t *testing.T, path, content string, age time.Duration) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	mtime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+}
+
+func eventLine(ts time.Time, tool string) string {
+	return fmt.Sprintf(`{"timestamp":%q,"phase":"pre","tool_name":%q,"session_id":"s"}`+"\n",
+		ts.Format(time.RFC3339Nano), tool)
+}
+
+func TestPurge(t *testing.T) {
+	dir := t.TempDir()
+	day := 24 * time.Hour
+	now := time.Now()
+
+	oldArchive := filepath.Join(dir, "observations-20240101-000000.jsonl")
+	recentArchive := filepath.Join(dir, "observations-20240301-000000.jsonl")
+	unrelated := filepath.Join(dir, "notes.jsonl")
+	writeAged(t, oldArchive, "old archive\n", 45*day)
+	writeAged(t, recentArchive, "recent archive\n", 5*day)
+	writeAged(t, unrelated, "not an observation file\n", 90*day)
+
+	oldEvent := eventLine(now.Add(-40*day), "OldTool")
+	newEvent := eventLine(now.Add(-1*day), "NewTool")
+	garbage := "not json\n"
+	active := filepath.Join(dir, "observations.jsonl")
+	writeAged(t, active, oldEvent+garbage+newEvent, 0)
+
+	result, err := observe.Purge(dir, now.Add(-30*day))
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, oldArchive)
+	assert.FileExists(t, recentArchive)
+	assert.FileExists(t, unrelated)
+
+	data, err := os.ReadFile(active)
+	require.NoError(t, err)
+	assert.Equal(t, garbage+newEvent, string(data))
+
+	assert.Equal(t, 1, result.FilesRemoved)
+	assert.Equal(t, 1, result.EventsRemoved)
+	assert.Equal(t, int64(len("old archive\n")+len(oldEvent)), result.BytesFreed)
+}
+
+func TestPurge_NothingToRemove(t *testing.T) {
+	dir := t.TempDir()
+	active := filepath.Join(dir, "observations.jsonl")
+	content := eventLine(time.Now(), "Bash")
+	writeAged(t, active, content, 0)
+
+	result, err := observe.Purge(dir, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, observe.PurgeResult{FilesRemoved: 0, EventsRemoved: 0, BytesFreed: 0}, result)
+
+	data, err := os.ReadFile(active)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
+
+func TestPurge_MissingDir(t *testing.T) {
+	result, err := observe.Purge(filepath.Join(t.TempDir(), "missing"), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.FilesRemoved)
+}
+
+func TestDefaultDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir, err := observe.DefaultDir()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(dir, home))
+	assert.Equal(t, "observations", filepath.Base(dir))
+}
@@ -0,0 +1,347 @@
+package observe
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidQuery is returned when a query expression cannot be parsed.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// queryFields maps the field names a query may use to the event value they
+// read. Both the short names and the JSON names are accepted.
+var queryFields = map[string]func(*Event) string{
+	"tool":           func(e *Event) string { return e.ToolName },
+	"tool_name":      func(e *Event) string { return e.ToolName },
+	"phase":          func(e *Event) string { return e.Phase },
+	"session":        func(e *Event) string { return e.SessionID },
+	"session_id":     func(e *Event) string { return e.SessionID },
+	"error":          func(e *Event) string { return e.Error },
+	"input":          func(e *Event) string { return string(e.ToolInput) },
+	"tool_input":     func(e *Event) string { return string(e.ToolInput) },
+	"output":         func(e *Event) string { return string(e.ToolOutput) },
+	"tool_output":    func(e *Event) string { return string(e.ToolOutput) },
+	"file":           func(e *Event) string { return e.FilePath },
+	"file_path":      func(e *Event) string { return e.FilePath },
+	"correlation":    func(e *Event) string { return e.CorrelationID },
+	"correlation_id": func(e *Event) string { return e.CorrelationID },
+}
+
+// Query is a parsed filter expression over events. Expressions compare
+// fields with ==, !=, or contains and combine comparisons with && and ||,
+// where && binds tighter. Parentheses group. Values are bare words or
+// quoted strings:
+//
+//	tool==Bash && phase==failure
+//	error contains "permission denied" || (tool==Edit && input contains .go)
+type Query struct {
+	root queryNode
+}
+
+// ParseQuery parses expr into a Query.
+func ParseQuery(expr string) (*Query, error) {
+	tokens, err := tokenizeQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalidQuery)
+	}
+
+	p := &queryParser{tokens: tokens, pos: 0}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidQuery, p.tokens[p.pos].text)
+	}
+
+	return &Query{root: root}, nil
+}
+
+// Match reports whether event satisfies the query.
+func (q *Query) Match(event *Event) bool {
+	return q.root.match(event)
+}
+
+// Filter writes each JSONL line from r whose event matches q to w and
+// returns how many matched. Lines that are not valid events are skipped.
+func (q *Query) Filter(r io.Reader, w io.Writer) (int, error) {
+	matched := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineBytes)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		var event Event
+		if json.Unmarshal(line, &event) != nil || !q.Match(&event) {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "%s\n", line); err != nil {
+			return matched, fmt.Errorf("write event: %w", err)
+		}
+		matched++
+	}
+	if err := scanner.Err(); err != nil {
+		return matched, fmt.Errorf("scan observations: %w", err)
+	}
+
+	return matched, nil
+}
+
+// EventFiles returns the observation files in dir, oldest first: rotated
+// archives in name order, then the active file. Files that do not exist
+// are left out.
+func EventFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, archivePattern))
+	if err != nil {
+		return nil, fmt.Errorf("list rotated observations: %w", err)
+	}
+	slices.Sort(files)
+
+	active := filepath.Join(dir, observationsFile)
+	if _, statErr := os.Stat(active); statErr == nil {
+		files = append(files, active)
+	}
+
+	return files, nil
+}
+
+// queryNode is one node of a parsed query.
+type queryNode interface {
+	match(event *Event) bool
+}
+
+// queryAnd matches when both sides match.
+type queryAnd struct{ left, right queryNode }
+
+func (n *queryAnd) match(e *Event) bool { return n.left.match(e) && n.right.match(e) }
+
+// queryOr matches when either side matches.
+type queryOr struct{ left, right queryNode }
+
+func (n *queryOr) match(e *Event) bool { return n.left.match(e) || n.right.match(e) }
+
+// queryCompare compares one event field with a literal value.
+type queryCompare struct {
+	field func(*Event) string
+	op    string
+	value string
+}
+
+func (n *queryCompare) match(e *Event) bool {
+	got := n.field(e)
+	switch n.op {
+	case "==":
+		return got == n.value
+	case "!=":
+		return got != n.value
+	default: // contains
+		return strings.Contains(got, n.value)
+	}
+}
+
+// Token kinds produced by tokenizeQuery.
+const (
+	tokenWord = iota
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+// queryToken is one lexical token of a query.
+type queryToken struct {
+	kind int
+	text string
+}
+
+// tokenizeQuery splits expr into words, quoted strings, operators, and
+// parentheses.
+func tokenizeQuery(expr string) ([]queryToken, error) {
+	var tokens []queryToken
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen, text: ")"})
+			i++
+		case strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="),
+			strings.HasPrefix(expr[i:], "&&"), strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, queryToken{kind: tokenOp, text: expr[i : i+2]})
+			i += 2
+		case c == '"' || c == '\'':
+			text, n, err := readQuoted(expr[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, queryToken{kind: tokenString, text: text})
+			i += n
+		default:
+			start := i
+			for i < len(expr) && isWordByte(expr[i]) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidQuery, c, i)
+			}
+			tokens = append(tokens, queryToken{kind: tokenWord, text: expr[start:i]})
+		}
+	}
+
+	return tokens, nil
+}
+
+// readQuoted reads the quoted string at the start of s and returns its
+// value and length. Double-quoted strings take Go escapes; single-quoted
+// strings are taken literally.
+func readQuoted(s string) (string, int, error) {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			if quote == '\'' {
+				return s[1:i], i + 1, nil
+			}
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", 0, fmt.Errorf("%w: bad string %s: %w", ErrInvalidQuery, s[:i+1], err)
+			}
+			return value, i + 1, nil
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: unterminated string %s", ErrInvalidQuery, s)
+}
+
+// isWordByte reports whether c may appear in a bare word.
+func isWordByte(c byte) bool {
+	if c >= 0x80 {
+		return true
+	}
+	r := rune(c)
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-./:*@+", r)
+}
+
+// queryParser is a recursive-descent parser over query tokens.
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+// parseOr parses and-expressions joined by ||.
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("||") {
+		right, rightErr := p.parseAnd()
+		if rightErr != nil {
+			return nil, rightErr
+		}
+		left = &queryOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses terms joined by &&.
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("&&") {
+		right, rightErr := p.parseTerm()
+		if rightErr != nil {
+			return nil, rightErr
+		}
+		left = &queryAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseTerm parses a parenthesized expression or a comparison.
+func (p *queryParser) parseTerm() (queryNode, error) {
+	tok, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("%w: expression ends early", ErrInvalidQuery)
+	}
+
+	if tok.kind == tokenLParen {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing, closed := p.next(); !closed || closing.kind != tokenRParen {
+			return nil, fmt.Errorf("%w: missing )", ErrInvalidQuery)
+		}
+		return node, nil
+	}
+
+	if tok.kind != tokenWord {
+		return nil, fmt.Errorf("%w: expected a field name, got %q", ErrInvalidQuery, tok.text)
+	}
+	field, known := queryFields[tok.text]
+	if !known {
+		return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidQuery, tok.text)
+	}
+
+	opTok, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no operator", ErrInvalidQuery, tok.text)
+	}
+	isCompareOp := opTok.kind == tokenOp && (opTok.text == "==" || opTok.text == "!=")
+	if !isCompareOp && (opTok.kind != tokenWord || opTok.text != "contains") {
+		return nil, fmt.Errorf("%w: expected ==, !=, or contains after %s, got %q",
+			ErrInvalidQuery, tok.text, opTok.text)
+	}
+
+	valueTok, ok := p.next()
+	if !ok || (valueTok.kind != tokenWord && valueTok.kind != tokenString) {
+		return nil, fmt.Errorf("%w: %s %s has no value", ErrInvalidQuery, tok.text, opTok.text)
+	}
+
+	return &queryCompare{field: field, op: opTok.text, value: valueTok.text}, nil
+}
+
+// next consumes and returns the next token.
+func (p *queryParser) next() (queryToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return queryToken{kind: 0, text: ""}, false
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	return tok, true
+}
+
+// acceptOp consumes the next token when it is the operator op.
+func (p *queryParser) acceptOp(op string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOp && p.tokens[p.pos].text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
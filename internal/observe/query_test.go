@@ -0,0 +1,155 @@
+package observe_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/observe"
+)
+
+func queryEvent(tool, phase, errText string) *observe.Event {
+	return &observe.Event{
+		Phase:         phase,
+		ToolName:      tool,
+		ToolInput:     json.RawMessage(`{"file_path":"/project/main.go"}`),
+		ToolOutput:    nil,
+		Error:         errText,
+		SessionID:     "sess-1",
+		FilePath:      "",
+		CorrelationID: "",
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	bashFailure := queryEvent("Bash", "failure", "permission denied")
+	bashPost := queryEvent("Bash", "post", "")
+	editPre := queryEvent("Edit", "pre", "")
+	editPre.FilePath = "/project/main.go"
+	bashFailure.CorrelationID = "toolu_01"
+	bashPost.CorrelationID = "toolu_01"
+
+	tests := []struct {
+		name string
+		expr string
+		want map[*observe.Event]bool
+	}{
+		{
+			name: "equality joined by and",
+			expr: "tool==Bash && phase==failure",
+			want: map[*observe.Event]bool{bashFailure: true, bashPost: false, editPre: false},
+		},
+		{
+			name: "inequality",
+			expr: "tool != Bash",
+			want: map[*observe.Event]bool{bashFailure: false, bashPost: false, editPre: true},
+		},
+		{
+			name: "contains with quoted value",
+			expr: `error contains "permission denied"`,
+			want: map[*observe.Event]bool{bashFailure: true, bashPost: false, editPre: false},
+		},
+		{
+			name: "or with and binding tighter",
+			expr: "phase==pre || tool==Bash && phase==post",
+			want: map[*observe.Event]bool{bashFailure: false, bashPost: true, editPre: true},
+		},
+		{
+			name: "parentheses group",
+			expr: "(phase==pre || tool==Bash) && error==''",
+			want: map[*observe.Event]bool{bashFailure: false, bashPost: true, editPre: true},
+		},
+		{
+			name: "file path",
+			expr: "file==/project/main.go && phase==pre",
+			want: map[*observe.Event]bool{bashFailure: false, bashPost: false, editPre: true},
+		},
+		{
+			name: "correlation id",
+			expr: "correlation_id==toolu_01",
+			want: map[*observe.Event]bool{bashFailure: true, bashPost: true, editPre: false},
+		},
+		{
+			name: "json field names and bare path values",
+			expr: "tool_name==Edit && tool_input contains /project/main.go",
+			want: map[*observe.Event]bool{bashFailure: false, bashPost: false, editPre: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := observe.ParseQuery(tt.expr)
+			require.NoError(t, err)
+			for event, want := range tt.want {
+				assert.Equal(t, want, q.Match(event), "%s on %s/%s", tt.expr, event.ToolName, event.Phase)
+			}
+		})
+	}
+}
+
+func TestParseQuery_Malformed(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{name: "empty", expr: "  "},
+		{name: "missing value", expr: "tool=="},
+		{name: "unknown field", expr: "command==ls"},
+		{name: "unknown operator", expr: "tool ~ Bash"},
+		{name: "single equals", expr: "tool=Bash"},
+		{name: "dangling and", expr: "tool==Bash &&"},
+		{name: "unclosed paren", expr: "(tool==Bash"},
+		{name: "unterminated string", expr: `error contains "denied`},
+		{name: "trailing token", expr: "tool==Bash phase==post"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := observe.ParseQuery(tt.expr)
+			require.ErrorIs(t, err, observe.ErrInvalidQuery)
+		})
+	}
+}
+
+func TestQuery_Filter(t *testing.T) {
+	lines := []string{
+		`{"phase":"failure","tool_name":"Bash","session_id":"a"}`,
+		`not json`,
+		`{"phase":"post","tool_name":"Bash","session_id":"a"}`,
+		`{"phase":"failure","tool_name":"Edit","session_id":"b"}`,
+	}
+
+	q, err := observe.ParseQuery("phase==failure")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	n, err := q.Filter(strings.NewReader(strings.Join(lines, "\n")), &out)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, n)
+	assert.Equal(t, lines[0]+"\n"+lines[3]+"\n", out.String())
+}
+
+func TestEventFiles(t *testing.T) {
+	dir := t.TempDir()
+	active := filepath.Join(dir, "observations.jsonl")
+	newer := filepath.Join(dir, "observations-20240601-000000.jsonl")
+	older := filepath.Join(dir, "observations-20240101-000000.jsonl")
+	for _, path := range []string{active, newer, older} {
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+
+	files, err := observe.EventFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{older, newer, active}, files)
+
+	files, err = observe.EventFiles(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 // Level represents the severity/type of output message.
@@ -37,26 +38,105 @@ type Terminal struct {
 	stdout io.Writer
 	stderr io.Writer
 	styles map[Level]lipgloss.Style
+	quiet  bool
+}
+
+// ColorMode selects when Terminal output is colored.
+type ColorMode string
+
+const (
+	// ColorAuto colors output when stdout is a terminal and NO_COLOR is
+	// not set.
+	ColorAuto ColorMode = "auto"
+	// ColorAlways colors output wherever it goes.
+	ColorAlways ColorMode = "always"
+	// ColorNever writes plain output.
+	ColorNever ColorMode = "never"
+)
+
+// ParseColorMode checks a --color flag value.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch mode := ColorMode(s); mode {
+	case ColorAuto, ColorAlways, ColorNever:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid color mode %q: must be %s, %s, or %s", s, ColorAuto, ColorAlways, ColorNever)
+	}
+}
+
+// TerminalOption configures a Terminal.
+type TerminalOption func(*Terminal)
+
+// WithColor sets when the Terminal colors its output. The default is
+// ColorAuto.
+func WithColor(mode ColorMode) TerminalOption {
+	return func(t *Terminal) {
+		r := lipgloss.DefaultRenderer()
+		if profile, ok := colorProfile(mode); ok {
+			r = lipgloss.NewRenderer(io.Discard)
+			r.SetColorProfile(profile)
+		}
+		t.styles = defaultStyles(r)
+	}
+}
+
+// SetDefaultColorMode applies mode to styles drawn outside a Terminal, such
+// as tables. ColorAuto leaves terminal detection in place.
+func SetDefaultColorMode(mode ColorMode) {
+	if profile, ok := colorProfile(mode); ok {
+		lipgloss.SetColorProfile(profile)
+	}
+}
+
+// colorProfile returns the fixed color profile for mode, or false for
+// ColorAuto, which detects it from the output.
+func colorProfile(mode ColorMode) (termenv.Profile, bool) {
+	switch mode {
+	case ColorAlways:
+		return termenv.TrueColor, true
+	case ColorNever:
+		return termenv.Ascii, true
+	case ColorAuto:
+		// Detected from the output.
+	}
+	return termenv.Ascii, false
 }
 
 // NewTerminal creates a new Terminal with default styling.
-func NewTerminal(stdout, stderr io.Writer) *Terminal {
-	return &Terminal{
+func NewTerminal(stdout, stderr io.Writer, opts ...TerminalOption) *Terminal {
+	t := &Terminal{
 		mu:     sync.Mutex{},
 		stdout: stdout,
 		stderr: stderr,
-		styles: defaultStyles(),
+		styles: defaultStyles(lipgloss.DefaultRenderer()),
+		quiet:  false,
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
-// defaultStyles returns the default lipgloss styles for each level.
-func defaultStyles() map[Level]lipgloss.Style {
+// SetQuiet enables or disables quiet mode. In quiet mode the Info, Success,
+// and Warning status messages are suppressed. Write and Raw carry a
+// command's primary output and are always written, as is everything sent
+// to stderr.
+func (t *Terminal) SetQuiet(quiet bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.quiet = quiet
+}
+
+// defaultStyles returns the default lipgloss styles for each level, drawn
+// by r.
+func defaultStyles(r *lipgloss.Renderer) map[Level]lipgloss.Style {
 	return map[Level]lipgloss.Style{
-		Info:    lipgloss.NewStyle().Foreground(lipgloss.Color("#89dceb")), // Sky blue
-		Success: lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1")), // Green
-		Warning: lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af")), // Yellow
-		Error:   lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")), // Red
-		Debug:   lipgloss.NewStyle().Foreground(lipgloss.Color("#94e2d5")), // Teal
+		Info:    r.NewStyle().Foreground(lipgloss.Color("#89dceb")), // Sky blue
+		Success: r.NewStyle().Foreground(lipgloss.Color("#a6e3a1")), // Green
+		Warning: r.NewStyle().Foreground(lipgloss.Color("#f9e2af")), // Yellow
+		Error:   r.NewStyle().Foreground(lipgloss.Color("#f38ba8")), // Red
+		Debug:   r.NewStyle().Foreground(lipgloss.Color("#94e2d5")), // Teal
 	}
 }
 
@@ -84,8 +164,16 @@ func (t *Terminal) WriteError(message string) error {
 	return nil
 }
 
-// Print writes a formatted message at the given level to stdout.
+// Print writes a formatted status message at the given level to stdout,
+// unless quiet mode is on.
 func (t *Terminal) Print(level Level, format string, args ...any) error {
+	t.mu.Lock()
+	quiet := t.quiet
+	t.mu.Unlock()
+	if quiet {
+		return nil
+	}
+
 	msg := fmt.Sprintf(format, args...)
 	styled := t.styles[level].Render(msg)
 	return t.Write(styled)
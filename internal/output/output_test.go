@@ -7,6 +7,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
 	"github.com/riddopic/cc-tools/internal/output"
 )
 
@@ -485,6 +488,46 @@ func TestTerminalRawError(t *testing.T) {
 	}
 }
 
+func TestTerminalQuiet(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	term := output.NewTerminal(stdout, stderr)
+	term.SetQuiet(true)
+
+	_ = term.Success("done")
+	_ = term.Info("info")
+	_ = term.Warning("careful")
+
+	if stdout.Len() > 0 {
+		t.Errorf("expected no status messages in quiet mode, got %q", stdout.String())
+	}
+
+	_ = term.Write("plain")
+	_ = term.Raw("raw")
+
+	if stdout.String() != "plain\nraw" {
+		t.Errorf("expected primary output in quiet mode, got %q", stdout.String())
+	}
+
+	_ = term.Error("failed")
+	_ = term.WriteError("plain error")
+
+	if !strings.Contains(stderr.String(), "failed") {
+		t.Error("expected Error output on stderr in quiet mode")
+	}
+	if !strings.Contains(stderr.String(), "plain error") {
+		t.Error("expected WriteError output on stderr in quiet mode")
+	}
+
+	term.SetQuiet(false)
+	_ = term.Info("visible")
+
+	if !strings.Contains(stdout.String(), "visible") {
+		t.Error("expected Info output after disabling quiet mode")
+	}
+}
+
 func TestTerminalStyle(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -750,3 +793,74 @@ func formatMessage(format string, args ...any) string {
 
 	return fmt.Sprintf(format, args...)
 }
+
+// setDefaultProfile fixes the default lipgloss renderer's color profile for
+// the test, standing in for terminal detection.
+func setDefaultProfile(t *testing.T, profile termenv.Profile) {
+	t.Helper()
+	previous := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(profile)
+	t.Cleanup(func() { lipgloss.SetColorProfile(previous) })
+}
+
+func TestWithColor(t *testing.T) {
+	const escape = "\x1b["
+
+	t.Run("never writes plain text on a terminal", func(t *testing.T) {
+		setDefaultProfile(t, termenv.TrueColor)
+		stdout := &bytes.Buffer{}
+
+		if err := output.NewTerminal(stdout, &bytes.Buffer{}).Info("hello"); err != nil {
+			t.Fatalf("Info() error = %v", err)
+		}
+		if !strings.Contains(stdout.String(), escape) {
+			t.Fatalf("auto should color output that looks like a terminal, got %q", stdout.String())
+		}
+
+		stdout.Reset()
+		term := output.NewTerminal(stdout, &bytes.Buffer{}, output.WithColor(output.ColorNever))
+		if err := term.Info("hello"); err != nil {
+			t.Fatalf("Info() error = %v", err)
+		}
+		if got := stdout.String(); got != "hello\n" {
+			t.Errorf("never output = %q, want %q", got, "hello\n")
+		}
+	})
+
+	t.Run("always colors a buffer", func(t *testing.T) {
+		setDefaultProfile(t, termenv.Ascii)
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+
+		if err := output.NewTerminal(stdout, stderr).Info("hello"); err != nil {
+			t.Fatalf("Info() error = %v", err)
+		}
+		if strings.Contains(stdout.String(), escape) {
+			t.Fatalf("auto should write plain text to a non-terminal, got %q", stdout.String())
+		}
+
+		stdout.Reset()
+		term := output.NewTerminal(stdout, stderr, output.WithColor(output.ColorAlways))
+		_ = term.Info("hello")
+		_ = term.Error("failed")
+		if !strings.Contains(stdout.String(), escape) {
+			t.Errorf("always stdout = %q, want escape codes", stdout.String())
+		}
+		if !strings.Contains(stderr.String(), escape) {
+			t.Errorf("always stderr = %q, want escape codes", stderr.String())
+		}
+	})
+}
+
+func TestParseColorMode(t *testing.T) {
+	for _, s := range []string{"auto", "always", "never"} {
+		mode, err := output.ParseColorMode(s)
+		if err != nil || mode != output.ColorMode(s) {
+			t.Errorf("ParseColorMode(%q) = %q, %v", s, mode, err)
+		}
+	}
+
+	if _, err := output.ParseColorMode("sometimes"); err == nil {
+		t.Error("ParseColorMode(\"sometimes\") succeeded")
+	}
+}
@@ -58,10 +58,40 @@ func DetectWithPreferred(projectDir, preferred string) string {
 	return Detect(projectDir)
 }
 
+// ReadEnvFile returns the PREFERRED_PACKAGE_MANAGER value recorded in the
+// env file, and whether one is recorded. A missing file records nothing.
+func ReadEnvFile(envFilePath string) (string, bool, error) {
+	data, err := os.ReadFile(envFilePath)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("read env file %s: %w", envFilePath, err)
+	}
+
+	value, ok := envFileValue(data)
+	return value, ok, nil
+}
+
+// envFileValue finds the PREFERRED_PACKAGE_MANAGER line in env file data.
+func envFileValue(data []byte) (string, bool) {
+	prefix := envVarName + "="
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		if value, found := strings.CutPrefix(scanner.Text(), prefix); found {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
 // WriteToEnvFile writes the PREFERRED_PACKAGE_MANAGER to the specified env file
 // so it persists across Bash commands in the Claude Code session.
 // If the file already contains a PREFERRED_PACKAGE_MANAGER line, the existing
-// value is preserved to respect the user's choice.
+// value is preserved to respect the user's choice and the file is not
+// written.
 func WriteToEnvFile(envFilePath, manager string) error {
 	prefix := envVarName + "="
 
@@ -70,13 +100,8 @@ func WriteToEnvFile(envFilePath, manager string) error {
 		return fmt.Errorf("read env file %s: %w", envFilePath, err)
 	}
 
-	if err == nil {
-		scanner := bufio.NewScanner(strings.NewReader(string(data)))
-		for scanner.Scan() {
-			if strings.HasPrefix(scanner.Text(), prefix) {
-				return nil // already set — respect existing value
-			}
-		}
+	if _, ok := envFileValue(data); ok {
+		return nil // already set — respect existing value
 	}
 
 	var content string
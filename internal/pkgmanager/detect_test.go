@@ -229,6 +229,26 @@ func TestWriteToEnvFile_Idempotent(t *testing.T) {
 		"file should contain exactly one entry after multiple writes")
 }
 
+func TestReadEnvFile(t *testing.T) {
+	dir := t.TempDir()
+
+	_, ok, err := pkgmanager.ReadEnvFile(filepath.Join(dir, "missing.env"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	envFile := filepath.Join(dir, "claude.env")
+	require.NoError(t, os.WriteFile(envFile, []byte("A=1\nPREFERRED_PACKAGE_MANAGER=pnpm\n"), 0o600))
+	got, ok, err := pkgmanager.ReadEnvFile(envFile)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "pnpm", got)
+
+	require.NoError(t, os.WriteFile(envFile, []byte("A=1\n"), 0o600))
+	_, ok, err = pkgmanager.ReadEnvFile(envFile)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
 func TestWriteToEnvFileError(t *testing.T) {
 	err := pkgmanager.WriteToEnvFile("/nonexistent/path/to/file.env", "npm")
 	require.Error(t, err)
@@ -0,0 +1,312 @@
+// Package selfupdate replaces the running cc-tools binary with the latest
+// GitHub release.
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRepo is the GitHub repository releases are fetched from.
+	DefaultRepo = "riddopic/cc-tools"
+
+	// defaultAPIURL is the GitHub REST API base URL.
+	defaultAPIURL = "https://api.github.com"
+
+	// checksumsAsset is the release asset listing the SHA-256 of every
+	// other asset, one "<hex>  <name>" line each.
+	checksumsAsset = "checksums.txt"
+
+	// httpTimeout bounds each request, including binary downloads.
+	httpTimeout = 2 * time.Minute
+)
+
+var (
+	// ErrNoAsset is returned when a release has no binary for this platform.
+	ErrNoAsset = errors.New("no release asset for this platform")
+	// ErrChecksumMissing is returned when the checksums file does not list
+	// the downloaded asset.
+	ErrChecksumMissing = errors.New("asset not listed in checksums")
+	// ErrChecksumMismatch is returned when a download does not match its
+	// published checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrInvalidVersion is returned for version strings that are not
+	// dotted numbers.
+	ErrInvalidVersion = errors.New("invalid version")
+)
+
+// HTTPClient sends HTTP requests. *http.Client satisfies it.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// Release is a published GitHub release.
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Option configures an Updater.
+type Option func(*Updater)
+
+// WithHTTPClient overrides the client used for API calls and downloads.
+func WithHTTPClient(client HTTPClient) Option {
+	return func(u *Updater) {
+		u.client = client
+	}
+}
+
+// WithAPIURL overrides the GitHub API base URL for testing.
+func WithAPIURL(apiURL string) Option {
+	return func(u *Updater) {
+		u.apiURL = strings.TrimRight(apiURL, "/")
+	}
+}
+
+// Updater finds and installs cc-tools releases.
+type Updater struct {
+	client HTTPClient
+	apiURL string
+	repo   string
+}
+
+// NewUpdater creates an Updater for DefaultRepo.
+func NewUpdater(opts ...Option) *Updater {
+	u := &Updater{
+		client: &http.Client{Timeout: httpTimeout},
+		apiURL: defaultAPIURL,
+		repo:   DefaultRepo,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
+}
+
+// AssetName returns the release asset holding the binary for goos and
+// goarch, for example "cc-tools_linux_amd64".
+func AssetName(goos, goarch string) string {
+	name := "cc-tools_" + goos + "_" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Latest fetches the most recent published release.
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", u.apiURL, u.repo)
+
+	body, err := u.get(ctx, url, "application/vnd.github+json")
+	if err != nil {
+		return nil, fmt.Errorf("fetch latest release: %w", err)
+	}
+	defer body.Close()
+
+	var release Release
+	if decodeErr := json.NewDecoder(body).Decode(&release); decodeErr != nil {
+		return nil, fmt.Errorf("decode latest release: %w", decodeErr)
+	}
+	return &release, nil
+}
+
+// Install downloads the release binary for the running platform, checks it
+// against the release's checksums file, and renames it over exePath.
+func (u *Updater) Install(ctx context.Context, release *Release, exePath string) error {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	binary, ok := release.asset(name)
+	if !ok {
+		return fmt.Errorf("%w: %s in %s", ErrNoAsset, name, release.TagName)
+	}
+	checksums, ok := release.asset(checksumsAsset)
+	if !ok {
+		return fmt.Errorf("%w: %s has no %s", ErrChecksumMissing, release.TagName, checksumsAsset)
+	}
+
+	sums, err := u.download(ctx, checksums.DownloadURL)
+	if err != nil {
+		return err
+	}
+
+	// The temp file lives next to the binary so the final rename stays on
+	// one filesystem and is atomic.
+	tmp, err := os.CreateTemp(filepath.Dir(exePath), ".cc-tools-update-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	hash := sha256.New()
+	if err = u.copyTo(ctx, io.MultiWriter(tmp, hash), binary.DownloadURL); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	if err = VerifyChecksum(sums, name, hash.Sum(nil)); err != nil {
+		return err
+	}
+
+	// #nosec G302 -- the replacement binary must be executable.
+	if err = os.Chmod(tmpPath, 0o755); err != nil {
+		return fmt.Errorf("chmod new binary: %w", err)
+	}
+	if err = os.Rename(tmpPath, exePath); err != nil {
+		return fmt.Errorf("replace %s: %w", exePath, err)
+	}
+	return nil
+}
+
+// VerifyChecksum checks sum against the entry for name in a checksums file
+// made of "<hex sha256>  <name>" lines.
+func VerifyChecksum(checksums []byte, name string, sum []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+
+		want, err := hex.DecodeString(fields[0])
+		if err != nil {
+			return fmt.Errorf("%w: bad checksum for %s: %w", ErrChecksumMismatch, name, err)
+		}
+		if !bytes.Equal(want, sum) {
+			return fmt.Errorf("%w: %s is %x, want %x", ErrChecksumMismatch, name, sum, want)
+		}
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read checksums: %w", err)
+	}
+	return fmt.Errorf("%w: %s", ErrChecksumMissing, name)
+}
+
+// CompareVersions compares two versions such as "v1.4.0" and "1.10.2",
+// returning -1, 0, or 1 as a is older than, equal to, or newer than b. A
+// leading "v" and any "-prerelease" or "+build" suffix are ignored.
+func CompareVersions(a, b string) (int, error) {
+	partsA, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	partsB, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range max(len(partsA), len(partsB)) {
+		var x, y int
+		if i < len(partsA) {
+			x = partsA[i]
+		}
+		if i < len(partsB) {
+			y = partsB[i]
+		}
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// parseVersion splits a version into its numeric components.
+func parseVersion(v string) ([]int, error) {
+	core := strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	if core == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidVersion, v)
+	}
+
+	var parts []int
+	for field := range strings.SplitSeq(core, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidVersion, v)
+		}
+		parts = append(parts, n)
+	}
+	return parts, nil
+}
+
+// asset returns the release asset called name.
+func (r *Release) asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{Name: "", DownloadURL: ""}, false
+}
+
+// download reads the whole body at url.
+func (u *Updater) download(ctx context.Context, url string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := u.copyTo(ctx, &buf, url); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// copyTo streams the body at url into w.
+func (u *Updater) copyTo(ctx context.Context, w io.Writer, url string) error {
+	body, err := u.get(ctx, url, "application/octet-stream")
+	if err != nil {
+		return fmt.Errorf("download %s: %w", url, err)
+	}
+	defer body.Close()
+
+	if _, err = io.Copy(w, body); err != nil {
+		return fmt.Errorf("download %s: %w", url, err)
+	}
+	return nil
+}
+
+// get issues a GET request and returns the body of a 200 response.
+func (u *Updater) get(ctx context.Context, url, accept string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
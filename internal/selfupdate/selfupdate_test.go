@@ -0,0 +1,171 @@
+package selfupdate_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/selfupdate"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{a: "v1.2.3", b: "v1.2.3", want: 0, wantErr: false},
+		{a: "1.2.3", b: "v1.2.3", want: 0, wantErr: false},
+		{a: "v1.2.3", b: "v1.2.4", want: -1, wantErr: false},
+		{a: "v1.10.0", b: "v1.9.9", want: 1, wantErr: false},
+		{a: "v2.0", b: "v2.0.0", want: 0, wantErr: false},
+		{a: "v2.0.1", b: "v2.0", want: 1, wantErr: false},
+		{a: "v1.3.0-rc.1", b: "v1.3.0", want: 0, wantErr: false},
+		{a: "dev", b: "v1.0.0", want: 0, wantErr: true},
+		{a: "v1.x", b: "v1.0.0", want: 0, wantErr: true},
+		{a: "v1.0.0", b: "", want: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			got, err := selfupdate.CompareVersions(tt.a, tt.b)
+			if tt.wantErr {
+				require.ErrorIs(t, err, selfupdate.ErrInvalidVersion)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("binary contents")
+	sum := sha256.Sum256(data)
+	good := hex.EncodeToString(sum[:])
+	other := sha256.Sum256([]byte("something else"))
+
+	tests := []struct {
+		name      string
+		checksums string
+		wantErr   error
+	}{
+		{name: "match", checksums: good + "  cc-tools_linux_amd64\n", wantErr: nil},
+		{name: "binary mode marker", checksums: good + " *cc-tools_linux_amd64\n", wantErr: nil},
+		{
+			name:      "match among other entries",
+			checksums: hex.EncodeToString(other[:]) + "  cc-tools_darwin_arm64\n" + good + "  cc-tools_linux_amd64\n",
+			wantErr:   nil,
+		},
+		{
+			name:      "mismatch",
+			checksums: hex.EncodeToString(other[:]) + "  cc-tools_linux_amd64\n",
+			wantErr:   selfupdate.ErrChecksumMismatch,
+		},
+		{name: "not listed", checksums: good + "  cc-tools_darwin_arm64\n", wantErr: selfupdate.ErrChecksumMissing},
+		{name: "malformed hex", checksums: "zz  cc-tools_linux_amd64\n", wantErr: selfupdate.ErrChecksumMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := selfupdate.VerifyChecksum([]byte(tt.checksums), "cc-tools_linux_amd64", sum[:])
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// newReleaseServer serves a latest release whose binary asset holds binary
+// and whose checksums file lists sum for it.
+func newReleaseServer(t *testing.T, binary []byte, sum string) *httptest.Server {
+	t.Helper()
+	name := selfupdate.AssetName(runtime.GOOS, runtime.GOARCH)
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/repos/"+selfupdate.DefaultRepo+"/releases/latest", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(selfupdate.Release{
+			TagName: "v9.9.9",
+			Assets: []selfupdate.Asset{
+				{Name: name, DownloadURL: srv.URL + "/download/" + name},
+				{Name: "checksums.txt", DownloadURL: srv.URL + "/download/checksums.txt"},
+			},
+		})
+	})
+	mux.HandleFunc("/download/"+name, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(binary)
+	})
+	mux.HandleFunc("/download/checksums.txt", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", sum, name)
+	})
+	return srv
+}
+
+func TestUpdater_Install(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho new\n")
+	digest := sha256.Sum256(binary)
+
+	t.Run("replaces the binary", func(t *testing.T) {
+		srv := newReleaseServer(t, binary, hex.EncodeToString(digest[:]))
+		updater := selfupdate.NewUpdater(selfupdate.WithAPIURL(srv.URL), selfupdate.WithHTTPClient(srv.Client()))
+
+		exePath := filepath.Join(t.TempDir(), "cc-tools")
+		require.NoError(t, os.WriteFile(exePath, []byte("old"), 0o755))
+
+		release, err := updater.Latest(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "v9.9.9", release.TagName)
+
+		require.NoError(t, updater.Install(context.Background(), release, exePath))
+		got, err := os.ReadFile(exePath)
+		require.NoError(t, err)
+		assert.Equal(t, binary, got)
+
+		entries, err := os.ReadDir(filepath.Dir(exePath))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "temp file should be cleaned up")
+	})
+
+	t.Run("keeps the binary on checksum mismatch", func(t *testing.T) {
+		wrong := sha256.Sum256([]byte("tampered"))
+		srv := newReleaseServer(t, binary, hex.EncodeToString(wrong[:]))
+		updater := selfupdate.NewUpdater(selfupdate.WithAPIURL(srv.URL), selfupdate.WithHTTPClient(srv.Client()))
+
+		exePath := filepath.Join(t.TempDir(), "cc-tools")
+		require.NoError(t, os.WriteFile(exePath, []byte("old"), 0o755))
+
+		release, err := updater.Latest(context.Background())
+		require.NoError(t, err)
+
+		err = updater.Install(context.Background(), release, exePath)
+		require.ErrorIs(t, err, selfupdate.ErrChecksumMismatch)
+		got, err := os.ReadFile(exePath)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(got))
+	})
+
+	t.Run("no asset for platform", func(t *testing.T) {
+		updater := selfupdate.NewUpdater()
+		release := &selfupdate.Release{TagName: "v1.0.0", Assets: nil}
+
+		err := updater.Install(context.Background(), release, filepath.Join(t.TempDir(), "cc-tools"))
+		require.True(t, errors.Is(err, selfupdate.ErrNoAsset), "error = %v", err)
+	})
+}
@@ -0,0 +1,67 @@
+package session
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// statsWindowDays is the number of days, ending today, covered by
+// Stats.PerDay.
+const statsWindowDays = 7
+
+// DayCount is the number of sessions recorded on one date.
+type DayCount struct {
+	Date     string `json:"date"`
+	Sessions int    `json:"sessions"`
+}
+
+// Stats summarizes a store's session history.
+type Stats struct {
+	Total                int        `json:"total"`
+	Skipped              int        `json:"skipped"`
+	PerDay               []DayCount `json:"per_day"`
+	AverageSummaryLength float64    `json:"average_summary_length"`
+	MostRecent           *Session   `json:"most_recent,omitempty"`
+}
+
+// ComputeStats aggregates sessions, as returned by [Store.Scan], relative to
+// now. PerDay covers the last seven days, oldest first. The average summary
+// length is in characters and only counts sessions that have a summary. The
+// most recent session is the one with the latest start time.
+func ComputeStats(sessions []*Session, skipped int, now time.Time) Stats {
+	stats := Stats{
+		Total:                len(sessions),
+		Skipped:              skipped,
+		PerDay:               make([]DayCount, statsWindowDays),
+		AverageSummaryLength: 0,
+		MostRecent:           nil,
+	}
+
+	index := make(map[string]int, statsWindowDays)
+	for i := range statsWindowDays {
+		date := now.AddDate(0, 0, i-statsWindowDays+1).Format(time.DateOnly)
+		stats.PerDay[i] = DayCount{Date: date, Sessions: 0}
+		index[date] = i
+	}
+
+	summaries, summaryChars := 0, 0
+	for _, sess := range sessions {
+		if i, ok := index[sess.Date]; ok {
+			stats.PerDay[i].Sessions++
+		}
+		if sess.Summary != "" {
+			summaries++
+			summaryChars += utf8.RuneCountInString(sess.Summary)
+		}
+		// Sessions arrive oldest first, so a later file wins a tie.
+		if stats.MostRecent == nil || !sess.Started.Before(stats.MostRecent.Started) {
+			stats.MostRecent = sess
+		}
+	}
+
+	if summaries > 0 {
+		stats.AverageSummaryLength = float64(summaryChars) / float64(summaries)
+	}
+
+	return stats
+}
@@ -0,0 +1,58 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/session"
+)
+
+func statsSession(id, date string, started time.Time, summary string) *session.Session {
+	return &session.Session{
+		Version:       "1",
+		ID:            id,
+		Date:          date,
+		Started:       started,
+		Ended:         time.Time{},
+		Title:         "",
+		Summary:       summary,
+		ToolsUsed:     nil,
+		FilesModified: nil,
+		MessageCount:  0,
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	sessions := []*session.Session{
+		statsSession("old", "2026-02-01", now.AddDate(0, 0, -37), "abcd"),
+		statsSession("edge", "2026-03-04", now.AddDate(0, 0, -6), ""),
+		statsSession("today", "2026-03-10", now.Add(-2*time.Hour), "héllo!"),
+		statsSession("late", "2026-03-10", now.Add(-time.Hour), ""),
+	}
+
+	stats := session.ComputeStats(sessions, 2, now)
+
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, 2, stats.Skipped)
+	assert.InDelta(t, 5.0, stats.AverageSummaryLength, 0.001)
+	require.NotNil(t, stats.MostRecent)
+	assert.Equal(t, "late", stats.MostRecent.ID)
+
+	require.Len(t, stats.PerDay, 7)
+	assert.Equal(t, session.DayCount{Date: "2026-03-04", Sessions: 1}, stats.PerDay[0])
+	assert.Equal(t, session.DayCount{Date: "2026-03-07", Sessions: 0}, stats.PerDay[3])
+	assert.Equal(t, session.DayCount{Date: "2026-03-10", Sessions: 2}, stats.PerDay[6])
+}
+
+func TestComputeStats_Empty(t *testing.T) {
+	stats := session.ComputeStats(nil, 0, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, 0, stats.Total)
+	assert.Zero(t, stats.AverageSummaryLength)
+	assert.Nil(t, stats.MostRecent)
+	assert.Len(t, stats.PerDay, 7)
+}
@@ -178,6 +178,42 @@ func (s *Store) Search(query string) ([]*Session, error) {
 	return result, nil
 }
 
+// snapshotDir is the subdirectory of the store that holds session snapshots.
+const snapshotDir = "snapshots"
+
+// snapshotTimeFormat is the timestamp layout used in snapshot filenames.
+const snapshotTimeFormat = "20060102-150405"
+
+// Snapshot writes a copy of session to snapshots/{id}-{timestamp}.json in
+// the store directory and returns the path written. Snapshots are not
+// returned by List, Load, or Search.
+func (s *Store) Snapshot(session *Session, at time.Time) (string, error) {
+	if session.ID == "" {
+		return "", ErrEmptyID
+	}
+
+	if !validSessionID.MatchString(session.ID) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidID, session.ID)
+	}
+
+	dir := filepath.Join(s.dir, snapshotDir)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("create snapshot directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal session: %w", err)
+	}
+
+	path := filepath.Join(dir, session.ID+"-"+at.Format(snapshotTimeFormat)+".json")
+	if writeErr := os.WriteFile(path, data, 0o600); writeErr != nil {
+		return "", fmt.Errorf("write snapshot file: %w", writeErr)
+	}
+
+	return path, nil
+}
+
 func (s *Store) filename(date, id string) string {
 	return date + "-" + id + ".json"
 }
@@ -197,25 +233,34 @@ func (s *Store) readSessionFile(path string) (*Session, error) {
 	return &sess, nil
 }
 
-func (s *Store) readAllSessions() ([]*Session, error) {
+// Scan reads every session file in chronological order. Files that cannot
+// be read or parsed are skipped and counted in skipped.
+func (s *Store) Scan() ([]*Session, int, error) {
 	pattern := filepath.Join(s.dir, "*.json")
 
 	matches, err := filepath.Glob(pattern)
 	if err != nil {
-		return nil, fmt.Errorf("glob session files: %w", err)
+		return nil, 0, fmt.Errorf("glob session files: %w", err)
 	}
 
 	// Glob returns sorted results, so filenames with date prefixes are chronologically ordered.
 	sessions := make([]*Session, 0, len(matches))
+	skipped := 0
 
 	for _, match := range matches {
 		sess, readErr := s.readSessionFile(match)
 		if readErr != nil {
+			skipped++
 			continue
 		}
 
 		sessions = append(sessions, sess)
 	}
 
-	return sessions, nil
+	return sessions, skipped, nil
+}
+
+func (s *Store) readAllSessions() ([]*Session, error) {
+	sessions, _, err := s.Scan()
+	return sessions, err
 }
@@ -521,3 +521,28 @@ func TestStore_LoadValidIDFormats(t *testing.T) {
 		})
 	}
 }
+
+func TestStore_ScanCountsUnreadableFiles(t *testing.T) {
+	dir := t.TempDir()
+	store := session.NewStore(dir)
+
+	require.NoError(t, store.Save(&session.Session{
+		Version:       "1",
+		ID:            "good",
+		Date:          "2026-02-14",
+		Started:       time.Date(2026, 2, 14, 10, 0, 0, 0, time.UTC),
+		Ended:         time.Time{},
+		Title:         "Readable",
+		Summary:       "",
+		ToolsUsed:     nil,
+		FilesModified: nil,
+		MessageCount:  0,
+	}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2026-02-15-bad.json"), []byte("{not json"), 0o600))
+
+	sessions, skipped, err := store.Scan()
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "good", sessions[0].ID)
+	assert.Equal(t, 1, skipped)
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// summaryListLimit caps how many files or tools a derived summary names
+// before collapsing the rest into a count.
+const summaryListLimit = 3
+
+// defaultTitle matches the placeholder title written at session end, which
+// carries no information about the work done.
+var defaultTitle = regexp.MustCompile(`^Session \d{2}:\d{2}$`)
+
+// Summarize derives a one-line summary from the metadata stored with a
+// session: its title when it is not the placeholder, the files it modified,
+// the tools it used, and its message count. The result is deterministic and
+// is empty when the session holds nothing to summarize.
+func Summarize(sess *Session) string {
+	var parts []string
+
+	if title := strings.TrimSpace(sess.Title); title != "" && !IsDefaultTitle(title) {
+		parts = append(parts, strings.TrimSuffix(title, ".")+".")
+	}
+
+	var activity string
+	if len(sess.FilesModified) > 0 {
+		names := make([]string, 0, len(sess.FilesModified))
+		for _, f := range sess.FilesModified {
+			names = append(names, filepath.Base(f))
+		}
+		activity = "Modified " + joinLimited(names, "file", "files")
+	}
+	if len(sess.ToolsUsed) > 0 {
+		tools := joinLimited(sess.ToolsUsed, "tool", "tools")
+		if activity == "" {
+			activity = "Used " + tools
+		} else {
+			activity += " using " + tools
+		}
+	}
+	if sess.MessageCount > 0 {
+		messages := pluralize(sess.MessageCount, "message", "messages")
+		if activity == "" {
+			activity = fmt.Sprintf("%d %s", sess.MessageCount, messages)
+		} else {
+			activity += fmt.Sprintf(" over %d %s", sess.MessageCount, messages)
+		}
+	}
+	if activity != "" {
+		parts = append(parts, activity+".")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// IsDefaultTitle reports whether title is the placeholder written at
+// session end, such as "Session 14:05".
+func IsDefaultTitle(title string) bool {
+	return defaultTitle.MatchString(title)
+}
+
+// joinLimited joins up to summaryListLimit items and appends a count of
+// the remainder, e.g. "a, b, c and 2 more files".
+func joinLimited(items []string, singular, plural string) string {
+	if len(items) <= summaryListLimit {
+		return strings.Join(items, ", ")
+	}
+	rest := len(items) - summaryListLimit
+	return fmt.Sprintf("%s and %d more %s",
+		strings.Join(items[:summaryListLimit], ", "), rest, pluralize(rest, singular, plural))
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,80 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riddopic/cc-tools/internal/session"
+)
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		files []string
+		tools []string
+		msgs  int
+		want  string
+	}{
+		{
+			name:  "placeholder title with activity",
+			title: "Session 14:05",
+			files: []string{"/repo/internal/store.go", "/repo/cmd/main.go"},
+			tools: []string{"Edit", "Write"},
+			msgs:  12,
+			want:  "Modified store.go, main.go using Edit, Write over 12 messages.",
+		},
+		{
+			name:  "descriptive title is kept",
+			title: "Refactor auth module",
+			files: []string{"auth.go"},
+			tools: nil,
+			msgs:  1,
+			want:  "Refactor auth module. Modified auth.go over 1 message.",
+		},
+		{
+			name:  "long lists are collapsed",
+			title: "",
+			files: []string{"a.go", "b.go", "c.go", "d.go", "e.go"},
+			tools: []string{"Bash", "Edit", "Grep", "Read"},
+			msgs:  0,
+			want:  "Modified a.go, b.go, c.go and 2 more files using Bash, Edit, Grep and 1 more tool.",
+		},
+		{
+			name:  "tools only",
+			title: "Session 09:30",
+			files: nil,
+			tools: []string{"Bash"},
+			msgs:  3,
+			want:  "Used Bash over 3 messages.",
+		},
+		{
+			name:  "nothing to summarize",
+			title: "Session 09:30",
+			files: nil,
+			tools: nil,
+			msgs:  0,
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess := &session.Session{
+				Version:       "1",
+				ID:            "abc123",
+				Date:          "2026-02-14",
+				Started:       time.Time{},
+				Ended:         time.Time{},
+				Title:         tt.title,
+				Summary:       "",
+				ToolsUsed:     tt.tools,
+				FilesModified: tt.files,
+				MessageCount:  tt.msgs,
+			}
+			assert.Equal(t, tt.want, session.Summarize(sess))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrTagNotFound indicates the session does not carry the requested tag.
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrInvalidTag indicates a tag that is empty or contains whitespace.
+	ErrInvalidTag = errors.New("invalid tag")
+)
+
+// TagManager manages freeform tags on session IDs. Unlike aliases, a
+// session can carry any number of tags and a tag can label any number of
+// sessions. Tags are kept in their own file, so removing session files
+// does not lose them.
+type TagManager struct {
+	path string
+}
+
+// NewTagManager creates a new TagManager that persists tags at the given file path.
+func NewTagManager(path string) *TagManager {
+	return &TagManager{path: path}
+}
+
+// Add labels a session with a tag. Adding a tag the session already
+// carries does nothing.
+func (tm *TagManager) Add(sessionID, tag string) error {
+	if err := validateTag(tag); err != nil {
+		return err
+	}
+
+	tags, err := tm.loadTags()
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(tags[sessionID], tag) {
+		return nil
+	}
+	tags[sessionID] = append(tags[sessionID], tag)
+	slices.Sort(tags[sessionID])
+
+	return tm.saveTags(tags)
+}
+
+// Remove takes a tag off a session.
+func (tm *TagManager) Remove(sessionID, tag string) error {
+	tags, err := tm.loadTags()
+	if err != nil {
+		return err
+	}
+
+	i := slices.Index(tags[sessionID], tag)
+	if i < 0 {
+		return fmt.Errorf("%w: %s on session %s", ErrTagNotFound, tag, sessionID)
+	}
+
+	tags[sessionID] = slices.Delete(tags[sessionID], i, i+1)
+	if len(tags[sessionID]) == 0 {
+		delete(tags, sessionID)
+	}
+
+	return tm.saveTags(tags)
+}
+
+// Tags returns the tags on a session in sorted order.
+func (tm *TagManager) Tags(sessionID string) ([]string, error) {
+	tags, err := tm.loadTags()
+	if err != nil {
+		return nil, err
+	}
+
+	return tags[sessionID], nil
+}
+
+// Sessions returns the IDs of the sessions carrying tag, in sorted order.
+func (tm *TagManager) Sessions(tag string) ([]string, error) {
+	tags, err := tm.loadTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for id, sessionTags := range tags {
+		if slices.Contains(sessionTags, tag) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	return ids, nil
+}
+
+// List returns all tags as a map from session ID to its sorted tags.
+func (tm *TagManager) List() (map[string][]string, error) {
+	return tm.loadTags()
+}
+
+// validateTag rejects tags that could not be given back on the command line.
+func validateTag(tag string) error {
+	if tag == "" || strings.ContainsFunc(tag, unicode.IsSpace) {
+		return fmt.Errorf("%w: %q must be non-empty and contain no whitespace", ErrInvalidTag, tag)
+	}
+	return nil
+}
+
+func (tm *TagManager) loadTags() (map[string][]string, error) {
+	data, err := os.ReadFile(tm.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string][]string), nil
+		}
+
+		return nil, fmt.Errorf("read tag file: %w", err)
+	}
+
+	var tags map[string][]string
+	if unmarshalErr := json.Unmarshal(data, &tags); unmarshalErr != nil {
+		return nil, fmt.Errorf("unmarshal tag file: %w", unmarshalErr)
+	}
+	if tags == nil {
+		tags = make(map[string][]string)
+	}
+
+	return tags, nil
+}
+
+func (tm *TagManager) saveTags(tags map[string][]string) error {
+	dir := filepath.Dir(tm.path)
+	if mkdirErr := os.MkdirAll(dir, 0o750); mkdirErr != nil {
+		return fmt.Errorf("create tag directory: %w", mkdirErr)
+	}
+
+	data, err := json.MarshalIndent(tags, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal tags: %w", err)
+	}
+
+	if writeErr := os.WriteFile(tm.path, data, 0o600); writeErr != nil {
+		return fmt.Errorf("write tag file: %w", writeErr)
+	}
+
+	return nil
+}
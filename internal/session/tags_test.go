@@ -0,0 +1,72 @@
+package session_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/session"
+)
+
+func TestTagManager_AddAndRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session-tags.json")
+	tm := session.NewTagManager(path)
+
+	require.NoError(t, tm.Add("sess1", "auth"))
+	require.NoError(t, tm.Add("sess1", "bug"))
+	require.NoError(t, tm.Add("sess1", "auth"), "adding a tag twice is a no-op")
+	require.NoError(t, tm.Add("sess2", "auth"))
+
+	tags, err := tm.Tags("sess1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auth", "bug"}, tags)
+
+	ids, err := tm.Sessions("auth")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sess1", "sess2"}, ids)
+
+	require.NoError(t, tm.Remove("sess1", "auth"))
+	ids, err = tm.Sessions("auth")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sess2"}, ids)
+
+	require.NoError(t, tm.Remove("sess1", "bug"))
+	all, err := tm.List()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"sess2": {"auth"}}, all, "untagged sessions are dropped")
+}
+
+func TestTagManager_RemoveReturnsErrorForUnknownTag(t *testing.T) {
+	tm := session.NewTagManager(filepath.Join(t.TempDir(), "session-tags.json"))
+	require.NoError(t, tm.Add("sess1", "auth"))
+
+	err := tm.Remove("sess1", "missing")
+	require.ErrorIs(t, err, session.ErrTagNotFound)
+
+	err = tm.Remove("other", "auth")
+	require.ErrorIs(t, err, session.ErrTagNotFound)
+}
+
+func TestTagManager_AddRejectsInvalidTags(t *testing.T) {
+	tm := session.NewTagManager(filepath.Join(t.TempDir(), "session-tags.json"))
+
+	require.ErrorIs(t, tm.Add("sess1", ""), session.ErrInvalidTag)
+	require.ErrorIs(t, tm.Add("sess1", "two words"), session.ErrInvalidTag)
+}
+
+func TestTagManager_SurvivesSessionRemoval(t *testing.T) {
+	dir := t.TempDir()
+	store := session.NewStore(filepath.Join(dir, "sessions"))
+	require.NoError(t, store.Save(&session.Session{Version: "1", ID: "sess1", Date: "2026-02-20", Title: "x"}))
+	tm := session.NewTagManager(filepath.Join(dir, "session-tags.json"))
+	require.NoError(t, tm.Add("sess1", "auth"))
+
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "sessions")))
+
+	tags, err := tm.Tags("sess1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auth"}, tags)
+}
@@ -0,0 +1,22 @@
+package shared
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CacheDir returns the cc-tools cache directory joined with sub. It respects
+// $XDG_CACHE_HOME and defaults to ~/.cache/cc-tools.
+func CacheDir(sub ...string) (string, error) {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("get home directory: %w", err)
+		}
+		base = filepath.Join(home, ".cache")
+	}
+
+	return filepath.Join(append([]string{base, "cc-tools"}, sub...)...), nil
+}
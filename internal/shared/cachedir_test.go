@@ -0,0 +1,37 @@
+package shared_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+func TestCacheDir(t *testing.T) {
+	t.Run("returns XDG_CACHE_HOME when set", func(t *testing.T) {
+		t.Setenv("XDG_CACHE_HOME", "/custom/cache")
+		got, err := shared.CacheDir()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("/custom/cache", "cc-tools"), got)
+	})
+
+	t.Run("joins subdirectories", func(t *testing.T) {
+		t.Setenv("XDG_CACHE_HOME", "/custom/cache")
+		got, err := shared.CacheDir("observations", "archive")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("/custom/cache", "cc-tools", "observations", "archive"), got)
+	})
+
+	t.Run("defaults to ~/.cache/cc-tools", func(t *testing.T) {
+		t.Setenv("XDG_CACHE_HOME", "")
+		home, err := os.UserHomeDir()
+		require.NoError(t, err)
+		got, err := shared.CacheDir("compact")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(home, ".cache", "cc-tools", "compact"), got)
+	})
+}
@@ -0,0 +1,31 @@
+package shared_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 21, 0, 0, 0, time.UTC)
+	clock := shared.NewFakeClock(start)
+
+	assert.Equal(t, start, clock.Now())
+	assert.Equal(t, start, clock.Now(), "time must not move on its own")
+
+	clock.Advance(90 * time.Minute)
+	assert.Equal(t, start.Add(90*time.Minute), clock.Now())
+
+	later := time.Date(2026, 1, 2, 7, 30, 0, 0, time.UTC)
+	clock.Set(later)
+	assert.Equal(t, later, clock.Now())
+}
+
+func TestRealClock(t *testing.T) {
+	before := time.Now()
+	got := shared.RealClock{}.Now()
+	assert.False(t, got.Before(before))
+}
@@ -14,8 +14,13 @@ const minDirParts = 2
 // GetDebugLogPathForDir returns the debug log path for a specific directory.
 func GetDebugLogPathForDir(dir string) string {
 	// Create a sanitized version of the directory path for the filename
-	// Use last two directory components if possible for readability
+	// Use last two directory components if possible for readability.
+	// Symlinks are resolved so the same physical directory always maps to
+	// the same log file.
 	cleanPath := filepath.Clean(dir)
+	if filepath.IsAbs(cleanPath) {
+		cleanPath = ResolvePath(cleanPath)
+	}
 	parts := strings.Split(cleanPath, string(filepath.Separator))
 
 	// Filter out empty parts (e.g., from root "/" which splits to ["", ""])
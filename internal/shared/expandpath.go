@@ -0,0 +1,52 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUndefinedVar is returned when a path refers to an environment variable
+// that is not set.
+var ErrUndefinedVar = errors.New("undefined environment variable")
+
+// ExpandPath expands a path from configuration or the command line and
+// makes it absolute. A leading ~ or ~/ becomes the user's home directory,
+// and $VAR and ${VAR} become the variable's value; a variable that is not
+// set is an error rather than an empty string. Other forms of ~, such as
+// ~user, are left as written. Relative results are resolved against the
+// working directory. An empty path is returned unchanged.
+func ExpandPath(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expand ~: %w", err)
+		}
+		path = filepath.Join(home, path[len("~"):])
+	}
+
+	var undefined []string
+	path = os.Expand(path, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			undefined = append(undefined, name)
+		}
+		return value
+	})
+	if len(undefined) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrUndefinedVar, strings.Join(undefined, ", "))
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("get absolute path: %w", err)
+	}
+
+	return absPath, nil
+}
@@ -0,0 +1,66 @@
+package shared_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CC_TOOLS_TEST_DIR", "/data/sounds")
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "empty", path: "", want: ""},
+		{name: "absolute", path: "/var/tmp/../log", want: "/var/log"},
+		{name: "relative", path: "audio", want: filepath.Join(cwd, "audio")},
+		{name: "tilde alone", path: "~", want: home},
+		{name: "tilde prefix", path: "~/.claude/audio", want: filepath.Join(home, ".claude", "audio")},
+		{name: "tilde user is not expanded", path: "~alice/audio", want: filepath.Join(cwd, "~alice", "audio")},
+		{name: "tilde inside path is not expanded", path: "/srv/~/audio", want: "/srv/~/audio"},
+		{name: "dollar var", path: "$HOME/audio", want: filepath.Join(home, "audio")},
+		{name: "braced var", path: "${CC_TOOLS_TEST_DIR}/alerts", want: "/data/sounds/alerts"},
+		{name: "var mid path", path: "/mnt$CC_TOOLS_TEST_DIR", want: "/mnt/data/sounds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, expandErr := shared.ExpandPath(tt.path)
+			require.NoError(t, expandErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpandPath_UndefinedVar(t *testing.T) {
+	t.Setenv("CC_TOOLS_TEST_UNSET", "")
+	require.NoError(t, os.Unsetenv("CC_TOOLS_TEST_UNSET"))
+
+	_, err := shared.ExpandPath("$CC_TOOLS_TEST_UNSET/audio")
+	require.ErrorIs(t, err, shared.ErrUndefinedVar)
+	assert.Contains(t, err.Error(), "CC_TOOLS_TEST_UNSET")
+
+	_, err = shared.ExpandPath("${CC_TOOLS_TEST_UNSET}")
+	require.ErrorIs(t, err, shared.ErrUndefinedVar)
+}
+
+func TestExpandPath_EmptyVarIsDefined(t *testing.T) {
+	t.Setenv("CC_TOOLS_TEST_EMPTY", "")
+
+	got, err := shared.ExpandPath("/srv$CC_TOOLS_TEST_EMPTY/audio")
+	require.NoError(t, err)
+	assert.Equal(t, "/srv/audio", got)
+}
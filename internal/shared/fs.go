@@ -31,6 +31,7 @@ type FS interface {
 	Stat(name string) (os.FileInfo, error)
 	Getwd() (string, error)
 	Abs(path string) (string, error)
+	EvalSymlinks(path string) (string, error)
 }
 
 // RealFS implements HooksFS, RegistryFS, and FS using the real filesystem.
@@ -117,6 +118,14 @@ func (r *RealFS) Abs(path string) (string, error) {
 	return abs, nil
 }
 
+func (r *RealFS) EvalSymlinks(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("eval symlinks %s: %w", path, err)
+	}
+	return resolved, nil
+}
+
 func (r *RealFS) MkdirAll(path string, perm os.FileMode) error {
 	if err := os.MkdirAll(path, perm); err != nil {
 		return fmt.Errorf("mkdir all %s: %w", path, err)
@@ -91,6 +91,60 @@ func (_c *MockFS_Abs_Call) RunAndReturn(run func(path string) (string, error)) *
 	return _c
 }
 
+// EvalSymlinks provides a mock function for the type MockFS
+func (_mock *MockFS) EvalSymlinks(path string) (string, error) {
+	ret := _mock.Called(path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EvalSymlinks")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return returnFunc(path)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) string); ok {
+		r0 = returnFunc(path)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockFS_EvalSymlinks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EvalSymlinks'
+type MockFS_EvalSymlinks_Call struct {
+	*mock.Call
+}
+
+// EvalSymlinks is a helper method to define mock.On call
+//   - path
+func (_e *MockFS_Expecter) EvalSymlinks(path interface{}) *MockFS_EvalSymlinks_Call {
+	return &MockFS_EvalSymlinks_Call{Call: _e.mock.On("EvalSymlinks", path)}
+}
+
+func (_c *MockFS_EvalSymlinks_Call) Run(run func(path string)) *MockFS_EvalSymlinks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockFS_EvalSymlinks_Call) Return(s string, err error) *MockFS_EvalSymlinks_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockFS_EvalSymlinks_Call) RunAndReturn(run func(path string) (string, error)) *MockFS_EvalSymlinks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Getwd provides a mock function for the type MockFS
 func (_mock *MockFS) Getwd() (string, error) {
 	ret := _mock.Called()
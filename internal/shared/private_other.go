@@ -0,0 +1,9 @@
+//go:build !unix
+
+package shared
+
+import "os"
+
+// privateToUser accepts every file on platforms without Unix owners and
+// modes, where the temp directory is already private to the user.
+func privateToUser(_ os.FileInfo) bool { return true }
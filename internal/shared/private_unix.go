@@ -0,0 +1,15 @@
+//go:build unix
+
+package shared
+
+import (
+	"os"
+	"syscall"
+)
+
+// privateToUser reports whether info describes a file owned by the user
+// running the process that no other user can read or write.
+func privateToUser(info os.FileInfo) bool {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(st.Uid) == os.Getuid() && info.Mode().Perm() == 0o700
+}
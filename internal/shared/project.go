@@ -41,6 +41,12 @@ func FindProjectRoot(startDir string, deps *Dependencies) (string, error) {
 		return "", fmt.Errorf("getting absolute path: %w", err)
 	}
 
+	// Resolve symlinks so a project reached through a link maps to the same
+	// root as its physical path.
+	if resolved, evalErr := deps.FS.EvalSymlinks(absDir); evalErr == nil {
+		absDir = resolved
+	}
+
 	for {
 		// Check for project root markers
 		markers := []string{
@@ -77,6 +83,42 @@ func FindProjectRoot(startDir string, deps *Dependencies) (string, error) {
 	return dir, nil
 }
 
+// ResolveProjectRoot returns override as the project root when it is set,
+// without looking for markers, and otherwise FindProjectRoot(startDir). An
+// override that is not an existing directory is an error.
+func ResolveProjectRoot(override, startDir string, deps *Dependencies) (string, error) {
+	if override == "" {
+		return FindProjectRoot(startDir, deps)
+	}
+	if deps == nil {
+		deps = NewDefaultDependencies()
+	}
+
+	root := ResolvePath(override)
+	info, err := deps.FS.Stat(root)
+	if err != nil {
+		return "", fmt.Errorf("project root: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("project root %s is not a directory", override)
+	}
+	return root, nil
+}
+
+// ResolvePath returns the absolute form of path with symlinks resolved. When
+// the path cannot be resolved, for example because it does not exist, the
+// cleaned absolute path is returned instead.
+func ResolvePath(path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	if resolved, evalErr := filepath.EvalSymlinks(absPath); evalErr == nil {
+		return resolved
+	}
+	return absPath
+}
+
 // DetectProjectType analyzes the project directory to determine its type.
 func DetectProjectType(projectDir string, deps *Dependencies) []string {
 	if deps == nil {
@@ -152,8 +194,25 @@ func fileExists(path string, deps *Dependencies) bool {
 }
 
 // ShouldSkipFile determines if a file should be skipped based on common patterns.
-// This function doesn't need dependency injection as it only does string manipulation.
+// Patterns are matched against the symlink-resolved path so a file reached
+// through a link is treated the same as its physical location.
 func ShouldSkipFile(filePath string) bool {
+	return ShouldSkipFileFold(filePath, false)
+}
+
+// ShouldSkipFileFold is ShouldSkipFile with optional case folding. With
+// fold set, directory patterns and test or generated file suffixes match
+// regardless of case, as suits a case-insensitive filesystem where
+// /project/VENDOR/lib.go is the vendor directory.
+func ShouldSkipFileFold(filePath string, fold bool) bool {
+	if filepath.IsAbs(filePath) {
+		filePath = ResolvePath(filePath)
+	}
+	if fold {
+		// Every built-in pattern is lower case.
+		filePath = strings.ToLower(filePath)
+	}
+
 	// Built-in patterns to always skip
 	skipPatterns := []string{
 		"/vendor/",
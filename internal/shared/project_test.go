@@ -42,6 +42,11 @@ func (m *mockFileSystem) Abs(path string) (string, error) {
 	return path, nil
 }
 
+// EvalSymlinks returns the path unchanged; the mock has no links.
+func (m *mockFileSystem) EvalSymlinks(path string) (string, error) {
+	return path, nil
+}
+
 // Mock FileInfo implementation.
 type mockFileInfo struct {
 	name    string
@@ -512,6 +517,37 @@ func TestShouldSkipFile(t *testing.T) {
 	}
 }
 
+func TestShouldSkipFileFold(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		fold     bool
+		expected bool
+	}{
+		{"upper case vendor", "/project/VENDOR/lib.go", true, true},
+		{"mixed case node_modules", "/project/Node_Modules/package/index.js", true, true},
+		{"mixed case go test file", "/project/Main_Test.GO", true, true},
+		{"mixed case generated file", "/project/Service.PB.go", true, true},
+		{"regular file", "/project/Main.go", true, false},
+		{"test in name but not suffix", "/project/Test_Utils.go", true, false},
+
+		// Without folding, matching stays exact.
+		{"upper case vendor without fold", "/project/VENDOR/lib.go", false, false},
+		{"mixed case node_modules without fold", "/project/Node_Modules/package/index.js", false, false},
+		{"mixed case go test file without fold", "/project/Main_Test.GO", false, false},
+		{"lower case vendor without fold", "/project/vendor/lib.go", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := shared.ShouldSkipFileFold(tt.filePath, tt.fold)
+			if result != tt.expected {
+				t.Errorf("ShouldSkipFileFold(%q, %v) = %v, expected %v", tt.filePath, tt.fold, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestProjectHelper(t *testing.T) {
 	t.Run("NewProjectHelper with nil deps", func(t *testing.T) {
 		helper := shared.NewProjectHelper(nil)
@@ -610,3 +646,94 @@ func TestNewDefaultDependencies(t *testing.T) {
 		t.Errorf("Getwd failed: %v", err)
 	}
 }
+
+func TestSymlinkedProjectRoot(t *testing.T) {
+	base := t.TempDir()
+	realRoot := filepath.Join(base, "real")
+	subDir := filepath.Join(realRoot, "pkg", "sub")
+	if err := os.MkdirAll(subDir, 0o750); err != nil {
+		t.Fatalf("creating project: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(realRoot, "go.mod"), []byte("module x\n"), 0o600); err != nil {
+		t.Fatalf("writing go.mod: %v", err)
+	}
+	realRoot, err := filepath.EvalSymlinks(realRoot)
+	if err != nil {
+		t.Fatalf("resolving real root: %v", err)
+	}
+
+	link := filepath.Join(base, "link")
+	if symErr := os.Symlink(realRoot, link); symErr != nil {
+		t.Skipf("symlinks not supported: %v", symErr)
+	}
+
+	viaLink, err := shared.FindProjectRoot(filepath.Join(link, "pkg", "sub"), nil)
+	if err != nil {
+		t.Fatalf("FindProjectRoot(link) error = %v", err)
+	}
+	viaReal, err := shared.FindProjectRoot(subDir, nil)
+	if err != nil {
+		t.Fatalf("FindProjectRoot(real) error = %v", err)
+	}
+	if viaLink != realRoot || viaReal != realRoot {
+		t.Errorf("FindProjectRoot() = %q via link, %q via real path; want %q", viaLink, viaReal, realRoot)
+	}
+
+	if got := shared.ResolvePath(link); got != realRoot {
+		t.Errorf("ResolvePath(link) = %q, want %q", got, realRoot)
+	}
+	if shared.GetDebugLogPathForDir(link) != shared.GetDebugLogPathForDir(realRoot) {
+		t.Error("GetDebugLogPathForDir() differs between link and real path")
+	}
+
+	vendorDir := filepath.Join(realRoot, "vendor")
+	if mkErr := os.MkdirAll(vendorDir, 0o750); mkErr != nil {
+		t.Fatalf("creating vendor dir: %v", mkErr)
+	}
+	vendorLink := filepath.Join(base, "deps")
+	if symErr := os.Symlink(vendorDir, vendorLink); symErr != nil {
+		t.Fatalf("linking vendor dir: %v", symErr)
+	}
+	vendored := filepath.Join(vendorLink, "lib.go")
+	if writeErr := os.WriteFile(vendored, []byte("package lib\n"), 0o600); writeErr != nil {
+		t.Fatalf("writing vendored file: %v", writeErr)
+	}
+	if !shared.ShouldSkipFile(vendored) {
+		t.Errorf("ShouldSkipFile(%q) = false, want true for a file linked into vendor/", vendored)
+	}
+}
+
+func TestResolveProjectRoot(t *testing.T) {
+	outer := t.TempDir()
+	inner := filepath.Join(outer, "services", "api")
+	if err := os.MkdirAll(inner, 0o750); err != nil {
+		t.Fatalf("creating dirs: %v", err)
+	}
+	for _, marker := range []string{filepath.Join(outer, "go.mod"), filepath.Join(inner, "Makefile")} {
+		if err := os.WriteFile(marker, nil, 0o600); err != nil {
+			t.Fatalf("writing marker: %v", err)
+		}
+	}
+	outer = shared.ResolvePath(outer)
+	inner = shared.ResolvePath(inner)
+
+	if got, err := shared.ResolveProjectRoot("", inner, nil); err != nil || got != inner {
+		t.Errorf("without override = %q, %v; want the nearest marker %q", got, err, inner)
+	}
+	if got, err := shared.ResolveProjectRoot(outer, inner, nil); err != nil || got != outer {
+		t.Errorf("with override = %q, %v; want %q regardless of markers", got, err, outer)
+	}
+	if _, err := shared.ResolveProjectRoot(filepath.Join(outer, "missing"), inner, nil); err == nil {
+		t.Error("expected an error for a missing override")
+	}
+	if _, err := shared.ResolveProjectRoot(filepath.Join(inner, "Makefile"), inner, nil); err == nil {
+		t.Error("expected an error for an override that is a file")
+	}
+}
+
+func TestResolvePath_Missing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "does", "not", "exist")
+	if got := shared.ResolvePath(missing); got != missing {
+		t.Errorf("ResolvePath(%q) = %q, want unchanged", missing, got)
+	}
+}
@@ -0,0 +1,40 @@
+package shared
+
+import "strings"
+
+// ShellQuote returns word quoted so that a POSIX shell reads it back as a
+// single word. Words made only of characters the shell treats literally are
+// returned unchanged; anything else is wrapped in single quotes, closing and
+// reopening them around each embedded single quote.
+func ShellQuote(word string) string {
+	if word == "" {
+		return "''"
+	}
+	if strings.IndexFunc(word, needsShellQuote) < 0 {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// ShellJoin returns argv as a command line for display, with each word
+// quoted by ShellQuote so the result can be pasted into a shell.
+func ShellJoin(argv []string) string {
+	words := make([]string, 0, len(argv))
+	for _, word := range argv {
+		words = append(words, ShellQuote(word))
+	}
+	return strings.Join(words, " ")
+}
+
+// needsShellQuote reports whether r has a meaning to the shell outside
+// quotes.
+func needsShellQuote(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	case strings.ContainsRune("-_./=:,+@%", r):
+		return false
+	default:
+		return true
+	}
+}
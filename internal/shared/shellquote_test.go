@@ -0,0 +1,32 @@
+package shared_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "", want: "''"},
+		{word: "lint", want: "lint"},
+		{word: "--flag=a/b.c", want: "--flag=a/b.c"},
+		{word: "My Project", want: "'My Project'"},
+		{word: "it's", want: `'it'\''s'`},
+		{word: "$HOME", want: "'$HOME'"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, shared.ShellQuote(tt.word), "ShellQuote(%q)", tt.word)
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	got := shared.ShellJoin([]string{"claude", "mcp", "add", "jira", "--project", "My Project"})
+	assert.Equal(t, "claude mcp add jira --project 'My Project'", got)
+}
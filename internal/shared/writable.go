@@ -0,0 +1,168 @@
+package shared
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// fallbackWarnedFile is the marker, kept in a fallback directory, recording
+// that the switch to it has been reported.
+const fallbackWarnedFile = ".fallback-warned"
+
+// ErrInsecureFallback indicates a fallback directory that is a symlink,
+// belongs to another user, or can be reached by other users. Its contents
+// may have been planted, so they are neither read nor written.
+var ErrInsecureFallback = errors.New("fallback directory is not private")
+
+// writableDirs caches dirWritable results for the life of the process, so
+// hot paths such as recording observations probe the file system once.
+var writableDirs sync.Map
+
+// FallbackDir returns the directory under the system temp directory that
+// stands in for dir when dir cannot be written, as on systems with a
+// read-only home directory. The same dir always maps to the same fallback,
+// so a later run finds what an earlier one wrote there.
+func FallbackDir(dir string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(dir)))
+	return filepath.Join(
+		os.TempDir(),
+		fmt.Sprintf("cc-tools-%d", os.Getuid()),
+		fmt.Sprintf("%s-%x", filepath.Base(dir), sum[:4]),
+	)
+}
+
+// IsMissing reports whether err from opening or stating a path means the
+// path is absent, including when one of its parents is a regular file
+// rather than a directory.
+func IsMissing(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR)
+}
+
+// ResolveFallbackFile returns the copy of path in the fallback of its
+// directory when the directory of path cannot be written, so a file saved
+// after WritableDir fell back is found again. The copy is used only when
+// the fallback directory passes CheckFallbackDir. Otherwise, including
+// whenever the directory of path is writable, it returns path.
+func ResolveFallbackFile(path string) string {
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+
+	dir := filepath.Dir(path)
+	if canWriteIn(dir) {
+		return path
+	}
+
+	fallbackDir := FallbackDir(dir)
+	if CheckFallbackDir(fallbackDir) != nil {
+		return path
+	}
+
+	fallback := filepath.Join(fallbackDir, filepath.Base(path))
+	if _, err := os.Stat(fallback); err == nil {
+		return fallback
+	}
+	return path
+}
+
+// CheckFallbackDir returns an error wrapping ErrInsecureFallback unless
+// dir, a directory returned by FallbackDir, and its per-user parent are
+// real directories owned by the current user with mode 0700. Another user
+// can create either one first in the shared temp directory.
+func CheckFallbackDir(dir string) error {
+	for _, d := range []string{filepath.Dir(dir), dir} {
+		info, err := os.Lstat(d)
+		if err != nil {
+			return fmt.Errorf("check fallback directory: %w", err)
+		}
+		if !info.IsDir() || !privateToUser(info) {
+			return fmt.Errorf("%w: %s", ErrInsecureFallback, d)
+		}
+	}
+	return nil
+}
+
+// WritableDir returns dir when it exists or can be created and a file can
+// be written in it. Otherwise it returns FallbackDir(dir), created with
+// owner-only permissions, and reports the switch on warnOut the first time
+// that fallback is used. A fallback that fails CheckFallbackDir is an
+// error.
+func WritableDir(dir string, warnOut io.Writer) (string, error) {
+	if dirWritable(dir, 0o750) {
+		return dir, nil
+	}
+
+	fallback := FallbackDir(dir)
+	if !dirWritable(fallback, 0o700) {
+		return "", fmt.Errorf("%s is not writable, and neither is the fallback %s", dir, fallback)
+	}
+	if err := CheckFallbackDir(fallback); err != nil {
+		return "", fmt.Errorf("%s is not writable: %w", dir, err)
+	}
+
+	marker := filepath.Join(fallback, fallbackWarnedFile)
+	if _, err := os.Stat(marker); err != nil {
+		_, _ = fmt.Fprintf(warnOut, "cc-tools: cannot write to %s; using %s instead\n", dir, fallback)
+		_ = os.WriteFile(marker, nil, 0o600)
+	}
+	return fallback, nil
+}
+
+// dirWritable creates dir with perm if needed and reports whether a file
+// can be created in it. The answer for each dir is cached for the life of
+// the process.
+func dirWritable(dir string, perm os.FileMode) bool {
+	if v, cached := writableDirs.Load(dir); cached {
+		ok, _ := v.(bool)
+		return ok
+	}
+
+	ok := probeWritable(dir, perm)
+	writableDirs.Store(dir, ok)
+	return ok
+}
+
+// canWriteIn reports whether a file could be written in dir, creating it
+// if needed, without creating anything: the nearest existing directory at
+// or above dir must accept a new file.
+func canWriteIn(dir string) bool {
+	for {
+		info, err := os.Stat(dir)
+		switch {
+		case err == nil:
+			return info.IsDir() && probeWritable(dir, 0)
+		case !errors.Is(err, fs.ErrNotExist):
+			return false
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// probeWritable creates dir with perm if needed and reports whether a file
+// can be created in it.
+func probeWritable(dir string, perm os.FileMode) bool {
+	if err := os.MkdirAll(dir, perm); err != nil {
+		return false
+	}
+
+	f, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	_ = f.Close()
+	_ = os.Remove(name)
+	return true
+}
@@ -0,0 +1,161 @@
+package shared_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// unwritableDir returns a directory path that cannot be created because a
+// parent is a regular file, which holds even when the tests run as root.
+func unwritableDir(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".cache"), nil, 0o600))
+	return filepath.Join(home, ".cache", "cc-tools")
+}
+
+func TestWritableDir(t *testing.T) {
+	t.Run("returns a writable dir, creating it", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "a", "b")
+		var warn bytes.Buffer
+
+		got, err := shared.WritableDir(dir, &warn)
+		require.NoError(t, err)
+		assert.Equal(t, dir, got)
+		assert.DirExists(t, dir)
+		assert.Empty(t, warn.String())
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries, "the write check leaves nothing behind")
+	})
+
+	t.Run("falls back to the temp dir with one warning", func(t *testing.T) {
+		t.Setenv("TMPDIR", t.TempDir())
+		dir := unwritableDir(t)
+		var warn bytes.Buffer
+
+		got, err := shared.WritableDir(dir, &warn)
+		require.NoError(t, err)
+		assert.Equal(t, shared.FallbackDir(dir), got)
+		assert.True(t, strings.HasPrefix(got, os.TempDir()))
+		assert.DirExists(t, got)
+		assert.Contains(t, warn.String(), "cannot write to "+dir)
+
+		warn.Reset()
+		again, err := shared.WritableDir(dir, &warn)
+		require.NoError(t, err)
+		assert.Equal(t, got, again, "the fallback is stable")
+		assert.Empty(t, warn.String(), "the switch is reported once")
+	})
+}
+
+func TestFallbackDir(t *testing.T) {
+	t.Setenv("TMPDIR", "/tmp/x")
+	a := shared.FallbackDir("/home/me/.cache/cc-tools")
+	assert.Equal(t, a, shared.FallbackDir("/home/me/.cache/cc-tools/"))
+	assert.NotEqual(t, a, shared.FallbackDir("/home/other/.cache/cc-tools"))
+	assert.True(t, strings.HasPrefix(filepath.Base(a), "cc-tools-"))
+}
+
+func TestResolveFallbackFile(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	path := filepath.Join(unwritableDir(t), "config.json")
+	assert.Equal(t, path, shared.ResolveFallbackFile(path), "nothing saved yet")
+
+	dir, err := shared.WritableDir(filepath.Dir(path), &bytes.Buffer{})
+	require.NoError(t, err)
+	saved := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(saved, []byte("{}"), 0o600))
+	assert.Equal(t, saved, shared.ResolveFallbackFile(path))
+
+	existing := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(existing, []byte("{}"), 0o600))
+	assert.Equal(t, existing, shared.ResolveFallbackFile(existing))
+}
+
+func TestResolveFallbackFile_WritableDirIgnoresFallback(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "cc-tools", "config.json")
+
+	fallback := shared.FallbackDir(filepath.Dir(path))
+	require.NoError(t, os.MkdirAll(fallback, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(fallback, "config.json"), []byte("{}"), 0o600))
+
+	assert.Equal(t, path, shared.ResolveFallbackFile(path),
+		"a missing file in a writable directory is not looked up in the fallback")
+}
+
+func TestResolveFallbackFile_InsecureFallbackIgnored(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	path := filepath.Join(unwritableDir(t), "config.json")
+
+	fallback := shared.FallbackDir(filepath.Dir(path))
+	require.NoError(t, os.MkdirAll(fallback, 0o700))
+	require.NoError(t, os.Chmod(fallback, 0o777)) //nolint:gosec // the insecure mode under test
+	require.NoError(t, os.WriteFile(filepath.Join(fallback, "config.json"), []byte("{}"), 0o600))
+
+	assert.Equal(t, path, shared.ResolveFallbackFile(path))
+}
+
+func TestCheckFallbackDir(t *testing.T) {
+	t.Run("accepts a private directory", func(t *testing.T) {
+		t.Setenv("TMPDIR", t.TempDir())
+		dir := shared.FallbackDir("/home/me/.config/cc-tools")
+		require.NoError(t, os.MkdirAll(dir, 0o700))
+		assert.NoError(t, shared.CheckFallbackDir(dir))
+	})
+
+	t.Run("rejects a directory others can write", func(t *testing.T) {
+		t.Setenv("TMPDIR", t.TempDir())
+		dir := shared.FallbackDir("/home/me/.config/cc-tools")
+		require.NoError(t, os.MkdirAll(dir, 0o700))
+		require.NoError(t, os.Chmod(filepath.Dir(dir), 0o777)) //nolint:gosec // the insecure mode under test
+		assert.ErrorIs(t, shared.CheckFallbackDir(dir), shared.ErrInsecureFallback)
+	})
+
+	t.Run("rejects a symlink", func(t *testing.T) {
+		t.Setenv("TMPDIR", t.TempDir())
+		dir := shared.FallbackDir("/home/me/.config/cc-tools")
+		require.NoError(t, os.MkdirAll(filepath.Dir(dir), 0o700))
+		target := t.TempDir()
+		require.NoError(t, os.Chmod(target, 0o700))
+		require.NoError(t, os.Symlink(target, dir))
+		assert.ErrorIs(t, shared.CheckFallbackDir(dir), shared.ErrInsecureFallback)
+	})
+
+	t.Run("rejects a missing directory", func(t *testing.T) {
+		t.Setenv("TMPDIR", t.TempDir())
+		assert.Error(t, shared.CheckFallbackDir(shared.FallbackDir("/home/me/.config/cc-tools")))
+	})
+}
+
+func TestWritableDir_InsecureFallback(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	dir := unwritableDir(t)
+	fallback := shared.FallbackDir(dir)
+	require.NoError(t, os.MkdirAll(fallback, 0o700))
+	require.NoError(t, os.Chmod(fallback, 0o755)) //nolint:gosec // the insecure mode under test
+
+	_, err := shared.WritableDir(dir, &bytes.Buffer{})
+	assert.ErrorIs(t, err, shared.ErrInsecureFallback)
+}
+
+func TestIsMissing(t *testing.T) {
+	_, err := os.Stat(filepath.Join(t.TempDir(), "absent"))
+	assert.True(t, shared.IsMissing(err))
+
+	_, err = os.Stat(filepath.Join(unwritableDir(t), "config.json"))
+	assert.True(t, shared.IsMissing(err), "a parent that is a file")
+
+	assert.False(t, shared.IsMissing(nil))
+	assert.False(t, shared.IsMissing(os.ErrPermission))
+}
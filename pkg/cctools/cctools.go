@@ -0,0 +1,288 @@
+// Package cctools is the supported Go API for embedding cc-tools. It exposes
+// lint and test command discovery, validation of an edited file, and config
+// loading, so that programs such as editor backends can reuse the engine
+// without running the cc-tools binary. The packages under internal/ may
+// change at any time; this package is the stable surface.
+package cctools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/riddopic/cc-tools/internal/config"
+	"github.com/riddopic/cc-tools/internal/hooks"
+	"github.com/riddopic/cc-tools/internal/shared"
+)
+
+// Config holds the cc-tools settings that discovery and validation use.
+// LoadConfig and DefaultConfig fill every field; a zero Config runs
+// commands with no timeout from the project root.
+type Config struct {
+	Validate  ValidateConfig
+	Discovery DiscoveryConfig
+}
+
+// ValidateConfig holds the validate.* settings.
+type ValidateConfig struct {
+	// Timeout is how long each command may run, in seconds.
+	Timeout int
+	// ExtraCommands are shell commands run alongside lint and test.
+	ExtraCommands []string
+	// Env holds KEY=VALUE pairs added to each command's environment.
+	Env []string
+	// CleanEnv starts commands from a minimal environment instead of the
+	// caller's.
+	CleanEnv bool
+	// InjectCI sets CI=true for each command.
+	InjectCI bool
+	// CaseInsensitiveSkip matches the built-in skip patterns regardless of
+	// case.
+	CaseInsensitiveSkip bool
+}
+
+// DiscoveryConfig holds the discovery.* settings.
+type DiscoveryConfig struct {
+	// RunFrom is "project_root" or "file_dir": the directory discovered
+	// commands run in.
+	RunFrom string
+}
+
+// CommandType names the kind of command to discover.
+type CommandType string
+
+const (
+	// CommandLint selects the project's lint command.
+	CommandLint CommandType = "lint"
+	// CommandTest selects the project's test command.
+	CommandTest CommandType = "test"
+)
+
+// Command is a discovered command: what to run, where, and where it was
+// found.
+type Command struct {
+	Type       CommandType
+	Command    string
+	Args       []string
+	WorkingDir string
+	// Source names where the command was found, such as "Makefile".
+	Source string
+}
+
+// String returns the command line, with words quoted as a POSIX shell
+// would need.
+func (c *Command) String() string {
+	if c == nil {
+		return ""
+	}
+	cmd := &hooks.DiscoveredCommand{
+		Type:       hooks.CommandType(c.Type),
+		Command:    c.Command,
+		Args:       c.Args,
+		WorkingDir: c.WorkingDir,
+		Source:     c.Source,
+	}
+	return cmd.String()
+}
+
+// Result holds the outcome of each command Validate ran. BothPassed is
+// true when every command passed.
+type Result struct {
+	LintResult   *CommandResult
+	TestResult   *CommandResult
+	ExtraResults []*CommandResult
+	BothPassed   bool
+}
+
+// CommandResult is the outcome of one command run by Validate.
+type CommandResult struct {
+	Type     CommandType
+	Success  bool
+	ExitCode int
+	// Message summarizes the outcome for display.
+	Message string
+	// Output is the command's stdout followed by its stderr.
+	Output  string
+	Command *Command
+	// Error is set when the command could not be run or timed out.
+	Error    error
+	Duration time.Duration
+}
+
+// Options adjusts how DiscoverCommand and Validate find the project. A nil
+// *Options uses the defaults.
+type Options struct {
+	// ProjectRoot, when set, is used as the project root instead of the
+	// nearest directory with a project marker. It must be an existing
+	// directory.
+	ProjectRoot string
+}
+
+// DefaultConfig returns the built-in settings.
+func DefaultConfig() *Config {
+	return configFromValues(config.GetDefaultConfig())
+}
+
+// LoadConfig reads the config file at path over the built-in defaults. An
+// empty path reads the user's config file. A missing file yields the
+// defaults; the file is never created.
+func LoadConfig(ctx context.Context, path string) (*Config, error) {
+	manager := config.NewManager()
+	if path != "" {
+		manager = config.NewManagerWithPath(path)
+	}
+
+	cfg, err := manager.GetConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	return configFromValues(cfg), nil
+}
+
+// DiscoverCommand finds the command of cmdType for dir, searching dir and
+// its parents up to the project root as validate does. A nil cfg uses the
+// defaults.
+func DiscoverCommand(
+	ctx context.Context, cmdType CommandType, dir string, cfg *Config, opts *Options,
+) (*Command, error) {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	projectRoot, err := shared.ResolveProjectRoot(opts.projectRoot(), dir, nil)
+	if err != nil {
+		return nil, fmt.Errorf("find project root: %w", err)
+	}
+
+	discovery := hooks.NewCommandDiscovery(projectRoot, cfg.Validate.Timeout, nil)
+	discovery.SetRunFrom(runFrom(cfg))
+	found, err := discovery.DiscoverCommand(ctx, hooks.CommandType(cmdType), dir)
+	if err != nil {
+		return nil, err
+	}
+	return commandFrom(found), nil
+}
+
+// Validate runs the lint, test, and extra commands for an edited file, as
+// the validate hook does, and returns their results. Nothing is printed,
+// and the cooldown and result cache do not apply. A file that validate
+// skips, such as one under node_modules, yields an empty passing result. A
+// nil cfg uses the defaults.
+func Validate(ctx context.Context, file string, cfg *Config, opts *Options) (*Result, error) {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	if shared.ShouldSkipFileFold(file, cfg.Validate.CaseInsensitiveSkip) {
+		return &Result{LintResult: nil, TestResult: nil, ExtraResults: nil, BothPassed: true}, nil
+	}
+
+	fileDir := filepath.Dir(file)
+	projectRoot, err := shared.ResolveProjectRoot(opts.projectRoot(), fileDir, nil)
+	if err != nil {
+		return nil, fmt.Errorf("find project root: %w", err)
+	}
+
+	skipConfig := &hooks.SkipConfig{
+		SkipLint:            false,
+		SkipTest:            false,
+		SkipDuringGitOps:    false,
+		WarnDirty:           false,
+		CaseInsensitiveSkip: cfg.Validate.CaseInsensitiveSkip,
+		Only:                "",
+		NoCooldown:          true,
+		Quiet:               true,
+		ProjectRoot:         projectRoot,
+	}
+	executor := hooks.NewParallelValidateExecutor(projectRoot, cfg.Validate.Timeout, false, skipConfig, nil)
+	executor.SetRunFrom(runFrom(cfg))
+	executor.SetExtraCommands(cfg.Validate.ExtraCommands)
+	executor.SetEnvPolicy(hooks.EnvPolicy{
+		Clean: cfg.Validate.CleanEnv,
+		Extra: cfg.Validate.Env,
+		NoCI:  !cfg.Validate.InjectCI,
+	})
+
+	result, err := executor.ExecuteValidations(ctx, projectRoot, fileDir)
+	if err != nil {
+		return nil, fmt.Errorf("validate %s: %w", file, err)
+	}
+	return resultFrom(result), nil
+}
+
+// projectRoot returns the project root override, or "" for none.
+func (o *Options) projectRoot() string {
+	if o == nil {
+		return ""
+	}
+	return o.ProjectRoot
+}
+
+// runFrom returns the discovery working directory mode set in cfg.
+func runFrom(cfg *Config) hooks.RunFrom {
+	if cfg.Discovery.RunFrom == string(hooks.RunFromFileDir) {
+		return hooks.RunFromFileDir
+	}
+	return hooks.RunFromProjectRoot
+}
+
+// configFromValues copies the settings the facade uses out of values.
+func configFromValues(values *config.Values) *Config {
+	return &Config{
+		Validate: ValidateConfig{
+			Timeout:             values.Validate.Timeout,
+			ExtraCommands:       values.Validate.ExtraCommands,
+			Env:                 values.Validate.Env,
+			CleanEnv:            values.Validate.CleanEnv,
+			InjectCI:            values.Validate.InjectCI,
+			CaseInsensitiveSkip: values.Validate.CaseInsensitiveSkip,
+		},
+		Discovery: DiscoveryConfig{RunFrom: values.Discovery.RunFrom},
+	}
+}
+
+// commandFrom converts a discovered command, keeping nil as nil.
+func commandFrom(cmd *hooks.DiscoveredCommand) *Command {
+	if cmd == nil {
+		return nil
+	}
+	return &Command{
+		Type:       CommandType(cmd.Type),
+		Command:    cmd.Command,
+		Args:       cmd.Args,
+		WorkingDir: cmd.WorkingDir,
+		Source:     cmd.Source,
+	}
+}
+
+// commandResultFrom converts one command's result, keeping nil as nil.
+func commandResultFrom(result *hooks.ValidationResult) *CommandResult {
+	if result == nil {
+		return nil
+	}
+	return &CommandResult{
+		Type:     CommandType(result.Type),
+		Success:  result.Success,
+		ExitCode: result.ExitCode,
+		Message:  result.Message,
+		Output:   result.Output,
+		Command:  commandFrom(result.Command),
+		Error:    result.Error,
+		Duration: result.Duration,
+	}
+}
+
+// resultFrom converts the results of a validation run.
+func resultFrom(result *hooks.ValidateResult) *Result {
+	var extra []*CommandResult
+	for _, r := range result.ExtraResults {
+		extra = append(extra, commandResultFrom(r))
+	}
+	return &Result{
+		LintResult:   commandResultFrom(result.LintResult),
+		TestResult:   commandResultFrom(result.TestResult),
+		ExtraResults: extra,
+		BothPassed:   result.BothPassed,
+	}
+}
@@ -0,0 +1,86 @@
+package cctools_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/riddopic/cc-tools/pkg/cctools"
+)
+
+// newProject writes a Go module with a Makefile whose lint target passes
+// and whose test target fails, and returns its main.go path.
+func newProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":   "module example.com/demo\n",
+		"Makefile": ".PHONY: lint test\nlint:\n\t@true\ntest:\n\t@echo broken; exit 1\n",
+		"main.go":  "package main\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return filepath.Join(dir, "main.go")
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	cfg, err := cctools.LoadConfig(context.Background(), path)
+	require.NoError(t, err)
+	assert.Equal(t, cctools.DefaultConfig().Validate.Timeout, cfg.Validate.Timeout)
+	assert.NoFileExists(t, path, "loading does not create the file")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"validate":{"timeout":90}}`), 0o600))
+	cfg, err = cctools.LoadConfig(context.Background(), path)
+	require.NoError(t, err)
+	assert.Equal(t, 90, cfg.Validate.Timeout)
+}
+
+func TestDiscoverCommand(t *testing.T) {
+	file := newProject(t)
+
+	cmd, err := cctools.DiscoverCommand(context.Background(), cctools.CommandLint, filepath.Dir(file), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "make lint", cmd.String())
+	assert.Equal(t, "Makefile", cmd.Source)
+}
+
+func TestValidate(t *testing.T) {
+	file := newProject(t)
+
+	result, err := cctools.Validate(context.Background(), file, nil, nil)
+	require.NoError(t, err)
+	assert.False(t, result.BothPassed)
+	require.NotNil(t, result.LintResult)
+	assert.True(t, result.LintResult.Success)
+	require.NotNil(t, result.TestResult)
+	assert.False(t, result.TestResult.Success)
+	assert.Contains(t, result.TestResult.Output, "broken")
+
+	skipped, err := cctools.Validate(context.Background(), filepath.Join(filepath.Dir(file), "node_modules", "x.js"), nil, nil)
+	require.NoError(t, err)
+	assert.True(t, skipped.BothPassed)
+	assert.Nil(t, skipped.LintResult)
+}
+
+func TestValidate_ProjectRoot(t *testing.T) {
+	file := newProject(t)
+	sub := filepath.Join(filepath.Dir(file), "scratch")
+	require.NoError(t, os.Mkdir(sub, 0o750))
+	scratch := filepath.Join(sub, "notes.go")
+	require.NoError(t, os.WriteFile(scratch, []byte("package scratch\n"), 0o600))
+
+	result, err := cctools.Validate(context.Background(), scratch, nil, &cctools.Options{ProjectRoot: sub})
+	require.NoError(t, err)
+	assert.True(t, result.BothPassed)
+	assert.Nil(t, result.LintResult, "the Makefile above the override root is not used")
+
+	_, err = cctools.Validate(context.Background(), file, nil, &cctools.Options{ProjectRoot: filepath.Join(sub, "missing")})
+	require.Error(t, err)
+}